@@ -1,15 +1,38 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
 
+	"github.com/antonovs105/project-management-system-go/internal/attachment"
+	"github.com/antonovs105/project-management-system-go/internal/comment"
+	"github.com/antonovs105/project-management-system-go/internal/event"
+	"github.com/antonovs105/project-management-system-go/internal/filter"
+	"github.com/antonovs105/project-management-system-go/internal/issuetype"
+	"github.com/antonovs105/project-management-system-go/internal/job"
+	"github.com/antonovs105/project-management-system-go/internal/mail"
 	authMiddleware "github.com/antonovs105/project-management-system-go/internal/middleware"
+	"github.com/antonovs105/project-management-system-go/internal/notification"
 	"github.com/antonovs105/project-management-system-go/internal/project"
 	"github.com/antonovs105/project-management-system-go/internal/projectmember"
+	"github.com/antonovs105/project-management-system-go/internal/pubsub"
+	"github.com/antonovs105/project-management-system-go/internal/report"
+	"github.com/antonovs105/project-management-system-go/internal/search"
+	"github.com/antonovs105/project-management-system-go/internal/sla"
+	"github.com/antonovs105/project-management-system-go/internal/sprint"
+	"github.com/antonovs105/project-management-system-go/internal/storage"
 	"github.com/antonovs105/project-management-system-go/internal/ticket"
 	"github.com/antonovs105/project-management-system-go/internal/user"
+	"github.com/antonovs105/project-management-system-go/internal/version"
+	"github.com/antonovs105/project-management-system-go/internal/webhook"
+	"github.com/antonovs105/project-management-system-go/internal/worklog"
 	"github.com/jmoiron/sqlx"
 	"github.com/joho/godotenv"
 	"github.com/labstack/echo/v4"
@@ -19,10 +42,24 @@ import (
 
 // Server structure
 type ApiServer struct {
-	db             *sqlx.DB
-	userHandler    *user.Handler
-	projectHandler *project.Handler
-	ticketHandler  *ticket.Handler
+	db                  *sqlx.DB
+	userHandler         *user.Handler
+	projectHandler      *project.Handler
+	ticketHandler       *ticket.Handler
+	issueTypeHandler    *issuetype.Handler
+	sprintHandler       *sprint.Handler
+	worklogHandler      *worklog.Handler
+	reportHandler       *report.Handler
+	slaHandler          *sla.Handler
+	versionHandler      *version.Handler
+	commentHandler      *comment.Handler
+	attachmentHandler   *attachment.Handler
+	searchHandler       *search.Handler
+	filterHandler       *filter.Handler
+	eventHandler        *event.Handler
+	webhookHandler      *webhook.Handler
+	notificationHandler *notification.Handler
+	jobHandler          *job.Handler
 }
 
 func main() {
@@ -48,6 +85,20 @@ func main() {
 
 	log.Println("DB connection successful")
 
+	// ctx is cancelled on SIGINT or SIGTERM to shut down gracefully
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// workers tracks background loops, shutdown waits for them to return
+	var workers sync.WaitGroup
+	background := func(run func()) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			run()
+		}()
+	}
+
 	// User dependencies
 	userRepo := user.NewRepository(db)
 	userService := user.NewService(userRepo, []byte(jwtSecret))
@@ -62,17 +113,178 @@ func main() {
 	projectService := project.NewService(projectRepo, projectMemberService)
 	projectHandler := project.NewHandler(projectService)
 
+	// issue type dependencies
+	issueTypeRepo := issuetype.NewRepository(db)
+	issueTypeService := issuetype.NewService(issueTypeRepo, projectService)
+	issueTypeHandler := issuetype.NewHandler(issueTypeService)
+
+	// pub/sub between instances
+	bus := pubsub.NewPostgres(db, dbSource)
+	defer bus.Close()
+
+	// webhook dependencies
+	webhookRepo := webhook.NewRepository(db)
+	webhookService := webhook.NewService(webhookRepo, projectService)
+	webhookHandler := webhook.NewHandler(webhookService)
+	background(func() { webhookService.RunDispatcher(ctx, 5*time.Second) })
+
+	// notification dependencies
+	notificationRepo := notification.NewRepository(db)
+	apiURL := getenv("API_URL", "http://localhost:8080")
+	mailSecret := os.Getenv("MAIL_SECRET")
+	if mailSecret == "" {
+		mailSecret = jwtSecret
+	}
+	notificationService := notification.NewService(notificationRepo, newMailer(), notification.EmailConfig{
+		AppURL: getenv("APP_URL", "http://localhost:5173"),
+		APIURL: apiURL,
+		Secret: []byte(mailSecret),
+	})
+	notificationHandler := notification.NewHandler(notificationService)
+
+	// event dependencies
+	eventRepo := event.NewRepository(db)
+	eventService := event.NewService(eventRepo, event.NewHub(), bus, projectMemberService)
+	eventHandler := event.NewHandler(eventService)
+	if err := eventService.Listen(ctx); err != nil {
+		log.Fatalf("Can't listen for events: %v", err)
+	}
+	relay := event.NewRelay(eventRepo, map[string]event.Consumer{
+		"realtime":      eventService,
+		"webhooks":      webhookService,
+		"notifications": notificationService,
+	})
+	background(func() { relay.RunRelay(ctx, time.Second) })
+
 	// Ticket dependencies
 	ticketRepo := ticket.NewRepository(db)
-	ticketService := ticket.NewService(ticketRepo, projectService)
+	ticketService := ticket.NewService(ticketRepo, projectService, issueTypeService)
 	ticketHandler := ticket.NewHandler(ticketService)
 
+	// sprint dependencies
+	sprintRepo := sprint.NewRepository(db)
+	sprintService := sprint.NewService(sprintRepo, projectService)
+	sprintHandler := sprint.NewHandler(sprintService)
+
+	// worklog dependencies
+	worklogRepo := worklog.NewRepository(db)
+	worklogService := worklog.NewService(worklogRepo, ticketService)
+	worklogHandler := worklog.NewHandler(worklogService)
+
+	// report dependencies
+	reportRepo := report.NewRepository(db)
+	reportService := report.NewService(reportRepo, projectService)
+	reportHandler := report.NewHandler(reportService)
+
+	// sla dependencies
+	slaRepo := sla.NewRepository(db)
+	slaService := sla.NewService(slaRepo, projectService, ticketService)
+	slaHandler := sla.NewHandler(slaService)
+
+	// version dependencies
+	versionRepo := version.NewRepository(db)
+	versionService := version.NewService(versionRepo, projectService, issueTypeService)
+	versionHandler := version.NewHandler(versionService)
+
+	// comment dependencies
+	commentRepo := comment.NewRepository(db)
+	commentService := comment.NewService(commentRepo, ticketService)
+	commentHandler := comment.NewHandler(commentService)
+
+	// attachment dependencies
+	maxUpload, err := strconv.ParseInt(getenv("ATTACHMENT_MAX_BYTES", strconv.Itoa(attachment.DefaultMaxSize)), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid ATTACHMENT_MAX_BYTES: %v", err)
+	}
+	attachmentRepo := attachment.NewRepository(db)
+	attachmentService := attachment.NewService(attachmentRepo, ticketService, projectService, newStorage(), attachment.Config{
+		MaxSize: maxUpload,
+		APIURL:  apiURL,
+		Secret:  []byte(jwtSecret),
+	})
+	attachmentHandler := attachment.NewHandler(attachmentService)
+	background(func() { attachmentService.RunThumbnailer(ctx, 5*time.Second) })
+
+	// search dependencies
+	searchRepo := search.NewRepository(db)
+	searchService := search.NewService(searchRepo)
+	searchHandler := search.NewHandler(searchService)
+
+	// filter dependencies
+	filterRepo := filter.NewRepository(db)
+	filterService := filter.NewService(filterRepo, projectService)
+	filterHandler := filter.NewHandler(filterService)
+
+	// background jobs dependencies
+	jobRepo := job.NewRepository(db)
+	jobService := job.NewService(jobRepo)
+	jobHandler := job.NewHandler(jobService)
+	if getenv("RUN_JOBS", "true") == "true" {
+		concurrency, err := strconv.Atoi(getenv("JOB_CONCURRENCY", "4"))
+		if err != nil {
+			log.Fatalf("Invalid JOB_CONCURRENCY: %v", err)
+		}
+		runner := job.NewRunner(jobRepo, concurrency)
+		job.Handle(runner, "sla.sweep", func(ctx context.Context, _ struct{}) error {
+			_, err := slaService.SweepBreaches(ctx)
+			return err
+		})
+		job.Handle(runner, "notifications.send_emails", func(ctx context.Context, _ struct{}) error {
+			_, err := notificationService.SendEmails(ctx)
+			return err
+		})
+		job.Handle(runner, "events.prune", func(ctx context.Context, _ struct{}) error {
+			_, err := eventService.Prune(ctx, 24*time.Hour)
+			return err
+		})
+		job.Handle(runner, "attachments.cleanup", func(ctx context.Context, _ struct{}) error {
+			_, err := attachmentService.CleanupDeleted(ctx)
+			return err
+		})
+		job.Handle(runner, "jobs.prune", func(ctx context.Context, _ struct{}) error {
+			_, err := jobService.Prune(ctx, 7*24*time.Hour)
+			return err
+		})
+		schedules := []struct{ name, spec string }{
+			{"sla.sweep", "@every 1m"},
+			{"notifications.send_emails", "@every 1m"},
+			{"events.prune", "@hourly"},
+			{"attachments.cleanup", "@every 1m"},
+			{"jobs.prune", "@daily"},
+		}
+		for _, sc := range schedules {
+			if err := runner.Schedule(sc.name, sc.spec, sc.name, struct{}{}); err != nil {
+				log.Fatalf("Invalid schedule %s: %v", sc.name, err)
+			}
+		}
+		background(func() {
+			// Run fails only while registering schedules, running without jobs is not an option
+			if err := runner.Run(ctx); err != nil && ctx.Err() == nil {
+				log.Fatalf("Job runner failed to start: %v", err)
+			}
+		})
+	}
+
 	// Dependency injection
 	server := &ApiServer{
-		db:             db,
-		userHandler:    userHandler,
-		projectHandler: projectHandler,
-		ticketHandler:  ticketHandler,
+		db:                  db,
+		userHandler:         userHandler,
+		projectHandler:      projectHandler,
+		ticketHandler:       ticketHandler,
+		issueTypeHandler:    issueTypeHandler,
+		sprintHandler:       sprintHandler,
+		worklogHandler:      worklogHandler,
+		reportHandler:       reportHandler,
+		slaHandler:          slaHandler,
+		versionHandler:      versionHandler,
+		commentHandler:      commentHandler,
+		attachmentHandler:   attachmentHandler,
+		searchHandler:       searchHandler,
+		filterHandler:       filterHandler,
+		eventHandler:        eventHandler,
+		webhookHandler:      webhookHandler,
+		notificationHandler: notificationHandler,
+		jobHandler:          jobHandler,
 	}
 
 	// New Echo
@@ -84,8 +296,9 @@ func main() {
 
 	// CORS
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
-		AllowOrigins: []string{"http://localhost:5173"},
-		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization},
+		AllowOrigins:  []string{"http://localhost:5173"},
+		AllowHeaders:  []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization},
+		ExposeHeaders: []string{"X-Total-Count", "Link"},
 	}))
 
 	// Routes
@@ -95,6 +308,14 @@ func main() {
 
 	e.POST("/login", server.userHandler.Login)
 
+	// unsubscribe links from emails work without login
+	e.GET("/unsubscribe", server.notificationHandler.Unsubscribe)
+	e.POST("/unsubscribe", server.notificationHandler.Unsubscribe)
+
+	// signed download links work without login
+	e.GET("/attachments/:id/download", server.attachmentHandler.Download)
+	e.GET("/attachments/:id/thumbnails/:size", server.attachmentHandler.Thumbnail)
+
 	// protected routes
 	api := e.Group("/api")
 
@@ -108,16 +329,124 @@ func main() {
 	api.PATCH("/projects/:id", server.projectHandler.Update)
 	api.DELETE("/projects/:id", server.projectHandler.Delete)
 	api.POST("/projects/:id/members", server.projectHandler.AddMember)
+	api.GET("/projects/:id/issue-types", server.issueTypeHandler.Get)
+	api.PUT("/projects/:id/issue-types", server.issueTypeHandler.Update)
 	api.POST("/projects/:projectID/tickets", server.ticketHandler.Create)
 	api.GET("/projects/:projectID/tickets", server.ticketHandler.List)
+	api.GET("/projects/:projectID/tickets/overdue", server.ticketHandler.Overdue)
+	api.GET("/me/tickets", server.ticketHandler.MyTickets)
 	api.GET("/tickets/:id", server.ticketHandler.Get)
 	api.PATCH("/tickets/:id", server.ticketHandler.Update)
 	api.DELETE("/tickets/:id", server.ticketHandler.Delete)
+	api.GET("/tickets/:id/children", server.ticketHandler.Children)
+	api.GET("/tickets/:id/ancestors", server.ticketHandler.Ancestors)
+	api.GET("/tickets/:id/tree", server.ticketHandler.Tree)
+	api.GET("/tickets/:id/history", server.ticketHandler.History)
+	api.GET("/tickets/:id/watchers", server.ticketHandler.Watchers)
+	api.POST("/tickets/:id/watch", server.ticketHandler.Watch)
+	api.DELETE("/tickets/:id/watch", server.ticketHandler.Unwatch)
 	api.GET("/projects/:projectID/graph", server.ticketHandler.GetGraph)
 	api.POST("/tickets/:id/links", server.ticketHandler.AddLink)
 	api.DELETE("/links/:linkID", server.ticketHandler.RemoveLink)
+	api.POST("/projects/:id/sprints", server.sprintHandler.Create)
+	api.GET("/projects/:id/sprints", server.sprintHandler.List)
+	api.GET("/projects/:id/backlog", server.sprintHandler.Backlog)
+	api.POST("/projects/:id/backlog", server.sprintHandler.MoveToBacklog)
+	api.GET("/projects/:id/board", server.sprintHandler.Board)
+	api.GET("/sprints/:id", server.sprintHandler.Get)
+	api.PATCH("/sprints/:id", server.sprintHandler.Update)
+	api.DELETE("/sprints/:id", server.sprintHandler.Delete)
+	api.POST("/sprints/:id/tickets", server.sprintHandler.AddTickets)
+	api.POST("/sprints/:id/start", server.sprintHandler.Start)
+	api.POST("/sprints/:id/complete", server.sprintHandler.Complete)
+	api.POST("/tickets/:id/worklogs", server.worklogHandler.Create)
+	api.GET("/tickets/:id/worklogs", server.worklogHandler.List)
+	api.DELETE("/worklogs/:id", server.worklogHandler.Delete)
+	api.GET("/me/worklogs", server.worklogHandler.Timesheet)
+	api.GET("/projects/:id/reports/burndown", server.reportHandler.Burndown)
+	api.GET("/projects/:id/reports/burnup", server.reportHandler.Burnup)
+	api.GET("/projects/:id/reports/velocity", server.reportHandler.Velocity)
+	api.GET("/projects/:id/reports/scope-change", server.reportHandler.ScopeChange)
+	api.GET("/projects/:id/reports/lead-time", server.reportHandler.LeadTime)
+	api.GET("/projects/:id/reports/cycle-time", server.reportHandler.CycleTime)
+	api.GET("/projects/:id/reports/throughput", server.reportHandler.Throughput)
+	api.GET("/projects/:id/reports/aging-wip", server.reportHandler.AgingWIP)
+	api.GET("/projects/:id/reports/cfd", server.reportHandler.CumulativeFlow)
+	api.GET("/projects/:id/reports/sla", server.slaHandler.Report)
+	api.GET("/projects/:id/sla-policies", server.slaHandler.GetPolicies)
+	api.PUT("/projects/:id/sla-policies", server.slaHandler.UpdatePolicies)
+	api.GET("/tickets/:id/sla", server.slaHandler.TicketSLA)
+	api.POST("/projects/:id/versions", server.versionHandler.Create)
+	api.GET("/projects/:id/versions", server.versionHandler.List)
+	api.GET("/versions/:id", server.versionHandler.Get)
+	api.PATCH("/versions/:id", server.versionHandler.Update)
+	api.DELETE("/versions/:id", server.versionHandler.Delete)
+	api.GET("/versions/:id/tickets", server.versionHandler.Tickets)
+	api.POST("/versions/:id/tickets", server.versionHandler.AddTickets)
+	api.DELETE("/versions/:id/tickets", server.versionHandler.RemoveTickets)
+	api.GET("/versions/:id/progress", server.versionHandler.Progress)
+	api.POST("/versions/:id/release", server.versionHandler.Release)
+	api.GET("/versions/:id/release-notes", server.versionHandler.ReleaseNotes)
+	api.POST("/tickets/:id/comments", server.commentHandler.Create)
+	api.GET("/tickets/:id/comments", server.commentHandler.List)
+	api.PATCH("/comments/:id", server.commentHandler.Update)
+	api.DELETE("/comments/:id", server.commentHandler.Delete)
+
+	// attachments
+	api.POST("/tickets/:id/attachments", server.attachmentHandler.Upload)
+	api.GET("/tickets/:id/attachments", server.attachmentHandler.List)
+	api.GET("/attachments/:id", server.attachmentHandler.Get)
+	api.DELETE("/attachments/:id", server.attachmentHandler.Delete)
+	api.GET("/projects/:projectID/covers", server.attachmentHandler.Covers)
+	api.GET("/search", server.searchHandler.Search)
+	api.GET("/tickets/query", server.filterHandler.Query)
+	api.POST("/filters", server.filterHandler.Create)
+	api.GET("/filters", server.filterHandler.List)
+	api.GET("/filters/:id", server.filterHandler.Get)
+	api.PATCH("/filters/:id", server.filterHandler.Update)
+	api.DELETE("/filters/:id", server.filterHandler.Delete)
+	api.POST("/filters/:id/favourite", server.filterHandler.Favourite)
+	api.DELETE("/filters/:id/favourite", server.filterHandler.Unfavourite)
+	api.GET("/filters/:id/tickets", server.filterHandler.Tickets)
+	api.GET("/projects/:projectID/events", server.eventHandler.Stream)
+	api.POST("/projects/:projectID/webhooks", server.webhookHandler.Create)
+	api.GET("/projects/:projectID/webhooks", server.webhookHandler.List)
+	api.GET("/webhooks/:id", server.webhookHandler.Get)
+	api.PATCH("/webhooks/:id", server.webhookHandler.Update)
+	api.DELETE("/webhooks/:id", server.webhookHandler.Delete)
+	api.GET("/webhooks/:id/deliveries", server.webhookHandler.Deliveries)
+	api.GET("/webhook-deliveries/:id", server.webhookHandler.Delivery)
+	api.POST("/webhook-deliveries/:id/redeliver", server.webhookHandler.Redeliver)
+	api.GET("/me/notifications", server.notificationHandler.List)
+	api.GET("/me/notifications/unread-count", server.notificationHandler.UnreadCount)
+	api.POST("/me/notifications/:id/read", server.notificationHandler.MarkRead)
+	api.POST("/me/notifications/read-all", server.notificationHandler.MarkAllRead)
+	api.GET("/me/notification-preferences", server.notificationHandler.GetPreferences)
+	api.PUT("/me/notification-preferences", server.notificationHandler.UpdatePreferences)
+	api.GET("/me/email-settings", server.notificationHandler.GetEmailSettings)
+	api.PUT("/me/email-settings", server.notificationHandler.UpdateEmailSettings)
+
+	// admin routes
+	admin := api.Group("/admin", authMiddleware.RequireRole("admin"))
+	admin.GET("/jobs", server.jobHandler.List)
+	admin.GET("/jobs/:id", server.jobHandler.Get)
+	admin.POST("/jobs/:id/retry", server.jobHandler.Retry)
 
-	e.Logger.Fatal(e.Start(":8080"))
+	go func() {
+		if err := e.Start(":8080"); err != nil && err != http.ErrServerClosed {
+			e.Logger.Fatal(err)
+		}
+	}()
+
+	// on shutdown stop taking requests, then let in-flight jobs and background loops finish
+	<-ctx.Done()
+	log.Println("Shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := e.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Server shutdown failed: %v", err)
+	}
+	workers.Wait()
 }
 
 // Handler
@@ -149,3 +478,53 @@ func (s *ApiServer) getProfile(c echo.Context) error {
 		"user_id": userID,
 	})
 }
+
+// getenv returns environment variable or fallback if it is not set
+func getenv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// newMailer picks mailer by MAIL_DRIVER, file drop is default for local development
+func newMailer() mail.Mailer {
+	from := getenv("MAIL_FROM", "PMS <noreply@localhost>")
+	switch driver := getenv("MAIL_DRIVER", "file"); driver {
+	case "smtp":
+		port, err := strconv.Atoi(getenv("SMTP_PORT", "587"))
+		if err != nil {
+			log.Fatalf("Invalid SMTP_PORT: %v", err)
+		}
+		return mail.NewSMTP(os.Getenv("SMTP_HOST"), port, os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), from)
+	case "file":
+		return mail.NewFileDrop(getenv("MAIL_DIR", "mail"), from)
+	default:
+		log.Fatalf("Unknown MAIL_DRIVER %q", driver)
+		return nil
+	}
+}
+
+// newStorage picks attachment storage by STORAGE_DRIVER, local directory is default
+func newStorage() storage.Storage {
+	switch driver := getenv("STORAGE_DRIVER", "local"); driver {
+	case "s3":
+		s3, err := storage.NewS3(storage.S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+			PathStyle: os.Getenv("S3_PATH_STYLE") == "true",
+		})
+		if err != nil {
+			log.Fatalf("Can't configure S3 storage: %v", err)
+		}
+		return s3
+	case "local":
+		return storage.NewLocal(getenv("STORAGE_DIR", "uploads"))
+	default:
+		log.Fatalf("Unknown STORAGE_DRIVER %q", driver)
+		return nil
+	}
+}
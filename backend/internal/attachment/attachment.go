@@ -0,0 +1,70 @@
+package attachment
+
+import (
+	"errors"
+	"time"
+)
+
+// Errors handlers map to HTTP statuses
+var (
+	ErrNotFound         = errors.New("attachment not found")
+	ErrTooLarge         = errors.New("file is too large")
+	ErrTypeNotAllowed   = errors.New("file type is not allowed")
+	ErrInvalidSignature = errors.New("download link is invalid or expired")
+	ErrNotUploader      = errors.New("only uploader can delete attachment")
+	ErrNoThumbnail      = errors.New("thumbnail not found")
+)
+
+// DefaultMaxSize is upload limit when config doesn't set one, 10 MB
+const DefaultMaxSize = 10 << 20
+
+// DefaultAllowedTypes are file types accepted by default: images, PDFs, text logs and archives
+var DefaultAllowedTypes = []string{
+	"image/png",
+	"image/jpeg",
+	"image/gif",
+	"image/webp",
+	"application/pdf",
+	"text/plain",
+	"application/zip",
+	"application/x-gzip",
+}
+
+type Attachment struct {
+	ID          int64     `db:"id" json:"id"`
+	TicketID    int64     `db:"ticket_id" json:"ticket_id"`
+	UploaderID  *int64    `db:"uploader_id" json:"uploader_id"`
+	Filename    string    `db:"filename" json:"filename"`
+	ContentType string    `db:"content_type" json:"content_type"`
+	Size        int64     `db:"size_bytes" json:"size_bytes"`
+	StorageKey  string    `db:"storage_key" json:"-"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+
+	// ThumbnailStatus is nil for files without thumbnails
+	ThumbnailStatus        *string   `db:"thumbnail_status" json:"thumbnail_status"`
+	ThumbnailAttempts      int       `db:"thumbnail_attempts" json:"-"`
+	ThumbnailNextAttemptAt time.Time `db:"thumbnail_next_attempt_at" json:"-"`
+
+	// URL is signed download link valid for limited time
+	URL string `db:"-" json:"url"`
+	// Thumbnails are signed links by size, set when thumbnails are ready
+	Thumbnails map[string]string `db:"-" json:"thumbnails,omitempty"`
+}
+
+// Thumbnail is scaled down copy of image attachment
+type Thumbnail struct {
+	AttachmentID int64  `db:"attachment_id"`
+	Size         string `db:"size"`
+	StorageKey   string `db:"storage_key"`
+	ContentType  string `db:"content_type"`
+	Width        int    `db:"width"`
+	Height       int    `db:"height"`
+}
+
+// Cover is thumbnail of latest image attached to ticket, board shows it on card
+type Cover struct {
+	TicketID     int64  `db:"ticket_id" json:"ticket_id"`
+	AttachmentID int64  `db:"id" json:"attachment_id"`
+	StorageKey   string `db:"storage_key" json:"-"`
+	URL          string `db:"-" json:"url"`
+}
@@ -0,0 +1,31 @@
+package attachment
+
+import (
+	"context"
+	"log"
+)
+
+// cleanupBatch is max number of files removed in one run
+const cleanupBatch = 100
+
+// CleanupDeleted removes files of attachments deleted directly or with their tickets and projects,
+// returns number of removed files. Failed files stay queued for next run
+func (s *Service) CleanupDeleted(ctx context.Context) (int, error) {
+	keys, err := s.repo.ListDeletions(ctx, cleanupBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, key := range keys {
+		if err := s.store.Delete(ctx, key); err != nil {
+			log.Printf("Can't remove file %s: %v", key, err)
+			continue
+		}
+		if err := s.repo.ForgetDeletion(ctx, key); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
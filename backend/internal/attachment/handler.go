@@ -0,0 +1,177 @@
+package attachment
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/antonovs105/project-management-system-go/internal/project"
+	"github.com/antonovs105/project-management-system-go/internal/storage"
+	"github.com/antonovs105/project-management-system-go/internal/ticket"
+	"github.com/labstack/echo/v4"
+)
+
+// multipartOverhead is room for multipart headers on top of file size limit
+const multipartOverhead = 1 << 20
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+func errorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, ErrNotFound), errors.Is(err, ErrNoThumbnail), errors.Is(err, ticket.ErrNotFound), errors.Is(err, storage.ErrNotFound),
+		errors.Is(err, project.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, ErrTypeNotAllowed):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, ErrNotUploader), errors.Is(err, ErrInvalidSignature):
+		return http.StatusForbidden
+	default:
+		return fallback
+	}
+}
+
+// Upload handler for POST /api/tickets/:id/attachments, file goes in multipart field "file"
+func (h *Handler) Upload(c echo.Context) error {
+	ticketID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ticket ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	req := c.Request()
+	req.Body = http.MaxBytesReader(c.Response(), req.Body, h.service.MaxSize()+multipartOverhead)
+	fh, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{"error": ErrTooLarge.Error()})
+		}
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "file is required"})
+	}
+	f, err := fh.Open()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	defer f.Close()
+
+	a, err := h.service.Upload(req.Context(), ticketID, userID, fh.Filename, f, fh.Size)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusBadRequest), map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusCreated, a)
+}
+
+// List handler for GET /api/tickets/:id/attachments
+func (h *Handler) List(c echo.Context) error {
+	ticketID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ticket ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	attachments, err := h.service.ListAttachments(c.Request().Context(), ticketID, userID)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, attachments)
+}
+
+// Get handler for GET /api/attachments/:id
+func (h *Handler) Get(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid attachment ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	a, err := h.service.GetAttachment(c.Request().Context(), id, userID)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, a)
+}
+
+// Delete handler for DELETE /api/attachments/:id
+func (h *Handler) Delete(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid attachment ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	if err := h.service.DeleteAttachment(c.Request().Context(), id, userID); err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Download handler for GET /attachments/:id/download, signed link replaces login
+func (h *Handler) Download(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid attachment ID"})
+	}
+	expires, err := strconv.ParseInt(c.QueryParam("expires"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusForbidden, map[string]string{"error": ErrInvalidSignature.Error()})
+	}
+
+	a, body, err := h.service.Open(c.Request().Context(), id, expires, c.QueryParam("signature"))
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+	defer body.Close()
+
+	header := c.Response().Header()
+	header.Set("Content-Disposition", storage.ContentDisposition(a.Filename))
+	header.Set("Content-Length", strconv.FormatInt(a.Size, 10))
+	header.Set("X-Content-Type-Options", "nosniff")
+	header.Set("Cache-Control", "private, max-age=300")
+	return c.Stream(http.StatusOK, a.ContentType, body)
+}
+
+// Thumbnail handler for GET /attachments/:id/thumbnails/:size, signed link replaces login
+func (h *Handler) Thumbnail(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid attachment ID"})
+	}
+	expires, err := strconv.ParseInt(c.QueryParam("expires"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusForbidden, map[string]string{"error": ErrInvalidSignature.Error()})
+	}
+
+	t, body, err := h.service.OpenThumbnail(c.Request().Context(), id, c.Param("size"), expires, c.QueryParam("signature"))
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+	defer body.Close()
+
+	header := c.Response().Header()
+	header.Set("X-Content-Type-Options", "nosniff")
+	header.Set("Cache-Control", "private, max-age=300")
+	return c.Stream(http.StatusOK, t.ContentType, body)
+}
+
+// Covers handler for GET /api/projects/:projectID/covers
+func (h *Handler) Covers(c echo.Context) error {
+	projectID, err := strconv.ParseInt(c.Param("projectID"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid project ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	covers, err := h.service.ListCovers(c.Request().Context(), projectID, userID)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, covers)
+}
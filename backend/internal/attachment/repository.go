@@ -0,0 +1,202 @@
+package attachment
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type Repository interface {
+	Create(ctx context.Context, a *Attachment) error
+	GetByID(ctx context.Context, id int64) (*Attachment, error)
+	ListByTicketID(ctx context.Context, ticketID int64) ([]Attachment, error)
+	Delete(ctx context.Context, id int64) error
+	ListDeletions(ctx context.Context, limit int) ([]string, error)
+	ForgetDeletion(ctx context.Context, key string) error
+	ClaimThumbnailJobs(ctx context.Context, limit int, lease time.Duration) ([]Attachment, error)
+	SaveThumbnails(ctx context.Context, attachmentID int64, thumbnails []Thumbnail) error
+	MarkThumbnailsReady(ctx context.Context, attachmentID int64, keys []string) error
+	SetThumbnailStatus(ctx context.Context, attachmentID int64, status string) error
+	GetThumbnail(ctx context.Context, attachmentID int64, size string) (*Thumbnail, error)
+	ListCovers(ctx context.Context, projectID int64) ([]Cover, error)
+}
+
+type PgRepository struct {
+	db *sqlx.DB
+}
+
+func NewRepository(db *sqlx.DB) Repository {
+	return &PgRepository{db: db}
+}
+
+// Create saves metadata of stored file
+func (r *PgRepository) Create(ctx context.Context, a *Attachment) error {
+	query := `
+		INSERT INTO attachments (ticket_id, uploader_id, filename, content_type, size_bytes, storage_key, thumbnail_status)
+		VALUES (:ticket_id, :uploader_id, :filename, :content_type, :size_bytes, :storage_key, :thumbnail_status)
+		RETURNING id, created_at`
+
+	rows, err := r.db.NamedQueryContext(ctx, query, a)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return errors.New("attachment creation failed: no returning row")
+	}
+	return rows.Scan(&a.ID, &a.CreatedAt)
+}
+
+func (r *PgRepository) GetByID(ctx context.Context, id int64) (*Attachment, error) {
+	var a Attachment
+	if err := r.db.GetContext(ctx, &a, `SELECT * FROM attachments WHERE id = $1`, id); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// ListByTicketID returns attachments of ticket, oldest first
+func (r *PgRepository) ListByTicketID(ctx context.Context, ticketID int64) ([]Attachment, error) {
+	var attachments []Attachment
+	query := `SELECT * FROM attachments WHERE ticket_id = $1 ORDER BY id`
+	if err := r.db.SelectContext(ctx, &attachments, query, ticketID); err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// Delete removes attachment, trigger queues its file for cleanup
+func (r *PgRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM attachments WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListDeletions returns storage keys of deleted attachments, oldest first
+func (r *PgRepository) ListDeletions(ctx context.Context, limit int) ([]string, error) {
+	var keys []string
+	query := `SELECT storage_key FROM attachment_deletions ORDER BY deleted_at LIMIT $1`
+	if err := r.db.SelectContext(ctx, &keys, query, limit); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// ForgetDeletion drops key after its file is removed from storage
+func (r *PgRepository) ForgetDeletion(ctx context.Context, key string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM attachment_deletions WHERE storage_key = $1`, key)
+	return err
+}
+
+// ClaimThumbnailJobs returns attachments waiting for thumbnails and hides them from other workers for lease
+func (r *PgRepository) ClaimThumbnailJobs(ctx context.Context, limit int, lease time.Duration) ([]Attachment, error) {
+	var attachments []Attachment
+	query := `
+		UPDATE attachments
+		SET thumbnail_next_attempt_at = now() + $2 * interval '1 millisecond',
+			thumbnail_attempts = thumbnail_attempts + 1
+		WHERE id IN (
+			SELECT id FROM attachments
+			WHERE thumbnail_status = 'pending' AND thumbnail_next_attempt_at <= now()
+			ORDER BY thumbnail_next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`
+	if err := r.db.SelectContext(ctx, &attachments, query, limit, lease.Milliseconds()); err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// SaveThumbnails records thumbnails of attachment before their files are written, so deleting
+// attachment meanwhile queues the files for cleanup. They are shown after MarkThumbnailsReady
+func (r *PgRepository) SaveThumbnails(ctx context.Context, attachmentID int64, thumbnails []Thumbnail) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO attachment_thumbnails (attachment_id, size, storage_key, content_type, width, height)
+		VALUES (:attachment_id, :size, :storage_key, :content_type, :width, :height)
+		ON CONFLICT (attachment_id, size) DO UPDATE
+		SET content_type = EXCLUDED.content_type, width = EXCLUDED.width, height = EXCLUDED.height`
+	for i := range thumbnails {
+		if _, err := tx.NamedExecContext(ctx, query, &thumbnails[i]); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// MarkThumbnailsReady shows written thumbnails. If attachment was deleted while files were written,
+// cleanup may have run before them, so keys are queued again and ErrNotFound is returned
+func (r *PgRepository) MarkThumbnailsReady(ctx context.Context, attachmentID int64, keys []string) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE attachments SET thumbnail_status = 'ready' WHERE id = $1`, attachmentID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected > 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO attachment_deletions (storage_key)
+		SELECT unnest($1::varchar[])
+		ON CONFLICT DO NOTHING`
+	if _, err := r.db.ExecContext(ctx, query, pq.Array(keys)); err != nil {
+		return err
+	}
+	return ErrNotFound
+}
+
+// SetThumbnailStatus changes thumbnail status of attachment
+func (r *PgRepository) SetThumbnailStatus(ctx context.Context, attachmentID int64, status string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE attachments SET thumbnail_status = $2 WHERE id = $1`, attachmentID, status)
+	return err
+}
+
+func (r *PgRepository) GetThumbnail(ctx context.Context, attachmentID int64, size string) (*Thumbnail, error) {
+	var t Thumbnail
+	query := `
+		SELECT t.* FROM attachment_thumbnails t
+		JOIN attachments a ON a.id = t.attachment_id AND a.thumbnail_status = 'ready'
+		WHERE t.attachment_id = $1 AND t.size = $2`
+	if err := r.db.GetContext(ctx, &t, query, attachmentID, size); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// ListCovers returns latest image attachment with ready thumbnails of every ticket in project
+func (r *PgRepository) ListCovers(ctx context.Context, projectID int64) ([]Cover, error) {
+	var covers []Cover
+	query := `
+		SELECT DISTINCT ON (a.ticket_id) a.ticket_id, a.id, a.storage_key
+		FROM attachments a
+		JOIN tickets t ON t.id = a.ticket_id
+		WHERE t.project_id = $1 AND a.thumbnail_status = 'ready'
+		ORDER BY a.ticket_id, a.id DESC`
+	if err := r.db.SelectContext(ctx, &covers, query, projectID); err != nil {
+		return nil, err
+	}
+	return covers, nil
+}
@@ -0,0 +1,119 @@
+package attachment
+
+import (
+	"context"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/project"
+	"github.com/antonovs105/project-management-system-go/internal/ticket"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRepository is a mock implementation of Repository
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Create(ctx context.Context, a *Attachment) error {
+	args := m.Called(ctx, a)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetByID(ctx context.Context, id int64) (*Attachment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Attachment), args.Error(1)
+}
+
+func (m *MockRepository) ListByTicketID(ctx context.Context, ticketID int64) ([]Attachment, error) {
+	args := m.Called(ctx, ticketID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Attachment), args.Error(1)
+}
+
+func (m *MockRepository) Delete(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ListDeletions(ctx context.Context, limit int) ([]string, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockRepository) ForgetDeletion(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ClaimThumbnailJobs(ctx context.Context, limit int, lease time.Duration) ([]Attachment, error) {
+	args := m.Called(ctx, limit, lease)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Attachment), args.Error(1)
+}
+
+func (m *MockRepository) SaveThumbnails(ctx context.Context, attachmentID int64, thumbnails []Thumbnail) error {
+	args := m.Called(ctx, attachmentID, thumbnails)
+	return args.Error(0)
+}
+
+func (m *MockRepository) MarkThumbnailsReady(ctx context.Context, attachmentID int64, keys []string) error {
+	args := m.Called(ctx, attachmentID, keys)
+	return args.Error(0)
+}
+
+func (m *MockRepository) SetThumbnailStatus(ctx context.Context, attachmentID int64, status string) error {
+	args := m.Called(ctx, attachmentID, status)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetThumbnail(ctx context.Context, attachmentID int64, size string) (*Thumbnail, error) {
+	args := m.Called(ctx, attachmentID, size)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Thumbnail), args.Error(1)
+}
+
+func (m *MockRepository) ListCovers(ctx context.Context, projectID int64) ([]Cover, error) {
+	args := m.Called(ctx, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Cover), args.Error(1)
+}
+
+// MockTicketGetter
+type MockTicketGetter struct {
+	mock.Mock
+}
+
+func (m *MockTicketGetter) GetTicketByID(ctx context.Context, ticketID, userID int64) (*ticket.Ticket, error) {
+	args := m.Called(ctx, ticketID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ticket.Ticket), args.Error(1)
+}
+
+// MockProjectChecker
+type MockProjectChecker struct {
+	mock.Mock
+}
+
+func (m *MockProjectChecker) GetProjectByID(ctx context.Context, projectID, userID int64) (*project.Project, error) {
+	args := m.Called(ctx, projectID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*project.Project), args.Error(1)
+}
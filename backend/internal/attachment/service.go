@@ -0,0 +1,306 @@
+package attachment
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/project"
+	"github.com/antonovs105/project-management-system-go/internal/storage"
+	"github.com/antonovs105/project-management-system-go/internal/ticket"
+)
+
+// maxFilenameLength matches filename column
+const maxFilenameLength = 255
+
+// TicketGetter interface
+type TicketGetter interface {
+	GetTicketByID(ctx context.Context, ticketID, userID int64) (*ticket.Ticket, error)
+}
+
+// ProjectChecker interface
+type ProjectChecker interface {
+	GetProjectByID(ctx context.Context, projectID, userID int64) (*project.Project, error)
+}
+
+// Config holds upload limits and download link settings
+type Config struct {
+	MaxSize      int64
+	AllowedTypes []string
+	// APIURL is backend address used in download links when storage can't presign them
+	APIURL string
+	// Secret signs download links
+	Secret []byte
+	// URLExpiry is how long download links work
+	URLExpiry time.Duration
+}
+
+type Service struct {
+	repo           Repository
+	ticketService  TicketGetter
+	projectService ProjectChecker
+	store          storage.Storage
+	cfg            Config
+	now            func() time.Time
+	// uploaded wakes thumbnail worker after image upload
+	uploaded chan struct{}
+}
+
+func NewService(repo Repository, ticketService TicketGetter, projectService ProjectChecker, store storage.Storage, cfg Config) *Service {
+	if cfg.MaxSize <= 0 {
+		cfg.MaxSize = DefaultMaxSize
+	}
+	if cfg.AllowedTypes == nil {
+		cfg.AllowedTypes = DefaultAllowedTypes
+	}
+	if cfg.URLExpiry <= 0 {
+		cfg.URLExpiry = 15 * time.Minute
+	}
+	return &Service{
+		repo:           repo,
+		ticketService:  ticketService,
+		projectService: projectService,
+		store:          store,
+		cfg:            cfg,
+		now:            time.Now,
+		uploaded:       make(chan struct{}, 1),
+	}
+}
+
+// MaxSize is biggest accepted file in bytes
+func (s *Service) MaxSize() int64 {
+	return s.cfg.MaxSize
+}
+
+// Upload stores file and attaches it to ticket. Type is detected from content, not taken from client
+func (s *Service) Upload(ctx context.Context, ticketID, userID int64, filename string, body io.Reader, size int64) (*Attachment, error) {
+	// check access
+	if _, err := s.ticketService.GetTicketByID(ctx, ticketID, userID); err != nil {
+		return nil, err
+	}
+
+	if size > s.cfg.MaxSize {
+		return nil, fmt.Errorf("%w: limit is %d bytes", ErrTooLarge, s.cfg.MaxSize)
+	}
+	if size <= 0 {
+		return nil, fmt.Errorf("file is empty")
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(body, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	head = head[:n]
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	if !s.allowed(contentType) {
+		return nil, fmt.Errorf("%w: %s", ErrTypeNotAllowed, contentType)
+	}
+
+	key, err := newKey(ticketID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.Put(ctx, key, io.MultiReader(bytes.NewReader(head), body), size, contentType); err != nil {
+		return nil, err
+	}
+
+	a := &Attachment{
+		TicketID:    ticketID,
+		UploaderID:  &userID,
+		Filename:    cleanFilename(filename),
+		ContentType: contentType,
+		Size:        size,
+		StorageKey:  key,
+	}
+	if thumbnailTypes[contentType] {
+		status := ThumbnailPending
+		a.ThumbnailStatus = &status
+	}
+	if err := s.repo.Create(ctx, a); err != nil {
+		if delErr := s.store.Delete(ctx, key); delErr != nil {
+			log.Printf("Can't remove orphan file %s: %v", key, delErr)
+		}
+		return nil, err
+	}
+	if a.ThumbnailStatus != nil {
+		select {
+		case s.uploaded <- struct{}{}:
+		default:
+		}
+	}
+
+	if err := s.sign(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// ListAttachments returns attachments of ticket with download links
+func (s *Service) ListAttachments(ctx context.Context, ticketID, userID int64) ([]Attachment, error) {
+	// check access
+	if _, err := s.ticketService.GetTicketByID(ctx, ticketID, userID); err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.repo.ListByTicketID(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	if attachments == nil {
+		attachments = []Attachment{}
+	}
+	for i := range attachments {
+		if err := s.sign(&attachments[i]); err != nil {
+			return nil, err
+		}
+	}
+	return attachments, nil
+}
+
+// GetAttachment returns attachment with fresh download link
+func (s *Service) GetAttachment(ctx context.Context, id, userID int64) (*Attachment, error) {
+	a, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	// check access
+	if _, err := s.ticketService.GetTicketByID(ctx, a.TicketID, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.sign(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// DeleteAttachment removes own attachment, file is removed from storage by cleanup
+func (s *Service) DeleteAttachment(ctx context.Context, id, userID int64) error {
+	a, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return ErrNotFound
+	}
+	// check access
+	if _, err := s.ticketService.GetTicketByID(ctx, a.TicketID, userID); err != nil {
+		return err
+	}
+	if a.UploaderID == nil || *a.UploaderID != userID {
+		return ErrNotUploader
+	}
+
+	return s.repo.Delete(ctx, id)
+}
+
+// Open checks signed download link and opens file of attachment
+func (s *Service) Open(ctx context.Context, id, expires int64, signature string) (*Attachment, io.ReadCloser, error) {
+	if !s.validSignature(fileResource(id), expires, signature) {
+		return nil, nil, ErrInvalidSignature
+	}
+
+	a, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, ErrNotFound
+	}
+	body, err := s.store.Get(ctx, a.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return a, body, nil
+}
+
+// sign sets download links of file and of its ready thumbnails
+func (s *Service) sign(a *Attachment) error {
+	url, err := s.link(a.StorageKey, fileResource(a.ID), a.Filename)
+	if err != nil {
+		return err
+	}
+	a.URL = url
+
+	if a.ThumbnailStatus == nil || *a.ThumbnailStatus != ThumbnailReady {
+		return nil
+	}
+	a.Thumbnails = make(map[string]string, len(ThumbnailSizes))
+	for size := range ThumbnailSizes {
+		url, err := s.link(thumbnailKey(a.StorageKey, size), thumbnailResource(a.ID, size), "")
+		if err != nil {
+			return err
+		}
+		a.Thumbnails[size] = url
+	}
+	return nil
+}
+
+// link makes time-limited link to stored object. Storage presigns it when it can,
+// otherwise link goes to backend resource path with signature
+func (s *Service) link(key, resource, filename string) (string, error) {
+	if presigner, ok := s.store.(storage.Presigner); ok {
+		return presigner.PresignGet(key, s.cfg.URLExpiry, filename)
+	}
+
+	expires := s.now().Add(s.cfg.URLExpiry).Unix()
+	return fmt.Sprintf("%s/%s?expires=%d&signature=%s", s.cfg.APIURL, resource, expires, s.signature(resource, expires)), nil
+}
+
+func (s *Service) validSignature(resource string, expires int64, signature string) bool {
+	return s.now().Unix() <= expires && hmac.Equal([]byte(signature), []byte(s.signature(resource, expires)))
+}
+
+func (s *Service) signature(resource string, expires int64) string {
+	mac := hmac.New(sha256.New, s.cfg.Secret)
+	mac.Write([]byte(resource + ":" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func fileResource(id int64) string {
+	return fmt.Sprintf("attachments/%d/download", id)
+}
+
+func (s *Service) allowed(contentType string) bool {
+	for _, t := range s.cfg.AllowedTypes {
+		if t == contentType {
+			return true
+		}
+	}
+	return false
+}
+
+// newKey makes random storage key, names from users never get into paths
+func newKey(ticketID int64) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("tickets/%d/%s", ticketID, hex.EncodeToString(b)), nil
+}
+
+// cleanFilename drops directories and control characters from uploaded name
+func cleanFilename(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == ".." || name == "/" {
+		return "file"
+	}
+	if runes := []rune(name); len(runes) > maxFilenameLength {
+		name = string(runes[:maxFilenameLength])
+	}
+	return name
+}
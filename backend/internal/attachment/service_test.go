@@ -0,0 +1,352 @@
+package attachment
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/storage"
+	"github.com/antonovs105/project-management-system-go/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func newTestService(t *testing.T) (*Service, *MockRepository, *MockTicketGetter, storage.Storage) {
+	mockRepo := new(MockRepository)
+	mockTicket := new(MockTicketGetter)
+	store := storage.NewLocal(t.TempDir())
+	service := NewService(mockRepo, mockTicket, new(MockProjectChecker), store, Config{
+		MaxSize: 1024,
+		APIURL:  "https://api.test",
+		Secret:  []byte("secret"),
+	})
+	service.now = func() time.Time { return time.Unix(1700000000, 0) }
+	return service, mockRepo, mockTicket, store
+}
+
+func TestService_Upload(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		service, mockRepo, mockTicket, store := newTestService(t)
+		mockTicket.On("GetTicketByID", ctx, int64(5), int64(1)).Return(&ticket.Ticket{ID: 5}, nil).Once()
+		mockRepo.On("Create", ctx, mock.MatchedBy(func(a *Attachment) bool {
+			return a.TicketID == 5 && *a.UploaderID == 1 && a.Filename == "shot.png" &&
+				a.ContentType == "image/png" && a.Size == int64(len(pngHeader)) && strings.HasPrefix(a.StorageKey, "tickets/5/") &&
+				*a.ThumbnailStatus == ThumbnailPending
+		})).Run(func(args mock.Arguments) { args.Get(1).(*Attachment).ID = 7 }).Return(nil).Once()
+
+		a, err := service.Upload(ctx, 5, 1, `C:\Users\bob\shot.png`, bytes.NewReader(pngHeader), int64(len(pngHeader)))
+
+		require.NoError(t, err)
+		r, err := store.Get(ctx, a.StorageKey)
+		require.NoError(t, err)
+		stored, _ := io.ReadAll(r)
+		r.Close()
+		assert.Equal(t, pngHeader, stored)
+		assert.True(t, strings.HasPrefix(a.URL, "https://api.test/attachments/7/download?expires=1700000900&signature="))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("TooLarge", func(t *testing.T) {
+		service, _, mockTicket, _ := newTestService(t)
+		mockTicket.On("GetTicketByID", ctx, int64(5), int64(1)).Return(&ticket.Ticket{ID: 5}, nil).Once()
+
+		_, err := service.Upload(ctx, 5, 1, "big.log", strings.NewReader("x"), 2048)
+
+		assert.ErrorIs(t, err, ErrTooLarge)
+	})
+
+	t.Run("TypeDetectedFromContent", func(t *testing.T) {
+		service, _, mockTicket, _ := newTestService(t)
+		mockTicket.On("GetTicketByID", ctx, int64(5), int64(1)).Return(&ticket.Ticket{ID: 5}, nil).Once()
+		page := "<html><script>alert(1)</script></html>"
+
+		_, err := service.Upload(ctx, 5, 1, "shot.png", strings.NewReader(page), int64(len(page)))
+
+		assert.ErrorIs(t, err, ErrTypeNotAllowed)
+	})
+
+	t.Run("FailedInsertRemovesFile", func(t *testing.T) {
+		service, mockRepo, mockTicket, store := newTestService(t)
+		mockTicket.On("GetTicketByID", ctx, int64(5), int64(1)).Return(&ticket.Ticket{ID: 5}, nil).Once()
+		var key string
+		mockRepo.On("Create", ctx, mock.Anything).Run(func(args mock.Arguments) {
+			key = args.Get(1).(*Attachment).StorageKey
+		}).Return(errors.New("db down")).Once()
+
+		_, err := service.Upload(ctx, 5, 1, "app.log", strings.NewReader("started\n"), 8)
+
+		assert.Error(t, err)
+		_, err = store.Get(ctx, key)
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+	})
+}
+
+func TestService_Open(t *testing.T) {
+	ctx := context.Background()
+	service, mockRepo, _, store := newTestService(t)
+	require.NoError(t, store.Put(ctx, "tickets/5/abc", strings.NewReader("log"), 3, "text/plain"))
+	a := &Attachment{ID: 7, TicketID: 5, StorageKey: "tickets/5/abc"}
+	require.NoError(t, service.sign(a))
+	link, _ := url.Parse(a.URL)
+	expires, _ := strconv.ParseInt(link.Query().Get("expires"), 10, 64)
+	signature := link.Query().Get("signature")
+
+	t.Run("Valid", func(t *testing.T) {
+		mockRepo.On("GetByID", ctx, int64(7)).Return(a, nil).Once()
+
+		_, body, err := service.Open(ctx, 7, expires, signature)
+
+		require.NoError(t, err)
+		data, _ := io.ReadAll(body)
+		body.Close()
+		assert.Equal(t, "log", string(data))
+	})
+
+	t.Run("OtherAttachment", func(t *testing.T) {
+		_, _, err := service.Open(ctx, 8, expires, signature)
+
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		service.now = func() time.Time { return time.Unix(expires+1, 0) }
+
+		_, _, err := service.Open(ctx, 7, expires, signature)
+
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+}
+
+func TestService_DeleteAttachment(t *testing.T) {
+	ctx := context.Background()
+	service, mockRepo, mockTicket, _ := newTestService(t)
+	uploader := int64(1)
+	a := &Attachment{ID: 7, TicketID: 5, UploaderID: &uploader}
+
+	t.Run("NotUploader", func(t *testing.T) {
+		mockRepo.On("GetByID", ctx, int64(7)).Return(a, nil).Once()
+		mockTicket.On("GetTicketByID", ctx, int64(5), int64(2)).Return(&ticket.Ticket{ID: 5}, nil).Once()
+
+		err := service.DeleteAttachment(ctx, 7, 2)
+
+		assert.ErrorIs(t, err, ErrNotUploader)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo.On("GetByID", ctx, int64(7)).Return(a, nil).Once()
+		mockTicket.On("GetTicketByID", ctx, int64(5), int64(1)).Return(&ticket.Ticket{ID: 5}, nil).Once()
+		mockRepo.On("Delete", ctx, int64(7)).Return(nil).Once()
+
+		err := service.DeleteAttachment(ctx, 7, 1)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestService_CleanupDeleted(t *testing.T) {
+	ctx := context.Background()
+	service, mockRepo, _, store := newTestService(t)
+	require.NoError(t, store.Put(ctx, "tickets/5/abc", strings.NewReader("log"), 3, "text/plain"))
+
+	mockRepo.On("ListDeletions", ctx, cleanupBatch).Return([]string{"tickets/5/abc", "tickets/5/gone"}, nil).Once()
+	mockRepo.On("ForgetDeletion", ctx, "tickets/5/abc").Return(nil).Once()
+	mockRepo.On("ForgetDeletion", ctx, "tickets/5/gone").Return(nil).Once()
+
+	removed, err := service.CleanupDeleted(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, removed)
+	_, err = store.Get(ctx, "tickets/5/abc")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCleanFilename(t *testing.T) {
+	assert.Equal(t, "report.pdf", cleanFilename("../../etc/report.pdf"))
+	assert.Equal(t, "a b.txt", cleanFilename(" a\x00 b.txt\n"))
+	assert.Equal(t, "file", cleanFilename(".."))
+}
+
+// testImage is w×h image with left half red and right half blue
+func testImage(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := color.RGBA{R: 255, A: 255}
+			if x >= w/2 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+// withOrientation puts EXIF segment with orientation tag right after JPEG start marker
+func withOrientation(jpegData []byte, orientation uint16) []byte {
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08\x00\x01\x01\x12\x00\x03\x00\x00\x00\x01\x00\x00\x00\x00\x00\x00\x00\x00")
+	binary.BigEndian.PutUint16(tiff[18:], orientation)
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+
+	out := []byte{0xFF, 0xD8, 0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(out[4:], uint16(len(segment)+2))
+	out = append(out, segment...)
+	return append(out, jpegData[2:]...)
+}
+
+func TestResize(t *testing.T) {
+	scaled := resize(testImage(100, 50), 10)
+
+	assert.Equal(t, image.Rect(0, 0, 10, 5), scaled.Bounds())
+	assert.Equal(t, color.RGBA{R: 255, A: 255}, scaled.RGBAAt(0, 0))
+	assert.Equal(t, color.RGBA{B: 255, A: 255}, scaled.RGBAAt(9, 4))
+
+	small := testImage(8, 4)
+	assert.Same(t, small, resize(small, 64))
+}
+
+func TestOrient(t *testing.T) {
+	// turning right puts left red half on top
+	turned := orient(testImage(4, 2), 6)
+
+	assert.Equal(t, image.Rect(0, 0, 2, 4), turned.Bounds())
+	assert.Equal(t, color.RGBA{R: 255, A: 255}, turned.RGBAAt(0, 0))
+	assert.Equal(t, color.RGBA{B: 255, A: 255}, turned.RGBAAt(1, 3))
+}
+
+func TestService_GenerateThumbnails(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		service, mockRepo, _, store := newTestService(t)
+		var buf bytes.Buffer
+		require.NoError(t, jpeg.Encode(&buf, testImage(2000, 1000), nil))
+		photo := withOrientation(buf.Bytes(), 6)
+		assert.Equal(t, 6, exifOrientation(photo))
+		require.NoError(t, store.Put(ctx, "tickets/5/abc", bytes.NewReader(photo), int64(len(photo)), "image/jpeg"))
+
+		pending := ThumbnailPending
+		mockRepo.On("ClaimThumbnailJobs", ctx, thumbnailBatch, thumbnailLease).Return([]Attachment{
+			{ID: 7, StorageKey: "tickets/5/abc", ContentType: "image/jpeg", ThumbnailStatus: &pending, ThumbnailAttempts: 1},
+		}, nil).Once()
+		var saved []Thumbnail
+		mockRepo.On("SaveThumbnails", ctx, int64(7), mock.Anything).Run(func(args mock.Arguments) {
+			saved = args.Get(2).([]Thumbnail)
+		}).Return(nil).Once()
+		var ready []string
+		mockRepo.On("MarkThumbnailsReady", ctx, int64(7), mock.Anything).Run(func(args mock.Arguments) {
+			ready = args.Get(2).([]string)
+		}).Return(nil).Once()
+
+		done, err := service.GenerateThumbnails(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, done)
+		require.Len(t, saved, len(ThumbnailSizes))
+		require.Len(t, ready, len(saved))
+		for _, th := range saved {
+			// photo is turned, so it is taller than wide
+			assert.Equal(t, ThumbnailSizes[th.Size], th.Height)
+			assert.Equal(t, ThumbnailSizes[th.Size]/2, th.Width)
+			assert.Equal(t, "image/jpeg", th.ContentType)
+
+			r, err := store.Get(ctx, th.StorageKey)
+			require.NoError(t, err)
+			data, _ := io.ReadAll(r)
+			r.Close()
+			assert.NotContains(t, string(data), "Exif")
+			cfg, err := jpeg.DecodeConfig(bytes.NewReader(data))
+			require.NoError(t, err)
+			assert.Equal(t, th.Width, cfg.Width)
+		}
+	})
+
+	t.Run("BrokenImageFails", func(t *testing.T) {
+		service, mockRepo, _, store := newTestService(t)
+		require.NoError(t, store.Put(ctx, "tickets/5/bad", strings.NewReader("\x89PNG broken"), 11, "image/png"))
+
+		mockRepo.On("ClaimThumbnailJobs", ctx, thumbnailBatch, thumbnailLease).Return([]Attachment{
+			{ID: 8, StorageKey: "tickets/5/bad", ContentType: "image/png", ThumbnailAttempts: 1},
+		}, nil).Once()
+		mockRepo.On("SetThumbnailStatus", ctx, int64(8), ThumbnailFailed).Return(nil).Once()
+
+		done, err := service.GenerateThumbnails(ctx)
+
+		assert.NoError(t, err)
+		assert.Zero(t, done)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("DeletedWhileWriting", func(t *testing.T) {
+		service, mockRepo, _, store := newTestService(t)
+		var buf bytes.Buffer
+		require.NoError(t, png.Encode(&buf, testImage(400, 300)))
+		require.NoError(t, store.Put(ctx, "tickets/5/gone", &buf, int64(buf.Len()), "image/png"))
+
+		mockRepo.On("ClaimThumbnailJobs", ctx, thumbnailBatch, thumbnailLease).Return([]Attachment{
+			{ID: 10, StorageKey: "tickets/5/gone", ContentType: "image/png", ThumbnailAttempts: 1},
+		}, nil).Once()
+		mockRepo.On("SaveThumbnails", ctx, int64(10), mock.Anything).Return(nil).Once()
+		// repository queues written files for cleanup
+		mockRepo.On("MarkThumbnailsReady", ctx, int64(10), mock.Anything).Return(ErrNotFound).Once()
+
+		done, err := service.GenerateThumbnails(ctx)
+
+		assert.NoError(t, err)
+		assert.Zero(t, done)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("MissingFileRetried", func(t *testing.T) {
+		service, mockRepo, _, _ := newTestService(t)
+		mockRepo.On("ClaimThumbnailJobs", ctx, thumbnailBatch, thumbnailLease).Return([]Attachment{
+			{ID: 9, StorageKey: "tickets/5/later", ContentType: "image/png", ThumbnailAttempts: 1},
+		}, nil).Once()
+
+		_, err := service.GenerateThumbnails(ctx)
+
+		assert.NoError(t, err)
+		mockRepo.AssertNotCalled(t, "SetThumbnailStatus", ctx, int64(9), ThumbnailFailed)
+	})
+}
+
+func TestService_ThumbnailLinks(t *testing.T) {
+	ctx := context.Background()
+	service, mockRepo, _, _ := newTestService(t)
+	ready := ThumbnailReady
+	a := &Attachment{ID: 7, StorageKey: "tickets/5/abc", ThumbnailStatus: &ready}
+	require.NoError(t, service.sign(a))
+	require.Len(t, a.Thumbnails, len(ThumbnailSizes))
+
+	link, _ := url.Parse(a.Thumbnails["small"])
+	assert.Equal(t, "/attachments/7/thumbnails/small", link.Path)
+	expires, _ := strconv.ParseInt(link.Query().Get("expires"), 10, 64)
+	signature := link.Query().Get("signature")
+
+	// signature of one size doesn't open other sizes or original
+	_, _, err := service.OpenThumbnail(ctx, 7, "large", expires, signature)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+	_, _, err = service.Open(ctx, 7, expires, signature)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	mockRepo.On("GetThumbnail", ctx, int64(7), "small").Return(nil, errors.New("no rows")).Once()
+	_, _, err = service.OpenThumbnail(ctx, 7, "small", expires, signature)
+	assert.ErrorIs(t, err, ErrNoThumbnail)
+}
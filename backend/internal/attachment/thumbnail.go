@@ -0,0 +1,180 @@
+package attachment
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+)
+
+// Thumbnail statuses
+const (
+	ThumbnailPending = "pending"
+	ThumbnailReady   = "ready"
+	ThumbnailFailed  = "failed"
+)
+
+// ThumbnailSizes are longest sides of thumbnails by size name
+var ThumbnailSizes = map[string]int{
+	"small":  64,
+	"medium": 256,
+	"large":  1024,
+}
+
+// CoverSize is thumbnail size shown on board cards
+const CoverSize = "medium"
+
+const (
+	thumbnailBatch = 10
+	// thumbnailLease is how long claimed job is hidden from other workers, failed jobs are retried after it
+	thumbnailLease       = time.Minute
+	maxThumbnailAttempts = 3
+)
+
+// thumbnailKey is storage key of thumbnail next to original file
+func thumbnailKey(storageKey, size string) string {
+	return storageKey + "." + size
+}
+
+// RunThumbnailer makes thumbnails of new images every interval or right after upload until ctx is done
+func (s *Service) RunThumbnailer(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.uploaded:
+		}
+		if _, err := s.GenerateThumbnails(ctx); err != nil {
+			log.Printf("Thumbnail generation failed: %v", err)
+		}
+	}
+}
+
+// GenerateThumbnails makes thumbnails for claimed images and returns number of processed ones.
+// Broken images are marked failed at once, storage errors are retried up to maxThumbnailAttempts
+func (s *Service) GenerateThumbnails(ctx context.Context) (int, error) {
+	jobs, err := s.repo.ClaimThumbnailJobs(ctx, thumbnailBatch, thumbnailLease)
+	if err != nil {
+		return 0, err
+	}
+
+	done := 0
+	for i := range jobs {
+		a := &jobs[i]
+		err := s.makeThumbnails(ctx, a)
+		if err == nil {
+			done++
+			continue
+		}
+
+		log.Printf("Can't make thumbnails of attachment %d: %v", a.ID, err)
+		if errors.Is(err, errUnsupportedImage) || a.ThumbnailAttempts >= maxThumbnailAttempts {
+			if err := s.repo.SetThumbnailStatus(ctx, a.ID, ThumbnailFailed); err != nil {
+				return done, err
+			}
+		}
+	}
+	return done, nil
+}
+
+func (s *Service) makeThumbnails(ctx context.Context, a *Attachment) error {
+	body, err := s.store.Get(ctx, a.StorageKey)
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(body)
+	body.Close()
+	if err != nil {
+		return err
+	}
+
+	img, err := decodeImage(data)
+	if err != nil {
+		return err
+	}
+
+	thumbnails := make([]Thumbnail, 0, len(ThumbnailSizes))
+	files := make([][]byte, 0, len(ThumbnailSizes))
+	for size, side := range ThumbnailSizes {
+		scaled := resize(img, side)
+		encoded, contentType, err := encodeThumbnail(scaled, a.ContentType)
+		if err != nil {
+			return err
+		}
+		thumbnails = append(thumbnails, Thumbnail{
+			AttachmentID: a.ID,
+			Size:         size,
+			StorageKey:   thumbnailKey(a.StorageKey, size),
+			ContentType:  contentType,
+			Width:        scaled.Bounds().Dx(),
+			Height:       scaled.Bounds().Dy(),
+		})
+		files = append(files, encoded)
+	}
+
+	// rows go first, so files of deleted attachment are always queued for cleanup
+	if err := s.repo.SaveThumbnails(ctx, a.ID, thumbnails); err != nil {
+		return err
+	}
+	keys := make([]string, len(thumbnails))
+	for i, t := range thumbnails {
+		if err := s.store.Put(ctx, t.StorageKey, bytes.NewReader(files[i]), int64(len(files[i])), t.ContentType); err != nil {
+			return err
+		}
+		keys[i] = t.StorageKey
+	}
+	return s.repo.MarkThumbnailsReady(ctx, a.ID, keys)
+}
+
+// OpenThumbnail checks signed thumbnail link and opens thumbnail file
+func (s *Service) OpenThumbnail(ctx context.Context, id int64, size string, expires int64, signature string) (*Thumbnail, io.ReadCloser, error) {
+	if !s.validSignature(thumbnailResource(id, size), expires, signature) {
+		return nil, nil, ErrInvalidSignature
+	}
+
+	t, err := s.repo.GetThumbnail(ctx, id, size)
+	if err != nil {
+		return nil, nil, ErrNoThumbnail
+	}
+	body, err := s.store.Get(ctx, t.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return t, body, nil
+}
+
+// ListCovers returns cover thumbnails of tickets in project
+func (s *Service) ListCovers(ctx context.Context, projectID, userID int64) ([]Cover, error) {
+	// check access
+	if _, err := s.projectService.GetProjectByID(ctx, projectID, userID); err != nil {
+		return nil, err
+	}
+
+	covers, err := s.repo.ListCovers(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if covers == nil {
+		covers = []Cover{}
+	}
+	for i := range covers {
+		c := &covers[i]
+		url, err := s.link(thumbnailKey(c.StorageKey, CoverSize), thumbnailResource(c.AttachmentID, CoverSize), "")
+		if err != nil {
+			return nil, err
+		}
+		c.URL = url
+	}
+	return covers, nil
+}
+
+func thumbnailResource(id int64, size string) string {
+	return fmt.Sprintf("attachments/%d/thumbnails/%s", id, size)
+}
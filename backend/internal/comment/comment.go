@@ -0,0 +1,12 @@
+package comment
+
+import "time"
+
+type Comment struct {
+	ID        int64     `db:"id" json:"id"`
+	TicketID  int64     `db:"ticket_id" json:"ticket_id"`
+	AuthorID  int64     `db:"author_id" json:"author_id"`
+	Body      string    `db:"body" json:"body"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
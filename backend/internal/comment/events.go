@@ -0,0 +1,9 @@
+package comment
+
+import "github.com/antonovs105/project-management-system-go/internal/ticket"
+
+// CommentAddedData is payload of comment.added event
+type CommentAddedData struct {
+	Comment *Comment       `json:"comment"`
+	Ticket  *ticket.Ticket `json:"ticket"`
+}
@@ -0,0 +1,88 @@
+package comment
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// Create handler for POST /api/tickets/:id/comments
+func (h *Handler) Create(c echo.Context) error {
+	ticketID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ticket ID"})
+	}
+
+	var req CommentRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+	}
+
+	userID := c.Get("userID").(int64)
+
+	comment, err := h.service.AddComment(c.Request().Context(), req, ticketID, userID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusCreated, comment)
+}
+
+// List handler for GET /api/tickets/:id/comments
+func (h *Handler) List(c echo.Context) error {
+	ticketID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ticket ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	comments, err := h.service.ListComments(c.Request().Context(), ticketID, userID)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, comments)
+}
+
+// Update handler for PATCH /api/comments/:id
+func (h *Handler) Update(c echo.Context) error {
+	commentID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid comment ID"})
+	}
+
+	var req CommentRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+	}
+
+	userID := c.Get("userID").(int64)
+
+	comment, err := h.service.UpdateComment(c.Request().Context(), req, commentID, userID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, comment)
+}
+
+// Delete handler for DELETE /api/comments/:id
+func (h *Handler) Delete(c echo.Context) error {
+	commentID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid comment ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	err = h.service.DeleteComment(c.Request().Context(), commentID, userID)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,122 @@
+package comment
+
+import (
+	"context"
+	"errors"
+
+	"github.com/antonovs105/project-management-system-go/internal/event"
+	"github.com/antonovs105/project-management-system-go/internal/ticket"
+	"github.com/jmoiron/sqlx"
+)
+
+type Repository interface {
+	Create(ctx context.Context, comment *Comment, ev event.Draft) error
+	GetByID(ctx context.Context, id int64) (*Comment, error)
+	ListByTicketID(ctx context.Context, ticketID int64) ([]Comment, error)
+	Update(ctx context.Context, comment *Comment) error
+	Delete(ctx context.Context, id int64) error
+}
+
+type PgRepository struct {
+	db *sqlx.DB
+}
+
+func NewRepository(db *sqlx.DB) Repository {
+	return &PgRepository{db: db}
+}
+
+// Create makes new comment in DB, makes author watch ticket and writes ev to outbox, search document of ticket is refreshed by trigger
+func (r *PgRepository) Create(ctx context.Context, comment *Comment, ev event.Draft) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO ticket_comments (ticket_id, author_id, body)
+		VALUES (:ticket_id, :author_id, :body)
+		RETURNING *`
+
+	rows, err := sqlx.NamedQueryContext(ctx, tx, query, comment)
+	if err != nil {
+		return err
+	}
+	if !rows.Next() {
+		rows.Close()
+		return errors.New("comment creation failed: no returning row")
+	}
+	if err := rows.StructScan(comment); err != nil {
+		rows.Close()
+		return err
+	}
+	rows.Close()
+
+	// commenter follows the discussion
+	if err := ticket.AddWatchers(ctx, tx, comment.TicketID, &comment.AuthorID); err != nil {
+		return err
+	}
+	if err := event.Write(ctx, tx, ev); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetByID finds comment by its id
+func (r *PgRepository) GetByID(ctx context.Context, id int64) (*Comment, error) {
+	var c Comment
+	err := r.db.GetContext(ctx, &c, `SELECT * FROM ticket_comments WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// ListByTicketID returns comments of ticket, oldest first
+func (r *PgRepository) ListByTicketID(ctx context.Context, ticketID int64) ([]Comment, error) {
+	var comments []Comment
+	query := `SELECT * FROM ticket_comments WHERE ticket_id = $1 ORDER BY created_at, id`
+
+	err := r.db.SelectContext(ctx, &comments, query, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// Update saves comment body
+func (r *PgRepository) Update(ctx context.Context, comment *Comment) error {
+	query := `
+		UPDATE ticket_comments SET body = :body, updated_at = now()
+		WHERE id = :id
+		RETURNING updated_at`
+
+	rows, err := r.db.NamedQueryContext(ctx, query, comment)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		return rows.Scan(&comment.UpdatedAt)
+	}
+	return errors.New("comment not found")
+}
+
+// Delete removes comment
+func (r *PgRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM ticket_comments WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("comment not found")
+	}
+	return nil
+}
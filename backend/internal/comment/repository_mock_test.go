@@ -0,0 +1,58 @@
+package comment
+
+import (
+	"context"
+
+	"github.com/antonovs105/project-management-system-go/internal/event"
+	"github.com/antonovs105/project-management-system-go/internal/ticket"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRepository is a mock implementation of Repository
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Create(ctx context.Context, comment *Comment, ev event.Draft) error {
+	args := m.Called(ctx, comment, ev)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetByID(ctx context.Context, id int64) (*Comment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Comment), args.Error(1)
+}
+
+func (m *MockRepository) ListByTicketID(ctx context.Context, ticketID int64) ([]Comment, error) {
+	args := m.Called(ctx, ticketID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Comment), args.Error(1)
+}
+
+func (m *MockRepository) Update(ctx context.Context, comment *Comment) error {
+	args := m.Called(ctx, comment)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Delete(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// MockTicketGetter
+type MockTicketGetter struct {
+	mock.Mock
+}
+
+func (m *MockTicketGetter) GetTicketByID(ctx context.Context, ticketID, userID int64) (*ticket.Ticket, error) {
+	args := m.Called(ctx, ticketID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ticket.Ticket), args.Error(1)
+}
@@ -0,0 +1,127 @@
+package comment
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/antonovs105/project-management-system-go/internal/event"
+	"github.com/antonovs105/project-management-system-go/internal/ticket"
+)
+
+// TicketGetter interface
+type TicketGetter interface {
+	GetTicketByID(ctx context.Context, ticketID, userID int64) (*ticket.Ticket, error)
+}
+
+type Service struct {
+	repo          Repository
+	ticketService TicketGetter
+}
+
+func NewService(repo Repository, ticketService TicketGetter) *Service {
+	return &Service{
+		repo:          repo,
+		ticketService: ticketService,
+	}
+}
+
+// CommentRequest DTO for creating and editing comment
+type CommentRequest struct {
+	Body string `json:"body"`
+}
+
+// AddComment adds comment to ticket
+func (s *Service) AddComment(ctx context.Context, req CommentRequest, ticketID, userID int64) (*Comment, error) {
+	// check access
+	t, err := s.ticketService.GetTicketByID(ctx, ticketID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		return nil, errors.New("comment body is required")
+	}
+
+	c := &Comment{
+		TicketID: ticketID,
+		AuthorID: userID,
+		Body:     body,
+	}
+	ev := event.Draft{
+		ProjectID: t.ProjectID,
+		ActorID:   userID,
+		Type:      event.CommentAdded,
+		Data:      CommentAddedData{Comment: c, Ticket: t},
+	}
+	if err := s.repo.Create(ctx, c, ev); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// ListComments returns comments of ticket, oldest first
+func (s *Service) ListComments(ctx context.Context, ticketID, userID int64) ([]Comment, error) {
+	// check access
+	_, err := s.ticketService.GetTicketByID(ctx, ticketID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	comments, err := s.repo.ListByTicketID(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	if comments == nil {
+		comments = []Comment{}
+	}
+	return comments, nil
+}
+
+// ownComment returns comment if user is its author and still has access to ticket
+func (s *Service) ownComment(ctx context.Context, commentID, userID int64) (*Comment, error) {
+	c, err := s.repo.GetByID(ctx, commentID)
+	if err != nil {
+		return nil, errors.New("comment not found")
+	}
+	if c.AuthorID != userID {
+		return nil, errors.New("only author can change comment")
+	}
+
+	// check access
+	_, err = s.ticketService.GetTicketByID(ctx, c.TicketID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// UpdateComment edits own comment
+func (s *Service) UpdateComment(ctx context.Context, req CommentRequest, commentID, userID int64) (*Comment, error) {
+	c, err := s.ownComment(ctx, commentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		return nil, errors.New("comment body is required")
+	}
+	c.Body = body
+
+	if err := s.repo.Update(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// DeleteComment removes own comment
+func (s *Service) DeleteComment(ctx context.Context, commentID, userID int64) error {
+	c, err := s.ownComment(ctx, commentID, userID)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Delete(ctx, c.ID)
+}
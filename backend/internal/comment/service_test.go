@@ -0,0 +1,74 @@
+package comment
+
+import (
+	"context"
+	"testing"
+
+	"github.com/antonovs105/project-management-system-go/internal/event"
+	"github.com/antonovs105/project-management-system-go/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestService_AddComment(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockTicket := new(MockTicketGetter)
+	service := NewService(mockRepo, mockTicket)
+
+	ctx := context.Background()
+	ticketID := int64(5)
+	userID := int64(1)
+
+	t.Run("Success", func(t *testing.T) {
+		mockTicket.On("GetTicketByID", ctx, ticketID, userID).Return(&ticket.Ticket{ID: ticketID, ProjectID: 10}, nil).Once()
+		mockRepo.On("Create", ctx, mock.MatchedBy(func(c *Comment) bool {
+			return c.TicketID == ticketID && c.AuthorID == userID && c.Body == "Looks good"
+		}), mock.MatchedBy(func(ev event.Draft) bool {
+			return ev.ProjectID == 10 && ev.ActorID == userID && ev.Type == event.CommentAdded
+		})).Return(nil).Once()
+
+		c, err := service.AddComment(ctx, CommentRequest{Body: "  Looks good\n"}, ticketID, userID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Looks good", c.Body)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("EmptyBody", func(t *testing.T) {
+		mockTicket.On("GetTicketByID", ctx, ticketID, userID).Return(&ticket.Ticket{ID: ticketID}, nil).Once()
+
+		_, err := service.AddComment(ctx, CommentRequest{Body: "   "}, ticketID, userID)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestService_UpdateComment(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockTicket := new(MockTicketGetter)
+	service := NewService(mockRepo, mockTicket)
+
+	ctx := context.Background()
+	userID := int64(1)
+
+	t.Run("Success", func(t *testing.T) {
+		c := &Comment{ID: 7, TicketID: 5, AuthorID: userID, Body: "old"}
+		mockRepo.On("GetByID", ctx, int64(7)).Return(c, nil).Once()
+		mockTicket.On("GetTicketByID", ctx, int64(5), userID).Return(&ticket.Ticket{ID: 5}, nil).Once()
+		mockRepo.On("Update", ctx, c).Return(nil).Once()
+
+		updated, err := service.UpdateComment(ctx, CommentRequest{Body: "new"}, 7, userID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "new", updated.Body)
+	})
+
+	t.Run("NotAuthor", func(t *testing.T) {
+		mockRepo.On("GetByID", ctx, int64(8)).Return(&Comment{ID: 8, TicketID: 5, AuthorID: 2}, nil).Once()
+
+		_, err := service.UpdateComment(ctx, CommentRequest{Body: "new"}, 8, userID)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "only author")
+	})
+}
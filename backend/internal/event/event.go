@@ -0,0 +1,85 @@
+package event
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event types
+const (
+	TicketCreated = "ticket.created"
+	TicketUpdated = "ticket.updated"
+	TicketDeleted = "ticket.deleted"
+	LinkAdded     = "link.added"
+	LinkRemoved   = "link.removed"
+	CommentAdded  = "comment.added"
+	MemberAdded   = "member.added"
+)
+
+// Types are all event types
+var Types = []string{TicketCreated, TicketUpdated, TicketDeleted, LinkAdded, LinkRemoved, CommentAdded, MemberAdded}
+
+// IsType tells if t is known event type
+func IsType(t string) bool {
+	for _, known := range Types {
+		if known == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is something that happened in project. TxID is id of transaction which wrote event,
+// outbox is ordered by (TxID, ID) and event position is used as SSE event id
+type Event struct {
+	ID        int64           `db:"id" json:"id"`
+	TxID      int64           `db:"tx_id" json:"-"`
+	ProjectID int64           `db:"project_id" json:"project_id"`
+	Type      string          `db:"type" json:"type"`
+	ActorID   *int64          `db:"actor_id" json:"actor_id"`
+	Data      json.RawMessage `db:"data" json:"data"`
+	CreatedAt time.Time       `db:"created_at" json:"created_at"`
+}
+
+// Position is place of event in outbox order
+type Position struct {
+	TxID int64
+	ID   int64
+}
+
+// Position returns place of event in outbox
+func (e Event) Position() Position {
+	return Position{TxID: e.TxID, ID: e.ID}
+}
+
+// After tells if p goes after other in outbox
+func (p Position) After(other Position) bool {
+	return p.TxID > other.TxID || (p.TxID == other.TxID && p.ID > other.ID)
+}
+
+// String formats position as "txid-id"
+func (p Position) String() string {
+	return fmt.Sprintf("%d-%d", p.TxID, p.ID)
+}
+
+// ParsePosition parses position formatted by String. Plain event id sent by older clients
+// is accepted as position without TxID
+func ParsePosition(s string) (Position, error) {
+	var p Position
+	tx, id, found := strings.Cut(s, "-")
+	if !found {
+		id, tx = tx, "0"
+	}
+	var err error
+	if p.TxID, err = strconv.ParseInt(tx, 10, 64); err != nil || p.TxID < 0 {
+		return Position{}, errors.New("invalid event position")
+	}
+	if p.ID, err = strconv.ParseInt(id, 10, 64); err != nil || p.ID < 0 {
+		return Position{}, errors.New("invalid event position")
+	}
+	return p, nil
+}
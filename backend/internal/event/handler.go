@@ -0,0 +1,108 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// heartbeatInterval keeps idle connections open through proxies
+const heartbeatInterval = 25 * time.Second
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// Stream handler for GET /api/projects/:projectID/events
+// Server-Sent Events stream, reconnecting clients send Last-Event-ID header (or last_event_id param).
+// Event id is position in outbox, plain numeric id of older clients is accepted too
+func (h *Handler) Stream(c echo.Context) error {
+	projectID, err := strconv.ParseInt(c.Param("projectID"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid project ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	lastID := c.Request().Header.Get("Last-Event-ID")
+	if lastID == "" {
+		lastID = c.QueryParam("last_event_id")
+	}
+	var after Position
+	if lastID != "" {
+		after, err = ParsePosition(lastID)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid last event ID"})
+		}
+	}
+
+	ctx := c.Request().Context()
+	sub, err := h.service.Subscribe(ctx, projectID, userID, after)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+	defer sub.Close()
+
+	w := c.Response()
+	w.Header().Set(echo.HeaderContentType, "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprint(w, "retry: 3000\n\n")
+	// event replayed from DB may still be on its way through bus, so it is skipped once it comes live
+	replayed := make(map[int64]bool, len(sub.Missed))
+	for _, e := range sub.Missed {
+		if err := writeEvent(w, e); err != nil {
+			return nil
+		}
+		replayed[e.ID] = true
+	}
+	w.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e, ok := <-sub.Live:
+			if !ok {
+				// dropped as too slow, client reconnects with Last-Event-ID
+				return nil
+			}
+			// already sent during replay
+			if replayed[e.ID] {
+				continue
+			}
+			if err := writeEvent(w, e); err != nil {
+				return nil
+			}
+			w.Flush()
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return nil
+			}
+			w.Flush()
+		}
+	}
+}
+
+// writeEvent writes event in SSE format, data is whole event as one line of JSON
+func writeEvent(w *echo.Response, e Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", e.Position(), e.Type, data)
+	return err
+}
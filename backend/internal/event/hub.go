@@ -0,0 +1,64 @@
+package event
+
+import "sync"
+
+// subscriberBuffer is number of events subscriber may lag behind before it is dropped
+const subscriberBuffer = 64
+
+// Hub fans out events of this process to subscribers of project
+type Hub struct {
+	mu   sync.Mutex
+	subs map[int64]map[chan Event]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{subs: make(map[int64]map[chan Event]struct{})}
+}
+
+// Subscribe returns channel of project events and function to unsubscribe.
+// Channel is closed when subscriber is too slow, it should reconnect and replay missed events
+func (h *Hub) Subscribe(projectID int64) (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+
+	h.mu.Lock()
+	if h.subs[projectID] == nil {
+		h.subs[projectID] = make(map[chan Event]struct{})
+	}
+	h.subs[projectID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() { h.remove(projectID, ch) }
+}
+
+// Broadcast sends event to every subscriber of its project without blocking
+func (h *Hub) Broadcast(e Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subs[e.ProjectID] {
+		select {
+		case ch <- e:
+		default:
+			// slow subscriber, drop it
+			h.removeLocked(e.ProjectID, ch)
+		}
+	}
+}
+
+func (h *Hub) remove(projectID int64, ch chan Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.removeLocked(projectID, ch)
+}
+
+func (h *Hub) removeLocked(projectID int64, ch chan Event) {
+	subs := h.subs[projectID]
+	if _, ok := subs[ch]; !ok {
+		return
+	}
+	delete(subs, ch)
+	close(ch)
+	if len(subs) == 0 {
+		delete(h.subs, projectID)
+	}
+}
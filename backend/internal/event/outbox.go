@@ -0,0 +1,30 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Draft is event written to outbox together with entity change
+type Draft struct {
+	ProjectID int64
+	ActorID   int64
+	Type      string
+	// Data is encoded to JSON on write, so it may point to entity filled earlier in same transaction
+	Data any
+}
+
+// Write stores event in outbox inside tx. Event gets id of writing transaction, relay reads
+// events of finished transactions only, so ids committed out of order are never skipped
+func Write(ctx context.Context, tx sqlx.ExtContext, d Draft) error {
+	data, err := json.Marshal(d.Data)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO events (project_id, type, actor_id, data) VALUES ($1, $2, $3, $4)`
+	_, err = tx.ExecContext(ctx, query, d.ProjectID, d.Type, d.ActorID, string(data))
+	return err
+}
@@ -0,0 +1,86 @@
+package event
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	// relayBatch is max number of events handled in one consumer transaction
+	relayBatch = 100
+	// maxEventAttempts is number of failures in a row after which event is moved to dead letters
+	maxEventAttempts = 10
+)
+
+// Consumer handles events from outbox. Writes made with tx are committed together with
+// consumer checkpoint, so they happen exactly once per event
+type Consumer interface {
+	Consume(ctx context.Context, tx *sqlx.Tx, e Event) error
+}
+
+// Relay passes outbox events to registered consumers, each one keeps its own checkpoint
+type Relay struct {
+	repo      Repository
+	consumers map[string]Consumer
+}
+
+func NewRelay(repo Repository, consumers map[string]Consumer) *Relay {
+	return &Relay{repo: repo, consumers: consumers}
+}
+
+// RunRelay passes new events to every consumer every interval until ctx is done
+func (r *Relay) RunRelay(ctx context.Context, interval time.Duration) {
+	var wg sync.WaitGroup
+	for name, consumer := range r.consumers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.run(ctx, name, consumer, interval)
+		}()
+	}
+	wg.Wait()
+}
+
+func (r *Relay) run(ctx context.Context, name string, consumer Consumer, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	ready := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// new consumer starts from current end of outbox, not from the whole history
+			if !ready {
+				if err := r.repo.EnsureCheckpoint(ctx, name); err != nil {
+					log.Printf("Can't create checkpoint for %s: %v", name, err)
+					continue
+				}
+				ready = true
+			}
+			if _, err := r.Drain(ctx, name, consumer); err != nil {
+				log.Printf("Relay to %s failed, will retry: %v", name, err)
+			}
+		}
+	}
+}
+
+// Drain passes all pending events to consumer and returns their number. Failed batch
+// is rolled back and retried on next call, event failing maxEventAttempts times is skipped then
+func (r *Relay) Drain(ctx context.Context, name string, consumer Consumer) (int, error) {
+	total := 0
+	for {
+		n, err := r.repo.ProcessBatch(ctx, name, relayBatch, maxEventAttempts, func(tx *sqlx.Tx, e Event) error {
+			return consumer.Consume(ctx, tx, e)
+		})
+		total += n
+		if err != nil || n < relayBatch {
+			return total, err
+		}
+	}
+}
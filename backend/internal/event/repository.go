@@ -0,0 +1,188 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type Repository interface {
+	GetByID(ctx context.Context, id int64) (*Event, error)
+	ListAfter(ctx context.Context, projectID int64, after Position, limit int) ([]Event, error)
+	ListAllAfter(ctx context.Context, after Position, limit int) ([]Event, error)
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+	EnsureCheckpoint(ctx context.Context, consumer string) error
+	ProcessBatch(ctx context.Context, consumer string, limit, maxAttempts int, handle func(tx *sqlx.Tx, e Event) error) (int, error)
+}
+
+type PgRepository struct {
+	db *sqlx.DB
+}
+
+func NewRepository(db *sqlx.DB) Repository {
+	return &PgRepository{db: db}
+}
+
+// GetByID finds event by its id
+func (r *PgRepository) GetByID(ctx context.Context, id int64) (*Event, error) {
+	var e Event
+	err := r.db.GetContext(ctx, &e, `SELECT * FROM events WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// ListAfter returns project events after position in outbox order. Like relay it reads events of
+// finished transactions only, so later events are never placed before returned ones
+func (r *PgRepository) ListAfter(ctx context.Context, projectID int64, after Position, limit int) ([]Event, error) {
+	var events []Event
+	query := `
+		SELECT * FROM events
+		WHERE project_id = $1 AND (tx_id, id) > ($2::xid8, $3)
+		  AND tx_id < pg_snapshot_xmin(pg_current_snapshot())
+		ORDER BY tx_id, id
+		LIMIT $4`
+
+	err := r.db.SelectContext(ctx, &events, query, projectID, after.TxID, after.ID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// ListAllAfter returns events of all projects after position in outbox order, finished transactions only
+func (r *PgRepository) ListAllAfter(ctx context.Context, after Position, limit int) ([]Event, error) {
+	var events []Event
+	query := `
+		SELECT * FROM events
+		WHERE (tx_id, id) > ($1::xid8, $2)
+		  AND tx_id < pg_snapshot_xmin(pg_current_snapshot())
+		ORDER BY tx_id, id
+		LIMIT $3`
+
+	err := r.db.SelectContext(ctx, &events, query, after.TxID, after.ID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// DeleteBefore removes events created before given time and already handled by every consumer,
+// returns their number
+func (r *PgRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+		DELETE FROM events
+		WHERE created_at < $1
+		  AND EXISTS (SELECT 1 FROM event_checkpoints)
+		  AND (tx_id, id) <= ALL (SELECT last_tx_id, last_event_id FROM event_checkpoints)`
+
+	result, err := r.db.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// EnsureCheckpoint creates consumer checkpoint at end of outbox if it doesn't exist. Events of
+// transactions still running are after it
+func (r *PgRepository) EnsureCheckpoint(ctx context.Context, consumer string) error {
+	query := `
+		INSERT INTO event_checkpoints (consumer, last_tx_id, last_event_id)
+		VALUES ($1, pg_snapshot_xmin(pg_current_snapshot()), 0)
+		ON CONFLICT (consumer) DO NOTHING`
+
+	_, err := r.db.ExecContext(ctx, query, consumer)
+	return err
+}
+
+// ProcessBatch passes up to limit events after consumer checkpoint to handle and moves checkpoint
+// in the same transaction. Checkpoint row is locked, so each event is handled once across instances.
+// Only events of transactions older than every running one are read: no event can commit before them later.
+// Failed event is counted on checkpoint, after maxAttempts it goes to dead letters and is skipped
+func (r *PgRepository) ProcessBatch(ctx context.Context, consumer string, limit, maxAttempts int, handle func(tx *sqlx.Tx, e Event) error) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var cp checkpoint
+	query := `
+		SELECT last_tx_id, last_event_id, failed_event_id, attempts, last_error
+		FROM event_checkpoints WHERE consumer = $1 FOR UPDATE`
+	if err := tx.GetContext(ctx, &cp, query, consumer); err != nil {
+		return 0, err
+	}
+
+	var events []Event
+	query = `
+		SELECT * FROM events
+		WHERE (tx_id, id) > ($1::xid8, $2)
+		  AND tx_id < pg_snapshot_xmin(pg_current_snapshot())
+		ORDER BY tx_id, id
+		LIMIT $3`
+	if err := tx.SelectContext(ctx, &events, query, cp.LastTxID, cp.LastEventID, limit); err != nil {
+		return 0, err
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	for _, e := range events {
+		if cp.FailedEventID != nil && *cp.FailedEventID == e.ID && cp.Attempts >= maxAttempts {
+			query := `INSERT INTO event_dead_letters (consumer, event_id, attempts, error) VALUES ($1, $2, $3, $4)`
+			if _, err := tx.ExecContext(ctx, query, consumer, e.ID, cp.Attempts, cp.LastError); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		if err := handle(tx, e); err != nil {
+			// handler may have broken tx, failure is counted outside of it
+			tx.Rollback()
+			attempts, recordErr := r.recordFailure(ctx, consumer, e.ID, err)
+			if recordErr != nil {
+				return 0, fmt.Errorf("event %d: %w (can't count attempt: %v)", e.ID, err, recordErr)
+			}
+			return 0, fmt.Errorf("event %d, attempt %d of %d: %w", e.ID, attempts, maxAttempts, err)
+		}
+	}
+
+	last := events[len(events)-1]
+	query = `
+		UPDATE event_checkpoints
+		SET last_tx_id = $2, last_event_id = $3, failed_event_id = NULL, attempts = 0, last_error = NULL, updated_at = now()
+		WHERE consumer = $1`
+	if _, err := tx.ExecContext(ctx, query, consumer, last.TxID, last.ID); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(events), nil
+}
+
+// checkpoint is consumer position in outbox and its failures on next event
+type checkpoint struct {
+	LastTxID      int64   `db:"last_tx_id"`
+	LastEventID   int64   `db:"last_event_id"`
+	FailedEventID *int64  `db:"failed_event_id"`
+	Attempts      int     `db:"attempts"`
+	LastError     *string `db:"last_error"`
+}
+
+// recordFailure counts failed attempt of consumer on event, returns number of attempts in a row
+func (r *PgRepository) recordFailure(ctx context.Context, consumer string, eventID int64, cause error) (int, error) {
+	var attempts int
+	query := `
+		UPDATE event_checkpoints
+		SET attempts = CASE WHEN failed_event_id = $2 THEN attempts + 1 ELSE 1 END,
+			failed_event_id = $2, last_error = $3, updated_at = now()
+		WHERE consumer = $1
+		RETURNING attempts`
+	err := r.db.GetContext(ctx, &attempts, query, consumer, eventID, cause.Error())
+	return attempts, err
+}
@@ -0,0 +1,70 @@
+package event
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRepository is a mock implementation of Repository
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) GetByID(ctx context.Context, id int64) (*Event, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Event), args.Error(1)
+}
+
+func (m *MockRepository) ListAllAfter(ctx context.Context, after Position, limit int) ([]Event, error) {
+	args := m.Called(ctx, after, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Event), args.Error(1)
+}
+
+func (m *MockRepository) ListAfter(ctx context.Context, projectID int64, after Position, limit int) ([]Event, error) {
+	args := m.Called(ctx, projectID, after, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Event), args.Error(1)
+}
+
+func (m *MockRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRepository) EnsureCheckpoint(ctx context.Context, consumer string) error {
+	args := m.Called(ctx, consumer)
+	return args.Error(0)
+}
+
+// ProcessBatch passes events given in expectation to handle, returns error of first failed one
+func (m *MockRepository) ProcessBatch(ctx context.Context, consumer string, limit, maxAttempts int, handle func(tx *sqlx.Tx, e Event) error) (int, error) {
+	args := m.Called(ctx, consumer, limit, maxAttempts)
+	events := args.Get(0).([]Event)
+	for _, e := range events {
+		if err := handle(nil, e); err != nil {
+			return 0, err
+		}
+	}
+	return len(events), args.Error(1)
+}
+
+// MockMemberChecker
+type MockMemberChecker struct {
+	mock.Mock
+}
+
+func (m *MockMemberChecker) GetUserRole(ctx context.Context, userID, projectID int64) (string, error) {
+	args := m.Called(ctx, userID, projectID)
+	return args.String(0), args.Error(1)
+}
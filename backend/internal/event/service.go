@@ -0,0 +1,172 @@
+package event
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/pubsub"
+	"github.com/jmoiron/sqlx"
+)
+
+// replayLimit is max number of missed events sent on reconnect
+const replayLimit = 1000
+
+// Channel is pub/sub channel events are fanned out through to every instance
+const Channel = "project_events"
+
+// MemberChecker interface
+type MemberChecker interface {
+	GetUserRole(ctx context.Context, userID, projectID int64) (string, error)
+}
+
+type Service struct {
+	repo          Repository
+	hub           *Hub
+	bus           pubsub.PubSub
+	memberService MemberChecker
+	// last is position of latest event received from bus, used to catch up after lost connection
+	mu   sync.Mutex
+	last Position
+}
+
+func NewService(repo Repository, hub *Hub, bus pubsub.PubSub, memberService MemberChecker) *Service {
+	return &Service{
+		repo:          repo,
+		hub:           hub,
+		bus:           bus,
+		memberService: memberService,
+	}
+}
+
+// message is event sent through bus. Events too large for bus go without data and are loaded from DB
+type message struct {
+	Event
+	TxID      int64 `json:"tx_id"`
+	Truncated bool  `json:"truncated,omitempty"`
+}
+
+// Consume sends event from outbox to subscribers on every instance. Bus is not transactional,
+// so event can be sent again if checkpoint is not saved, stream clients skip ids they have seen
+func (s *Service) Consume(ctx context.Context, tx *sqlx.Tx, e Event) error {
+	err := s.send(ctx, message{Event: e, TxID: e.TxID})
+	if errors.Is(err, pubsub.ErrPayloadTooLarge) {
+		truncated := message{Event: e, TxID: e.TxID, Truncated: true}
+		truncated.Data = nil
+		err = s.send(ctx, truncated)
+	}
+	return err
+}
+
+func (s *Service) send(ctx context.Context, msg message) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return s.bus.Publish(ctx, Channel, payload)
+}
+
+// Listen delivers events published by any instance to local subscribers until ctx is done
+func (s *Service) Listen(ctx context.Context) error {
+	return s.bus.Subscribe(ctx, Channel, func(payload []byte) {
+		if payload == nil {
+			s.catchUp(ctx)
+			return
+		}
+
+		var msg message
+		if err := json.Unmarshal(payload, &msg); err != nil {
+			log.Printf("Can't decode event from bus: %v", err)
+			return
+		}
+		e := msg.Event
+		e.TxID = msg.TxID
+		if msg.Truncated {
+			full, err := s.repo.GetByID(ctx, e.ID)
+			if err != nil {
+				log.Printf("Can't load event %d: %v", e.ID, err)
+				return
+			}
+			e = *full
+		}
+		s.broadcast(e)
+	})
+}
+
+// catchUp broadcasts events stored while bus connection was down
+func (s *Service) catchUp(ctx context.Context) {
+	s.mu.Lock()
+	last := s.last
+	s.mu.Unlock()
+	if last == (Position{}) {
+		return
+	}
+	missed, err := s.repo.ListAllAfter(ctx, last, replayLimit)
+	if err != nil {
+		log.Printf("Can't load events missed by bus: %v", err)
+		return
+	}
+	for _, e := range missed {
+		s.broadcast(e)
+	}
+}
+
+func (s *Service) broadcast(e Event) {
+	s.mu.Lock()
+	if e.Position().After(s.last) {
+		s.last = e.Position()
+	}
+	s.mu.Unlock()
+	s.hub.Broadcast(e)
+}
+
+// Subscription is stream of project events. Missed are events after last seen position, Live may repeat them
+type Subscription struct {
+	Missed []Event
+	Live   <-chan Event
+	Close  func()
+}
+
+// Subscribe checks access and subscribes user to project events. With non-zero after
+// events after it are replayed
+func (s *Service) Subscribe(ctx context.Context, projectID, userID int64, after Position) (*Subscription, error) {
+	// only members see project events
+	if _, err := s.memberService.GetUserRole(ctx, userID, projectID); err != nil {
+		return nil, errors.New("project not found or access denied")
+	}
+
+	// subscribe before reading missed events so nothing falls in between
+	live, unsubscribe := s.hub.Subscribe(projectID)
+	sub := &Subscription{Live: live, Close: unsubscribe}
+
+	if after.TxID == 0 && after.ID > 0 {
+		// plain event id, its transaction is looked up. Pruned event is too old to replay from
+		e, err := s.repo.GetByID(ctx, after.ID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return sub, nil
+		}
+		if err != nil {
+			unsubscribe()
+			return nil, err
+		}
+		after = e.Position()
+	}
+	if after != (Position{}) {
+		missed, err := s.repo.ListAfter(ctx, projectID, after, replayLimit)
+		if err != nil {
+			unsubscribe()
+			return nil, err
+		}
+		sub.Missed = missed
+	}
+	return sub, nil
+}
+
+// Prune deletes events older than maxAge and handled by every consumer
+func (s *Service) Prune(ctx context.Context, maxAge time.Duration) (int64, error) {
+	return s.repo.DeleteBefore(ctx, time.Now().Add(-maxAge))
+}
@@ -0,0 +1,253 @@
+package event
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/pubsub"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHub(t *testing.T) {
+	hub := NewHub()
+
+	t.Run("ProjectScoped", func(t *testing.T) {
+		ch, unsubscribe := hub.Subscribe(1)
+		other, unsubscribeOther := hub.Subscribe(2)
+		defer unsubscribe()
+		defer unsubscribeOther()
+
+		hub.Broadcast(Event{ID: 1, ProjectID: 1})
+
+		assert.Equal(t, int64(1), (<-ch).ID)
+		assert.Len(t, other, 0)
+	})
+
+	t.Run("SlowSubscriberDropped", func(t *testing.T) {
+		ch, unsubscribe := hub.Subscribe(3)
+
+		for i := 0; i <= subscriberBuffer; i++ {
+			hub.Broadcast(Event{ID: int64(i), ProjectID: 3})
+		}
+
+		for range ch {
+		}
+		// channel is closed, unsubscribe after drop is no-op
+		unsubscribe()
+	})
+}
+
+func TestService_Consume(t *testing.T) {
+	mockRepo := new(MockRepository)
+	hub := NewHub()
+	bus := pubsub.NewMemory()
+	defer bus.Close()
+	service := NewService(mockRepo, hub, bus, new(MockMemberChecker))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	assert.NoError(t, service.Listen(ctx))
+	live, unsubscribe := hub.Subscribe(10)
+	defer unsubscribe()
+
+	t.Run("Success", func(t *testing.T) {
+		err := service.Consume(ctx, nil, Event{ID: 42, ProjectID: 10, Type: TicketCreated, Data: []byte(`{"id":5}`)})
+
+		assert.NoError(t, err)
+		e := receive(t, live)
+		assert.Equal(t, int64(42), e.ID)
+		assert.JSONEq(t, `{"id":5}`, string(e.Data))
+	})
+
+	t.Run("TooLargeForBus", func(t *testing.T) {
+		service := NewService(mockRepo, hub, limitedBus{bus}, new(MockMemberChecker))
+		big := strings.Repeat("x", 10000)
+		stored := &Event{ID: 43, ProjectID: 10, Type: TicketUpdated, Data: []byte(`"` + big + `"`)}
+		mockRepo.On("GetByID", mock.Anything, int64(43)).Return(stored, nil).Once()
+
+		err := service.Consume(ctx, nil, *stored)
+
+		assert.NoError(t, err)
+		e := receive(t, live)
+		assert.Equal(t, int64(43), e.ID)
+		assert.Len(t, e.Data, len(big)+2)
+	})
+}
+
+// limitedBus rejects large messages like NOTIFY does
+type limitedBus struct{ pubsub.PubSub }
+
+func (b limitedBus) Publish(ctx context.Context, channel string, payload []byte) error {
+	if len(payload) > 1000 {
+		return pubsub.ErrPayloadTooLarge
+	}
+	return b.PubSub.Publish(ctx, channel, payload)
+}
+
+func TestService_Listen_CatchUp(t *testing.T) {
+	mockRepo := new(MockRepository)
+	hub := NewHub()
+	bus := pubsub.NewMemory()
+	defer bus.Close()
+	service := NewService(mockRepo, hub, bus, new(MockMemberChecker))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	assert.NoError(t, service.Listen(ctx))
+	live, unsubscribe := hub.Subscribe(10)
+	defer unsubscribe()
+
+	assert.NoError(t, bus.Publish(ctx, Channel, []byte(`{"id":7,"tx_id":3,"project_id":10,"type":"ticket.created","data":{}}`)))
+	assert.Equal(t, int64(7), receive(t, live).ID)
+
+	// connection lost and restored, events after last received are loaded from DB
+	mockRepo.On("ListAllAfter", mock.Anything, Position{TxID: 3, ID: 7}, replayLimit).Return([]Event{{ID: 8, TxID: 4, ProjectID: 10}}, nil).Once()
+	assert.NoError(t, bus.Publish(ctx, Channel, nil))
+
+	assert.Equal(t, int64(8), receive(t, live).ID)
+}
+
+func receive(t *testing.T, ch <-chan Event) Event {
+	t.Helper()
+	select {
+	case e := <-ch:
+		return e
+	case <-time.After(time.Second):
+		t.Fatal("event not received")
+		return Event{}
+	}
+}
+
+func TestService_Subscribe(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockMember := new(MockMemberChecker)
+	service := NewService(mockRepo, NewHub(), pubsub.NewMemory(), mockMember)
+
+	ctx := context.Background()
+
+	t.Run("Replay", func(t *testing.T) {
+		missed := []Event{{ID: 9, TxID: 5, ProjectID: 10}, {ID: 8, TxID: 6, ProjectID: 10}}
+		mockMember.On("GetUserRole", ctx, int64(1), int64(10)).Return("member", nil).Once()
+		mockRepo.On("ListAfter", ctx, int64(10), Position{TxID: 4, ID: 7}, replayLimit).Return(missed, nil).Once()
+
+		sub, err := service.Subscribe(ctx, 10, 1, Position{TxID: 4, ID: 7})
+
+		assert.NoError(t, err)
+		assert.Equal(t, missed, sub.Missed)
+		sub.Close()
+	})
+
+	t.Run("ReplayFromPlainID", func(t *testing.T) {
+		mockMember.On("GetUserRole", ctx, int64(1), int64(10)).Return("member", nil).Once()
+		mockRepo.On("GetByID", ctx, int64(7)).Return(&Event{ID: 7, TxID: 4}, nil).Once()
+		mockRepo.On("ListAfter", ctx, int64(10), Position{TxID: 4, ID: 7}, replayLimit).Return([]Event{}, nil).Once()
+
+		sub, err := service.Subscribe(ctx, 10, 1, Position{ID: 7})
+
+		assert.NoError(t, err)
+		sub.Close()
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("PlainIDPruned", func(t *testing.T) {
+		mockMember.On("GetUserRole", ctx, int64(1), int64(10)).Return("member", nil).Once()
+		mockRepo.On("GetByID", ctx, int64(3)).Return(nil, sql.ErrNoRows).Once()
+
+		sub, err := service.Subscribe(ctx, 10, 1, Position{ID: 3})
+
+		assert.NoError(t, err)
+		assert.Empty(t, sub.Missed)
+		sub.Close()
+	})
+
+	t.Run("FreshConnection", func(t *testing.T) {
+		mockMember.On("GetUserRole", ctx, int64(1), int64(10)).Return("member", nil).Once()
+
+		sub, err := service.Subscribe(ctx, 10, 1, Position{})
+
+		assert.NoError(t, err)
+		assert.Empty(t, sub.Missed)
+		sub.Close()
+	})
+
+	t.Run("AccessDenied", func(t *testing.T) {
+		mockMember.On("GetUserRole", ctx, int64(2), int64(10)).Return("", sql.ErrNoRows).Once()
+
+		_, err := service.Subscribe(ctx, 10, 2, Position{})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestParsePosition(t *testing.T) {
+	p, err := ParsePosition(Position{TxID: 812, ID: 40}.String())
+	assert.NoError(t, err)
+	assert.Equal(t, Position{TxID: 812, ID: 40}, p)
+
+	p, err = ParsePosition("40")
+	assert.NoError(t, err)
+	assert.Equal(t, Position{ID: 40}, p)
+
+	for _, bad := range []string{"", "x", "1-", "-5", "1-2-3", "1--2"} {
+		_, err := ParsePosition(bad)
+		assert.Error(t, err, bad)
+	}
+	assert.True(t, Position{TxID: 2, ID: 1}.After(Position{TxID: 1, ID: 9}))
+}
+
+func TestRelay_Drain(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("AllBatches", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		consumer := new(recordingConsumer)
+		relay := NewRelay(mockRepo, map[string]Consumer{"webhooks": consumer})
+
+		full := make([]Event, relayBatch)
+		for i := range full {
+			full[i] = Event{ID: int64(i + 1)}
+		}
+		mockRepo.On("ProcessBatch", ctx, "webhooks", relayBatch, maxEventAttempts).Return(full, nil).Once()
+		mockRepo.On("ProcessBatch", ctx, "webhooks", relayBatch, maxEventAttempts).Return([]Event{{ID: 101}}, nil).Once()
+
+		n, err := relay.Drain(ctx, "webhooks", consumer)
+
+		assert.NoError(t, err)
+		assert.Equal(t, relayBatch+1, n)
+		assert.Len(t, consumer.events, relayBatch+1)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("ConsumerFails", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		consumer := &recordingConsumer{err: errors.New("db down")}
+		relay := NewRelay(mockRepo, map[string]Consumer{"webhooks": consumer})
+
+		mockRepo.On("ProcessBatch", ctx, "webhooks", relayBatch, maxEventAttempts).Return([]Event{{ID: 1}, {ID: 2}}, nil).Once()
+
+		n, err := relay.Drain(ctx, "webhooks", consumer)
+
+		assert.Error(t, err)
+		assert.Equal(t, 0, n)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+type recordingConsumer struct {
+	events []Event
+	err    error
+}
+
+func (c *recordingConsumer) Consume(ctx context.Context, tx *sqlx.Tx, e Event) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.events = append(c.events, e)
+	return nil
+}
@@ -0,0 +1,38 @@
+package filter
+
+import (
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/ticket"
+)
+
+// Filter visibility
+const (
+	VisibilityPrivate = "private"
+	VisibilityProject = "project"
+	VisibilityPublic  = "public"
+)
+
+// Filter is saved ticket query
+type Filter struct {
+	ID          int64     `db:"id" json:"id"`
+	OwnerID     int64     `db:"owner_id" json:"owner_id"`
+	Name        string    `db:"name" json:"name"`
+	Description string    `db:"description" json:"description"`
+	Query       string    `db:"query" json:"query"`
+	Visibility  string    `db:"visibility" json:"visibility"`
+	ProjectID   *int64    `db:"project_id" json:"project_id"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+	// Favourite is set for user who reads filter
+	Favourite bool `db:"favourite" json:"favourite"`
+}
+
+// QueryResult is one page of tickets matching query
+type QueryResult struct {
+	Query   string          `json:"query"`
+	Total   int             `json:"total"`
+	Limit   int             `json:"limit"`
+	Offset  int             `json:"offset"`
+	Tickets []ticket.Ticket `json:"tickets"`
+}
@@ -0,0 +1,180 @@
+package filter
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/antonovs105/project-management-system-go/internal/tql"
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// Create handler for POST /api/filters
+func (h *Handler) Create(c echo.Context) error {
+	var req FilterRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+	}
+
+	userID := c.Get("userID").(int64)
+
+	f, err := h.service.CreateFilter(c.Request().Context(), req, userID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusCreated, f)
+}
+
+// List handler for GET /api/filters?favourites=true
+func (h *Handler) List(c echo.Context) error {
+	userID := c.Get("userID").(int64)
+	favouritesOnly := c.QueryParam("favourites") == "true"
+
+	filters, err := h.service.ListFilters(c.Request().Context(), userID, favouritesOnly)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, filters)
+}
+
+// Get handler for GET /api/filters/:id
+func (h *Handler) Get(c echo.Context) error {
+	filterID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid filter ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	f, err := h.service.GetFilterByID(c.Request().Context(), filterID, userID)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, f)
+}
+
+// Update handler for PATCH /api/filters/:id
+func (h *Handler) Update(c echo.Context) error {
+	filterID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid filter ID"})
+	}
+
+	var req UpdateFilterRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+	}
+
+	userID := c.Get("userID").(int64)
+
+	f, err := h.service.UpdateFilter(c.Request().Context(), req, filterID, userID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, f)
+}
+
+// Delete handler for DELETE /api/filters/:id
+func (h *Handler) Delete(c echo.Context) error {
+	filterID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid filter ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	err = h.service.DeleteFilter(c.Request().Context(), filterID, userID)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Favourite handler for POST /api/filters/:id/favourite
+func (h *Handler) Favourite(c echo.Context) error {
+	return h.setFavourite(c, true)
+}
+
+// Unfavourite handler for DELETE /api/filters/:id/favourite
+func (h *Handler) Unfavourite(c echo.Context) error {
+	return h.setFavourite(c, false)
+}
+
+func (h *Handler) setFavourite(c echo.Context, favourite bool) error {
+	filterID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid filter ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	err = h.service.SetFavourite(c.Request().Context(), filterID, userID, favourite)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Tickets handler for GET /api/filters/:id/tickets?limit=50&offset=0
+func (h *Handler) Tickets(c echo.Context) error {
+	filterID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid filter ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	limit, offset, err := pageParams(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	result, err := h.service.RunFilter(c.Request().Context(), filterID, userID, limit, offset)
+	if err != nil {
+		return queryError(c, err)
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// Query handler for GET /api/tickets/query?q=project = PMS AND status != done&limit=50&offset=0
+func (h *Handler) Query(c echo.Context) error {
+	userID := c.Get("userID").(int64)
+
+	limit, offset, err := pageParams(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	result, err := h.service.RunQuery(c.Request().Context(), c.QueryParam("q"), userID, limit, offset)
+	if err != nil {
+		return queryError(c, err)
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// queryError answers 400 for invalid queries, 404 for others
+func queryError(c echo.Context, err error) error {
+	var qe *tql.Error
+	if errors.As(err, &qe) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+}
+
+func pageParams(c echo.Context) (limit, offset int, err error) {
+	if v := c.QueryParam("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil {
+			return 0, 0, errors.New("Invalid limit")
+		}
+	}
+	if v := c.QueryParam("offset"); v != "" {
+		if offset, err = strconv.Atoi(v); err != nil {
+			return 0, 0, errors.New("Invalid offset")
+		}
+	}
+	return limit, offset, nil
+}
@@ -0,0 +1,171 @@
+package filter
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/antonovs105/project-management-system-go/internal/ticket"
+	"github.com/antonovs105/project-management-system-go/internal/tql"
+	"github.com/jmoiron/sqlx"
+)
+
+type Repository interface {
+	Create(ctx context.Context, filter *Filter) error
+	GetByID(ctx context.Context, id, userID int64) (*Filter, error)
+	ListVisible(ctx context.Context, userID int64, favouritesOnly bool) ([]Filter, error)
+	Update(ctx context.Context, filter *Filter) error
+	Delete(ctx context.Context, id int64) error
+	SetFavourite(ctx context.Context, userID, filterID int64, favourite bool) error
+	RunQuery(ctx context.Context, userID int64, query *tql.SQL, limit, offset int) ([]ticket.Ticket, int, error)
+}
+
+type PgRepository struct {
+	db *sqlx.DB
+}
+
+func NewRepository(db *sqlx.DB) Repository {
+	return &PgRepository{db: db}
+}
+
+// visibleTo is condition of filters user can read, $1 is user id
+const visibleTo = `
+	(f.owner_id = $1 OR f.visibility = 'public' OR (f.visibility = 'project' AND EXISTS (
+		SELECT 1 FROM project_members pm WHERE pm.project_id = f.project_id AND pm.user_id = $1)))`
+
+// selectFilters adds favourite flag of user $1
+const selectFilters = `
+	SELECT f.*, EXISTS (
+		SELECT 1 FROM filter_favourites ff WHERE ff.filter_id = f.id AND ff.user_id = $1) AS favourite
+	FROM filters f`
+
+// Create makes new filter in DB
+func (r *PgRepository) Create(ctx context.Context, filter *Filter) error {
+	query := `
+		INSERT INTO filters (owner_id, name, description, query, visibility, project_id)
+		VALUES (:owner_id, :name, :description, :query, :visibility, :project_id)
+		RETURNING *`
+
+	rows, err := r.db.NamedQueryContext(ctx, query, filter)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		return rows.StructScan(filter)
+	}
+	return errors.New("filter creation failed: no returning row")
+}
+
+// GetByID finds filter visible to user
+func (r *PgRepository) GetByID(ctx context.Context, id, userID int64) (*Filter, error) {
+	var f Filter
+	query := selectFilters + ` WHERE f.id = $2 AND` + visibleTo
+
+	err := r.db.GetContext(ctx, &f, query, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// ListVisible returns own, shared and public filters, favourites first
+func (r *PgRepository) ListVisible(ctx context.Context, userID int64, favouritesOnly bool) ([]Filter, error) {
+	var filters []Filter
+	query := `
+		SELECT * FROM (` + selectFilters + ` WHERE` + visibleTo + `) visible
+		WHERE favourite OR NOT $2
+		ORDER BY favourite DESC, name, id`
+
+	err := r.db.SelectContext(ctx, &filters, query, userID, favouritesOnly)
+	if err != nil {
+		return nil, err
+	}
+	return filters, nil
+}
+
+// Update saves filter fields
+func (r *PgRepository) Update(ctx context.Context, filter *Filter) error {
+	query := `
+		UPDATE filters
+		SET name = :name, description = :description, query = :query,
+			visibility = :visibility, project_id = :project_id, updated_at = now()
+		WHERE id = :id`
+
+	result, err := r.db.NamedExecContext(ctx, query, filter)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("filter not found")
+	}
+	return nil
+}
+
+// Delete removes filter with favourites
+func (r *PgRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM filters WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("filter not found")
+	}
+	return nil
+}
+
+// SetFavourite adds or removes filter from user favourites, repeated calls are no-op
+func (r *PgRepository) SetFavourite(ctx context.Context, userID, filterID int64, favourite bool) error {
+	query := `DELETE FROM filter_favourites WHERE user_id = $1 AND filter_id = $2`
+	if favourite {
+		query = `
+			INSERT INTO filter_favourites (user_id, filter_id) VALUES ($1, $2)
+			ON CONFLICT DO NOTHING`
+	}
+	_, err := r.db.ExecContext(ctx, query, userID, filterID)
+	return err
+}
+
+// RunQuery returns page of tickets matching compiled query in projects of user and number of all matches
+func (r *PgRepository) RunQuery(ctx context.Context, userID int64, query *tql.SQL, limit, offset int) ([]ticket.Ticket, int, error) {
+	n := len(query.Args)
+	param := func(i int) string { return "$" + strconv.Itoa(n+i) }
+
+	sql := `
+		SELECT t.*, COUNT(*) OVER () AS total
+		FROM tickets t
+		JOIN projects p ON p.id = t.project_id
+		JOIN project_members pm ON pm.project_id = t.project_id AND pm.user_id = ` + param(1) + `
+		WHERE ` + query.Where + `
+		ORDER BY ` + query.OrderBy + `
+		LIMIT ` + param(2) + ` OFFSET ` + param(3)
+
+	args := append(append([]any{}, query.Args...), userID, limit, offset)
+
+	var rows []struct {
+		ticket.Ticket
+		Total int `db:"total"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, sql, args...); err != nil {
+		return nil, 0, err
+	}
+
+	tickets := make([]ticket.Ticket, 0, len(rows))
+	total := 0
+	for _, row := range rows {
+		tickets = append(tickets, row.Ticket)
+		total = row.Total
+	}
+	return tickets, total, nil
+}
@@ -0,0 +1,72 @@
+package filter
+
+import (
+	"context"
+
+	"github.com/antonovs105/project-management-system-go/internal/project"
+	"github.com/antonovs105/project-management-system-go/internal/ticket"
+	"github.com/antonovs105/project-management-system-go/internal/tql"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRepository is a mock implementation of Repository
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Create(ctx context.Context, filter *Filter) error {
+	args := m.Called(ctx, filter)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetByID(ctx context.Context, id, userID int64) (*Filter, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Filter), args.Error(1)
+}
+
+func (m *MockRepository) ListVisible(ctx context.Context, userID int64, favouritesOnly bool) ([]Filter, error) {
+	args := m.Called(ctx, userID, favouritesOnly)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Filter), args.Error(1)
+}
+
+func (m *MockRepository) Update(ctx context.Context, filter *Filter) error {
+	args := m.Called(ctx, filter)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Delete(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRepository) SetFavourite(ctx context.Context, userID, filterID int64, favourite bool) error {
+	args := m.Called(ctx, userID, filterID, favourite)
+	return args.Error(0)
+}
+
+func (m *MockRepository) RunQuery(ctx context.Context, userID int64, query *tql.SQL, limit, offset int) ([]ticket.Ticket, int, error) {
+	args := m.Called(ctx, userID, query, limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]ticket.Ticket), args.Int(1), args.Error(2)
+}
+
+// MockProjectChecker
+type MockProjectChecker struct {
+	mock.Mock
+}
+
+func (m *MockProjectChecker) GetProjectByID(ctx context.Context, projectID, userID int64) (*project.Project, error) {
+	args := m.Called(ctx, projectID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*project.Project), args.Error(1)
+}
@@ -0,0 +1,232 @@
+package filter
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/project"
+	"github.com/antonovs105/project-management-system-go/internal/tql"
+)
+
+// Limits of query result page
+const (
+	defaultLimit = 50
+	maxLimit     = 200
+)
+
+// ProjectChecker interface
+type ProjectChecker interface {
+	GetProjectByID(ctx context.Context, projectID, userID int64) (*project.Project, error)
+}
+
+type Service struct {
+	repo           Repository
+	projectService ProjectChecker
+	now            func() time.Time
+}
+
+func NewService(repo Repository, projectService ProjectChecker) *Service {
+	return &Service{
+		repo:           repo,
+		projectService: projectService,
+		now:            time.Now,
+	}
+}
+
+// FilterRequest DTO for creating filter
+type FilterRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Query       string `json:"query"`
+	Visibility  string `json:"visibility"`
+	ProjectID   *int64 `json:"project_id"`
+}
+
+// CreateFilter saves query of user, private by default
+func (s *Service) CreateFilter(ctx context.Context, req FilterRequest, userID int64) (*Filter, error) {
+	f := &Filter{
+		OwnerID:     userID,
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+		Query:       strings.TrimSpace(req.Query),
+		Visibility:  req.Visibility,
+		ProjectID:   req.ProjectID,
+	}
+	if f.Visibility == "" {
+		f.Visibility = VisibilityPrivate
+	}
+	if err := s.validate(ctx, f, userID); err != nil {
+		return nil, err
+	}
+
+	// unique constraint guards against duplicate names
+	if err := s.repo.Create(ctx, f); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// validate checks name, query syntax and sharing target
+func (s *Service) validate(ctx context.Context, f *Filter, userID int64) error {
+	if f.Name == "" {
+		return errors.New("filter name is required")
+	}
+	if _, err := s.compile(f.Query, userID); err != nil {
+		return err
+	}
+
+	switch f.Visibility {
+	case VisibilityPrivate, VisibilityPublic:
+		f.ProjectID = nil
+	case VisibilityProject:
+		if f.ProjectID == nil {
+			return errors.New("project is required to share filter with project")
+		}
+		// check access
+		if _, err := s.projectService.GetProjectByID(ctx, *f.ProjectID, userID); err != nil {
+			return err
+		}
+	default:
+		return errors.New("visibility must be private, project or public")
+	}
+	return nil
+}
+
+// compile parses and compiles query for user
+func (s *Service) compile(query string, userID int64) (*tql.SQL, error) {
+	q, err := tql.Parse(query)
+	if err != nil {
+		return nil, err
+	}
+	return tql.Compile(q, tql.Env{UserID: userID, Now: s.now()})
+}
+
+// ListFilters returns filters visible to user
+func (s *Service) ListFilters(ctx context.Context, userID int64, favouritesOnly bool) ([]Filter, error) {
+	filters, err := s.repo.ListVisible(ctx, userID, favouritesOnly)
+	if err != nil {
+		return nil, err
+	}
+	if filters == nil {
+		filters = []Filter{}
+	}
+	return filters, nil
+}
+
+// GetFilterByID returns filter if user owns it or it is shared with user
+func (s *Service) GetFilterByID(ctx context.Context, filterID, userID int64) (*Filter, error) {
+	f, err := s.repo.GetByID(ctx, filterID, userID)
+	if err != nil {
+		return nil, errors.New("filter not found")
+	}
+	return f, nil
+}
+
+// ownFilter returns filter if user is its owner
+func (s *Service) ownFilter(ctx context.Context, filterID, userID int64) (*Filter, error) {
+	f, err := s.GetFilterByID(ctx, filterID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if f.OwnerID != userID {
+		return nil, errors.New("only owner can change filter")
+	}
+	return f, nil
+}
+
+// UpdateFilterRequest DTO for updating filter
+type UpdateFilterRequest struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	Query       *string `json:"query"`
+	Visibility  *string `json:"visibility"`
+	ProjectID   **int64 `json:"project_id"`
+}
+
+// UpdateFilter edits own filter
+func (s *Service) UpdateFilter(ctx context.Context, req UpdateFilterRequest, filterID, userID int64) (*Filter, error) {
+	f, err := s.ownFilter(ctx, filterID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		f.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Description != nil {
+		f.Description = *req.Description
+	}
+	if req.Query != nil {
+		f.Query = strings.TrimSpace(*req.Query)
+	}
+	if req.Visibility != nil {
+		f.Visibility = *req.Visibility
+	}
+	if req.ProjectID != nil {
+		f.ProjectID = *req.ProjectID
+	}
+	if err := s.validate(ctx, f, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, f); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// DeleteFilter removes own filter
+func (s *Service) DeleteFilter(ctx context.Context, filterID, userID int64) error {
+	f, err := s.ownFilter(ctx, filterID, userID)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Delete(ctx, f.ID)
+}
+
+// SetFavourite marks or unmarks visible filter as favourite of user
+func (s *Service) SetFavourite(ctx context.Context, filterID, userID int64, favourite bool) error {
+	f, err := s.GetFilterByID(ctx, filterID, userID)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.SetFavourite(ctx, userID, f.ID, favourite)
+}
+
+// RunQuery returns tickets matching query in projects of user
+func (s *Service) RunQuery(ctx context.Context, query string, userID int64, limit, offset int) (*QueryResult, error) {
+	compiled, err := s.compile(query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	tickets, total, err := s.repo.RunQuery(ctx, userID, compiled, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return &QueryResult{Query: query, Total: total, Limit: limit, Offset: offset, Tickets: tickets}, nil
+}
+
+// RunFilter runs saved filter for user, currentUser() means user who runs it
+func (s *Service) RunFilter(ctx context.Context, filterID, userID int64, limit, offset int) (*QueryResult, error) {
+	f, err := s.GetFilterByID(ctx, filterID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.RunQuery(ctx, f.Query, userID, limit, offset)
+}
@@ -0,0 +1,115 @@
+package filter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/antonovs105/project-management-system-go/internal/project"
+	"github.com/antonovs105/project-management-system-go/internal/ticket"
+	"github.com/antonovs105/project-management-system-go/internal/tql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestService_CreateFilter(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockProject := new(MockProjectChecker)
+	service := NewService(mockRepo, mockProject)
+
+	ctx := context.Background()
+	userID := int64(1)
+	projectID := int64(10)
+
+	t.Run("SharedWithProject", func(t *testing.T) {
+		req := FilterRequest{
+			Name:       " My open work ",
+			Query:      "assignee = currentUser() AND status != done",
+			Visibility: VisibilityProject,
+			ProjectID:  &projectID,
+		}
+		mockProject.On("GetProjectByID", ctx, projectID, userID).Return(&project.Project{}, nil).Once()
+		mockRepo.On("Create", ctx, mock.MatchedBy(func(f *Filter) bool {
+			return f.Name == "My open work" && f.OwnerID == userID && *f.ProjectID == projectID
+		})).Return(nil).Once()
+
+		f, err := service.CreateFilter(ctx, req, userID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, VisibilityProject, f.Visibility)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("PrivateByDefault", func(t *testing.T) {
+		req := FilterRequest{Name: "Bugs", Query: "type = bug", ProjectID: &projectID}
+		mockRepo.On("Create", ctx, mock.MatchedBy(func(f *Filter) bool {
+			return f.Name == "Bugs"
+		})).Return(nil).Once()
+
+		f, err := service.CreateFilter(ctx, req, userID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, VisibilityPrivate, f.Visibility)
+		assert.Nil(t, f.ProjectID)
+	})
+
+	t.Run("InvalidQuery", func(t *testing.T) {
+		_, err := service.CreateFilter(ctx, FilterRequest{Name: "Broken", Query: "status = "}, userID)
+
+		var qe *tql.Error
+		assert.True(t, errors.As(err, &qe))
+	})
+
+	t.Run("ProjectAccessDenied", func(t *testing.T) {
+		other := int64(11)
+		req := FilterRequest{Name: "Theirs", Query: "type = bug", Visibility: VisibilityProject, ProjectID: &other}
+		mockProject.On("GetProjectByID", ctx, other, userID).Return(nil, errors.New("access denied")).Once()
+
+		_, err := service.CreateFilter(ctx, req, userID)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestService_UpdateFilter(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockProject := new(MockProjectChecker)
+	service := NewService(mockRepo, mockProject)
+
+	ctx := context.Background()
+	userID := int64(1)
+
+	t.Run("NotOwner", func(t *testing.T) {
+		mockRepo.On("GetByID", ctx, int64(5), userID).Return(&Filter{ID: 5, OwnerID: 2, Visibility: VisibilityPublic}, nil).Once()
+		name := "Renamed"
+
+		_, err := service.UpdateFilter(ctx, UpdateFilterRequest{Name: &name}, 5, userID)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "only owner")
+	})
+}
+
+func TestService_RunFilter(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockProject := new(MockProjectChecker)
+	service := NewService(mockRepo, mockProject)
+
+	ctx := context.Background()
+	userID := int64(1)
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo.On("GetByID", ctx, int64(5), userID).Return(&Filter{ID: 5, OwnerID: 2, Query: "assignee = currentUser()"}, nil).Once()
+		mockRepo.On("RunQuery", ctx, userID, mock.MatchedBy(func(q *tql.SQL) bool {
+			// currentUser() is user who runs filter, not its owner
+			return len(q.Args) == 1 && q.Args[0] == userID
+		}), maxLimit, 0).Return([]ticket.Ticket{{ID: 100}}, 1, nil).Once()
+
+		result, err := service.RunFilter(ctx, 5, userID, 1000, -1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Total)
+		assert.Len(t, result.Tickets, 1)
+		mockRepo.AssertExpectations(t)
+	})
+}
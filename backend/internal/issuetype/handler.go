@@ -0,0 +1,60 @@
+package issuetype
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/antonovs105/project-management-system-go/internal/project"
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// Get handler for GET /api/projects/:id/issue-types
+func (h *Handler) Get(c echo.Context) error {
+	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid project ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	scheme, err := h.service.GetScheme(c.Request().Context(), projectID, userID)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, scheme)
+}
+
+type updateSchemeRequest struct {
+	Types []IssueTypeRequest `json:"types"`
+}
+
+// Update handler for PUT /api/projects/:id/issue-types
+func (h *Handler) Update(c echo.Context) error {
+	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid project ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	var req updateSchemeRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+	}
+
+	scheme, err := h.service.UpdateScheme(c.Request().Context(), projectID, userID, req.Types)
+	if errors.Is(err, project.ErrForbidden) {
+		return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, scheme)
+}
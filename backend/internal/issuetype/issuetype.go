@@ -0,0 +1,81 @@
+package issuetype
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ErrTypeInUse is returned when new scheme drops type which tickets still have
+var ErrTypeInUse = errors.New("is used by tickets and can't be removed")
+
+type IssueType struct {
+	ID             int64          `db:"id" json:"id"`
+	ProjectID      int64          `db:"project_id" json:"project_id"`
+	Name           string         `db:"name" json:"name"`
+	Rank           int            `db:"rank" json:"rank"`
+	Icon           string         `db:"icon" json:"icon"`
+	Color          string         `db:"color" json:"color"`
+	RequiresParent bool           `db:"requires_parent" json:"requires_parent"`
+	AllowedParents pq.StringArray `db:"allowed_parents" json:"allowed_parents"`
+	CreatedAt      time.Time      `db:"created_at" json:"created_at"`
+}
+
+// Scheme is set of issue types used by project
+type Scheme struct {
+	Types []IssueType `json:"types"`
+}
+
+// DefaultScheme is used by projects without own scheme (Epic > Task > Subtask)
+func DefaultScheme() *Scheme {
+	return &Scheme{Types: []IssueType{
+		{Name: "epic", Rank: 3, Icon: "zap", Color: "#9333ea", AllowedParents: pq.StringArray{}},
+		{Name: "task", Rank: 2, Icon: "check-square", Color: "#2563eb", AllowedParents: pq.StringArray{}},
+		{Name: "subtask", Rank: 1, Icon: "list", Color: "#64748b", RequiresParent: true, AllowedParents: pq.StringArray{}},
+	}}
+}
+
+// Get finds type by name
+func (s *Scheme) Get(name string) (*IssueType, bool) {
+	for i := range s.Types {
+		if s.Types[i].Name == name {
+			return &s.Types[i], true
+		}
+	}
+	return nil, false
+}
+
+// DefaultType is type for tickets created without one: "task" if present,
+// otherwise lowest rank type that can live without parent
+func (s *Scheme) DefaultType() string {
+	if _, ok := s.Get("task"); ok {
+		return "task"
+	}
+	result := ""
+	lowest := 0
+	for _, t := range s.Types {
+		if !t.RequiresParent && (result == "" || t.Rank < lowest) {
+			result = t.Name
+			lowest = t.Rank
+		}
+	}
+	return result
+}
+
+// CanParent checks if ticket of parentType can be parent of ticket of childType
+func (s *Scheme) CanParent(parentType, childType string) bool {
+	parent, ok := s.Get(parentType)
+	if !ok {
+		return false
+	}
+	child, ok := s.Get(childType)
+	if !ok {
+		return false
+	}
+	if len(child.AllowedParents) > 0 {
+		return slices.Contains(child.AllowedParents, parent.Name)
+	}
+	return parent.Rank > child.Rank
+}
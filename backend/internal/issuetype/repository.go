@@ -0,0 +1,81 @@
+package issuetype
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type Repository interface {
+	ListByProjectID(ctx context.Context, projectID int64) ([]IssueType, error)
+	ReplaceForProject(ctx context.Context, projectID int64, types []IssueType) error
+}
+
+type PgRepository struct {
+	db *sqlx.DB
+}
+
+func NewRepository(db *sqlx.DB) Repository {
+	return &PgRepository{db: db}
+}
+
+// ListByProjectID returns issue types of project ordered from highest rank
+func (r *PgRepository) ListByProjectID(ctx context.Context, projectID int64) ([]IssueType, error) {
+	var types []IssueType
+	query := `SELECT * FROM issue_types WHERE project_id = $1 ORDER BY rank DESC, name`
+
+	err := r.db.SelectContext(ctx, &types, query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return types, nil
+}
+
+// ReplaceForProject swaps whole scheme of project in one transaction. Project row is locked, so
+// tickets can't get a type between the in-use check and the swap
+func (r *PgRepository) ReplaceForProject(ctx context.Context, projectID int64, types []IssueType) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT 1 FROM projects WHERE id = $1 FOR UPDATE`, projectID); err != nil {
+		return err
+	}
+
+	names := make([]string, len(types))
+	for i, t := range types {
+		names[i] = t.Name
+	}
+	var used []string
+	query := `
+		SELECT DISTINCT type FROM tickets
+		WHERE project_id = $1 AND type IS NOT NULL AND NOT (type = ANY($2))
+		ORDER BY type`
+	if err := tx.SelectContext(ctx, &used, query, projectID, pq.Array(names)); err != nil {
+		return err
+	}
+	if len(used) > 0 {
+		return fmt.Errorf("type %q %w", used[0], ErrTypeInUse)
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM issue_types WHERE project_id = $1`, projectID)
+	if err != nil {
+		return err
+	}
+
+	query = `
+		INSERT INTO issue_types (project_id, name, rank, icon, color, requires_parent, allowed_parents)
+		VALUES (:project_id, :name, :rank, :icon, :color, :requires_parent, :allowed_parents)`
+	for i := range types {
+		types[i].ProjectID = projectID
+		if _, err := tx.NamedExecContext(ctx, query, &types[i]); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,47 @@
+package issuetype
+
+import (
+	"context"
+
+	"github.com/antonovs105/project-management-system-go/internal/project"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRepository is a mock implementation of Repository
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) ListByProjectID(ctx context.Context, projectID int64) ([]IssueType, error) {
+	args := m.Called(ctx, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]IssueType), args.Error(1)
+}
+
+func (m *MockRepository) ReplaceForProject(ctx context.Context, projectID int64, types []IssueType) error {
+	args := m.Called(ctx, projectID, types)
+	return args.Error(0)
+}
+
+// MockProjectChecker
+type MockProjectChecker struct {
+	mock.Mock
+}
+
+func (m *MockProjectChecker) GetProjectByID(ctx context.Context, projectID, userID int64) (*project.Project, error) {
+	args := m.Called(ctx, projectID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*project.Project), args.Error(1)
+}
+
+func (m *MockProjectChecker) GetManagedProject(ctx context.Context, projectID, userID int64) (*project.Project, error) {
+	args := m.Called(ctx, projectID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*project.Project), args.Error(1)
+}
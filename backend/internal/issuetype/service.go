@@ -0,0 +1,154 @@
+package issuetype
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/antonovs105/project-management-system-go/internal/project"
+	"github.com/lib/pq"
+)
+
+// MaxNameLength is limit of type name, tickets.type column holds up to 20 characters
+const MaxNameLength = 20
+
+// ProjectChecker interface
+type ProjectChecker interface {
+	GetProjectByID(ctx context.Context, projectID, userID int64) (*project.Project, error)
+	GetManagedProject(ctx context.Context, projectID, userID int64) (*project.Project, error)
+}
+
+type Service struct {
+	repo           Repository
+	projectService ProjectChecker
+}
+
+func NewService(repo Repository, projectService ProjectChecker) *Service {
+	return &Service{
+		repo:           repo,
+		projectService: projectService,
+	}
+}
+
+// SchemeForProject returns scheme of project or default one. Doesn't check access
+func (s *Service) SchemeForProject(ctx context.Context, projectID int64) (*Scheme, error) {
+	types, err := s.repo.ListByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if len(types) == 0 {
+		return DefaultScheme(), nil
+	}
+	return &Scheme{Types: types}, nil
+}
+
+// GetScheme returns scheme of project for user
+func (s *Service) GetScheme(ctx context.Context, projectID, userID int64) (*Scheme, error) {
+	// check access
+	_, err := s.projectService.GetProjectByID(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.SchemeForProject(ctx, projectID)
+}
+
+// IssueTypeRequest DTO for one type of scheme
+type IssueTypeRequest struct {
+	Name           string   `json:"name"`
+	Rank           int      `json:"rank"`
+	Icon           string   `json:"icon"`
+	Color          string   `json:"color"`
+	RequiresParent bool     `json:"requires_parent"`
+	AllowedParents []string `json:"allowed_parents"`
+}
+
+// UpdateScheme replaces scheme of project, only owners and managers may do it
+func (s *Service) UpdateScheme(ctx context.Context, projectID, userID int64, req []IssueTypeRequest) (*Scheme, error) {
+	// check access
+	_, err := s.projectService.GetManagedProject(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := &Scheme{Types: make([]IssueType, 0, len(req))}
+	for _, r := range req {
+		allowed := pq.StringArray{}
+		for _, p := range r.AllowedParents {
+			allowed = append(allowed, strings.ToLower(strings.TrimSpace(p)))
+		}
+		scheme.Types = append(scheme.Types, IssueType{
+			ProjectID:      projectID,
+			Name:           strings.ToLower(strings.TrimSpace(r.Name)),
+			Rank:           r.Rank,
+			Icon:           r.Icon,
+			Color:          r.Color,
+			RequiresParent: r.RequiresParent,
+			AllowedParents: allowed,
+		})
+	}
+
+	if err := validateScheme(scheme); err != nil {
+		return nil, err
+	}
+
+	// types in use can't be removed, repository checks it under project lock
+	if err := s.repo.ReplaceForProject(ctx, projectID, scheme.Types); err != nil {
+		return nil, err
+	}
+	return scheme, nil
+}
+
+// validateScheme checks names, ranks and parent rules are consistent
+func validateScheme(scheme *Scheme) error {
+	if len(scheme.Types) == 0 {
+		return errors.New("scheme must have at least one type")
+	}
+
+	seen := make(map[string]bool)
+	for _, t := range scheme.Types {
+		if t.Name == "" || utf8.RuneCountInString(t.Name) > MaxNameLength {
+			return fmt.Errorf("type name must be 1-%d characters", MaxNameLength)
+		}
+		if seen[t.Name] {
+			return fmt.Errorf("duplicate type %q", t.Name)
+		}
+		seen[t.Name] = true
+		if t.Rank < 1 {
+			return fmt.Errorf("type %q: rank must be positive", t.Name)
+		}
+	}
+
+	if scheme.DefaultType() == "" {
+		return errors.New("at least one type must be allowed without parent")
+	}
+
+	for _, t := range scheme.Types {
+		for _, p := range t.AllowedParents {
+			parent, ok := scheme.Get(p)
+			if !ok {
+				return fmt.Errorf("type %q: unknown parent type %q", t.Name, p)
+			}
+			// ranks keep hierarchy acyclic and depth bounded
+			if parent.Rank <= t.Rank {
+				return fmt.Errorf("type %q: parent type %q must be of higher rank", t.Name, p)
+			}
+		}
+		if t.RequiresParent && !hasPossibleParent(scheme, t) {
+			return fmt.Errorf("type %q requires parent but no type can parent it", t.Name)
+		}
+	}
+
+	return nil
+}
+
+func hasPossibleParent(scheme *Scheme, child IssueType) bool {
+	for _, t := range scheme.Types {
+		if scheme.CanParent(t.Name, child.Name) {
+			return true
+		}
+	}
+	return false
+}
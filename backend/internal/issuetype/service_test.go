@@ -0,0 +1,115 @@
+package issuetype
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/antonovs105/project-management-system-go/internal/project"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestScheme_CanParent(t *testing.T) {
+	scheme := &Scheme{Types: []IssueType{
+		{Name: "initiative", Rank: 5},
+		{Name: "epic", Rank: 4, AllowedParents: []string{"initiative"}},
+		{Name: "story", Rank: 3},
+		{Name: "bug", Rank: 3},
+		{Name: "subtask", Rank: 1, RequiresParent: true, AllowedParents: []string{"story", "bug"}},
+	}}
+
+	assert.True(t, scheme.CanParent("initiative", "epic"))
+	assert.True(t, scheme.CanParent("epic", "story"))
+	assert.True(t, scheme.CanParent("initiative", "story"))
+	assert.False(t, scheme.CanParent("story", "bug"))
+	assert.True(t, scheme.CanParent("bug", "subtask"))
+	assert.False(t, scheme.CanParent("epic", "subtask"))
+	assert.False(t, scheme.CanParent("unknown", "story"))
+	assert.Equal(t, "story", scheme.DefaultType())
+}
+
+func TestService_SchemeForProject(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewService(mockRepo, new(MockProjectChecker))
+	ctx := context.Background()
+
+	t.Run("DefaultWhenEmpty", func(t *testing.T) {
+		mockRepo.On("ListByProjectID", ctx, int64(1)).Return([]IssueType{}, nil).Once()
+
+		scheme, err := service.SchemeForProject(ctx, 1)
+
+		assert.NoError(t, err)
+		assert.Len(t, scheme.Types, 3)
+		assert.Equal(t, "task", scheme.DefaultType())
+	})
+}
+
+func TestService_UpdateScheme(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockProject := new(MockProjectChecker)
+	service := NewService(mockRepo, mockProject)
+
+	ctx := context.Background()
+	projectID := int64(10)
+	userID := int64(1)
+
+	t.Run("Success", func(t *testing.T) {
+		mockProject.On("GetManagedProject", ctx, projectID, userID).Return(&project.Project{}, nil).Once()
+		mockRepo.On("ReplaceForProject", ctx, projectID, mock.Anything).Return(nil).Once()
+
+		scheme, err := service.UpdateScheme(ctx, projectID, userID, []IssueTypeRequest{
+			{Name: "Story", Rank: 2},
+			{Name: "task", Rank: 2},
+			{Name: "subtask", Rank: 1, RequiresParent: true, AllowedParents: []string{"story"}},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "story", scheme.Types[0].Name)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("UsedTypeRemoved", func(t *testing.T) {
+		mockProject.On("GetManagedProject", ctx, projectID, userID).Return(&project.Project{}, nil).Once()
+		mockRepo.On("ReplaceForProject", ctx, projectID, mock.Anything).Return(fmt.Errorf("type %q %w", "epic", ErrTypeInUse)).Once()
+
+		_, err := service.UpdateScheme(ctx, projectID, userID, []IssueTypeRequest{{Name: "task", Rank: 1}})
+
+		assert.ErrorIs(t, err, ErrTypeInUse)
+		assert.Contains(t, err.Error(), "used by tickets")
+	})
+
+	t.Run("NameLength", func(t *testing.T) {
+		longest := strings.Repeat("я", MaxNameLength)
+		mockProject.On("GetManagedProject", ctx, projectID, userID).Return(&project.Project{}, nil).Twice()
+		mockRepo.On("ReplaceForProject", ctx, projectID, mock.Anything).Return(nil).Once()
+
+		_, err := service.UpdateScheme(ctx, projectID, userID, []IssueTypeRequest{{Name: longest, Rank: 1}})
+		assert.NoError(t, err)
+
+		_, err = service.UpdateScheme(ctx, projectID, userID, []IssueTypeRequest{{Name: longest + "x", Rank: 1}})
+		assert.Error(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("ParentOfLowerRank", func(t *testing.T) {
+		mockProject.On("GetManagedProject", ctx, projectID, userID).Return(&project.Project{}, nil).Once()
+
+		_, err := service.UpdateScheme(ctx, projectID, userID, []IssueTypeRequest{
+			{Name: "epic", Rank: 3, AllowedParents: []string{"task"}},
+			{Name: "task", Rank: 2},
+		})
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "higher rank")
+	})
+
+	t.Run("Forbidden", func(t *testing.T) {
+		mockProject.On("GetManagedProject", ctx, projectID, userID).Return(nil, project.ErrForbidden).Once()
+
+		_, err := service.UpdateScheme(ctx, projectID, userID, []IssueTypeRequest{{Name: "task", Rank: 1}})
+
+		assert.ErrorIs(t, err, project.ErrForbidden)
+	})
+}
@@ -0,0 +1,76 @@
+package job
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/antonovs105/project-management-system-go/internal/pagination"
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// errorStatus maps service error to HTTP status, fallback is used for unknown errors
+func errorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrNotFailed):
+		return http.StatusConflict
+	case errors.Is(err, ErrBadStatus):
+		return http.StatusBadRequest
+	default:
+		return fallback
+	}
+}
+
+// List handler for GET /api/admin/jobs, ?status=failed shows failed jobs only
+func (h *Handler) List(c echo.Context) error {
+	page, err := pagination.Parse(c.QueryParams(), DefaultSort, SortFields)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	result, err := h.service.ListJobs(c.Request().Context(), c.QueryParam("status"), page)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	pagination.SetHeaders(c.Response().Header(), c.Request().URL, result.Total, result.Next)
+	return c.JSON(http.StatusOK, result.Items)
+}
+
+// Get handler for GET /api/admin/jobs/:id
+func (h *Handler) Get(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid job ID"})
+	}
+
+	j, err := h.service.GetJob(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, j)
+}
+
+// Retry handler for POST /api/admin/jobs/:id/retry
+func (h *Handler) Retry(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid job ID"})
+	}
+
+	j, err := h.service.RetryJob(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, j)
+}
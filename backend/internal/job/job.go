@@ -0,0 +1,101 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Job statuses
+const (
+	StatusPending = "pending"
+	StatusRunning = "running"
+	StatusDone    = "done"
+	StatusFailed  = "failed"
+)
+
+// DefaultMaxAttempts before job is failed
+const DefaultMaxAttempts = 5
+
+var (
+	ErrNotFound  = errors.New("job not found")
+	ErrNotFailed = errors.New("only failed jobs can be retried")
+	ErrBadStatus = errors.New("status must be pending, running, done or failed")
+	ErrNoHandler = errors.New("no handler for job type")
+)
+
+// Job is one unit of background work of some type. Payload is passed to handler of the type
+type Job struct {
+	ID          int64           `db:"id" json:"id"`
+	Type        string          `db:"type" json:"type"`
+	Payload     json.RawMessage `db:"payload" json:"payload"`
+	Status      string          `db:"status" json:"status"`
+	Attempts    int             `db:"attempts" json:"attempts"`
+	MaxAttempts int             `db:"max_attempts" json:"max_attempts"`
+	RunAt       time.Time       `db:"run_at" json:"run_at"`
+	LockedUntil *time.Time      `db:"locked_until" json:"-"`
+	LastError   *string         `db:"last_error" json:"last_error"`
+	CreatedAt   time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time       `db:"updated_at" json:"updated_at"`
+	FinishedAt  *time.Time      `db:"finished_at" json:"finished_at"`
+}
+
+// Schedule enqueues job of Type every time Spec fires
+type Schedule struct {
+	Name      string          `db:"name" json:"name"`
+	Spec      string          `db:"spec" json:"spec"`
+	Type      string          `db:"type" json:"type"`
+	Payload   json.RawMessage `db:"payload" json:"payload"`
+	NextRunAt time.Time       `db:"next_run_at" json:"next_run_at"`
+	LastRunAt *time.Time      `db:"last_run_at" json:"last_run_at"`
+}
+
+// Options of enqueued job, zero values mean run now with DefaultMaxAttempts
+type Options struct {
+	RunAt       time.Time
+	MaxAttempts int
+}
+
+// Enqueue adds job to queue. Pass transaction as db to enqueue job only if it commits
+func Enqueue(ctx context.Context, db sqlx.ExtContext, jobType string, payload any, opts Options) (int64, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return 0, err
+	}
+	if opts.RunAt.IsZero() {
+		opts.RunAt = time.Now()
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = DefaultMaxAttempts
+	}
+
+	var id int64
+	query := `INSERT INTO jobs (type, payload, max_attempts, run_at) VALUES ($1, $2, $3, $4) RETURNING id`
+	err = sqlx.GetContext(ctx, db, &id, query, jobType, string(data), opts.MaxAttempts, opts.RunAt)
+	return id, err
+}
+
+// permanentError is failure retrying won't fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks handler error so job is failed at once without retries
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was marked by Permanent
+func IsPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
@@ -0,0 +1,197 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/pagination"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type Repository interface {
+	Claim(ctx context.Context, types []string, limit int, lease time.Duration) ([]Job, error)
+	ExtendLease(ctx context.Context, ids []int64, lease time.Duration) error
+	Complete(ctx context.Context, j *Job) error
+	Retry(ctx context.Context, j *Job, delay time.Duration, errMsg string) error
+	Fail(ctx context.Context, j *Job, errMsg string) error
+	GetByID(ctx context.Context, id int64) (*Job, error)
+	ListPage(ctx context.Context, status string, page pagination.Params) ([]Job, int, error)
+	Requeue(ctx context.Context, id int64) error
+	DeleteFinished(ctx context.Context, before time.Time) (int64, error)
+	SaveSchedule(ctx context.Context, s *Schedule) error
+	FireSchedules(ctx context.Context, names []string, next func(s *Schedule, now time.Time) time.Time) (int, error)
+}
+
+type PgRepository struct {
+	db *sqlx.DB
+}
+
+func NewRepository(db *sqlx.DB) Repository {
+	return &PgRepository{db: db}
+}
+
+// Claim takes due pending jobs of given types and running jobs whose lease expired, because
+// their worker crashed. Claimed jobs are running for lease and count one more attempt
+func (r *PgRepository) Claim(ctx context.Context, types []string, limit int, lease time.Duration) ([]Job, error) {
+	var jobs []Job
+	query := `
+		UPDATE jobs
+		SET status = 'running', attempts = attempts + 1,
+			locked_until = now() + $3 * interval '1 millisecond', updated_at = now()
+		WHERE id IN (
+			SELECT id FROM jobs
+			WHERE type = ANY($1)
+				AND ((status = 'pending' AND run_at <= now()) OR (status = 'running' AND locked_until <= now()))
+			ORDER BY run_at, id
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`
+
+	err := r.db.SelectContext(ctx, &jobs, query, pq.Array(types), limit, lease.Milliseconds())
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// ExtendLease keeps running jobs hidden from other workers for another lease
+func (r *PgRepository) ExtendLease(ctx context.Context, ids []int64, lease time.Duration) error {
+	query := `
+		UPDATE jobs SET locked_until = now() + $2 * interval '1 millisecond'
+		WHERE id = ANY($1) AND status = 'running'`
+	_, err := r.db.ExecContext(ctx, query, pq.Array(ids), lease.Milliseconds())
+	return err
+}
+
+// finish updates job only if it is still the same attempt, so worker which lost its lease
+// doesn't overwrite result of worker that took job over
+func (r *PgRepository) finish(ctx context.Context, j *Job, set string, args ...any) error {
+	args = append([]any{j.ID, j.Attempts}, args...)
+	query := `UPDATE jobs SET ` + set + `, locked_until = NULL, updated_at = now()
+		WHERE id = $1 AND attempts = $2 AND status = 'running'`
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+// Complete marks job done
+func (r *PgRepository) Complete(ctx context.Context, j *Job) error {
+	return r.finish(ctx, j, `status = 'done', last_error = NULL, finished_at = now()`)
+}
+
+// Retry returns job to queue to run again after delay
+func (r *PgRepository) Retry(ctx context.Context, j *Job, delay time.Duration, errMsg string) error {
+	return r.finish(ctx, j, `status = 'pending', run_at = now() + $3 * interval '1 millisecond', last_error = $4`,
+		delay.Milliseconds(), errMsg)
+}
+
+// Fail marks job failed, it stays for inspection until retried by admin
+func (r *PgRepository) Fail(ctx context.Context, j *Job, errMsg string) error {
+	return r.finish(ctx, j, `status = 'failed', last_error = $3, finished_at = now()`, errMsg)
+}
+
+// GetByID finds job by its id
+func (r *PgRepository) GetByID(ctx context.Context, id int64) (*Job, error) {
+	var j Job
+	err := r.db.GetContext(ctx, &j, `SELECT * FROM jobs WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
+
+// ListPage returns page of jobs with status, all when empty (limit+1 rows to detect next page) and total count
+func (r *PgRepository) ListPage(ctx context.Context, status string, page pagination.Params) ([]Job, int, error) {
+	where := "TRUE"
+	var args []any
+	if status != "" {
+		args = append(args, status)
+		where = "status = $1"
+	}
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM jobs WHERE `+where, args...); err != nil {
+		return nil, 0, err
+	}
+
+	keyset, orderBy, args := page.Keyset(SortFields, "id", args)
+	args = append(args, page.Limit+1)
+	query := fmt.Sprintf(`SELECT * FROM jobs WHERE %s AND %s ORDER BY %s LIMIT $%d`, where, keyset, orderBy, len(args))
+
+	var jobs []Job
+	if err := r.db.SelectContext(ctx, &jobs, query, args...); err != nil {
+		return nil, 0, err
+	}
+	return jobs, total, nil
+}
+
+// Requeue puts failed job back to queue with fresh attempts
+func (r *PgRepository) Requeue(ctx context.Context, id int64) error {
+	query := `
+		UPDATE jobs
+		SET status = 'pending', attempts = 0, run_at = now(), finished_at = NULL, updated_at = now()
+		WHERE id = $1 AND status = 'failed'`
+	res, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFailed
+	}
+	return nil
+}
+
+// DeleteFinished deletes done jobs finished before given time, failed jobs are kept
+func (r *PgRepository) DeleteFinished(ctx context.Context, before time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM jobs WHERE status = 'done' AND finished_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// SaveSchedule creates or updates schedule. Planned run is kept unless spec changed
+func (r *PgRepository) SaveSchedule(ctx context.Context, s *Schedule) error {
+	query := `
+		INSERT INTO job_schedules (name, spec, type, payload, next_run_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (name) DO UPDATE
+		SET spec = EXCLUDED.spec, type = EXCLUDED.type, payload = EXCLUDED.payload,
+			next_run_at = CASE WHEN job_schedules.spec = EXCLUDED.spec
+				THEN job_schedules.next_run_at ELSE EXCLUDED.next_run_at END`
+	_, err := r.db.ExecContext(ctx, query, s.Name, s.Spec, s.Type, string(s.Payload), s.NextRunAt)
+	return err
+}
+
+// FireSchedules enqueues job for every due schedule of given names and plans its next run.
+// Schedules are locked, so only one of many workers fires each of them
+func (r *PgRepository) FireSchedules(ctx context.Context, names []string, next func(s *Schedule, now time.Time) time.Time) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var schedules []Schedule
+	query := `
+		SELECT * FROM job_schedules
+		WHERE next_run_at <= now() AND name = ANY($1)
+		FOR UPDATE SKIP LOCKED`
+	if err := tx.SelectContext(ctx, &schedules, query, pq.Array(names)); err != nil {
+		return 0, err
+	}
+
+	for i := range schedules {
+		s := &schedules[i]
+		if _, err := Enqueue(ctx, tx, s.Type, s.Payload, Options{}); err != nil {
+			return 0, err
+		}
+		update := `UPDATE job_schedules SET next_run_at = $2, last_run_at = now() WHERE name = $1`
+		if _, err := tx.ExecContext(ctx, update, s.Name, next(s, time.Now())); err != nil {
+			return 0, err
+		}
+	}
+	return len(schedules), tx.Commit()
+}
@@ -0,0 +1,78 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/pagination"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRepository is a mock implementation of Repository
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Claim(ctx context.Context, types []string, limit int, lease time.Duration) ([]Job, error) {
+	args := m.Called(ctx, types, limit, lease)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Job), args.Error(1)
+}
+
+func (m *MockRepository) ExtendLease(ctx context.Context, ids []int64, lease time.Duration) error {
+	args := m.Called(ctx, ids, lease)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Complete(ctx context.Context, j *Job) error {
+	args := m.Called(ctx, j)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Retry(ctx context.Context, j *Job, delay time.Duration, errMsg string) error {
+	args := m.Called(ctx, j, delay, errMsg)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Fail(ctx context.Context, j *Job, errMsg string) error {
+	args := m.Called(ctx, j, errMsg)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetByID(ctx context.Context, id int64) (*Job, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Job), args.Error(1)
+}
+
+func (m *MockRepository) ListPage(ctx context.Context, status string, page pagination.Params) ([]Job, int, error) {
+	args := m.Called(ctx, status, page)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]Job), args.Int(1), args.Error(2)
+}
+
+func (m *MockRepository) Requeue(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRepository) DeleteFinished(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRepository) SaveSchedule(ctx context.Context, s *Schedule) error {
+	args := m.Called(ctx, s)
+	return args.Error(0)
+}
+
+func (m *MockRepository) FireSchedules(ctx context.Context, names []string, next func(s *Schedule, now time.Time) time.Time) (int, error) {
+	args := m.Called(ctx, names, next)
+	return args.Int(0), args.Error(1)
+}
@@ -0,0 +1,281 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Runner settings
+const (
+	pollInterval = time.Second
+	// lease is how long claimed job is hidden from other workers, runner extends it while job runs
+	lease = time.Minute
+	// shutdownTimeout is how long in-flight jobs may finish after runner is stopped
+	shutdownTimeout = 30 * time.Second
+	firstRetryDelay = 10 * time.Second
+	maxRetryDelay   = time.Hour
+)
+
+// RetryDelay is exponential backoff after failed attempt: 10s, 20s, 40s ... capped at 1h
+func RetryDelay(attempt int) time.Duration {
+	delay := firstRetryDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= maxRetryDelay {
+			return maxRetryDelay
+		}
+	}
+	return delay
+}
+
+// HandlerFunc runs job. Returned error retries job, unless it is Permanent
+type HandlerFunc func(ctx context.Context, j *Job) error
+
+// Runner claims jobs of registered types and runs up to concurrency of them at once
+type Runner struct {
+	repo        Repository
+	concurrency int
+	handlers    map[string]HandlerFunc
+	schedules   []Schedule
+	specs       map[string]Spec
+
+	pollInterval    time.Duration
+	lease           time.Duration
+	shutdownTimeout time.Duration
+
+	mu      sync.Mutex
+	running map[int64]struct{}
+}
+
+func NewRunner(repo Repository, concurrency int) *Runner {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &Runner{
+		repo:            repo,
+		concurrency:     concurrency,
+		handlers:        make(map[string]HandlerFunc),
+		specs:           make(map[string]Spec),
+		pollInterval:    pollInterval,
+		lease:           lease,
+		shutdownTimeout: shutdownTimeout,
+		running:         make(map[int64]struct{}),
+	}
+}
+
+// HandleFunc registers handler of job type
+func (r *Runner) HandleFunc(jobType string, fn HandlerFunc) {
+	r.handlers[jobType] = fn
+}
+
+// Handle registers handler of job type which gets payload decoded into T.
+// Payload that can't be decoded fails job without retries
+func Handle[T any](r *Runner, jobType string, fn func(ctx context.Context, payload T) error) {
+	r.HandleFunc(jobType, func(ctx context.Context, j *Job) error {
+		var payload T
+		if err := json.Unmarshal(j.Payload, &payload); err != nil {
+			return Permanent(fmt.Errorf("decode payload: %w", err))
+		}
+		return fn(ctx, payload)
+	})
+}
+
+// Schedule registers recurring job, it is enqueued with payload whenever spec fires.
+// Job type must have handler when runner starts
+func (r *Runner) Schedule(name, spec, jobType string, payload any) error {
+	parsed, err := ParseSpec(spec)
+	if err != nil {
+		return err
+	}
+	if parsed.Next(time.Now().UTC()).IsZero() {
+		return fmt.Errorf("schedule %q never fires", name)
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	r.specs[name] = parsed
+	r.schedules = append(r.schedules, Schedule{Name: name, Spec: spec, Type: jobType, Payload: data})
+	return nil
+}
+
+// Run saves schedules and runs jobs until ctx is done. Then it stops claiming jobs and waits
+// for in-flight ones; those still running after shutdown timeout are cancelled
+func (r *Runner) Run(ctx context.Context) error {
+	for i := range r.schedules {
+		s := &r.schedules[i]
+		if _, ok := r.handlers[s.Type]; !ok {
+			return fmt.Errorf("schedule %q: %w %q", s.Name, ErrNoHandler, s.Type)
+		}
+		s.NextRunAt = r.next(s, time.Now())
+		if err := r.repo.SaveSchedule(ctx, s); err != nil {
+			return err
+		}
+	}
+
+	types := make([]string, 0, len(r.handlers))
+	for t := range r.handlers {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	// jobs outlive ctx, so they can finish after stop
+	jobCtx, cancelJobs := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelJobs()
+	slots := make(chan struct{}, r.concurrency)
+	var wg sync.WaitGroup
+
+	poll := time.NewTicker(r.pollInterval)
+	defer poll.Stop()
+	heartbeat := time.NewTicker(r.lease / 3)
+	defer heartbeat.Stop()
+
+	for ctx.Err() == nil {
+		select {
+		case <-ctx.Done():
+		case <-poll.C:
+			r.poll(ctx, jobCtx, types, slots, &wg)
+		case <-heartbeat.C:
+			r.extendLeases(jobCtx)
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	timeout := time.NewTimer(r.shutdownTimeout)
+	defer timeout.Stop()
+
+	for {
+		select {
+		case <-done:
+			return nil
+		case <-heartbeat.C:
+			r.extendLeases(jobCtx)
+		case <-timeout.C:
+			log.Printf("Job runner: cancelling jobs still running after %s", r.shutdownTimeout)
+			cancelJobs()
+		}
+	}
+}
+
+// scheduleNames lists registered schedules, others in DB belong to other runners or were removed
+func (r *Runner) scheduleNames() []string {
+	names := make([]string, 0, len(r.schedules))
+	for _, s := range r.schedules {
+		names = append(names, s.Name)
+	}
+	return names
+}
+
+// next plans run of schedule after now
+func (r *Runner) next(s *Schedule, now time.Time) time.Time {
+	return r.specs[s.Name].Next(now.UTC())
+}
+
+// poll fires due schedules and claims jobs for free slots
+func (r *Runner) poll(ctx, jobCtx context.Context, types []string, slots chan struct{}, wg *sync.WaitGroup) {
+	if len(r.schedules) > 0 {
+		if _, err := r.repo.FireSchedules(ctx, r.scheduleNames(), r.next); err != nil {
+			log.Printf("Job runner: firing schedules failed: %v", err)
+		}
+	}
+
+	free := cap(slots) - len(slots)
+	if free == 0 {
+		return
+	}
+	jobs, err := r.repo.Claim(ctx, types, free, r.lease)
+	if err != nil {
+		log.Printf("Job runner: claiming jobs failed: %v", err)
+		return
+	}
+
+	for i := range jobs {
+		j := &jobs[i]
+		slots <- struct{}{}
+		r.mu.Lock()
+		r.running[j.ID] = struct{}{}
+		r.mu.Unlock()
+
+		wg.Add(1)
+		go func() {
+			defer func() {
+				r.mu.Lock()
+				delete(r.running, j.ID)
+				r.mu.Unlock()
+				<-slots
+				wg.Done()
+			}()
+			r.execute(jobCtx, j)
+		}()
+	}
+}
+
+// extendLeases keeps leases of running jobs
+func (r *Runner) extendLeases(ctx context.Context) {
+	r.mu.Lock()
+	ids := make([]int64, 0, len(r.running))
+	for id := range r.running {
+		ids = append(ids, id)
+	}
+	r.mu.Unlock()
+
+	if len(ids) == 0 {
+		return
+	}
+	if err := r.repo.ExtendLease(ctx, ids, r.lease); err != nil {
+		log.Printf("Job runner: extending leases failed: %v", err)
+	}
+}
+
+// execute runs job and records result: done, retry later or failed
+func (r *Runner) execute(ctx context.Context, j *Job) {
+	var err error
+	if j.Attempts > j.MaxAttempts {
+		// worker died on every attempt, job never got to record failure
+		err = Permanent(fmt.Errorf("lease expired after %d attempts", j.MaxAttempts))
+	} else {
+		err = r.call(ctx, j)
+	}
+
+	// result is recorded even if jobs were cancelled on shutdown
+	ctx = context.WithoutCancel(ctx)
+	switch {
+	case err == nil:
+		err = r.repo.Complete(ctx, j)
+	case IsPermanent(err) || j.Attempts >= j.MaxAttempts:
+		log.Printf("Job %d (%s) failed: %v", j.ID, j.Type, err)
+		err = r.repo.Fail(ctx, j, err.Error())
+	default:
+		err = r.repo.Retry(ctx, j, RetryDelay(j.Attempts), err.Error())
+	}
+	if err != nil {
+		log.Printf("Job %d (%s): saving result failed: %v", j.ID, j.Type, err)
+	}
+}
+
+// call runs handler of job, panic is turned into error
+func (r *Runner) call(ctx context.Context, j *Job) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			log.Printf("Job %d (%s) panicked: %v\n%s", j.ID, j.Type, p, debug.Stack())
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+
+	handler, ok := r.handlers[j.Type]
+	if !ok {
+		return Permanent(fmt.Errorf("%w %q", ErrNoHandler, j.Type))
+	}
+	return handler(ctx, j)
+}
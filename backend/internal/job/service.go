@@ -0,0 +1,73 @@
+package job
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/pagination"
+)
+
+// DefaultSort of job list, newest first
+const DefaultSort = "-created"
+
+// SortFields are fields jobs can be sorted by
+var SortFields = map[string]pagination.SortField{
+	"created": {Column: "created_at", Cast: "timestamptz"},
+	"run_at":  {Column: "run_at", Cast: "timestamptz"},
+}
+
+// Service lets admins inspect and retry jobs
+type Service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// ListJobs returns page of jobs with status, all when status is empty
+func (s *Service) ListJobs(ctx context.Context, status string, page pagination.Params) (*pagination.Page[Job], error) {
+	switch status {
+	case "", StatusPending, StatusRunning, StatusDone, StatusFailed:
+	default:
+		return nil, ErrBadStatus
+	}
+
+	jobs, total, err := s.repo.ListPage(ctx, status, page)
+	if err != nil {
+		return nil, err
+	}
+	return pagination.NewPage(jobs, total, page, func(j *Job) pagination.Cursor {
+		if page.Sort == "run_at" {
+			return page.Cursor(j.RunAt.Format(time.RFC3339Nano), j.ID)
+		}
+		return page.Cursor(j.CreatedAt.Format(time.RFC3339Nano), j.ID)
+	}), nil
+}
+
+// GetJob returns job by id
+func (s *Service) GetJob(ctx context.Context, id int64) (*Job, error) {
+	j, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return j, err
+}
+
+// RetryJob puts failed job back to queue
+func (s *Service) RetryJob(ctx context.Context, id int64) (*Job, error) {
+	if _, err := s.GetJob(ctx, id); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Requeue(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.GetJob(ctx, id)
+}
+
+// Prune deletes done jobs finished more than maxAge ago
+func (s *Service) Prune(ctx context.Context, maxAge time.Duration) (int64, error) {
+	return s.repo.DeleteFinished(ctx, time.Now().Add(-maxAge))
+}
@@ -0,0 +1,318 @@
+package job
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/pagination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestParseSpec_Next(t *testing.T) {
+	tests := []struct {
+		spec  string
+		after string
+		want  string
+	}{
+		{"*/15 * * * *", "2024-03-10 10:07", "2024-03-10 10:15"},
+		{"*/15 * * * *", "2024-03-10 10:45", "2024-03-10 11:00"},
+		{"0 9 * * 1-5", "2024-03-08 09:00", "2024-03-11 09:00"}, // friday -> monday
+		{"30 2 1 * *", "2024-01-31 12:00", "2024-02-01 02:30"},
+		{"0 0 29 2 *", "2023-03-01 00:00", "2024-02-29 00:00"},
+		{"0 12 13 * 5", "2024-03-10 00:00", "2024-03-13 12:00"}, // 13th or friday, whichever comes first
+		{"0 0 * * 7", "2024-03-10 00:00", "2024-03-17 00:00"},   // 7 is sunday
+		{"5,10-12/2 * * * *", "2024-03-10 10:06", "2024-03-10 10:10"},
+		{"@hourly", "2024-03-10 10:00", "2024-03-10 11:00"},
+		{"@daily", "2024-03-10 10:00", "2024-03-11 00:00"},
+		{"@weekly", "2024-03-10 10:00", "2024-03-17 00:00"},
+		{"@monthly", "2024-12-10 10:00", "2025-01-01 00:00"},
+		{"@every 90s", "2024-03-10 10:00", "2024-03-10 10:01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			spec, err := ParseSpec(tt.spec)
+			require.NoError(t, err)
+
+			next := spec.Next(date(tt.after))
+			if tt.spec == "@every 90s" {
+				assert.Equal(t, date(tt.after).Add(90*time.Second), next)
+				return
+			}
+			assert.Equal(t, date(tt.want), next)
+		})
+	}
+}
+
+func TestParseSpec_Invalid(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *",
+		"* * * * 8", "5-1 * * * *", "*/0 * * * *", "a * * * *", "@yearly", "@every 1ms", "@every soon"} {
+		_, err := ParseSpec(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestParseSpec_NeverFires(t *testing.T) {
+	spec, err := ParseSpec("0 0 30 2 *")
+	require.NoError(t, err)
+	assert.True(t, spec.Next(date("2024-01-01 00:00")).IsZero())
+
+	r := NewRunner(new(MockRepository), 1)
+	assert.Error(t, r.Schedule("never", "0 0 30 2 *", "noop", nil))
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, 10*time.Second, RetryDelay(1))
+	assert.Equal(t, 20*time.Second, RetryDelay(2))
+	assert.Equal(t, 80*time.Second, RetryDelay(4))
+	assert.Equal(t, time.Hour, RetryDelay(20))
+}
+
+func TestPermanent(t *testing.T) {
+	base := errors.New("bad input")
+	err := Permanent(base)
+
+	assert.True(t, IsPermanent(err))
+	assert.ErrorIs(t, err, base)
+	assert.False(t, IsPermanent(base))
+	assert.NoError(t, Permanent(nil))
+}
+
+type greeting struct {
+	Name string `json:"name"`
+}
+
+func TestRunner_Execute(t *testing.T) {
+	ctx := context.Background()
+	failure := errors.New("smtp down")
+
+	tests := []struct {
+		name    string
+		job     Job
+		handler func(ctx context.Context, g greeting) error
+		expect  func(m *MockRepository, j *Job)
+	}{
+		{
+			name:    "Success",
+			job:     Job{ID: 1, Type: "greet", Payload: []byte(`{"name":"Ann"}`), Attempts: 1, MaxAttempts: 5},
+			handler: func(ctx context.Context, g greeting) error { return nil },
+			expect: func(m *MockRepository, j *Job) {
+				m.On("Complete", mock.Anything, j).Return(nil)
+			},
+		},
+		{
+			name:    "Error retries with backoff",
+			job:     Job{ID: 2, Type: "greet", Payload: []byte(`{}`), Attempts: 3, MaxAttempts: 5},
+			handler: func(ctx context.Context, g greeting) error { return failure },
+			expect: func(m *MockRepository, j *Job) {
+				m.On("Retry", mock.Anything, j, 40*time.Second, "smtp down").Return(nil)
+			},
+		},
+		{
+			name:    "Last attempt fails job",
+			job:     Job{ID: 3, Type: "greet", Payload: []byte(`{}`), Attempts: 5, MaxAttempts: 5},
+			handler: func(ctx context.Context, g greeting) error { return failure },
+			expect: func(m *MockRepository, j *Job) {
+				m.On("Fail", mock.Anything, j, "smtp down").Return(nil)
+			},
+		},
+		{
+			name:    "Permanent error fails job at once",
+			job:     Job{ID: 4, Type: "greet", Payload: []byte(`{}`), Attempts: 1, MaxAttempts: 5},
+			handler: func(ctx context.Context, g greeting) error { return Permanent(failure) },
+			expect: func(m *MockRepository, j *Job) {
+				m.On("Fail", mock.Anything, j, "smtp down").Return(nil)
+			},
+		},
+		{
+			name:    "Bad payload fails job at once",
+			job:     Job{ID: 5, Type: "greet", Payload: []byte(`[1]`), Attempts: 1, MaxAttempts: 5},
+			handler: func(ctx context.Context, g greeting) error { return nil },
+			expect: func(m *MockRepository, j *Job) {
+				m.On("Fail", mock.Anything, j, mock.MatchedBy(func(msg string) bool {
+					return len(msg) > 0
+				})).Return(nil)
+			},
+		},
+		{
+			name:    "Panic is retried",
+			job:     Job{ID: 6, Type: "greet", Payload: []byte(`{}`), Attempts: 1, MaxAttempts: 5},
+			handler: func(ctx context.Context, g greeting) error { panic("boom") },
+			expect: func(m *MockRepository, j *Job) {
+				m.On("Retry", mock.Anything, j, 10*time.Second, "panic: boom").Return(nil)
+			},
+		},
+		{
+			name: "Job of crashed workers is failed without running",
+			job:  Job{ID: 7, Type: "greet", Payload: []byte(`{}`), Attempts: 6, MaxAttempts: 5},
+			handler: func(ctx context.Context, g greeting) error {
+				t.Fatal("handler must not run")
+				return nil
+			},
+			expect: func(m *MockRepository, j *Job) {
+				m.On("Fail", mock.Anything, j, "lease expired after 5 attempts").Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			r := NewRunner(mockRepo, 1)
+			Handle(r, "greet", tt.handler)
+			j := tt.job
+			tt.expect(mockRepo, &j)
+
+			r.execute(ctx, &j)
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRunner_Execute_PassesPayload(t *testing.T) {
+	mockRepo := new(MockRepository)
+	r := NewRunner(mockRepo, 1)
+	var got greeting
+	Handle(r, "greet", func(ctx context.Context, g greeting) error {
+		got = g
+		return nil
+	})
+	j := &Job{ID: 1, Type: "greet", Payload: []byte(`{"name":"Ann"}`), Attempts: 1, MaxAttempts: 5}
+	mockRepo.On("Complete", mock.Anything, j).Return(nil)
+
+	r.execute(context.Background(), j)
+
+	assert.Equal(t, "Ann", got.Name)
+}
+
+func TestRunner_Run(t *testing.T) {
+	mockRepo := new(MockRepository)
+	r := NewRunner(mockRepo, 2)
+	r.pollInterval = 10 * time.Millisecond
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var finished atomic.Bool
+	Handle(r, "slow", func(ctx context.Context, _ struct{}) error {
+		close(started)
+		<-release
+		finished.Store(true)
+		return nil
+	})
+	require.NoError(t, r.Schedule("slow.daily", "@daily", "slow", struct{}{}))
+
+	mockRepo.On("SaveSchedule", mock.Anything, mock.MatchedBy(func(s *Schedule) bool {
+		return s.Name == "slow.daily" && s.Type == "slow" && string(s.Payload) == "{}" && s.NextRunAt.After(time.Now())
+	})).Return(nil).Once()
+	mockRepo.On("FireSchedules", mock.Anything, []string{"slow.daily"}, mock.Anything).Return(0, nil)
+	job := Job{ID: 1, Type: "slow", Payload: []byte(`{}`), Attempts: 1, MaxAttempts: 5}
+	mockRepo.On("Claim", mock.Anything, []string{"slow"}, 2, lease).Return([]Job{job}, nil).Once()
+	mockRepo.On("Claim", mock.Anything, []string{"slow"}, 1, lease).Return([]Job{}, nil).Maybe()
+	mockRepo.On("Complete", mock.Anything, mock.MatchedBy(func(j *Job) bool { return j.ID == 1 })).Return(nil).Once()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- r.Run(ctx) }()
+
+	<-started
+	cancel()
+	// in-flight job keeps runner from stopping
+	select {
+	case <-done:
+		t.Fatal("runner stopped before in-flight job finished")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	require.NoError(t, <-done)
+	assert.True(t, finished.Load())
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRunner_Run_UnknownScheduleType(t *testing.T) {
+	r := NewRunner(new(MockRepository), 1)
+	require.NoError(t, r.Schedule("orphan", "@hourly", "missing", nil))
+
+	err := r.Run(context.Background())
+
+	assert.ErrorIs(t, err, ErrNoHandler)
+}
+
+func TestService_ListJobs(t *testing.T) {
+	t.Run("Failed jobs", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo)
+		page := pagination.Params{Limit: 1, Sort: "created", Desc: true}
+		jobs := []Job{{ID: 3, Status: StatusFailed}, {ID: 2, Status: StatusFailed}}
+		mockRepo.On("ListPage", mock.Anything, StatusFailed, page).Return(jobs, 5, nil)
+
+		result, err := service.ListJobs(context.Background(), StatusFailed, page)
+
+		require.NoError(t, err)
+		assert.Len(t, result.Items, 1)
+		assert.Equal(t, 5, result.Total)
+		assert.NotEmpty(t, result.Next)
+	})
+
+	t.Run("Invalid status", func(t *testing.T) {
+		service := NewService(new(MockRepository))
+
+		_, err := service.ListJobs(context.Background(), "broken", pagination.Params{Limit: 10})
+
+		assert.ErrorIs(t, err, ErrBadStatus)
+	})
+}
+
+func TestService_RetryJob(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo)
+		mockRepo.On("GetByID", ctx, int64(1)).Return(&Job{ID: 1, Status: StatusFailed}, nil).Once()
+		mockRepo.On("Requeue", ctx, int64(1)).Return(nil)
+		mockRepo.On("GetByID", ctx, int64(1)).Return(&Job{ID: 1, Status: StatusPending}, nil).Once()
+
+		j, err := service.RetryJob(ctx, 1)
+
+		require.NoError(t, err)
+		assert.Equal(t, StatusPending, j.Status)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Not failed", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo)
+		mockRepo.On("GetByID", ctx, int64(1)).Return(&Job{ID: 1, Status: StatusDone}, nil)
+		mockRepo.On("Requeue", ctx, int64(1)).Return(ErrNotFailed)
+
+		_, err := service.RetryJob(ctx, 1)
+
+		assert.ErrorIs(t, err, ErrNotFailed)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo)
+		mockRepo.On("GetByID", ctx, int64(9)).Return(nil, sql.ErrNoRows)
+
+		_, err := service.RetryJob(ctx, 9)
+
+		assert.ErrorIs(t, err, ErrNotFound)
+		mockRepo.AssertNotCalled(t, "Requeue", ctx, int64(9))
+	})
+}
@@ -0,0 +1,34 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileDrop writes every message to dir as .eml file instead of sending, for local development
+type FileDrop struct {
+	dir  string
+	from string
+}
+
+func NewFileDrop(dir, from string) *FileDrop {
+	return &FileDrop{dir: dir, from: from}
+}
+
+// Send saves message to new file named by time
+func (f *FileDrop) Send(ctx context.Context, msg Message) error {
+	now := time.Now()
+	data, err := msg.Bytes(f.from, now)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(f.dir, 0o755); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", now.UTC().Format("20060102T150405.000000000"), randomID()[:8])
+	return os.WriteFile(filepath.Join(f.dir, name), data, 0o644)
+}
@@ -0,0 +1,144 @@
+package mail
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessage_Bytes(t *testing.T) {
+	msg := Message{
+		To:      "bob@example.com",
+		Subject: "Ticket PMS-1 — assigned",
+		Text:    "Hello, Bob",
+		HTML:    "<p>Hello, Bob</p>",
+		Headers: map[string]string{"list-unsubscribe": "<https://example.com/u>"},
+	}
+
+	t.Run("Multipart", func(t *testing.T) {
+		data, err := msg.Bytes("PMS <noreply@example.com>", time.Now())
+		require.NoError(t, err)
+
+		parsed, err := mail.ReadMessage(strings.NewReader(string(data)))
+		require.NoError(t, err)
+		subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+		require.NoError(t, err)
+		assert.Equal(t, msg.Subject, subject)
+		assert.Equal(t, "<https://example.com/u>", parsed.Header.Get("List-Unsubscribe"))
+		assert.Contains(t, parsed.Header.Get("Message-Id"), "@example.com>")
+
+		_, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+		require.NoError(t, err)
+		reader := multipart.NewReader(parsed.Body, params["boundary"])
+		var bodies []string
+		for {
+			p, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			b, _ := io.ReadAll(p)
+			bodies = append(bodies, string(b))
+		}
+		assert.Equal(t, []string{"Hello, Bob", "<p>Hello, Bob</p>"}, bodies)
+	})
+
+	t.Run("HeaderInjection", func(t *testing.T) {
+		bad := msg
+		bad.To = "bob@example.com\r\nBcc: eve@example.com"
+
+		_, err := bad.Bytes("noreply@example.com", time.Now())
+
+		assert.Error(t, err)
+	})
+}
+
+func TestFileDrop_Send(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	mailer := NewFileDrop(dir, "noreply@example.com")
+
+	err := mailer.Send(context.Background(), Message{To: "bob@example.com", Subject: "Hi", Text: "Hello"})
+
+	require.NoError(t, err)
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.True(t, strings.HasSuffix(files[0].Name(), ".eml"))
+}
+
+func TestSMTP_Send(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	received := make(chan []string, 1)
+	go serveSMTP(ln, received)
+
+	addr := ln.Addr().(*net.TCPAddr)
+	mailer := NewSMTP("127.0.0.1", addr.Port, "", "", "PMS <noreply@example.com>")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err = mailer.Send(ctx, Message{To: "Bob <bob@example.com>", Subject: "Hi", Text: "Hello"})
+
+	require.NoError(t, err)
+	commands := <-received
+	assert.Contains(t, commands, "MAIL FROM:<noreply@example.com>")
+	assert.Contains(t, commands, "RCPT TO:<bob@example.com>")
+}
+
+// serveSMTP accepts one connection and answers every command with success
+func serveSMTP(ln net.Listener, received chan<- []string) {
+	conn, err := ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(s string) { io.WriteString(conn, s+"\r\n") }
+	reply("220 localhost ESMTP")
+
+	var commands []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			break
+		}
+		cmd := strings.TrimRight(line, "\r\n")
+		commands = append(commands, cmd)
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"):
+			reply("250-localhost")
+			reply("250 SIZE 10240000")
+		case cmd == "DATA":
+			reply("354 go ahead")
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil || l == ".\r\n" {
+					break
+				}
+			}
+			reply("250 queued")
+		case cmd == "QUIT":
+			reply("221 bye")
+			received <- commands
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+	received <- commands
+}
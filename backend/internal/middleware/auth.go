@@ -17,6 +17,12 @@ func JWTMiddleware(secret []byte) echo.MiddlewareFunc {
 		return func(c echo.Context) error {
 			// taking jwt
 			authHeader := c.Request().Header.Get("Authorization")
+			// EventSource can't set headers, event streams may pass token in query
+			if authHeader == "" && c.Request().Header.Get("Accept") == "text/event-stream" {
+				if token := c.QueryParam("access_token"); token != "" {
+					authHeader = "Bearer " + token
+				}
+			}
 			if authHeader == "" {
 				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Missing authorization header"})
 			}
@@ -55,6 +61,10 @@ func JWTMiddleware(secret []byte) echo.MiddlewareFunc {
 				userID := int64(userIDFloat)
 
 				c.Set("userID", userID)
+				// tokens issued before roles were added have no role claim
+				if role, ok := claims["role"].(string); ok {
+					c.Set("userRole", role)
+				}
 
 				// next handler in pipeline
 				return next(c)
@@ -64,3 +74,18 @@ func JWTMiddleware(secret []byte) echo.MiddlewareFunc {
 		}
 	}
 }
+
+// RequireRole allows request only for users with one of roles, it must run after JWTMiddleware
+func RequireRole(roles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			role, _ := c.Get("userRole").(string)
+			for _, r := range roles {
+				if role == r {
+					return next(c)
+				}
+			}
+			return c.JSON(http.StatusForbidden, map[string]string{"error": "Insufficient permissions"})
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/mail"
+)
+
+// Email modes
+const (
+	EmailOff       = "off"
+	EmailImmediate = "immediate"
+	EmailDaily     = "daily"
+)
+
+const (
+	// emailBatchDelay is how long immediate emails wait, so close notifications go in one email
+	emailBatchDelay = 2 * time.Minute
+	digestInterval  = 24 * time.Hour
+	// maxEmailItems is max number of notifications in one email, the rest goes in next one
+	maxEmailItems   = 50
+	recipientsBatch = 100
+)
+
+// IsEmailMode tells if m is known email mode
+func IsEmailMode(m string) bool {
+	return m == EmailOff || m == EmailImmediate || m == EmailDaily
+}
+
+// EmailSettings tells how user gets notification emails
+type EmailSettings struct {
+	UserID       int64      `db:"user_id" json:"-"`
+	Mode         string     `db:"mode" json:"mode"`
+	LastDigestAt *time.Time `db:"last_digest_at" json:"last_digest_at"`
+}
+
+// Recipient is user with notifications due to be emailed
+type Recipient struct {
+	UserID   int64  `db:"user_id"`
+	Username string `db:"username"`
+	Email    string `db:"email"`
+	Mode     string `db:"mode"`
+}
+
+// EmailItem is notification with names needed to show it in email
+type EmailItem struct {
+	Notification
+	ActorName   string `db:"actor_name"`
+	ProjectName string `db:"project_name"`
+}
+
+// EmailConfig tells where email links point to
+type EmailConfig struct {
+	// AppURL is frontend address, APIURL is backend address for unsubscribe links
+	AppURL string
+	APIURL string
+	// Secret signs unsubscribe tokens
+	Secret []byte
+}
+
+// GetEmailSettings returns email settings of user
+func (s *Service) GetEmailSettings(ctx context.Context, userID int64) (*EmailSettings, error) {
+	return s.repo.GetEmailSettings(ctx, userID)
+}
+
+// UpdateEmailMode sets how user gets notification emails
+func (s *Service) UpdateEmailMode(ctx context.Context, userID int64, mode string) (*EmailSettings, error) {
+	if !IsEmailMode(mode) {
+		return nil, fmt.Errorf("email mode must be %s, %s or %s", EmailOff, EmailImmediate, EmailDaily)
+	}
+	if err := s.repo.SaveEmailMode(ctx, userID, mode); err != nil {
+		return nil, err
+	}
+	return s.repo.GetEmailSettings(ctx, userID)
+}
+
+// CheckUnsubscribeToken tells if token is valid without changing anything
+func (s *Service) CheckUnsubscribeToken(token string) error {
+	_, err := ParseUnsubscribeToken(s.email.Secret, token)
+	return err
+}
+
+// Unsubscribe turns off emails of user from signed token
+func (s *Service) Unsubscribe(ctx context.Context, token string) error {
+	userID, err := ParseUnsubscribeToken(s.email.Secret, token)
+	if err != nil {
+		return err
+	}
+	return s.repo.SaveEmailMode(ctx, userID, EmailOff)
+}
+
+// SendEmails sends one email to every user with due notifications and returns number of sent emails.
+// Immediate emails wait emailBatchDelay to collect close notifications, daily ones go once a day.
+// Notifications read in app before that are not emailed
+func (s *Service) SendEmails(ctx context.Context) (int, error) {
+	now := s.now()
+	recipients, err := s.repo.ListDueRecipients(ctx, now.Add(-emailBatchDelay), now.Add(-digestInterval), recipientsBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, r := range recipients {
+		ok, err := s.sendTo(ctx, r, now)
+		if err != nil {
+			log.Printf("Can't email notifications to user %d: %v", r.UserID, err)
+			continue
+		}
+		if ok {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// sendTo claims pending notifications of recipient and emails them. Claim is released
+// if sending fails, so they are retried on next run
+func (s *Service) sendTo(ctx context.Context, r Recipient, now time.Time) (bool, error) {
+	items, err := s.repo.ClaimPending(ctx, r.UserID, maxEmailItems)
+	if err != nil || len(items) == 0 {
+		return false, err
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+
+	ids := make([]int64, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+
+	msg, err := s.composeEmail(r, items)
+	if err == nil {
+		err = s.mailer.Send(ctx, msg)
+	}
+	if err != nil {
+		if releaseErr := s.repo.ReleasePending(ctx, ids); releaseErr != nil {
+			log.Printf("Can't release notifications %v: %v", ids, releaseErr)
+		}
+		return false, err
+	}
+
+	if r.Mode == EmailDaily {
+		if err := s.repo.SetDigestSent(ctx, r.UserID, now); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+// composeEmail renders email with items. Single immediate notification gets its own subject
+func (s *Service) composeEmail(r Recipient, items []EmailItem) (mail.Message, error) {
+	unsubscribeURL := s.email.APIURL + "/unsubscribe?token=" + url.QueryEscape(UnsubscribeToken(s.email.Secret, r.UserID))
+	view := emailView{
+		Username:       r.Username,
+		Digest:         r.Mode == EmailDaily,
+		SettingsURL:    s.email.AppURL + "/settings/notifications",
+		UnsubscribeURL: unsubscribeURL,
+	}
+
+	var subject string
+	for _, item := range items {
+		iv := s.itemView(item)
+		rendered, err := emailTemplates.item(item.Type, iv)
+		if err != nil {
+			return mail.Message{}, err
+		}
+		view.Items = append(view.Items, rendered)
+
+		if len(items) == 1 && !view.Digest {
+			if subject, err = emailTemplates.subject(item.Type, iv); err != nil {
+				return mail.Message{}, err
+			}
+		}
+	}
+	if subject == "" {
+		subject = fmt.Sprintf("%d new notifications", len(items))
+		if view.Digest {
+			subject = fmt.Sprintf("Your daily digest: %d notifications", len(items))
+		}
+	}
+
+	html, text, err := emailTemplates.bodies(view)
+	if err != nil {
+		return mail.Message{}, err
+	}
+	return mail.Message{
+		To:      r.Email,
+		Subject: subject,
+		Text:    text,
+		HTML:    html,
+		Headers: map[string]string{
+			// one-click unsubscribe from mail client, RFC 8058
+			"List-Unsubscribe":      "<" + unsubscribeURL + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	}, nil
+}
+
+func (s *Service) itemView(item EmailItem) itemView {
+	var details Details
+	if err := json.Unmarshal(item.Data, &details); err != nil {
+		log.Printf("Can't decode notification %d: %v", item.ID, err)
+	}
+	actor := item.ActorName
+	if actor == "" {
+		actor = "Someone"
+	}
+
+	link := fmt.Sprintf("%s/projects/%d", s.email.AppURL, item.ProjectID)
+	if details.TicketKey != "" {
+		link += "?ticket=" + url.QueryEscape(details.TicketKey)
+	}
+	return itemView{Details: details, Actor: actor, Project: item.ProjectName, URL: link}
+}
@@ -0,0 +1,176 @@
+package notification
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/antonovs105/project-management-system-go/internal/pagination"
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// List handler for GET /api/me/notifications
+func (h *Handler) List(c echo.Context) error {
+	userID := c.Get("userID").(int64)
+
+	unreadOnly := false
+	if v := c.QueryParam("unread"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "unread must be true or false"})
+		}
+		unreadOnly = b
+	}
+	page, err := pagination.Parse(c.QueryParams(), DefaultSort, SortFields)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	result, err := h.service.ListNotifications(c.Request().Context(), userID, unreadOnly, page)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	pagination.SetHeaders(c.Response().Header(), c.Request().URL, result.Total, result.Next)
+	return c.JSON(http.StatusOK, result.Items)
+}
+
+// UnreadCount handler for GET /api/me/notifications/unread-count
+func (h *Handler) UnreadCount(c echo.Context) error {
+	userID := c.Get("userID").(int64)
+
+	count, err := h.service.UnreadCount(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, map[string]int{"unread": count})
+}
+
+// MarkRead handler for POST /api/me/notifications/:id/read
+func (h *Handler) MarkRead(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid notification ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	if err := h.service.MarkRead(c.Request().Context(), id, userID); err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// MarkAllRead handler for POST /api/me/notifications/read-all
+func (h *Handler) MarkAllRead(c echo.Context) error {
+	userID := c.Get("userID").(int64)
+
+	updated, err := h.service.MarkAllRead(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, map[string]int64{"updated": updated})
+}
+
+// GetPreferences handler for GET /api/me/notification-preferences
+func (h *Handler) GetPreferences(c echo.Context) error {
+	userID := c.Get("userID").(int64)
+
+	prefs, err := h.service.GetPreferences(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, prefs)
+}
+
+// UpdatePreferences handler for PUT /api/me/notification-preferences
+func (h *Handler) UpdatePreferences(c echo.Context) error {
+	var req map[string]bool
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+	}
+	userID := c.Get("userID").(int64)
+
+	prefs, err := h.service.UpdatePreferences(c.Request().Context(), userID, req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, prefs)
+}
+
+// GetEmailSettings handler for GET /api/me/email-settings
+func (h *Handler) GetEmailSettings(c echo.Context) error {
+	userID := c.Get("userID").(int64)
+
+	settings, err := h.service.GetEmailSettings(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, settings)
+}
+
+// UpdateEmailSettings handler for PUT /api/me/email-settings
+func (h *Handler) UpdateEmailSettings(c echo.Context) error {
+	var req struct {
+		Mode string `json:"mode"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+	}
+	userID := c.Get("userID").(int64)
+
+	settings, err := h.service.UpdateEmailMode(c.Request().Context(), userID, req.Mode)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, settings)
+}
+
+// Unsubscribe handler for GET and POST /unsubscribe. GET only shows confirmation form, since mail
+// scanners open links too. POST turns emails off, it is also one-click unsubscribe from mail client
+func (h *Handler) Unsubscribe(c echo.Context) error {
+	token := c.QueryParam("token")
+	if token == "" {
+		token = c.FormValue("token")
+	}
+
+	if c.Request().Method != http.MethodPost {
+		if err := h.service.CheckUnsubscribeToken(token); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return renderUnsubscribePage(c, token, false)
+	}
+
+	if err := h.service.Unsubscribe(c.Request().Context(), token); err != nil {
+		if errors.Is(err, ErrInvalidToken) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	// RFC 8058 one-click request, nobody reads the page
+	if c.FormValue("List-Unsubscribe") == "One-Click" {
+		return c.NoContent(http.StatusOK)
+	}
+	return renderUnsubscribePage(c, "", true)
+}
+
+func renderUnsubscribePage(c echo.Context, token string, done bool) error {
+	var buf bytes.Buffer
+	data := struct {
+		Token string
+		Done  bool
+	}{token, done}
+	if err := unsubscribePage.Execute(&buf, data); err != nil {
+		return err
+	}
+	return c.HTMLBlob(http.StatusOK, buf.Bytes())
+}
@@ -0,0 +1,25 @@
+package notification
+
+import (
+	"regexp"
+	"strings"
+)
+
+// mentionPattern matches @username not preceded by word character, so emails are skipped
+var mentionPattern = regexp.MustCompile(`\B@([A-Za-z0-9_][A-Za-z0-9_.-]*)`)
+
+// ParseMentions returns lowercased usernames mentioned in text, each once
+func ParseMentions(text string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, m := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		// trailing punctuation belongs to sentence: "thanks @bob."
+		name := strings.ToLower(strings.TrimRight(m[1], ".-"))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,61 @@
+package notification
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Notification types
+const (
+	TypeAssigned      = "assigned"
+	TypeMentioned     = "mentioned"
+	TypeCommented     = "commented"
+	TypeStatusChanged = "status_changed"
+	TypeInvited       = "invited"
+)
+
+// Types are all notification types
+var Types = []string{TypeAssigned, TypeMentioned, TypeCommented, TypeStatusChanged, TypeInvited}
+
+// IsType tells if t is known notification type
+func IsType(t string) bool {
+	for _, known := range Types {
+		if known == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Notification tells user about something that happened in project. Data holds Details
+type Notification struct {
+	ID        int64           `db:"id" json:"id"`
+	UserID    int64           `db:"user_id" json:"user_id"`
+	Type      string          `db:"type" json:"type"`
+	EventID   int64           `db:"event_id" json:"event_id"`
+	ProjectID int64           `db:"project_id" json:"project_id"`
+	TicketID  *int64          `db:"ticket_id" json:"ticket_id"`
+	ActorID   *int64          `db:"actor_id" json:"actor_id"`
+	Data      json.RawMessage `db:"data" json:"data"`
+	ReadAt    *time.Time      `db:"read_at" json:"read_at"`
+	EmailedAt *time.Time      `db:"emailed_at" json:"emailed_at"`
+	CreatedAt time.Time       `db:"created_at" json:"created_at"`
+}
+
+// Details are copied from event, so notification can be shown after ticket changes
+type Details struct {
+	TicketKey   string `json:"ticket_key,omitempty"`
+	TicketTitle string `json:"ticket_title,omitempty"`
+	// Status is new ticket status for status_changed
+	Status    string `json:"status,omitempty"`
+	CommentID int64  `json:"comment_id,omitempty"`
+	Excerpt   string `json:"excerpt,omitempty"`
+	// Role is project role for invited
+	Role string `json:"role,omitempty"`
+}
+
+// Preference turns notifications of one type on or off, types without preference are on
+type Preference struct {
+	Type    string `db:"type" json:"type"`
+	Enabled bool   `db:"enabled" json:"enabled"`
+}
@@ -0,0 +1,266 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/pagination"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type Repository interface {
+	CreateForUsers(ctx context.Context, tx sqlx.ExtContext, n *Notification, userIDs []int64) error
+	UserIDsByUsernames(ctx context.Context, tx sqlx.QueryerContext, usernames []string) ([]int64, error)
+	WatcherIDs(ctx context.Context, tx sqlx.QueryerContext, ticketID int64) ([]int64, error)
+	ListPage(ctx context.Context, userID int64, unreadOnly bool, page pagination.Params) ([]Notification, int, error)
+	CountUnread(ctx context.Context, userID int64) (int, error)
+	MarkRead(ctx context.Context, id, userID int64) error
+	MarkAllRead(ctx context.Context, userID int64) (int64, error)
+	ListPreferences(ctx context.Context, userID int64) ([]Preference, error)
+	SavePreferences(ctx context.Context, userID int64, prefs []Preference) error
+	GetEmailSettings(ctx context.Context, userID int64) (*EmailSettings, error)
+	SaveEmailMode(ctx context.Context, userID int64, mode string) error
+	ListDueRecipients(ctx context.Context, immediateBefore, digestBefore time.Time, limit int) ([]Recipient, error)
+	ClaimPending(ctx context.Context, userID int64, limit int) ([]EmailItem, error)
+	ReleasePending(ctx context.Context, ids []int64) error
+	SetDigestSent(ctx context.Context, userID int64, at time.Time) error
+}
+
+type PgRepository struct {
+	db *sqlx.DB
+}
+
+func NewRepository(db *sqlx.DB) Repository {
+	return &PgRepository{db: db}
+}
+
+// CreateForUsers stores copy of n for every user of userIDs who is project member and
+// hasn't turned its type off. Notifications about deleted tickets are skipped
+func (r *PgRepository) CreateForUsers(ctx context.Context, tx sqlx.ExtContext, n *Notification, userIDs []int64) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO notifications (user_id, type, event_id, project_id, ticket_id, actor_id, data)
+		SELECT m.user_id, $2, $3, $4, $5, $6, $7
+		FROM project_members m
+		WHERE m.project_id = $4 AND m.user_id = ANY($1)
+			AND ($5::bigint IS NULL OR EXISTS (SELECT 1 FROM tickets WHERE id = $5))
+			AND NOT EXISTS (
+				SELECT 1 FROM notification_preferences p
+				WHERE p.user_id = m.user_id AND p.type = $2 AND NOT p.enabled
+			)
+		ON CONFLICT (user_id, event_id, type) DO NOTHING`
+
+	_, err := tx.ExecContext(ctx, query, pq.Array(userIDs), n.Type, n.EventID, n.ProjectID, n.TicketID, n.ActorID, string(n.Data))
+	return err
+}
+
+// UserIDsByUsernames resolves lowercased usernames to user ids, unknown names are skipped
+func (r *PgRepository) UserIDsByUsernames(ctx context.Context, tx sqlx.QueryerContext, usernames []string) ([]int64, error) {
+	if len(usernames) == 0 {
+		return nil, nil
+	}
+
+	var ids []int64
+	query := `SELECT id FROM users WHERE lower(username) = ANY($1) ORDER BY id`
+	if err := sqlx.SelectContext(ctx, tx, &ids, query, pq.Array(usernames)); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// WatcherIDs returns users watching ticket
+func (r *PgRepository) WatcherIDs(ctx context.Context, tx sqlx.QueryerContext, ticketID int64) ([]int64, error) {
+	var ids []int64
+	query := `SELECT user_id FROM ticket_watchers WHERE ticket_id = $1 ORDER BY user_id`
+	if err := sqlx.SelectContext(ctx, tx, &ids, query, ticketID); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// ListPage returns page of user notifications (limit+1 rows to detect next page) and total count
+func (r *PgRepository) ListPage(ctx context.Context, userID int64, unreadOnly bool, page pagination.Params) ([]Notification, int, error) {
+	where := "user_id = $1"
+	if unreadOnly {
+		where += " AND read_at IS NULL"
+	}
+	args := []any{userID}
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM notifications WHERE `+where, args...); err != nil {
+		return nil, 0, err
+	}
+
+	keyset, orderBy, args := page.Keyset(SortFields, "id", args)
+	args = append(args, page.Limit+1)
+	query := fmt.Sprintf(`SELECT * FROM notifications WHERE %s AND %s ORDER BY %s LIMIT $%d`, where, keyset, orderBy, len(args))
+
+	var notifications []Notification
+	if err := r.db.SelectContext(ctx, &notifications, query, args...); err != nil {
+		return nil, 0, err
+	}
+	return notifications, total, nil
+}
+
+// CountUnread counts notifications user hasn't read
+func (r *PgRepository) CountUnread(ctx context.Context, userID int64) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL`
+	err := r.db.GetContext(ctx, &count, query, userID)
+	return count, err
+}
+
+// MarkRead marks notification of user as read, reading it again keeps first read time
+func (r *PgRepository) MarkRead(ctx context.Context, id, userID int64) error {
+	query := `UPDATE notifications SET read_at = COALESCE(read_at, now()) WHERE id = $1 AND user_id = $2`
+	result, err := r.db.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("notification not found")
+	}
+	return nil
+}
+
+// MarkAllRead marks all unread notifications of user as read and returns their number
+func (r *PgRepository) MarkAllRead(ctx context.Context, userID int64) (int64, error) {
+	query := `UPDATE notifications SET read_at = now() WHERE user_id = $1 AND read_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// ListPreferences returns preferences user has saved
+func (r *PgRepository) ListPreferences(ctx context.Context, userID int64) ([]Preference, error) {
+	var prefs []Preference
+	query := `SELECT type, enabled FROM notification_preferences WHERE user_id = $1 ORDER BY type`
+	if err := r.db.SelectContext(ctx, &prefs, query, userID); err != nil {
+		return nil, err
+	}
+	return prefs, nil
+}
+
+// SavePreferences inserts or replaces given preferences of user in one transaction
+func (r *PgRepository) SavePreferences(ctx context.Context, userID int64, prefs []Preference) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO notification_preferences (user_id, type, enabled)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, type) DO UPDATE SET enabled = EXCLUDED.enabled`
+	for _, p := range prefs {
+		if _, err := tx.ExecContext(ctx, query, userID, p.Type, p.Enabled); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetEmailSettings returns email settings of user, users without saved settings get immediate emails
+func (r *PgRepository) GetEmailSettings(ctx context.Context, userID int64) (*EmailSettings, error) {
+	var settings EmailSettings
+	query := `
+		SELECT u.id AS user_id, COALESCE(s.mode, 'immediate') AS mode, s.last_digest_at
+		FROM users u
+		LEFT JOIN notification_email_settings s ON s.user_id = u.id
+		WHERE u.id = $1`
+	if err := r.db.GetContext(ctx, &settings, query, userID); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// SaveEmailMode inserts or updates email mode of user
+func (r *PgRepository) SaveEmailMode(ctx context.Context, userID int64, mode string) error {
+	query := `
+		INSERT INTO notification_email_settings (user_id, mode)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET mode = EXCLUDED.mode, updated_at = now()`
+	_, err := r.db.ExecContext(ctx, query, userID, mode)
+	return err
+}
+
+// ListDueRecipients returns users with unread notifications not emailed yet: immediate ones
+// having notification older than immediateBefore, daily ones not emailed since digestBefore
+func (r *PgRepository) ListDueRecipients(ctx context.Context, immediateBefore, digestBefore time.Time, limit int) ([]Recipient, error) {
+	var recipients []Recipient
+	query := `
+		SELECT u.id AS user_id, u.username, u.email, COALESCE(s.mode, 'immediate') AS mode
+		FROM users u
+		LEFT JOIN notification_email_settings s ON s.user_id = u.id
+		WHERE u.id IN (
+				SELECT user_id FROM notifications
+				WHERE emailed_at IS NULL AND read_at IS NULL AND created_at <= $1
+			)
+			AND (
+				COALESCE(s.mode, 'immediate') = 'immediate'
+				OR (s.mode = 'daily' AND (s.last_digest_at IS NULL OR s.last_digest_at <= $2))
+			)
+		ORDER BY u.id
+		LIMIT $3`
+	if err := r.db.SelectContext(ctx, &recipients, query, immediateBefore, digestBefore, limit); err != nil {
+		return nil, err
+	}
+	return recipients, nil
+}
+
+// ClaimPending marks up to limit unread not emailed notifications of user as emailed and returns them.
+// Rows locked by other instance are skipped, so notification is emailed once
+func (r *PgRepository) ClaimPending(ctx context.Context, userID int64, limit int) ([]EmailItem, error) {
+	var items []EmailItem
+	query := `
+		WITH claimed AS (
+			UPDATE notifications SET emailed_at = now()
+			WHERE id IN (
+				SELECT id FROM notifications
+				WHERE user_id = $1 AND emailed_at IS NULL AND read_at IS NULL
+				ORDER BY id
+				LIMIT $2
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING *
+		)
+		SELECT c.*, COALESCE(a.username, '') AS actor_name, p.name AS project_name
+		FROM claimed c
+		JOIN projects p ON p.id = c.project_id
+		LEFT JOIN users a ON a.id = c.actor_id`
+	if err := r.db.SelectContext(ctx, &items, query, userID, limit); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ReleasePending returns claimed notifications to pending after failed email
+func (r *PgRepository) ReleasePending(ctx context.Context, ids []int64) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE notifications SET emailed_at = NULL WHERE id = ANY($1)`, pq.Array(ids))
+	return err
+}
+
+// SetDigestSent remembers when user got last daily digest
+func (r *PgRepository) SetDigestSent(ctx context.Context, userID int64, at time.Time) error {
+	query := `
+		INSERT INTO notification_email_settings (user_id, mode, last_digest_at)
+		VALUES ($1, 'daily', $2)
+		ON CONFLICT (user_id) DO UPDATE SET last_digest_at = EXCLUDED.last_digest_at`
+	_, err := r.db.ExecContext(ctx, query, userID, at)
+	return err
+}
@@ -0,0 +1,126 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/mail"
+	"github.com/antonovs105/project-management-system-go/internal/pagination"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRepository is a mock implementation of Repository
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) CreateForUsers(ctx context.Context, tx sqlx.ExtContext, n *Notification, userIDs []int64) error {
+	args := m.Called(ctx, tx, n, userIDs)
+	return args.Error(0)
+}
+
+func (m *MockRepository) UserIDsByUsernames(ctx context.Context, tx sqlx.QueryerContext, usernames []string) ([]int64, error) {
+	args := m.Called(ctx, tx, usernames)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]int64), args.Error(1)
+}
+
+func (m *MockRepository) WatcherIDs(ctx context.Context, tx sqlx.QueryerContext, ticketID int64) ([]int64, error) {
+	args := m.Called(ctx, tx, ticketID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]int64), args.Error(1)
+}
+
+func (m *MockRepository) ListPage(ctx context.Context, userID int64, unreadOnly bool, page pagination.Params) ([]Notification, int, error) {
+	args := m.Called(ctx, userID, unreadOnly, page)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]Notification), args.Int(1), args.Error(2)
+}
+
+func (m *MockRepository) CountUnread(ctx context.Context, userID int64) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockRepository) MarkRead(ctx context.Context, id, userID int64) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) MarkAllRead(ctx context.Context, userID int64) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRepository) ListPreferences(ctx context.Context, userID int64) ([]Preference, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Preference), args.Error(1)
+}
+
+func (m *MockRepository) SavePreferences(ctx context.Context, userID int64, prefs []Preference) error {
+	args := m.Called(ctx, userID, prefs)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetEmailSettings(ctx context.Context, userID int64) (*EmailSettings, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*EmailSettings), args.Error(1)
+}
+
+func (m *MockRepository) SaveEmailMode(ctx context.Context, userID int64, mode string) error {
+	args := m.Called(ctx, userID, mode)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ListDueRecipients(ctx context.Context, immediateBefore, digestBefore time.Time, limit int) ([]Recipient, error) {
+	args := m.Called(ctx, immediateBefore, digestBefore, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Recipient), args.Error(1)
+}
+
+func (m *MockRepository) ClaimPending(ctx context.Context, userID int64, limit int) ([]EmailItem, error) {
+	args := m.Called(ctx, userID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]EmailItem), args.Error(1)
+}
+
+func (m *MockRepository) ReleasePending(ctx context.Context, ids []int64) error {
+	args := m.Called(ctx, ids)
+	return args.Error(0)
+}
+
+func (m *MockRepository) SetDigestSent(ctx context.Context, userID int64, at time.Time) error {
+	args := m.Called(ctx, userID, at)
+	return args.Error(0)
+}
+
+// RecordingMailer keeps sent messages
+type RecordingMailer struct {
+	Sent []mail.Message
+	Err  error
+}
+
+func (m *RecordingMailer) Send(ctx context.Context, msg mail.Message) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	m.Sent = append(m.Sent, msg)
+	return nil
+}
@@ -0,0 +1,256 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/comment"
+	"github.com/antonovs105/project-management-system-go/internal/event"
+	"github.com/antonovs105/project-management-system-go/internal/mail"
+	"github.com/antonovs105/project-management-system-go/internal/pagination"
+	"github.com/antonovs105/project-management-system-go/internal/projectmember"
+	"github.com/antonovs105/project-management-system-go/internal/ticket"
+	"github.com/jmoiron/sqlx"
+)
+
+// excerptLength is max number of characters of comment copied to notification
+const excerptLength = 200
+
+// DefaultSort is newest first
+const DefaultSort = "-created"
+
+// SortFields are fields notifications can be sorted by
+var SortFields = map[string]pagination.SortField{
+	"created": {Column: "created_at", Cast: "timestamptz"},
+}
+
+var ErrNotFound = errors.New("notification not found")
+
+type Service struct {
+	repo   Repository
+	mailer mail.Mailer
+	email  EmailConfig
+	now    func() time.Time
+}
+
+func NewService(repo Repository, mailer mail.Mailer, email EmailConfig) *Service {
+	return &Service{
+		repo:   repo,
+		mailer: mailer,
+		email:  email,
+		now:    time.Now,
+	}
+}
+
+// ListNotifications returns page of user notifications, newest first by default
+func (s *Service) ListNotifications(ctx context.Context, userID int64, unreadOnly bool, page pagination.Params) (*pagination.Page[Notification], error) {
+	notifications, total, err := s.repo.ListPage(ctx, userID, unreadOnly, page)
+	if err != nil {
+		return nil, err
+	}
+	return pagination.NewPage(notifications, total, page, func(n *Notification) pagination.Cursor {
+		return page.Cursor(n.CreatedAt.Format(time.RFC3339Nano), n.ID)
+	}), nil
+}
+
+// UnreadCount returns number of unread notifications of user
+func (s *Service) UnreadCount(ctx context.Context, userID int64) (int, error) {
+	return s.repo.CountUnread(ctx, userID)
+}
+
+// MarkRead marks own notification as read
+func (s *Service) MarkRead(ctx context.Context, id, userID int64) error {
+	if err := s.repo.MarkRead(ctx, id, userID); err != nil {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// MarkAllRead marks all notifications of user as read and returns number of changed ones
+func (s *Service) MarkAllRead(ctx context.Context, userID int64) (int64, error) {
+	return s.repo.MarkAllRead(ctx, userID)
+}
+
+// GetPreferences returns every notification type with its state for user
+func (s *Service) GetPreferences(ctx context.Context, userID int64) (map[string]bool, error) {
+	saved, err := s.repo.ListPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	prefs := make(map[string]bool, len(Types))
+	for _, t := range Types {
+		prefs[t] = true
+	}
+	for _, p := range saved {
+		prefs[p.Type] = p.Enabled
+	}
+	return prefs, nil
+}
+
+// UpdatePreferences turns given notification types on or off, other types keep their state
+func (s *Service) UpdatePreferences(ctx context.Context, userID int64, changes map[string]bool) (map[string]bool, error) {
+	prefs := make([]Preference, 0, len(changes))
+	for t, enabled := range changes {
+		if !IsType(t) {
+			return nil, fmt.Errorf("unknown notification type %q", t)
+		}
+		prefs = append(prefs, Preference{Type: t, Enabled: enabled})
+	}
+
+	if err := s.repo.SavePreferences(ctx, userID, prefs); err != nil {
+		return nil, err
+	}
+	return s.GetPreferences(ctx, userID)
+}
+
+// recipients collects users to notify about one event, each user gets one notification
+// of the first type added for them. Actor is never notified about own actions
+type recipients struct {
+	seen   map[int64]bool
+	types  []string
+	byType map[string][]int64
+}
+
+func newRecipients(actorID *int64) *recipients {
+	r := &recipients{seen: make(map[int64]bool), byType: make(map[string][]int64)}
+	if actorID != nil {
+		r.seen[*actorID] = true
+	}
+	return r
+}
+
+func (r *recipients) add(notificationType string, userIDs ...int64) {
+	for _, id := range userIDs {
+		if r.seen[id] {
+			continue
+		}
+		r.seen[id] = true
+		if _, ok := r.byType[notificationType]; !ok {
+			r.types = append(r.types, notificationType)
+		}
+		r.byType[notificationType] = append(r.byType[notificationType], id)
+	}
+}
+
+// Consume makes notifications for event in relay transaction
+func (s *Service) Consume(ctx context.Context, tx *sqlx.Tx, e event.Event) error {
+	base := Notification{EventID: e.ID, ProjectID: e.ProjectID, ActorID: e.ActorID}
+	var details Details
+	to := newRecipients(e.ActorID)
+
+	switch e.Type {
+	case event.TicketCreated:
+		var t ticket.Ticket
+		if err := json.Unmarshal(e.Data, &t); err != nil {
+			return err
+		}
+		base.TicketID = &t.ID
+		details = ticketDetails(&t)
+
+		if t.AssigneeID != nil {
+			to.add(TypeAssigned, *t.AssigneeID)
+		}
+		mentioned, err := s.repo.UserIDsByUsernames(ctx, tx, ParseMentions(t.Description))
+		if err != nil {
+			return err
+		}
+		to.add(TypeMentioned, mentioned...)
+
+	case event.TicketUpdated:
+		var data ticket.TicketUpdatedData
+		if err := json.Unmarshal(e.Data, &data); err != nil {
+			return err
+		}
+		if data.Ticket == nil {
+			return nil
+		}
+		base.TicketID = &data.Ticket.ID
+		details = ticketDetails(data.Ticket)
+
+		// assignment goes first, new assignee is told about it rather than about status
+		for _, c := range data.Changes {
+			if c.Field == ticket.FieldAssigneeID {
+				if assigneeID := ticket.ParseInt(c.NewValue); assigneeID != nil {
+					to.add(TypeAssigned, *assigneeID)
+				}
+			}
+		}
+		for _, c := range data.Changes {
+			if c.Field == ticket.FieldStatus {
+				details.Status = data.Ticket.Status
+				watchers, err := s.repo.WatcherIDs(ctx, tx, data.Ticket.ID)
+				if err != nil {
+					return err
+				}
+				to.add(TypeStatusChanged, watchers...)
+			}
+		}
+
+	case event.CommentAdded:
+		var data comment.CommentAddedData
+		if err := json.Unmarshal(e.Data, &data); err != nil {
+			return err
+		}
+		if data.Comment == nil || data.Ticket == nil {
+			return nil
+		}
+		base.TicketID = &data.Ticket.ID
+		details = ticketDetails(data.Ticket)
+		details.CommentID = data.Comment.ID
+		details.Excerpt = excerpt(data.Comment.Body)
+
+		mentioned, err := s.repo.UserIDsByUsernames(ctx, tx, ParseMentions(data.Comment.Body))
+		if err != nil {
+			return err
+		}
+		to.add(TypeMentioned, mentioned...)
+		watchers, err := s.repo.WatcherIDs(ctx, tx, data.Ticket.ID)
+		if err != nil {
+			return err
+		}
+		to.add(TypeCommented, watchers...)
+
+	case event.MemberAdded:
+		var pm projectmember.ProjectMember
+		if err := json.Unmarshal(e.Data, &pm); err != nil {
+			return err
+		}
+		details.Role = pm.Role
+		to.add(TypeInvited, pm.UserID)
+
+	default:
+		return nil
+	}
+
+	data, err := json.Marshal(details)
+	if err != nil {
+		return err
+	}
+	base.Data = data
+
+	for _, t := range to.types {
+		n := base
+		n.Type = t
+		if err := s.repo.CreateForUsers(ctx, tx, &n, to.byType[t]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ticketDetails(t *ticket.Ticket) Details {
+	return Details{TicketKey: t.Key, TicketTitle: t.Title}
+}
+
+// excerpt cuts text to excerptLength characters
+func excerpt(text string) string {
+	runes := []rune(text)
+	if len(runes) <= excerptLength {
+		return text
+	}
+	return string(runes[:excerptLength]) + "…"
+}
@@ -0,0 +1,294 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/comment"
+	"github.com/antonovs105/project-management-system-go/internal/event"
+	"github.com/antonovs105/project-management-system-go/internal/projectmember"
+	"github.com/antonovs105/project-management-system-go/internal/ticket"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestParseMentions(t *testing.T) {
+	assert.Equal(t, []string{"bob", "alice.smith"}, ParseMentions("@Bob please ask @alice.smith. Thanks @bob"))
+	assert.Empty(t, ParseMentions("mail me at bob@example.com"))
+}
+
+func newEvent(t *testing.T, eventType string, data any) event.Event {
+	raw, err := json.Marshal(data)
+	assert.NoError(t, err)
+	actorID := int64(1)
+	return event.Event{ID: 50, ProjectID: 10, Type: eventType, ActorID: &actorID, Data: raw}
+}
+
+// ofType matches notification of given type
+func ofType(notificationType string) any {
+	return mock.MatchedBy(func(n *Notification) bool {
+		return n.Type == notificationType && n.EventID == 50 && n.ProjectID == 10
+	})
+}
+
+func TestService_Consume(t *testing.T) {
+	ctx := context.Background()
+	var tx *sqlx.Tx
+	assignee := int64(2)
+	watched := &ticket.Ticket{ID: 5, Key: "PMS-5", Title: "Login", ProjectID: 10, ReporterID: 3, AssigneeID: &assignee, Status: "done"}
+
+	t.Run("TicketCreated", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, nil, EmailConfig{})
+
+		created := *watched
+		created.Description = "cc @carol and @me"
+		mockRepo.On("UserIDsByUsernames", ctx, tx, []string{"carol", "me"}).Return([]int64{4, 1}, nil).Once()
+		mockRepo.On("CreateForUsers", ctx, tx, ofType(TypeAssigned), []int64{2}).Return(nil).Once()
+		mockRepo.On("CreateForUsers", ctx, tx, ofType(TypeMentioned), []int64{4}).Return(nil).Once()
+
+		err := service.Consume(ctx, tx, newEvent(t, event.TicketCreated, created))
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("StatusAndAssigneeChanged", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, nil, EmailConfig{})
+
+		data := ticket.TicketUpdatedData{Ticket: watched, Changes: []ticket.Change{
+			{Field: ticket.FieldStatus, NewValue: &watched.Status},
+			{Field: ticket.FieldAssigneeID, NewValue: ticket.FormatInt(&assignee)},
+		}}
+		mockRepo.On("WatcherIDs", ctx, tx, int64(5)).Return([]int64{2, 3, 6}, nil).Once()
+		mockRepo.On("CreateForUsers", ctx, tx, ofType(TypeAssigned), []int64{2}).Return(nil).Once()
+		// new assignee is told about assignment only
+		mockRepo.On("CreateForUsers", ctx, tx, mock.MatchedBy(func(n *Notification) bool {
+			return n.Type == TypeStatusChanged && *n.TicketID == 5 &&
+				assert.JSONEq(t, `{"ticket_key":"PMS-5","ticket_title":"Login","status":"done"}`, string(n.Data))
+		}), []int64{3, 6}).Return(nil).Once()
+
+		err := service.Consume(ctx, tx, newEvent(t, event.TicketUpdated, data))
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("CommentAdded", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, nil, EmailConfig{})
+
+		data := comment.CommentAddedData{Comment: &comment.Comment{ID: 9, Body: "@reporter see this"}, Ticket: watched}
+		mockRepo.On("UserIDsByUsernames", ctx, tx, []string{"reporter"}).Return([]int64{3}, nil).Once()
+		mockRepo.On("CreateForUsers", ctx, tx, ofType(TypeMentioned), []int64{3}).Return(nil).Once()
+		mockRepo.On("WatcherIDs", ctx, tx, int64(5)).Return([]int64{1, 2, 3, 8}, nil).Once()
+		// reporter was mentioned and commenter is actor, so other watchers get comment notification
+		mockRepo.On("CreateForUsers", ctx, tx, ofType(TypeCommented), []int64{2, 8}).Return(nil).Once()
+
+		err := service.Consume(ctx, tx, newEvent(t, event.CommentAdded, data))
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("MemberAdded", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, nil, EmailConfig{})
+
+		pm := projectmember.ProjectMember{UserID: 7, ProjectID: 10, Role: "member"}
+		mockRepo.On("CreateForUsers", ctx, tx, mock.MatchedBy(func(n *Notification) bool {
+			return n.Type == TypeInvited && n.TicketID == nil && string(n.Data) == `{"role":"member"}`
+		}), []int64{7}).Return(nil).Once()
+
+		err := service.Consume(ctx, tx, newEvent(t, event.MemberAdded, pm))
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("OtherEvent", func(t *testing.T) {
+		service := NewService(new(MockRepository), nil, EmailConfig{})
+
+		err := service.Consume(ctx, tx, newEvent(t, event.LinkAdded, ticket.TicketLink{ID: 1}))
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestService_Preferences(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewService(mockRepo, nil, EmailConfig{})
+
+	ctx := context.Background()
+
+	t.Run("Update", func(t *testing.T) {
+		mockRepo.On("SavePreferences", ctx, int64(1), []Preference{{Type: TypeCommented, Enabled: false}}).Return(nil).Once()
+		mockRepo.On("ListPreferences", ctx, int64(1)).Return([]Preference{{Type: TypeCommented, Enabled: false}}, nil).Once()
+
+		prefs, err := service.UpdatePreferences(ctx, 1, map[string]bool{TypeCommented: false})
+
+		assert.NoError(t, err)
+		assert.False(t, prefs[TypeCommented])
+		assert.True(t, prefs[TypeAssigned])
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("UnknownType", func(t *testing.T) {
+		_, err := service.UpdatePreferences(ctx, 1, map[string]bool{"digest": true})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestService_MarkRead(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewService(mockRepo, nil, EmailConfig{})
+
+	ctx := context.Background()
+	mockRepo.On("MarkRead", ctx, int64(5), int64(2)).Return(errors.New("notification not found")).Once()
+
+	err := service.MarkRead(ctx, 5, 2)
+
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestUnsubscribeToken(t *testing.T) {
+	secret := []byte("secret")
+	token := UnsubscribeToken(secret, 42)
+
+	userID, err := ParseUnsubscribeToken(secret, token)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), userID)
+
+	_, err = ParseUnsubscribeToken([]byte("other"), token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, err = ParseUnsubscribeToken(secret, "43"+token[2:])
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func emailItem(id int64, notificationType string, details Details) EmailItem {
+	data, _ := json.Marshal(details)
+	return EmailItem{
+		Notification: Notification{ID: id, UserID: 2, Type: notificationType, ProjectID: 10, Data: data},
+		ActorName:    "alice",
+		ProjectName:  "Website",
+	}
+}
+
+func TestService_SendEmails(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	cfg := EmailConfig{AppURL: "https://app.test", APIURL: "https://api.test", Secret: []byte("secret")}
+	due := func(m *MockRepository, r Recipient) {
+		m.On("ListDueRecipients", ctx, now.Add(-emailBatchDelay), now.Add(-digestInterval), recipientsBatch).Return([]Recipient{r}, nil).Once()
+	}
+
+	t.Run("Immediate", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mailer := &RecordingMailer{}
+		service := NewService(mockRepo, mailer, cfg)
+		service.now = func() time.Time { return now }
+
+		due(mockRepo, Recipient{UserID: 2, Username: "bob", Email: "bob@example.com", Mode: EmailImmediate})
+		mockRepo.On("ClaimPending", ctx, int64(2), maxEmailItems).Return([]EmailItem{
+			emailItem(7, TypeAssigned, Details{TicketKey: "PMS-5", TicketTitle: "Login <form>"}),
+		}, nil).Once()
+
+		sent, err := service.SendEmails(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, sent)
+		assert.Len(t, mailer.Sent, 1)
+		msg := mailer.Sent[0]
+		assert.Equal(t, "bob@example.com", msg.To)
+		assert.Equal(t, "[PMS-5] alice assigned you: Login <form>", msg.Subject)
+		assert.Contains(t, msg.Text, "https://app.test/projects/10?ticket=PMS-5")
+		assert.Contains(t, msg.HTML, "Login &lt;form&gt;")
+		assert.Equal(t, "<https://api.test/unsubscribe?token="+UnsubscribeToken(cfg.Secret, 2)+">", msg.Headers["List-Unsubscribe"])
+		assert.Equal(t, "List-Unsubscribe=One-Click", msg.Headers["List-Unsubscribe-Post"])
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("DailyDigest", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mailer := &RecordingMailer{}
+		service := NewService(mockRepo, mailer, cfg)
+		service.now = func() time.Time { return now }
+
+		due(mockRepo, Recipient{UserID: 2, Username: "bob", Email: "bob@example.com", Mode: EmailDaily})
+		mockRepo.On("ClaimPending", ctx, int64(2), maxEmailItems).Return([]EmailItem{
+			emailItem(9, TypeCommented, Details{TicketKey: "PMS-5", TicketTitle: "Login", Excerpt: "looks good"}),
+			emailItem(8, TypeInvited, Details{Role: "member"}),
+		}, nil).Once()
+		mockRepo.On("SetDigestSent", ctx, int64(2), now).Return(nil).Once()
+
+		sent, err := service.SendEmails(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, sent)
+		msg := mailer.Sent[0]
+		assert.Equal(t, "Your daily digest: 2 notifications", msg.Subject)
+		assert.Contains(t, msg.Text, "looks good")
+		assert.Contains(t, msg.Text, "https://app.test/projects/10\n")
+		// items go in order they happened
+		assert.Less(t, strings.Index(msg.Text, "Website"), strings.Index(msg.Text, "looks good"))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("SendFailedReleasesClaim", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, &RecordingMailer{Err: errors.New("smtp down")}, cfg)
+		service.now = func() time.Time { return now }
+
+		due(mockRepo, Recipient{UserID: 2, Username: "bob", Email: "bob@example.com", Mode: EmailImmediate})
+		mockRepo.On("ClaimPending", ctx, int64(2), maxEmailItems).Return([]EmailItem{
+			emailItem(7, TypeMentioned, Details{TicketKey: "PMS-5", TicketTitle: "Login"}),
+		}, nil).Once()
+		mockRepo.On("ReleasePending", ctx, []int64{7}).Return(nil).Once()
+
+		sent, err := service.SendEmails(ctx)
+
+		assert.NoError(t, err)
+		assert.Zero(t, sent)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestService_EmailSettings(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewService(mockRepo, nil, EmailConfig{Secret: []byte("secret")})
+
+	ctx := context.Background()
+
+	t.Run("UpdateMode", func(t *testing.T) {
+		mockRepo.On("SaveEmailMode", ctx, int64(1), EmailDaily).Return(nil).Once()
+		mockRepo.On("GetEmailSettings", ctx, int64(1)).Return(&EmailSettings{UserID: 1, Mode: EmailDaily}, nil).Once()
+
+		settings, err := service.UpdateEmailMode(ctx, 1, EmailDaily)
+
+		assert.NoError(t, err)
+		assert.Equal(t, EmailDaily, settings.Mode)
+	})
+
+	t.Run("UnknownMode", func(t *testing.T) {
+		_, err := service.UpdateEmailMode(ctx, 1, "weekly")
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Unsubscribe", func(t *testing.T) {
+		mockRepo.On("SaveEmailMode", ctx, int64(3), EmailOff).Return(nil).Once()
+
+		assert.NoError(t, service.CheckUnsubscribeToken(UnsubscribeToken([]byte("secret"), 3)))
+		assert.ErrorIs(t, service.CheckUnsubscribeToken("3.forged"), ErrInvalidToken)
+		assert.NoError(t, service.Unsubscribe(ctx, UnsubscribeToken([]byte("secret"), 3)))
+		assert.ErrorIs(t, service.Unsubscribe(ctx, "3.forged"), ErrInvalidToken)
+		mockRepo.AssertExpectations(t)
+	})
+}
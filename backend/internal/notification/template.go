@@ -0,0 +1,106 @@
+package notification
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+// unsubscribePage asks to confirm unsubscribe and tells when it is done
+var unsubscribePage = htmltemplate.Must(htmltemplate.ParseFS(templateFS, "templates/unsubscribe.html"))
+
+// emailTemplates are parsed once, each notification type has html and text template
+// defining "item", text one also defines "subject"
+var emailTemplates = loadTemplates()
+
+type templateSet struct {
+	htmlLayout *htmltemplate.Template
+	textLayout *texttemplate.Template
+	html       map[string]*htmltemplate.Template
+	text       map[string]*texttemplate.Template
+}
+
+func loadTemplates() *templateSet {
+	set := &templateSet{
+		htmlLayout: htmltemplate.Must(htmltemplate.ParseFS(templateFS, "templates/layout.html")),
+		textLayout: texttemplate.Must(texttemplate.ParseFS(templateFS, "templates/layout.txt")),
+		html:       make(map[string]*htmltemplate.Template),
+		text:       make(map[string]*texttemplate.Template),
+	}
+	for _, t := range Types {
+		set.html[t] = htmltemplate.Must(htmltemplate.ParseFS(templateFS, "templates/"+t+".html"))
+		set.text[t] = texttemplate.Must(texttemplate.ParseFS(templateFS, "templates/"+t+".txt"))
+	}
+	return set
+}
+
+// itemView is data of one notification in email
+type itemView struct {
+	Details
+	Actor   string
+	Project string
+	URL     string
+}
+
+// renderedItem is notification rendered for both email bodies
+type renderedItem struct {
+	HTML htmltemplate.HTML
+	Text string
+}
+
+// emailView is data of email layout
+type emailView struct {
+	Username       string
+	Digest         bool
+	Items          []renderedItem
+	SettingsURL    string
+	UnsubscribeURL string
+}
+
+// subject renders subject of email about single notification
+func (ts *templateSet) subject(notificationType string, item itemView) (string, error) {
+	tmpl, ok := ts.text[notificationType]
+	if !ok {
+		return "", fmt.Errorf("no template for notification type %q", notificationType)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "subject", item); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// item renders one notification
+func (ts *templateSet) item(notificationType string, item itemView) (renderedItem, error) {
+	htmlTmpl, ok := ts.html[notificationType]
+	if !ok {
+		return renderedItem{}, fmt.Errorf("no template for notification type %q", notificationType)
+	}
+	var htmlBuf, textBuf bytes.Buffer
+	if err := htmlTmpl.ExecuteTemplate(&htmlBuf, "item", item); err != nil {
+		return renderedItem{}, err
+	}
+	if err := ts.text[notificationType].ExecuteTemplate(&textBuf, "item", item); err != nil {
+		return renderedItem{}, err
+	}
+	// item html is escaped by its own template
+	return renderedItem{HTML: htmltemplate.HTML(htmlBuf.String()), Text: textBuf.String()}, nil
+}
+
+// bodies renders html and text email bodies
+func (ts *templateSet) bodies(view emailView) (html, text string, err error) {
+	var htmlBuf, textBuf bytes.Buffer
+	if err := ts.htmlLayout.Execute(&htmlBuf, view); err != nil {
+		return "", "", err
+	}
+	if err := ts.textLayout.Execute(&textBuf, view); err != nil {
+		return "", "", err
+	}
+	return htmlBuf.String(), textBuf.String(), nil
+}
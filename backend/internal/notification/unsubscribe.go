@@ -0,0 +1,37 @@
+package notification
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidToken = errors.New("invalid unsubscribe token")
+
+// UnsubscribeToken signs user id, link with it turns off emails without login
+func UnsubscribeToken(secret []byte, userID int64) string {
+	id := strconv.FormatInt(userID, 10)
+	return id + "." + signUnsubscribe(secret, id)
+}
+
+// ParseUnsubscribeToken checks token signature and returns user id
+func ParseUnsubscribeToken(secret []byte, token string) (int64, error) {
+	id, sig, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(signUnsubscribe(secret, id))) {
+		return 0, ErrInvalidToken
+	}
+	userID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0, ErrInvalidToken
+	}
+	return userID, nil
+}
+
+func signUnsubscribe(secret []byte, id string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte("unsubscribe:" + id))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,188 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Page size limits
+const (
+	DefaultLimit = 50
+	MaxLimit     = 200
+)
+
+// ErrInvalidCursor is returned for cursors which can't be decoded or were made for other sort
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// SortField is sortable column. Cast is SQL type of cursor value, e.g. timestamptz
+type SortField struct {
+	Column string
+	Cast   string
+}
+
+// Cursor points after last row of previous page: its sort value and id
+type Cursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    int64  `json:"id"`
+}
+
+// Encode makes opaque cursor string
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses cursor made by Encode
+func DecodeCursor(s string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID <= 0 {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// Params is requested page
+type Params struct {
+	Limit int
+	// Sort is field name, Desc is set by "-" prefix in query: sort=-updated
+	Sort  string
+	Desc  bool
+	After *Cursor
+}
+
+// Parse reads limit, sort and cursor from query string
+func Parse(q url.Values, defaultSort string, fields map[string]SortField) (Params, error) {
+	p := Params{Limit: DefaultLimit}
+
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			return p, errors.New("limit must be positive number")
+		}
+		p.Limit = min(limit, MaxLimit)
+	}
+
+	sort := q.Get("sort")
+	if sort == "" {
+		sort = defaultSort
+	}
+	if strings.HasPrefix(sort, "-") {
+		p.Desc = true
+		sort = sort[1:]
+	}
+	if _, ok := fields[sort]; !ok {
+		return p, fmt.Errorf("can't sort by %q", sort)
+	}
+	p.Sort = sort
+
+	if v := q.Get("cursor"); v != "" {
+		c, err := DecodeCursor(v)
+		if err != nil {
+			return p, err
+		}
+		if c.Sort != p.sortKey() || !validValue(fields[sort].Cast, c.Value) {
+			return p, ErrInvalidCursor
+		}
+		p.After = c
+	}
+	return p, nil
+}
+
+// validValue checks cursor value can be cast to SQL type, so tampered cursor fails before query
+func validValue(cast, value string) bool {
+	switch cast {
+	case "timestamptz":
+		_, err := time.Parse(time.RFC3339Nano, value)
+		return err == nil
+	case "bigint", "int":
+		_, err := strconv.ParseInt(value, 10, 64)
+		return err == nil
+	default:
+		return true
+	}
+}
+
+// sortKey identifies sort with direction, cursor is valid only for the same sort
+func (p Params) sortKey() string {
+	if p.Desc {
+		return "-" + p.Sort
+	}
+	return p.Sort
+}
+
+// Keyset returns condition selecting rows after cursor ("TRUE" without cursor) and ORDER BY
+// with idColumn as tie breaker. Cursor values are added to args, placeholders continue numbering
+func (p Params) Keyset(fields map[string]SortField, idColumn string, args []any) (cond, orderBy string, newArgs []any) {
+	f := fields[p.Sort]
+	dir, cmp := "ASC", ">"
+	if p.Desc {
+		dir, cmp = "DESC", "<"
+	}
+	orderBy = f.Column + " " + dir + ", " + idColumn + " " + dir
+
+	if p.After == nil {
+		return "TRUE", orderBy, args
+	}
+	args = append(args, p.After.Value, p.After.ID)
+	n := len(args)
+	cond = fmt.Sprintf("(%s, %s) %s ($%d::%s, $%d)", f.Column, idColumn, cmp, n-1, f.Cast, n)
+	return cond, orderBy, args
+}
+
+// Cursor makes cursor after row with given sort value
+func (p Params) Cursor(value string, id int64) Cursor {
+	return Cursor{Sort: p.sortKey(), Value: value, ID: id}
+}
+
+// Page is one page of items. Next is empty on the last page
+type Page[T any] struct {
+	Items []T
+	Total int
+	Next  string
+}
+
+// NewPage trims rows fetched with limit+1 to page and makes next cursor from last item
+func NewPage[T any](rows []T, total int, p Params, cursorOf func(item *T) Cursor) *Page[T] {
+	page := &Page[T]{Items: rows, Total: total}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+	if len(rows) > p.Limit {
+		page.Items = rows[:p.Limit]
+		page.Next = cursorOf(&page.Items[p.Limit-1]).Encode()
+	}
+	return page
+}
+
+// SetHeaders writes X-Total-Count and Link with first and next page built from request URL
+func SetHeaders(h http.Header, u *url.URL, total int, next string) {
+	h.Set("X-Total-Count", strconv.Itoa(total))
+
+	link := func(cursor, rel string) string {
+		q := u.Query()
+		q.Del("cursor")
+		if cursor != "" {
+			q.Set("cursor", cursor)
+		}
+		target := url.URL{Path: u.Path, RawQuery: q.Encode()}
+		return fmt.Sprintf("<%s>; rel=\"%s\"", target.String(), rel)
+	}
+
+	links := []string{link("", "first")}
+	if next != "" {
+		links = append(links, link(next, "next"))
+	}
+	h.Set("Link", strings.Join(links, ", "))
+}
@@ -0,0 +1,125 @@
+package pagination
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var fields = map[string]SortField{
+	"created": {Column: "created_at", Cast: "timestamptz"},
+	"name":    {Column: "name", Cast: "text"},
+}
+
+func TestParse(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		p, err := Parse(url.Values{}, "-created", fields)
+
+		assert.NoError(t, err)
+		assert.Equal(t, DefaultLimit, p.Limit)
+		assert.Equal(t, "created", p.Sort)
+		assert.True(t, p.Desc)
+		assert.Nil(t, p.After)
+	})
+
+	t.Run("LimitCapped", func(t *testing.T) {
+		p, err := Parse(url.Values{"limit": {"1000"}, "sort": {"name"}}, "-created", fields)
+
+		assert.NoError(t, err)
+		assert.Equal(t, MaxLimit, p.Limit)
+		assert.False(t, p.Desc)
+	})
+
+	t.Run("InvalidInput", func(t *testing.T) {
+		for _, q := range []url.Values{
+			{"limit": {"0"}},
+			{"limit": {"abc"}},
+			{"sort": {"password"}},
+			{"cursor": {"!!!"}},
+		} {
+			_, err := Parse(q, "-created", fields)
+			assert.Error(t, err, q.Encode())
+		}
+	})
+
+	t.Run("CursorOfOtherSort", func(t *testing.T) {
+		c := Cursor{Sort: "name", Value: "a", ID: 1}.Encode()
+
+		_, err := Parse(url.Values{"cursor": {c}, "sort": {"-name"}}, "-created", fields)
+
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	})
+
+	t.Run("CursorWithBadValue", func(t *testing.T) {
+		c := Cursor{Sort: "-created", Value: "yesterday", ID: 1}.Encode()
+
+		_, err := Parse(url.Values{"cursor": {c}}, "-created", fields)
+
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	})
+
+	t.Run("CursorRoundTrip", func(t *testing.T) {
+		c := Cursor{Sort: "-name", Value: "beta", ID: 7}.Encode()
+
+		p, err := Parse(url.Values{"cursor": {c}, "sort": {"-name"}}, "-created", fields)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &Cursor{Sort: "-name", Value: "beta", ID: 7}, p.After)
+	})
+}
+
+func TestParams_Keyset(t *testing.T) {
+	t.Run("FirstPage", func(t *testing.T) {
+		p := Params{Limit: 10, Sort: "created", Desc: true}
+
+		cond, orderBy, args := p.Keyset(fields, "id", []any{int64(5)})
+
+		assert.Equal(t, "TRUE", cond)
+		assert.Equal(t, "created_at DESC, id DESC", orderBy)
+		assert.Equal(t, []any{int64(5)}, args)
+	})
+
+	t.Run("AfterCursor", func(t *testing.T) {
+		p := Params{Limit: 10, Sort: "name", After: &Cursor{Sort: "name", Value: "beta", ID: 7}}
+
+		cond, orderBy, args := p.Keyset(fields, "id", []any{int64(5)})
+
+		assert.Equal(t, "(name, id) > ($2::text, $3)", cond)
+		assert.Equal(t, "name ASC, id ASC", orderBy)
+		assert.Equal(t, []any{int64(5), "beta", int64(7)}, args)
+	})
+}
+
+func TestNewPage(t *testing.T) {
+	p := Params{Limit: 2, Sort: "name"}
+	cursorOf := func(s *string) Cursor { return p.Cursor(*s, int64(len(*s))) }
+
+	t.Run("HasNext", func(t *testing.T) {
+		page := NewPage([]string{"a", "bb", "ccc"}, 5, p, cursorOf)
+
+		assert.Equal(t, []string{"a", "bb"}, page.Items)
+		assert.Equal(t, 5, page.Total)
+		next, err := DecodeCursor(page.Next)
+		assert.NoError(t, err)
+		assert.Equal(t, &Cursor{Sort: "name", Value: "bb", ID: 2}, next)
+	})
+
+	t.Run("LastPage", func(t *testing.T) {
+		page := NewPage[string](nil, 0, p, cursorOf)
+
+		assert.Equal(t, []string{}, page.Items)
+		assert.Empty(t, page.Next)
+	})
+}
+
+func TestSetHeaders(t *testing.T) {
+	u, _ := url.Parse("/api/projects?sort=name&cursor=old&limit=2")
+	h := http.Header{}
+
+	SetHeaders(h, u, 5, "next")
+
+	assert.Equal(t, "5", h.Get("X-Total-Count"))
+	assert.Equal(t, `</api/projects?limit=2&sort=name>; rel="first", </api/projects?cursor=next&limit=2&sort=name>; rel="next"`, h.Get("Link"))
+}
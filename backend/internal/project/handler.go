@@ -1,10 +1,13 @@
 package project
 
 import (
+	"errors"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 
+	"github.com/antonovs105/project-management-system-go/internal/pagination"
 	"github.com/labstack/echo/v4"
 )
 
@@ -18,6 +21,7 @@ func NewHandler(service *Service) *Handler {
 
 type createProjectRequest struct {
 	Name        string `json:"name"`
+	Key         string `json:"key"`
 	Description string `json:"description"`
 }
 
@@ -31,8 +35,11 @@ func (h *Handler) Create(c echo.Context) error {
 	// Taking userID from context
 	userID := c.Get("userID").(int64)
 
-	project, err := h.service.CreateProject(c.Request().Context(), req.Name, req.Description, userID)
+	project, err := h.service.CreateProject(c.Request().Context(), req.Name, req.Key, req.Description, userID)
 	if err != nil {
+		if errors.Is(err, ErrInvalidKey) || errors.Is(err, ErrKeyInUse) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create project"})
 	}
 
@@ -61,14 +68,21 @@ func (h *Handler) Get(c echo.Context) error {
 func (h *Handler) List(c echo.Context) error {
 	userID := c.Get("userID").(int64)
 
+	page, err := pagination.Parse(c.QueryParams(), DefaultSort, SortFields)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	filter := ListFilter{Name: strings.TrimSpace(c.QueryParam("q"))}
+
 	// Call service for projects list
-	projects, err := h.service.ListUserProjects(c.Request().Context(), userID)
+	result, err := h.service.ListUserProjects(c.Request().Context(), userID, filter, page)
 	if err != nil {
 		log.Printf("Error listing user projects: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve projects"})
 	}
 
-	return c.JSON(http.StatusOK, projects)
+	pagination.SetHeaders(c.Response().Header(), c.Request().URL, result.Total, result.Next)
+	return c.JSON(http.StatusOK, result.Items)
 }
 
 // Update handler for PATCH /api/projects/:id
@@ -91,6 +105,12 @@ func (h *Handler) Update(c echo.Context) error {
 	// call service for update
 	err = h.service.UpdateProject(c.Request().Context(), projectID, userID, req)
 	if err != nil {
+		if errors.Is(err, ErrInvalidKey) || errors.Is(err, ErrKeyInUse) || errors.Is(err, ErrInvalidTimezone) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		if errors.Is(err, ErrForbidden) {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+		}
 		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
 	}
 
@@ -0,0 +1,85 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// keyPattern is format of project key, e.g. PMS
+var keyPattern = regexp.MustCompile(`^[A-Z][A-Z0-9]{1,9}$`)
+
+// maxKeyLength matches projects.key column
+const maxKeyLength = 10
+
+// ErrInvalidKey is returned for keys not matching keyPattern
+var ErrInvalidKey = errors.New("invalid project key: 2-10 uppercase letters or digits, starting with a letter")
+
+// ErrKeyInUse is returned when key belongs to another project
+var ErrKeyInUse = errors.New("project key already in use")
+
+// NormalizeKey uppercases key and checks its format
+func NormalizeKey(key string) (string, error) {
+	key = strings.ToUpper(strings.TrimSpace(key))
+	if !keyPattern.MatchString(key) {
+		return "", ErrInvalidKey
+	}
+	return key, nil
+}
+
+// keyFromName builds key candidate from project name:
+// initials for several words ("Project Management System" -> PMS), first letters for one word
+func keyFromName(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !(r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)))
+	})
+
+	var b strings.Builder
+	if len(words) > 1 {
+		for _, w := range words {
+			b.WriteByte(w[0])
+		}
+	} else if len(words) == 1 {
+		b.WriteString(words[0])
+		if b.Len() > 4 {
+			return normalizeCandidate(b.String()[:4])
+		}
+	}
+	return normalizeCandidate(b.String())
+}
+
+func normalizeCandidate(candidate string) string {
+	candidate = strings.ToUpper(strings.TrimLeftFunc(candidate, unicode.IsDigit))
+	if len(candidate) > maxKeyLength {
+		candidate = candidate[:maxKeyLength]
+	}
+	for len(candidate) < 2 {
+		candidate += "P"
+	}
+	return candidate
+}
+
+// generateKey finds free key for project name, adding numeric suffix on collisions
+func (s *Service) generateKey(ctx context.Context, name string) (string, error) {
+	base := keyFromName(name)
+	candidate := base
+	for i := 2; i < 100; i++ {
+		inUse, err := s.repo.KeyInUse(ctx, candidate, 0)
+		if err != nil {
+			return "", err
+		}
+		if !inUse {
+			return candidate, nil
+		}
+		suffix := fmt.Sprint(i)
+		if len(base)+len(suffix) > maxKeyLength {
+			candidate = base[:maxKeyLength-len(suffix)] + suffix
+		} else {
+			candidate = base + suffix
+		}
+	}
+	return "", errors.New("could not generate project key, please provide one")
+}
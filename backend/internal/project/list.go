@@ -0,0 +1,50 @@
+package project
+
+import (
+	"context"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/pagination"
+)
+
+// DefaultSort is newest projects first
+const DefaultSort = "-created"
+
+// SortFields are fields projects can be sorted by
+var SortFields = map[string]pagination.SortField{
+	"created": {Column: "created_at", Cast: "timestamptz"},
+	"updated": {Column: "updated_at", Cast: "timestamptz"},
+	"name":    {Column: "name", Cast: "text"},
+	"key":     {Column: "key", Cast: "text"},
+}
+
+// sortValue returns value of sort field as cursor value
+func sortValue(p *Project, field string) string {
+	switch field {
+	case "updated":
+		return p.UpdatedAt.Format(time.RFC3339Nano)
+	case "name":
+		return p.Name
+	case "key":
+		return p.Key
+	default:
+		return p.CreatedAt.Format(time.RFC3339Nano)
+	}
+}
+
+// ListFilter narrows project list
+type ListFilter struct {
+	// Name matches part of project name or key, case insensitive
+	Name string
+}
+
+// ListUserProjects returns page of projects user owns or joined as member
+func (s *Service) ListUserProjects(ctx context.Context, userID int64, filter ListFilter, page pagination.Params) (*pagination.Page[Project], error) {
+	projects, total, err := s.repo.ListPage(ctx, userID, filter, page)
+	if err != nil {
+		return nil, err
+	}
+	return pagination.NewPage(projects, total, page, func(p *Project) pagination.Cursor {
+		return page.Cursor(sortValue(p, page.Sort), p.ID)
+	}), nil
+}
@@ -3,10 +3,15 @@ package project
 import "time"
 
 type Project struct {
-	ID          int64     `db:"id"`
-	Name        string    `db:"name"`
-	Description string    `db:"description"`
-	OwnerID     int64     `db:"owner_id"`
-	CreatedAt   time.Time `db:"created_at"`
-	UpdatedAt   time.Time `db:"updated_at"`
+	ID          int64  `db:"id"`
+	Name        string `db:"name"`
+	Key         string `db:"key"`
+	Description string `db:"description"`
+	OwnerID     int64  `db:"owner_id"`
+	// Timezone is IANA name used for dates of project tickets
+	Timezone string `db:"timezone"`
+	// TicketCounter is last ticket number given in project
+	TicketCounter int64     `db:"ticket_counter"`
+	CreatedAt     time.Time `db:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at"`
 }
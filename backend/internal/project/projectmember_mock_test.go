@@ -20,6 +20,14 @@ func (m *MockMemberService) AddMember(ctx context.Context, userID, projectID int
 	return args.Get(0).(*projectmember.ProjectMember), args.Error(1)
 }
 
+func (m *MockMemberService) InviteMember(ctx context.Context, userID, projectID int64, role string, invitedBy int64) (*projectmember.ProjectMember, error) {
+	args := m.Called(ctx, userID, projectID, role, invitedBy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*projectmember.ProjectMember), args.Error(1)
+}
+
 func (m *MockMemberService) GetUserRole(ctx context.Context, userID, projectID int64) (string, error) {
 	args := m.Called(ctx, userID, projectID)
 	return args.String(0), args.Error(1)
@@ -3,16 +3,38 @@ package project
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 
+	"github.com/antonovs105/project-management-system-go/internal/pagination"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
+// keyConstraints are constraints which keep current keys and aliases unique, see migration 000028
+var keyConstraints = map[string]bool{
+	"projects_key_key":         true,
+	"project_key_aliases_pkey": true,
+	"project_keys_unique":      true,
+}
+
+// keyConflict turns violation of key uniqueness into ErrKeyInUse. KeyInUse is checked before
+// writing, so this only happens when another project takes the key meanwhile
+func keyConflict(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" && keyConstraints[pqErr.Constraint] {
+		return ErrKeyInUse
+	}
+	return err
+}
+
 type Repository interface {
 	Create(ctx context.Context, project *Project) error
 	GetByID(ctx context.Context, id int64) (*Project, error)
-	ListByOwnerID(ctx context.Context, ownerID int64) ([]Project, error)
+	ListPage(ctx context.Context, userID int64, filter ListFilter, page pagination.Params) ([]Project, int, error)
 	Update(ctx context.Context, project *Project) error
 	Delete(ctx context.Context, id int64) error
+	KeyInUse(ctx context.Context, key string, exceptProjectID int64) (bool, error)
 }
 
 type PgRepository struct {
@@ -26,13 +48,13 @@ func NewRepository(db *sqlx.DB) Repository {
 // Create makes new project in DB
 func (r *PgRepository) Create(ctx context.Context, project *Project) error {
 	query := `
-		INSERT INTO projects (name, description, owner_id)
-		VALUES (:name, :description, :owner_id)
+		INSERT INTO projects (name, key, description, owner_id, timezone)
+		VALUES (:name, :key, :description, :owner_id, :timezone)
 		RETURNING *`
 
 	rows, err := r.db.NamedQueryContext(ctx, query, project)
 	if err != nil {
-		return err
+		return keyConflict(err)
 	}
 
 	defer rows.Close()
@@ -42,6 +64,8 @@ func (r *PgRepository) Create(ctx context.Context, project *Project) error {
 		if err != nil {
 			return err
 		}
+	} else if err := rows.Err(); err != nil {
+		return keyConflict(err)
 	} else {
 		return errors.New("project creation failed: no returning row")
 	}
@@ -57,31 +81,54 @@ func (r *PgRepository) GetByID(ctx context.Context, id int64) (*Project, error)
 	return &p, err
 }
 
-// ListByOwnerID finds all prijects created by user
-func (r *PgRepository) ListByOwnerID(ctx context.Context, ownerID int64) ([]Project, error) {
-	var projects []Project
-
-	query := `SELECT * FROM projects WHERE owner_id = $1 ORDER BY created_at DESC`
+// ListPage returns page of projects user is member of (limit+1 rows to detect next page) and total count
+func (r *PgRepository) ListPage(ctx context.Context, userID int64, filter ListFilter, page pagination.Params) ([]Project, int, error) {
+	where := "id IN (SELECT project_id FROM project_members WHERE user_id = $1)"
+	args := []any{userID}
+	if filter.Name != "" {
+		args = append(args, likePattern(filter.Name))
+		where += ` AND (name ILIKE $2 ESCAPE '\' OR key ILIKE $2 ESCAPE '\')`
+	}
 
-	err := r.db.SelectContext(ctx, &projects, query, ownerID)
-	if err != nil {
-		return nil, err
+	var total int
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM projects WHERE `+where, args...); err != nil {
+		return nil, 0, err
 	}
 
-	return projects, nil
+	keyset, orderBy, args := page.Keyset(SortFields, "id", args)
+	args = append(args, page.Limit+1)
+	query := fmt.Sprintf(`SELECT * FROM projects WHERE %s AND %s ORDER BY %s LIMIT $%d`, where, keyset, orderBy, len(args))
+
+	var projects []Project
+	if err := r.db.SelectContext(ctx, &projects, query, args...); err != nil {
+		return nil, 0, err
+	}
+	return projects, total, nil
 }
 
-// Update updates (how unexpectable) project data in DB
+// Update saves name, description, timezone and key of project in one transaction.
+// Key change keeps old key as alias and rewrites ticket keys
 func (r *PgRepository) Update(ctx context.Context, project *Project) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := renameKey(ctx, tx, project.ID, project.Key); err != nil {
+		return keyConflict(err)
+	}
+
 	query := `
 		UPDATE projects 
 		SET 
 			name = :name,
 			description = :description,
+			timezone = :timezone,
 			updated_at = now()
 		WHERE id = :id`
 
-	result, err := r.db.NamedExecContext(ctx, query, project)
+	result, err := tx.NamedExecContext(ctx, query, project)
 	if err != nil {
 		return err
 	}
@@ -95,7 +142,7 @@ func (r *PgRepository) Update(ctx context.Context, project *Project) error {
 		return errors.New("no rows affected, project not found")
 	}
 
-	return nil
+	return tx.Commit()
 }
 
 // Delete deletes (wow) project from DB
@@ -118,3 +165,50 @@ func (r *PgRepository) Delete(ctx context.Context, id int64) error {
 
 	return nil
 }
+
+// KeyInUse checks if key is taken by another project, either as current key or old alias
+func (r *PgRepository) KeyInUse(ctx context.Context, key string, exceptProjectID int64) (bool, error) {
+	var inUse bool
+	query := `
+		SELECT EXISTS (SELECT 1 FROM projects WHERE key = $1 AND id <> $2)
+			OR EXISTS (SELECT 1 FROM project_key_aliases WHERE key = $1 AND project_id <> $2)`
+	err := r.db.GetContext(ctx, &inUse, query, key, exceptProjectID)
+	return inUse, err
+}
+
+// renameKey changes project key inside tx, keeps old one as alias and rewrites ticket keys
+func renameKey(ctx context.Context, tx *sqlx.Tx, projectID int64, newKey string) error {
+	var oldKey string
+	err := tx.GetContext(ctx, &oldKey, `SELECT key FROM projects WHERE id = $1 FOR UPDATE`, projectID)
+	if err != nil {
+		return err
+	}
+	if oldKey == newKey {
+		return nil
+	}
+
+	// renaming back to old key makes it current again
+	_, err = tx.ExecContext(ctx, `DELETE FROM project_key_aliases WHERE key = $1 AND project_id = $2`, newKey, projectID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO project_key_aliases (key, project_id) VALUES ($1, $2)`, oldKey, projectID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE projects SET key = $2, updated_at = now() WHERE id = $1`, projectID, newKey)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE tickets SET key = $2 || '-' || number WHERE project_id = $1`, projectID, newKey)
+	return err
+}
+
+// likePattern escapes LIKE wildcards and wraps text for substring match
+func likePattern(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + r.Replace(s) + "%"
+}
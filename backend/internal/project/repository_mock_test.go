@@ -3,6 +3,7 @@ package project
 import (
 	"context"
 
+	"github.com/antonovs105/project-management-system-go/internal/pagination"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -24,12 +25,12 @@ func (m *MockRepository) GetByID(ctx context.Context, id int64) (*Project, error
 	return args.Get(0).(*Project), args.Error(1)
 }
 
-func (m *MockRepository) ListByOwnerID(ctx context.Context, ownerID int64) ([]Project, error) {
-	args := m.Called(ctx, ownerID)
+func (m *MockRepository) ListPage(ctx context.Context, userID int64, filter ListFilter, page pagination.Params) ([]Project, int, error) {
+	args := m.Called(ctx, userID, filter, page)
 	if args.Get(0) == nil {
-		return nil, args.Error(1)
+		return nil, 0, args.Error(2)
 	}
-	return args.Get(0).([]Project), args.Error(1)
+	return args.Get(0).([]Project), args.Int(1), args.Error(2)
 }
 
 func (m *MockRepository) Update(ctx context.Context, project *Project) error {
@@ -41,3 +42,8 @@ func (m *MockRepository) Delete(ctx context.Context, id int64) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
+
+func (m *MockRepository) KeyInUse(ctx context.Context, key string, exceptProjectID int64) (bool, error) {
+	args := m.Called(ctx, key, exceptProjectID)
+	return args.Bool(0), args.Error(1)
+}
@@ -2,6 +2,7 @@ package project
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"log"
 
@@ -10,6 +11,7 @@ import (
 
 type MemberAdder interface {
 	AddMember(ctx context.Context, userID, projectID int64, role string) (*projectmember.ProjectMember, error)
+	InviteMember(ctx context.Context, userID, projectID int64, role string, invitedBy int64) (*projectmember.ProjectMember, error)
 	GetUserRole(ctx context.Context, userID, projectID int64) (string, error)
 }
 
@@ -25,17 +27,37 @@ func NewService(repo Repository, pmService MemberAdder) *Service {
 	}
 }
 
-// CreateProject is business logic for creating project
-func (s *Service) CreateProject(ctx context.Context, name, description string, userID int64) (*Project, error) {
-	// TODO: Business logc here
+// CreateProject is business logic for creating project. Empty key is generated from name
+func (s *Service) CreateProject(ctx context.Context, name, key, description string, userID int64) (*Project, error) {
+	var err error
+	if key == "" {
+		key, err = s.generateKey(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		key, err = NormalizeKey(key)
+		if err != nil {
+			return nil, err
+		}
+		inUse, err := s.repo.KeyInUse(ctx, key, 0)
+		if err != nil {
+			return nil, err
+		}
+		if inUse {
+			return nil, ErrKeyInUse
+		}
+	}
 
 	p := &Project{
 		Name:        name,
+		Key:         key,
 		Description: description,
 		OwnerID:     userID,
+		Timezone:    DefaultTimezone,
 	}
 
-	err := s.repo.Create(ctx, p)
+	err = s.repo.Create(ctx, p)
 	if err != nil {
 		return nil, err
 	}
@@ -52,6 +74,9 @@ func (s *Service) CreateProject(ctx context.Context, name, description string, u
 
 func (s *Service) GetProjectByID(ctx context.Context, projectID, userID int64) (*Project, error) {
 	project, err := s.repo.GetByID(ctx, projectID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -60,7 +85,7 @@ func (s *Service) GetProjectByID(ctx context.Context, projectID, userID int64) (
 	role, err := s.projectMemberService.GetUserRole(ctx, userID, projectID)
 	if err != nil {
 		// Если `err` (особенно `sql.ErrNoRows`), значит пользователь не участник проекта.
-		return nil, errors.New("project not found or access denied")
+		return nil, ErrNotFound
 	}
 
 	log.Printf("User %d has role '%s' in project %d", userID, role, projectID)
@@ -68,25 +93,46 @@ func (s *Service) GetProjectByID(ctx context.Context, projectID, userID int64) (
 	return project, nil
 }
 
-// ListUserProjects returns projects list of user
-// For now just calls repository
-func (s *Service) ListUserProjects(ctx context.Context, userID int64) ([]Project, error) {
-	// TODO: add logic for projects secondary roles
-	projects, err := s.repo.ListByOwnerID(ctx, userID)
+// ErrNotFound is returned when project doesn't exist or user is not its member
+var ErrNotFound = errors.New("project not found or access denied")
+
+// ErrForbidden is returned when member's role doesn't allow changing project configuration
+var ErrForbidden = errors.New("only project owners or managers can change project configuration")
+
+// GetManagedProject returns project if user is its owner or manager
+func (s *Service) GetManagedProject(ctx context.Context, projectID, userID int64) (*Project, error) {
+	p, err := s.GetProjectByID(ctx, projectID, userID)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.requireManager(ctx, projectID, userID); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
 
-	return projects, nil
+// requireManager returns ErrForbidden unless user is owner or manager of project
+func (s *Service) requireManager(ctx context.Context, projectID, userID int64) error {
+	role, err := s.projectMemberService.GetUserRole(ctx, userID, projectID)
+	if err != nil {
+		return err
+	}
+	if role != "owner" && role != "manager" {
+		return ErrForbidden
+	}
+	return nil
 }
 
 // UpdateProjectRequest struct for providing data for update
 type UpdateProjectRequest struct {
 	Name        *string `json:"name"`
+	Key         *string `json:"key"`
 	Description *string `json:"description"`
+	Timezone    *string `json:"timezone"`
 }
 
-// UpdateProject logic for updating project
+// UpdateProject logic for updating project. Any member may change name and description,
+// key and timezone only owners and managers: they rewrite ticket keys and SLA and due date handling
 func (s *Service) UpdateProject(ctx context.Context, projectID, userID int64, req UpdateProjectRequest) error {
 	// find project, check accwss
 	projectToUpdate, err := s.GetProjectByID(ctx, projectID, userID)
@@ -102,6 +148,36 @@ func (s *Service) UpdateProject(ctx context.Context, projectID, userID int64, re
 		projectToUpdate.Description = *req.Description
 	}
 
+	newKey, newTimezone := projectToUpdate.Key, projectToUpdate.Timezone
+	if req.Key != nil {
+		if newKey, err = NormalizeKey(*req.Key); err != nil {
+			return err
+		}
+	}
+	if req.Timezone != nil {
+		if newTimezone, err = NormalizeTimezone(*req.Timezone); err != nil {
+			return err
+		}
+	}
+	if newKey != projectToUpdate.Key || newTimezone != projectToUpdate.Timezone {
+		if err := s.requireManager(ctx, projectID, userID); err != nil {
+			return err
+		}
+	}
+	projectToUpdate.Timezone = newTimezone
+
+	// key rename keeps old key as alias, repository saves it with other fields in one transaction
+	if newKey != projectToUpdate.Key {
+		inUse, err := s.repo.KeyInUse(ctx, newKey, projectID)
+		if err != nil {
+			return err
+		}
+		if inUse {
+			return ErrKeyInUse
+		}
+		projectToUpdate.Key = newKey
+	}
+
 	// save changes
 	return s.repo.Update(ctx, projectToUpdate)
 }
@@ -130,7 +206,7 @@ func (s *Service) AddMemberToProject(ctx context.Context, projectID, currentUser
 	}
 
 	// If good, call projectMemberService to ad new user (newUserID).
-	_, err = s.projectMemberService.AddMember(ctx, newUserID, projectID, role)
+	_, err = s.projectMemberService.InviteMember(ctx, newUserID, projectID, role, currentUserID)
 	if err != nil {
 		// TODO: add more clarity errors
 		return err
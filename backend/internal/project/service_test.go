@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -21,9 +22,10 @@ func TestService_CreateProject(t *testing.T) {
 	userID := int64(1)
 
 	t.Run("Success", func(t *testing.T) {
+		mockRepo.On("KeyInUse", ctx, "TP", int64(0)).Return(false, nil).Once()
 		// Expect Create to be called
 		mockRepo.On("Create", ctx, mock.MatchedBy(func(p *Project) bool {
-			return p.Name == name && p.OwnerID == userID
+			return p.Name == name && p.Key == "TP" && p.OwnerID == userID
 		})).Return(nil).Run(func(args mock.Arguments) {
 			p := args.Get(1).(*Project)
 			p.ID = 100 // Simulate ID assignment
@@ -32,7 +34,7 @@ func TestService_CreateProject(t *testing.T) {
 		// Expect AddMember to be called
 		mockPM.On("AddMember", ctx, userID, int64(100), "owner").Return(nil, nil).Once()
 
-		p, err := service.CreateProject(ctx, name, desc, userID)
+		p, err := service.CreateProject(ctx, name, "", desc, userID)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, p)
@@ -42,9 +44,10 @@ func TestService_CreateProject(t *testing.T) {
 	})
 
 	t.Run("RepoError", func(t *testing.T) {
+		mockRepo.On("KeyInUse", ctx, "PMS", int64(0)).Return(false, nil).Once()
 		mockRepo.On("Create", ctx, mock.Anything).Return(errors.New("db error")).Once()
 
-		p, err := service.CreateProject(ctx, name, desc, userID)
+		p, err := service.CreateProject(ctx, name, "pms", desc, userID)
 
 		assert.Error(t, err)
 		assert.Nil(t, p)
@@ -52,6 +55,99 @@ func TestService_CreateProject(t *testing.T) {
 	})
 }
 
+func TestService_CreateProject_Key(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockPM := new(MockMemberService)
+	service := NewService(mockRepo, mockPM)
+
+	ctx := context.Background()
+	userID := int64(1)
+
+	t.Run("InvalidKey", func(t *testing.T) {
+		p, err := service.CreateProject(ctx, "Name", "1-bad", "", userID)
+
+		assert.ErrorIs(t, err, ErrInvalidKey)
+		assert.Nil(t, p)
+	})
+
+	t.Run("KeyInUse", func(t *testing.T) {
+		mockRepo.On("KeyInUse", ctx, "PMS", int64(0)).Return(true, nil).Once()
+
+		p, err := service.CreateProject(ctx, "Name", "PMS", "", userID)
+
+		assert.ErrorIs(t, err, ErrKeyInUse)
+		assert.Nil(t, p)
+	})
+
+	t.Run("GeneratedKeyCollision", func(t *testing.T) {
+		mockRepo.On("KeyInUse", ctx, "BACK", int64(0)).Return(true, nil).Once()
+		mockRepo.On("KeyInUse", ctx, "BACK2", int64(0)).Return(false, nil).Once()
+		mockRepo.On("Create", ctx, mock.MatchedBy(func(p *Project) bool { return p.Key == "BACK2" })).Return(nil).Once()
+		mockPM.On("AddMember", ctx, userID, mock.Anything, "owner").Return(nil, nil).Once()
+
+		p, err := service.CreateProject(ctx, "backend", "", "", userID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "BACK2", p.Key)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestService_UpdateProject_RenameKey(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockPM := new(MockMemberService)
+	service := NewService(mockRepo, mockPM)
+
+	ctx := context.Background()
+	projectID := int64(100)
+	userID := int64(1)
+
+	mockRepo.On("GetByID", ctx, projectID).Return(&Project{ID: projectID, Key: "OLD"}, nil).Once()
+	mockPM.On("GetUserRole", ctx, userID, projectID).Return("owner", nil).Twice()
+	mockRepo.On("KeyInUse", ctx, "NEW", projectID).Return(false, nil).Once()
+	mockRepo.On("Update", ctx, mock.MatchedBy(func(p *Project) bool { return p.Key == "NEW" })).Return(nil).Once()
+
+	newKey := "new"
+	err := service.UpdateProject(ctx, projectID, userID, UpdateProjectRequest{Key: &newKey})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_UpdateProject_MemberRestrictions(t *testing.T) {
+	ctx := context.Background()
+	projectID := int64(100)
+	userID := int64(2)
+	newKey, sameKey, newTimezone, newName := "NEW", "old", "Europe/Berlin", "Renamed"
+
+	for name, tc := range map[string]struct {
+		req     UpdateProjectRequest
+		allowed bool
+	}{
+		"Name":     {UpdateProjectRequest{Name: &newName, Key: &sameKey}, true},
+		"Key":      {UpdateProjectRequest{Key: &newKey}, false},
+		"Timezone": {UpdateProjectRequest{Timezone: &newTimezone}, false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			mockPM := new(MockMemberService)
+			service := NewService(mockRepo, mockPM)
+			mockRepo.On("GetByID", ctx, projectID).Return(&Project{ID: projectID, Key: "OLD", Timezone: DefaultTimezone}, nil)
+			mockPM.On("GetUserRole", ctx, userID, projectID).Return("member", nil)
+			mockRepo.On("Update", ctx, mock.Anything).Return(nil)
+
+			err := service.UpdateProject(ctx, projectID, userID, tc.req)
+
+			if tc.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrForbidden)
+				mockRepo.AssertNotCalled(t, "Update", ctx, mock.Anything)
+			}
+		})
+	}
+}
+
 func TestService_GetProjectByID(t *testing.T) {
 	mockRepo := new(MockRepository)
 	mockPM := new(MockMemberService)
@@ -89,6 +185,81 @@ func TestService_GetProjectByID(t *testing.T) {
 		assert.Error(t, err)
 		assert.Nil(t, p)
 		assert.Contains(t, err.Error(), "project not found or access denied")
+		assert.ErrorIs(t, err, ErrNotFound)
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+func TestService_GetManagedProject(t *testing.T) {
+	ctx := context.Background()
+	projectID := int64(100)
+
+	for role, allowed := range map[string]bool{"owner": true, "manager": true, "member": false} {
+		t.Run(role, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			mockPM := new(MockMemberService)
+			service := NewService(mockRepo, mockPM)
+			mockRepo.On("GetByID", ctx, projectID).Return(&Project{ID: projectID}, nil)
+			mockPM.On("GetUserRole", ctx, int64(1), projectID).Return(role, nil)
+
+			p, err := service.GetManagedProject(ctx, projectID, 1)
+
+			if allowed {
+				assert.NoError(t, err)
+				assert.Equal(t, projectID, p.ID)
+			} else {
+				assert.ErrorIs(t, err, ErrForbidden)
+			}
+		})
+	}
+}
+
+func TestService_AddMemberToProject(t *testing.T) {
+	mockPM := new(MockMemberService)
+	service := NewService(new(MockRepository), mockPM)
+
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		mockPM.On("GetUserRole", ctx, int64(1), int64(100)).Return("manager", nil).Once()
+		mockPM.On("InviteMember", ctx, int64(2), int64(100), "member", int64(1)).Return(nil, nil).Once()
+
+		err := service.AddMemberToProject(ctx, 100, 1, 2, "member")
+
+		assert.NoError(t, err)
+		mockPM.AssertExpectations(t)
+	})
+
+	t.Run("InsufficientRole", func(t *testing.T) {
+		mockPM.On("GetUserRole", ctx, int64(3), int64(100)).Return("member", nil).Once()
+
+		err := service.AddMemberToProject(ctx, 100, 3, 2, "member")
+
+		assert.Error(t, err)
+	})
+}
+
+func TestNormalizeTimezone(t *testing.T) {
+	tz, err := NormalizeTimezone("")
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultTimezone, tz)
+
+	tz, err = NormalizeTimezone(" Europe/Berlin ")
+	assert.NoError(t, err)
+	assert.Equal(t, "Europe/Berlin", tz)
+
+	_, err = NormalizeTimezone("Mars/Olympus")
+	assert.ErrorIs(t, err, ErrInvalidTimezone)
+}
+
+func TestLikePattern(t *testing.T) {
+	assert.Equal(t, `%100\%\_done\\%`, likePattern(`100%_done\`))
+}
+
+func TestKeyConflict(t *testing.T) {
+	taken := &pq.Error{Code: "23505", Constraint: "project_keys_unique"}
+	assert.ErrorIs(t, keyConflict(taken), ErrKeyInUse)
+
+	other := &pq.Error{Code: "23505", Constraint: "projects_name_key"}
+	assert.Equal(t, error(other), keyConflict(other))
+}
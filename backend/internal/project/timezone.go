@@ -0,0 +1,43 @@
+package project
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	// embedded zone database, servers without tzdata still resolve IANA names
+	_ "time/tzdata"
+)
+
+// DefaultTimezone is used by projects created without one
+const DefaultTimezone = "UTC"
+
+// ErrInvalidTimezone is returned for names unknown to IANA database
+var ErrInvalidTimezone = errors.New("invalid timezone, use IANA name like Europe/Berlin")
+
+// NormalizeTimezone checks that timezone can be loaded
+func NormalizeTimezone(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return DefaultTimezone, nil
+	}
+	if _, err := time.LoadLocation(name); err != nil {
+		return "", ErrInvalidTimezone
+	}
+	return name, nil
+}
+
+// Location returns project timezone, UTC if it can't be loaded
+func (p *Project) Location() *time.Location {
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Today returns current date in project timezone as UTC midnight
+func (p *Project) Today(now time.Time) time.Time {
+	local := now.In(p.Location())
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+}
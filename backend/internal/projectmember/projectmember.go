@@ -3,8 +3,8 @@ package projectmember
 import "time"
 
 type ProjectMember struct {
-	UserID    int64     `db:"user_id"`
-	ProjectID int64     `db:"project_id"`
-	Role      string    `db:"role"`
-	CreatedAt time.Time `db:"created_at"`
+	UserID    int64     `db:"user_id" json:"user_id"`
+	ProjectID int64     `db:"project_id" json:"project_id"`
+	Role      string    `db:"role" json:"role"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
 }
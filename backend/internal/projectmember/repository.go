@@ -3,6 +3,7 @@ package projectmember
 import (
 	"context"
 
+	"github.com/antonovs105/project-management-system-go/internal/event"
 	"github.com/jmoiron/sqlx"
 )
 
@@ -23,6 +24,28 @@ func (r *Repository) Add(ctx context.Context, pm *ProjectMember) error {
 	return err
 }
 
+// AddWithEvent adds user into project and writes ev to outbox in one transaction
+func (r *Repository) AddWithEvent(ctx context.Context, pm *ProjectMember, ev event.Draft) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO project_members (user_id, project_id, role)
+		VALUES ($1, $2, $3)
+		RETURNING created_at`
+	if err := tx.GetContext(ctx, &pm.CreatedAt, query, pm.UserID, pm.ProjectID, pm.Role); err != nil {
+		return err
+	}
+	if err := event.Write(ctx, tx, ev); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
 // FindByUserAndProject finds if user in project
 func (r *Repository) FindByUserAndProject(ctx context.Context, userID, projectID int64) (*ProjectMember, error) {
 	var pm ProjectMember
@@ -1,6 +1,10 @@
 package projectmember
 
-import "context"
+import (
+	"context"
+
+	"github.com/antonovs105/project-management-system-go/internal/event"
+)
 
 type Service struct {
 	repo *Repository
@@ -30,6 +34,21 @@ func (s *Service) AddMember(ctx context.Context, userID, projectID int64, role s
 	return pm, nil
 }
 
+// InviteMember adds user into project on behalf of invitedBy, user is notified about it
+func (s *Service) InviteMember(ctx context.Context, userID, projectID int64, role string, invitedBy int64) (*ProjectMember, error) {
+	pm := &ProjectMember{
+		UserID:    userID,
+		ProjectID: projectID,
+		Role:      role,
+	}
+	ev := event.Draft{ProjectID: projectID, ActorID: invitedBy, Type: event.MemberAdded, Data: pm}
+
+	if err := s.repo.AddWithEvent(ctx, pm, ev); err != nil {
+		return nil, err
+	}
+	return pm, nil
+}
+
 func (s *Service) GetUserRole(ctx context.Context, userID, projectID int64) (string, error) {
 	return s.repo.GetUserRoleInProject(ctx, userID, projectID)
 }
@@ -0,0 +1,79 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+)
+
+// Memory is PubSub inside one process, for tests and single instance setups
+type Memory struct {
+	mu   sync.Mutex
+	subs subscribers
+	// deliver keeps handler calls in publish order and off publisher's goroutine
+	deliver chan func()
+	done    chan struct{}
+	once    sync.Once
+}
+
+func NewMemory() *Memory {
+	m := &Memory{deliver: make(chan func(), 256), done: make(chan struct{})}
+	go m.run()
+	return m
+}
+
+// Publish sends payload to every subscriber of channel
+func (m *Memory) Publish(ctx context.Context, channel string, payload []byte) error {
+	m.mu.Lock()
+	handlers := m.subs.of(channel)
+	m.mu.Unlock()
+
+	msg := append([]byte(nil), payload...)
+	select {
+	case m.deliver <- func() {
+		for _, h := range handlers {
+			h(msg)
+		}
+	}:
+		return nil
+	case <-m.done:
+		return context.Canceled
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Subscribe registers handler until ctx is done
+func (m *Memory) Subscribe(ctx context.Context, channel string, handler Handler) error {
+	h := &handler
+	m.mu.Lock()
+	m.subs.add(channel, h)
+	m.mu.Unlock()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-m.done:
+		}
+		m.mu.Lock()
+		m.subs.remove(channel, h)
+		m.mu.Unlock()
+	}()
+	return nil
+}
+
+// Close stops delivery
+func (m *Memory) Close() error {
+	m.once.Do(func() { close(m.done) })
+	return nil
+}
+
+func (m *Memory) run() {
+	for {
+		select {
+		case f := <-m.deliver:
+			f()
+		case <-m.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemory(t *testing.T) {
+	bus := NewMemory()
+	defer bus.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	received := make(chan string, 10)
+	assert.NoError(t, bus.Subscribe(ctx, "a", func(p []byte) { received <- "a:" + string(p) }))
+	assert.NoError(t, bus.Subscribe(context.Background(), "b", func(p []byte) { received <- "b:" + string(p) }))
+
+	t.Run("ChannelScopedInOrder", func(t *testing.T) {
+		assert.NoError(t, bus.Publish(ctx, "a", []byte("1")))
+		assert.NoError(t, bus.Publish(ctx, "b", []byte("2")))
+		assert.NoError(t, bus.Publish(ctx, "a", []byte("3")))
+
+		assert.Equal(t, []string{"a:1", "b:2", "a:3"}, collect(t, received, 3))
+	})
+
+	t.Run("UnsubscribedOnCancel", func(t *testing.T) {
+		cancel()
+		assert.Eventually(t, func() bool {
+			bus.mu.Lock()
+			defer bus.mu.Unlock()
+			return len(bus.subs.of("a")) == 0
+		}, time.Second, time.Millisecond)
+
+		assert.NoError(t, bus.Publish(context.Background(), "a", []byte("4")))
+		assert.NoError(t, bus.Publish(context.Background(), "b", []byte("5")))
+
+		assert.Equal(t, []string{"b:5"}, collect(t, received, 1))
+	})
+}
+
+func collect(t *testing.T, ch <-chan string, n int) []string {
+	t.Helper()
+	var got []string
+	for len(got) < n {
+		select {
+		case s := <-ch:
+			got = append(got, s)
+		case <-time.After(time.Second):
+			t.Fatalf("received %d of %d messages", len(got), n)
+		}
+	}
+	return got
+}
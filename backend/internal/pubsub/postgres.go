@@ -0,0 +1,128 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// maxNotifyPayload is limit of NOTIFY payload in default Postgres build (8000 bytes, minus margin)
+const maxNotifyPayload = 7900
+
+// Postgres is PubSub over LISTEN/NOTIFY. Messages reach every instance connected to the same DB
+type Postgres struct {
+	db       *sqlx.DB
+	listener *pq.Listener
+
+	// listenMu orders LISTEN/UNLISTEN calls with their handler changes. It is not taken by run:
+	// Listen waits until run drains notifications, so holding mu there would deadlock
+	listenMu sync.Mutex
+	mu       sync.Mutex
+	subs     subscribers
+	done     chan struct{}
+	once     sync.Once
+}
+
+// NewPostgres opens dedicated listening connection with dataSource, db is used for NOTIFY
+func NewPostgres(db *sqlx.DB, dataSource string) *Postgres {
+	p := &Postgres{db: db, done: make(chan struct{})}
+	p.listener = pq.NewListener(dataSource, time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("Pub/sub listener: %v", err)
+		}
+	})
+	go p.run()
+	return p
+}
+
+// Publish sends NOTIFY, it is delivered after current transaction commits
+func (p *Postgres) Publish(ctx context.Context, channel string, payload []byte) error {
+	if len(payload) > maxNotifyPayload {
+		return ErrPayloadTooLarge
+	}
+	_, err := p.db.ExecContext(ctx, `SELECT pg_notify($1, $2)`, channel, string(payload))
+	return err
+}
+
+// Subscribe starts LISTEN on channel for first subscriber and registers handler until ctx is done
+func (p *Postgres) Subscribe(ctx context.Context, channel string, handler Handler) error {
+	h := &handler
+	p.listenMu.Lock()
+	p.mu.Lock()
+	first := p.subs.add(channel, h)
+	p.mu.Unlock()
+	if first {
+		if err := p.listener.Listen(channel); err != nil && !errors.Is(err, pq.ErrChannelAlreadyOpen) {
+			p.mu.Lock()
+			p.subs.remove(channel, h)
+			p.mu.Unlock()
+			p.listenMu.Unlock()
+			return err
+		}
+	}
+	p.listenMu.Unlock()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-p.done:
+			return
+		}
+		p.listenMu.Lock()
+		defer p.listenMu.Unlock()
+		p.mu.Lock()
+		last := p.subs.remove(channel, h)
+		p.mu.Unlock()
+		if last {
+			if err := p.listener.Unlisten(channel); err != nil && !errors.Is(err, pq.ErrChannelNotOpen) {
+				log.Printf("Pub/sub unlisten %s: %v", channel, err)
+			}
+		}
+	}()
+	return nil
+}
+
+// Close closes listening connection
+func (p *Postgres) Close() error {
+	var err error
+	p.once.Do(func() {
+		close(p.done)
+		err = p.listener.Close()
+	})
+	return err
+}
+
+func (p *Postgres) run() {
+	for {
+		select {
+		case <-p.done:
+			return
+		case n, ok := <-p.listener.Notify:
+			if !ok {
+				return
+			}
+			p.mu.Lock()
+			var handlers []Handler
+			if n == nil {
+				// reconnected, notifications sent while connection was down are lost
+				handlers = p.subs.all()
+			} else {
+				handlers = p.subs.of(n.Channel)
+			}
+			p.mu.Unlock()
+
+			for _, h := range handlers {
+				if n == nil {
+					h(nil)
+				} else {
+					h([]byte(n.Extra))
+				}
+			}
+		}
+	}
+}
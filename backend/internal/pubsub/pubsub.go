@@ -0,0 +1,65 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrPayloadTooLarge is returned when message does not fit into transport limit
+var ErrPayloadTooLarge = errors.New("payload too large")
+
+// Handler receives messages of channel. Nil payload means connection was lost and restored,
+// messages published meanwhile were missed
+type Handler func(payload []byte)
+
+// PubSub delivers messages published on any backend instance to subscribers on every instance,
+// including publishing one
+type PubSub interface {
+	Publish(ctx context.Context, channel string, payload []byte) error
+	// Subscribe calls handler for every message of channel until ctx is done. Handler is called
+	// from one goroutine per PubSub, it should not block
+	Subscribe(ctx context.Context, channel string, handler Handler) error
+	Close() error
+}
+
+// subscribers keeps handlers by channel, common part of implementations
+type subscribers struct {
+	byChannel map[string]map[*Handler]struct{}
+}
+
+func (s *subscribers) add(channel string, h *Handler) (first bool) {
+	if s.byChannel == nil {
+		s.byChannel = make(map[string]map[*Handler]struct{})
+	}
+	if s.byChannel[channel] == nil {
+		s.byChannel[channel] = make(map[*Handler]struct{})
+		first = true
+	}
+	s.byChannel[channel][h] = struct{}{}
+	return first
+}
+
+func (s *subscribers) remove(channel string, h *Handler) (last bool) {
+	delete(s.byChannel[channel], h)
+	if len(s.byChannel[channel]) == 0 {
+		delete(s.byChannel, channel)
+		return true
+	}
+	return false
+}
+
+func (s *subscribers) of(channel string) []Handler {
+	handlers := make([]Handler, 0, len(s.byChannel[channel]))
+	for h := range s.byChannel[channel] {
+		handlers = append(handlers, *h)
+	}
+	return handlers
+}
+
+func (s *subscribers) all() []Handler {
+	var handlers []Handler
+	for channel := range s.byChannel {
+		handlers = append(handlers, s.of(channel)...)
+	}
+	return handlers
+}
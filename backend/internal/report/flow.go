@@ -0,0 +1,338 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/ticket"
+)
+
+// defaultFlowDays is range of flow reports without dates
+const defaultFlowDays = 30
+
+// DateRange is inclusive range of days
+type DateRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// NewDateRange validates range, zero dates default to last 30 days till today
+func NewDateRange(from, to, now time.Time) (DateRange, error) {
+	if to.IsZero() {
+		to = now
+	}
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -(defaultFlowDays - 1))
+	}
+	r := DateRange{From: startOfDay(from), To: startOfDay(to)}
+	if r.To.Before(r.From) {
+		return r, errors.New("'to' date must not be before 'from'")
+	}
+	if len(r.days()) > maxReportDays {
+		return r, errors.New("date range is too long, max is one year")
+	}
+	return r, nil
+}
+
+// days returns every day of range
+func (r DateRange) days() []time.Time {
+	var days []time.Time
+	for d := r.From; !d.After(r.To); d = d.Add(day) {
+		days = append(days, d)
+		if len(days) > maxReportDays {
+			break
+		}
+	}
+	return days
+}
+
+// contains reports if moment is inside range
+func (r DateRange) contains(t time.Time) bool {
+	return !t.Before(r.From) && t.Before(r.To.Add(day))
+}
+
+// FlowItem is one ticket in distribution
+type FlowItem struct {
+	TicketID   int64     `json:"ticket_id"`
+	Key        string    `json:"key"`
+	ResolvedAt time.Time `json:"resolved_at"`
+	Days       float64   `json:"days"`
+}
+
+// Distribution of lead or cycle time in days
+type Distribution struct {
+	Count   int        `json:"count"`
+	Min     float64    `json:"min"`
+	Max     float64    `json:"max"`
+	Average float64    `json:"average"`
+	P50     float64    `json:"p50"`
+	P75     float64    `json:"p75"`
+	P85     float64    `json:"p85"`
+	P95     float64    `json:"p95"`
+	Items   []FlowItem `json:"items"`
+}
+
+// WeekThroughput is work finished during week starting on Monday
+type WeekThroughput struct {
+	WeekStart string  `json:"week_start"`
+	Count     int     `json:"count"`
+	Points    float64 `json:"points"`
+}
+
+// AgingItem is ticket in progress with its age
+type AgingItem struct {
+	TicketID  int64     `json:"ticket_id"`
+	Key       string    `json:"key"`
+	Title     string    `json:"title"`
+	Status    string    `json:"status"`
+	StartedAt time.Time `json:"started_at"`
+	AgeDays   float64   `json:"age_days"`
+	// AtRisk means ticket is older than 85% of tickets finished in range
+	AtRisk bool `json:"at_risk"`
+}
+
+// AgingWIP is work in progress, oldest first
+type AgingWIP struct {
+	CycleTimeP85 float64     `json:"cycle_time_p85"`
+	Items        []AgingItem `json:"items"`
+}
+
+// CFDPoint is number of tickets per status at the end of day
+type CFDPoint struct {
+	Date   string         `json:"date"`
+	Counts map[string]int `json:"counts"`
+}
+
+// CumulativeFlow is data for cumulative flow diagram, statuses go from todo to done
+type CumulativeFlow struct {
+	Statuses []string   `json:"statuses"`
+	Series   []CFDPoint `json:"series"`
+}
+
+// GetLeadTime returns distribution of time from creation to resolution of tickets resolved in range
+func (s *Service) GetLeadTime(ctx context.Context, projectID, userID int64, r DateRange) (*Distribution, error) {
+	return s.flowDistribution(ctx, projectID, userID, r, func(t *ticket.Ticket) *time.Time {
+		return &t.CreatedAt
+	})
+}
+
+// GetCycleTime returns distribution of time from start of work to resolution of tickets resolved in range
+func (s *Service) GetCycleTime(ctx context.Context, projectID, userID int64, r DateRange) (*Distribution, error) {
+	return s.flowDistribution(ctx, projectID, userID, r, func(t *ticket.Ticket) *time.Time {
+		return t.StartedAt
+	})
+}
+
+func (s *Service) flowDistribution(ctx context.Context, projectID, userID int64, r DateRange, startOf func(t *ticket.Ticket) *time.Time) (*Distribution, error) {
+	tickets, err := s.projectTickets(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return distribution(tickets, r, startOf), nil
+}
+
+// projectTickets checks access and loads tickets of project
+func (s *Service) projectTickets(ctx context.Context, projectID, userID int64) ([]ticket.Ticket, error) {
+	// check access
+	_, err := s.projectService.GetProjectByID(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ListProjectTickets(ctx, projectID)
+}
+
+// distribution collects durations of tickets resolved in range
+func distribution(tickets []ticket.Ticket, r DateRange, startOf func(t *ticket.Ticket) *time.Time) *Distribution {
+	result := &Distribution{Items: []FlowItem{}}
+	var values []float64
+	for i := range tickets {
+		t := &tickets[i]
+		start := startOf(t)
+		if t.ResolvedAt == nil || start == nil || !r.contains(*t.ResolvedAt) {
+			continue
+		}
+		days := math.Max(0, t.ResolvedAt.Sub(*start).Hours()/24)
+		values = append(values, days)
+		result.Items = append(result.Items, FlowItem{
+			TicketID:   t.ID,
+			Key:        t.Key,
+			ResolvedAt: *t.ResolvedAt,
+			Days:       round2(days),
+		})
+	}
+	sort.Slice(result.Items, func(i, j int) bool {
+		return result.Items[i].ResolvedAt.Before(result.Items[j].ResolvedAt)
+	})
+
+	result.Count = len(values)
+	if len(values) == 0 {
+		return result
+	}
+	sort.Float64s(values)
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	result.Min = round2(values[0])
+	result.Max = round2(values[len(values)-1])
+	result.Average = round2(sum / float64(len(values)))
+	result.P50 = round2(percentile(values, 50))
+	result.P75 = round2(percentile(values, 75))
+	result.P85 = round2(percentile(values, 85))
+	result.P95 = round2(percentile(values, 95))
+	return result
+}
+
+// percentile uses nearest rank method on sorted values
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// GetThroughput returns number and points of tickets resolved per week
+func (s *Service) GetThroughput(ctx context.Context, projectID, userID int64, r DateRange) ([]WeekThroughput, error) {
+	tickets, err := s.projectTickets(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var weeks []WeekThroughput
+	index := make(map[time.Time]int)
+	for w := weekStart(r.From); !w.After(r.To); w = w.AddDate(0, 0, 7) {
+		index[w] = len(weeks)
+		weeks = append(weeks, WeekThroughput{WeekStart: w.Format(time.DateOnly)})
+	}
+
+	for _, t := range tickets {
+		if t.ResolvedAt == nil || !r.contains(*t.ResolvedAt) {
+			continue
+		}
+		w := &weeks[index[weekStart(*t.ResolvedAt)]]
+		w.Count++
+		if t.StoryPoints != nil {
+			w.Points += *t.StoryPoints
+		}
+	}
+	for i := range weeks {
+		weeks[i].Points = round2(weeks[i].Points)
+	}
+	return weeks, nil
+}
+
+// weekStart returns Monday of week
+func weekStart(t time.Time) time.Time {
+	d := startOfDay(t)
+	offset := (int(d.Weekday()) + 6) % 7
+	return d.AddDate(0, 0, -offset)
+}
+
+// GetAgingWIP returns tickets in progress by age, compared to cycle time of tickets resolved in range
+func (s *Service) GetAgingWIP(ctx context.Context, projectID, userID int64, r DateRange) (*AgingWIP, error) {
+	tickets, err := s.projectTickets(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	cycle := distribution(tickets, r, func(t *ticket.Ticket) *time.Time { return t.StartedAt })
+	result := &AgingWIP{CycleTimeP85: cycle.P85, Items: []AgingItem{}}
+
+	now := s.now()
+	for _, t := range tickets {
+		if ticket.StatusCategory(t.Status) != ticket.CategoryInProgress || t.StartedAt == nil {
+			continue
+		}
+		age := round2(math.Max(0, now.Sub(*t.StartedAt).Hours()/24))
+		result.Items = append(result.Items, AgingItem{
+			TicketID:  t.ID,
+			Key:       t.Key,
+			Title:     t.Title,
+			Status:    t.Status,
+			StartedAt: *t.StartedAt,
+			AgeDays:   age,
+			AtRisk:    cycle.Count > 0 && age > cycle.P85,
+		})
+	}
+	sort.Slice(result.Items, func(i, j int) bool {
+		return result.Items[i].AgeDays > result.Items[j].AgeDays
+	})
+	return result, nil
+}
+
+// GetCumulativeFlow returns number of tickets in every status at the end of each day of range
+func (s *Service) GetCumulativeFlow(ctx context.Context, projectID, userID int64, r DateRange) (*CumulativeFlow, error) {
+	tickets, err := s.projectTickets(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := s.repo.ListProjectChanges(ctx, projectID, ticket.FieldStatus, r.From)
+	if err != nil {
+		return nil, err
+	}
+
+	days := r.days()
+	now := s.now()
+	seen := make(map[string]bool)
+	createdAt := make(map[int64]time.Time, len(tickets))
+	for _, t := range tickets {
+		createdAt[t.ID] = t.CreatedAt
+	}
+
+	// replay goes back in time, series is filled from the last day
+	tl := newTimeline(tickets, changes)
+	series := make([]CFDPoint, len(days))
+	for i := len(days) - 1; i >= 0; i-- {
+		moment := days[i].Add(day)
+		if moment.After(now) {
+			moment = now
+		}
+		counts := make(map[string]int)
+		for id, st := range tl.at(moment) {
+			if st.Status == "" || createdAt[id].After(moment) {
+				continue
+			}
+			counts[st.Status]++
+			seen[st.Status] = true
+		}
+		series[i] = CFDPoint{Date: days[i].Format(time.DateOnly), Counts: counts}
+	}
+
+	statuses := make([]string, 0, len(seen))
+	for status := range seen {
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		ci, cj := categoryOrder[ticket.StatusCategory(statuses[i])], categoryOrder[ticket.StatusCategory(statuses[j])]
+		if ci != cj {
+			return ci < cj
+		}
+		return statuses[i] < statuses[j]
+	})
+
+	// every status has value on every day so diagram bands are continuous
+	for _, p := range series {
+		for _, status := range statuses {
+			if _, ok := p.Counts[status]; !ok {
+				p.Counts[status] = 0
+			}
+		}
+	}
+
+	return &CumulativeFlow{Statuses: statuses, Series: series}, nil
+}
+
+// categoryOrder sorts statuses along workflow
+var categoryOrder = map[string]int{
+	ticket.CategoryTodo:       0,
+	ticket.CategoryInProgress: 1,
+	ticket.CategoryDone:       2,
+}
@@ -0,0 +1,120 @@
+package report
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/project"
+	"github.com/antonovs105/project-management-system-go/internal/ticket"
+	"github.com/stretchr/testify/assert"
+)
+
+// flowFixture has three tickets resolved in March, one in progress since Mar 1 and one new
+func flowFixture() []ticket.Ticket {
+	return []ticket.Ticket{
+		{ID: 1, Key: "PMS-1", Status: "done", CreatedAt: at(1, 0), StartedAt: ptr(at(2, 0)), ResolvedAt: ptr(at(3, 0)), StoryPoints: ptr(2.0)},
+		{ID: 2, Key: "PMS-2", Status: "done", CreatedAt: at(1, 0), StartedAt: ptr(at(1, 12)), ResolvedAt: ptr(at(5, 0))},
+		{ID: 3, Key: "PMS-3", Status: "done", CreatedAt: at(2, 0), StartedAt: ptr(at(4, 0)), ResolvedAt: ptr(at(12, 0)), StoryPoints: ptr(3.0)},
+		{ID: 4, Key: "PMS-4", Status: "in_progress", CreatedAt: at(1, 0), StartedAt: ptr(at(1, 0))},
+		{ID: 5, Key: "PMS-5", Status: "new", CreatedAt: at(3, 0)},
+	}
+}
+
+func TestNewDateRange(t *testing.T) {
+	r, err := NewDateRange(time.Time{}, time.Time{}, at(30, 15))
+	assert.NoError(t, err)
+	assert.Equal(t, at(1, 0), r.From)
+	assert.Equal(t, at(30, 0), r.To)
+
+	_, err = NewDateRange(at(5, 0), at(1, 0), at(30, 0))
+	assert.Error(t, err)
+
+	_, err = NewDateRange(at(1, 0), at(1, 0).AddDate(2, 0, 0), at(30, 0))
+	assert.Error(t, err)
+}
+
+func TestService_GetCycleTime(t *testing.T) {
+	service, mockRepo, mockProject := newTestService()
+	ctx := context.Background()
+	mockProject.On("GetProjectByID", ctx, int64(10), int64(1)).Return(&project.Project{}, nil).Twice()
+	mockRepo.On("ListProjectTickets", ctx, int64(10)).Return(flowFixture(), nil).Twice()
+
+	r := DateRange{From: at(1, 0), To: at(10, 0)}
+	cycle, err := service.GetCycleTime(ctx, 10, 1, r)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, cycle.Count)
+	assert.Equal(t, 1.0, cycle.Min)
+	assert.Equal(t, 3.5, cycle.Max)
+	assert.Equal(t, 1.0, cycle.P50)
+	assert.Equal(t, 3.5, cycle.P85)
+	assert.Equal(t, "PMS-1", cycle.Items[0].Key)
+
+	lead, err := service.GetLeadTime(ctx, 10, 1, r)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2.0, lead.Min)
+	assert.Equal(t, 4.0, lead.Max)
+}
+
+func TestService_GetThroughput(t *testing.T) {
+	service, mockRepo, mockProject := newTestService()
+	ctx := context.Background()
+	mockProject.On("GetProjectByID", ctx, int64(10), int64(1)).Return(&project.Project{}, nil).Once()
+	mockRepo.On("ListProjectTickets", ctx, int64(10)).Return(flowFixture(), nil).Once()
+
+	// Mar 1 2024 is Friday
+	weeks, err := service.GetThroughput(ctx, 10, 1, DateRange{From: at(1, 0), To: at(14, 0)})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []WeekThroughput{
+		{WeekStart: "2024-02-26", Count: 1, Points: 2},
+		{WeekStart: "2024-03-04", Count: 1},
+		{WeekStart: "2024-03-11", Count: 1, Points: 3},
+	}, weeks)
+}
+
+func TestService_GetAgingWIP(t *testing.T) {
+	service, mockRepo, mockProject := newTestService()
+	ctx := context.Background()
+	mockProject.On("GetProjectByID", ctx, int64(10), int64(1)).Return(&project.Project{}, nil).Once()
+	mockRepo.On("ListProjectTickets", ctx, int64(10)).Return(flowFixture(), nil).Once()
+
+	wip, err := service.GetAgingWIP(ctx, 10, 1, DateRange{From: at(1, 0), To: at(14, 0)})
+
+	assert.NoError(t, err)
+	assert.Len(t, wip.Items, 1)
+	assert.Equal(t, "PMS-4", wip.Items[0].Key)
+	assert.Equal(t, 2.5, wip.Items[0].AgeDays)
+	assert.False(t, wip.Items[0].AtRisk)
+}
+
+func TestService_GetCumulativeFlow(t *testing.T) {
+	service, mockRepo, mockProject := newTestService()
+	ctx := context.Background()
+
+	tickets := []ticket.Ticket{
+		{ID: 1, Status: "done", CreatedAt: at(1, 0)},
+		{ID: 2, Status: "in_progress", CreatedAt: at(1, 0)},
+		{ID: 3, Status: "new", CreatedAt: at(2, 10)},
+	}
+	changes := []ticket.Change{
+		{ID: 1, TicketID: 1, Field: ticket.FieldStatus, OldValue: ptr("new"), NewValue: ptr("in_progress"), ChangedAt: at(1, 10)},
+		{ID: 2, TicketID: 1, Field: ticket.FieldStatus, OldValue: ptr("in_progress"), NewValue: ptr("done"), ChangedAt: at(2, 10)},
+		{ID: 3, TicketID: 2, Field: ticket.FieldStatus, OldValue: ptr("new"), NewValue: ptr("in_progress"), ChangedAt: at(3, 10)},
+	}
+	r := DateRange{From: at(1, 0), To: at(3, 0)}
+	mockProject.On("GetProjectByID", ctx, int64(10), int64(1)).Return(&project.Project{}, nil).Once()
+	mockRepo.On("ListProjectTickets", ctx, int64(10)).Return(tickets, nil).Once()
+	mockRepo.On("ListProjectChanges", ctx, int64(10), ticket.FieldStatus, at(1, 0)).Return(changes, nil).Once()
+
+	cfd, err := service.GetCumulativeFlow(ctx, 10, 1, r)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"new", "in_progress", "done"}, cfd.Statuses)
+	assert.Equal(t, map[string]int{"new": 1, "in_progress": 1, "done": 0}, cfd.Series[0].Counts)
+	assert.Equal(t, map[string]int{"new": 2, "in_progress": 0, "done": 1}, cfd.Series[1].Counts)
+	// now is Mar 3 12:00
+	assert.Equal(t, map[string]int{"new": 1, "in_progress": 1, "done": 1}, cfd.Series[2].Counts)
+}
@@ -0,0 +1,228 @@
+package report
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// sprintReportParams reads project id, optional sprint and metric of sprint report
+func sprintReportParams(c echo.Context) (projectID int64, sprintID *int64, m Metric, err error) {
+	projectID, err = strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return 0, nil, "", errors.New("Invalid project ID")
+	}
+	if v := c.QueryParam("sprint"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return 0, nil, "", errors.New("Invalid sprint ID")
+		}
+		sprintID = &id
+	}
+	m, err = ParseMetric(c.QueryParam("metric"))
+	if err != nil {
+		return 0, nil, "", err
+	}
+	return projectID, sprintID, m, nil
+}
+
+// errorStatus maps service errors to HTTP status
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrSprintNotFound), errors.Is(err, ErrNoActiveSprint):
+		return http.StatusNotFound
+	case errors.Is(err, ErrNotStarted):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// Burndown handler for GET /api/projects/:id/reports/burndown?sprint=&metric=
+// Without sprint active one is used, metric is points (default), issues or time
+func (h *Handler) Burndown(c echo.Context) error {
+	projectID, sprintID, m, err := sprintReportParams(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	userID := c.Get("userID").(int64)
+
+	report, err := h.service.GetBurndown(c.Request().Context(), projectID, userID, sprintID, m)
+	if err != nil {
+		return c.JSON(errorStatus(err), map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+// Burnup handler for GET /api/projects/:id/reports/burnup?sprint=&metric=
+func (h *Handler) Burnup(c echo.Context) error {
+	projectID, sprintID, m, err := sprintReportParams(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	userID := c.Get("userID").(int64)
+
+	report, err := h.service.GetBurnup(c.Request().Context(), projectID, userID, sprintID, m)
+	if err != nil {
+		return c.JSON(errorStatus(err), map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+// ScopeChange handler for GET /api/projects/:id/reports/scope-change?sprint=&metric=
+func (h *Handler) ScopeChange(c echo.Context) error {
+	projectID, sprintID, m, err := sprintReportParams(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	userID := c.Get("userID").(int64)
+
+	report, err := h.service.GetScopeChange(c.Request().Context(), projectID, userID, sprintID, m)
+	if err != nil {
+		return c.JSON(errorStatus(err), map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+// Velocity handler for GET /api/projects/:id/reports/velocity?sprints=&metric=
+func (h *Handler) Velocity(c echo.Context) error {
+	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid project ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	count := 0
+	if v := c.QueryParam("sprints"); v != "" {
+		count, err = strconv.Atoi(v)
+		if err != nil || count < 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid sprints count"})
+		}
+	}
+	m, err := ParseMetric(c.QueryParam("metric"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	report, err := h.service.GetVelocity(c.Request().Context(), projectID, userID, count, m)
+	if err != nil {
+		return c.JSON(errorStatus(err), map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+// flowReportParams reads project id and from/to dates (YYYY-MM-DD) of flow report
+func flowReportParams(c echo.Context, now time.Time) (int64, DateRange, error) {
+	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return 0, DateRange{}, errors.New("Invalid project ID")
+	}
+
+	var from, to time.Time
+	if v := c.QueryParam("from"); v != "" {
+		from, err = time.Parse(time.DateOnly, v)
+		if err != nil {
+			return 0, DateRange{}, errors.New("Invalid from date")
+		}
+	}
+	if v := c.QueryParam("to"); v != "" {
+		to, err = time.Parse(time.DateOnly, v)
+		if err != nil {
+			return 0, DateRange{}, errors.New("Invalid to date")
+		}
+	}
+
+	r, err := NewDateRange(from, to, now)
+	if err != nil {
+		return 0, DateRange{}, err
+	}
+	return projectID, r, nil
+}
+
+// LeadTime handler for GET /api/projects/:id/reports/lead-time?from=&to=
+// Range defaults to last 30 days
+func (h *Handler) LeadTime(c echo.Context) error {
+	projectID, r, err := flowReportParams(c, time.Now())
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	userID := c.Get("userID").(int64)
+
+	report, err := h.service.GetLeadTime(c.Request().Context(), projectID, userID, r)
+	if err != nil {
+		return c.JSON(errorStatus(err), map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+// CycleTime handler for GET /api/projects/:id/reports/cycle-time?from=&to=
+func (h *Handler) CycleTime(c echo.Context) error {
+	projectID, r, err := flowReportParams(c, time.Now())
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	userID := c.Get("userID").(int64)
+
+	report, err := h.service.GetCycleTime(c.Request().Context(), projectID, userID, r)
+	if err != nil {
+		return c.JSON(errorStatus(err), map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+// Throughput handler for GET /api/projects/:id/reports/throughput?from=&to=
+func (h *Handler) Throughput(c echo.Context) error {
+	projectID, r, err := flowReportParams(c, time.Now())
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	userID := c.Get("userID").(int64)
+
+	report, err := h.service.GetThroughput(c.Request().Context(), projectID, userID, r)
+	if err != nil {
+		return c.JSON(errorStatus(err), map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+// AgingWIP handler for GET /api/projects/:id/reports/aging-wip?from=&to=
+// Range selects resolved tickets used as cycle time reference
+func (h *Handler) AgingWIP(c echo.Context) error {
+	projectID, r, err := flowReportParams(c, time.Now())
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	userID := c.Get("userID").(int64)
+
+	report, err := h.service.GetAgingWIP(c.Request().Context(), projectID, userID, r)
+	if err != nil {
+		return c.JSON(errorStatus(err), map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+// CumulativeFlow handler for GET /api/projects/:id/reports/cfd?from=&to=
+func (h *Handler) CumulativeFlow(c echo.Context) error {
+	projectID, r, err := flowReportParams(c, time.Now())
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	userID := c.Get("userID").(int64)
+
+	report, err := h.service.GetCumulativeFlow(c.Request().Context(), projectID, userID, r)
+	if err != nil {
+		return c.JSON(errorStatus(err), map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, report)
+}
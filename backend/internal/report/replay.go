@@ -0,0 +1,134 @@
+package report
+
+import (
+	"sort"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/ticket"
+)
+
+// ticketState is part of ticket that matters for reports
+type ticketState struct {
+	Status            string
+	SprintID          *int64
+	StoryPoints       *float64
+	RemainingEstimate *int64
+}
+
+// snapshot is state of tickets at some moment
+type snapshot map[int64]ticketState
+
+// timeline replays change log backward from current state of tickets.
+// Snapshots must be taken going back in time
+type timeline struct {
+	states  map[int64]*ticketState
+	changes []ticket.Change
+	next    int
+}
+
+func newTimeline(tickets []ticket.Ticket, changes []ticket.Change) *timeline {
+	states := make(map[int64]*ticketState, len(tickets))
+	for _, t := range tickets {
+		states[t.ID] = &ticketState{
+			Status:            t.Status,
+			SprintID:          t.SprintID,
+			StoryPoints:       t.StoryPoints,
+			RemainingEstimate: t.RemainingEstimate,
+		}
+	}
+
+	// newest first
+	sorted := make([]ticket.Change, len(changes))
+	copy(sorted, changes)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].ChangedAt.Equal(sorted[j].ChangedAt) {
+			return sorted[i].ID > sorted[j].ID
+		}
+		return sorted[i].ChangedAt.After(sorted[j].ChangedAt)
+	})
+
+	return &timeline{states: states, changes: sorted}
+}
+
+// at reverts changes made after moment and returns state of tickets at it
+func (tl *timeline) at(moment time.Time) snapshot {
+	for tl.next < len(tl.changes) && tl.changes[tl.next].ChangedAt.After(moment) {
+		tl.revert(&tl.changes[tl.next])
+		tl.next++
+	}
+
+	result := make(snapshot, len(tl.states))
+	for id, st := range tl.states {
+		result[id] = *st
+	}
+	return result
+}
+
+// revert sets field back to old value. Status without old value means ticket didn't exist yet
+func (tl *timeline) revert(c *ticket.Change) {
+	st, ok := tl.states[c.TicketID]
+	if !ok {
+		return
+	}
+	switch c.Field {
+	case ticket.FieldStatus:
+		st.Status = ""
+		if c.OldValue != nil {
+			st.Status = *c.OldValue
+		}
+	case ticket.FieldSprintID:
+		st.SprintID = ticket.ParseInt(c.OldValue)
+	case ticket.FieldStoryPoints:
+		st.StoryPoints = ticket.ParseFloat(c.OldValue)
+	case ticket.FieldRemainingEstimate:
+		st.RemainingEstimate = ticket.ParseInt(c.OldValue)
+	}
+}
+
+// inSprint reports if ticket existed and was planned into sprint
+func (st ticketState) inSprint(sprintID int64) bool {
+	return st.Status != "" && st.SprintID != nil && *st.SprintID == sprintID
+}
+
+func (st ticketState) done() bool {
+	return ticket.StatusCategory(st.Status) == ticket.CategoryDone
+}
+
+// value returns ticket work in metric
+func (m Metric) value(st ticketState) float64 {
+	switch m {
+	case MetricIssues:
+		return 1
+	case MetricTime:
+		if st.RemainingEstimate != nil {
+			return float64(*st.RemainingEstimate)
+		}
+		return 0
+	default:
+		if st.StoryPoints != nil {
+			return *st.StoryPoints
+		}
+		return 0
+	}
+}
+
+// sprintTotals sums work of tickets in sprint: all of it and done part
+func (m Metric) sprintTotals(s snapshot, sprintID int64) (scope, done float64) {
+	for _, st := range s {
+		if !st.inSprint(sprintID) {
+			continue
+		}
+		v := m.value(st)
+		scope += v
+		if st.done() {
+			done += v
+		}
+	}
+	return scope, done
+}
+
+// startOfDay truncates moment to UTC midnight
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
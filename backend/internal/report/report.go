@@ -0,0 +1,111 @@
+package report
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// Metric is what reports sum up
+type Metric string
+
+const (
+	// MetricPoints sums story points, tickets without estimate count as 0
+	MetricPoints Metric = "points"
+	// MetricIssues counts tickets
+	MetricIssues Metric = "issues"
+	// MetricTime sums remaining estimate in minutes, burndown only
+	MetricTime Metric = "time"
+)
+
+// Errors handlers map to HTTP statuses
+var (
+	ErrSprintNotFound = errors.New("sprint not found")
+	ErrNoActiveSprint = errors.New("project has no active sprint, sprint parameter required")
+	ErrNotStarted     = errors.New("sprint is not started yet")
+	ErrInvalidMetric  = errors.New("invalid metric, use points, issues or time")
+)
+
+// ParseMetric validates metric name, empty means points
+func ParseMetric(s string) (Metric, error) {
+	switch Metric(s) {
+	case "":
+		return MetricPoints, nil
+	case MetricPoints, MetricIssues, MetricTime:
+		return Metric(s), nil
+	}
+	return "", ErrInvalidMetric
+}
+
+// BurndownPoint is remaining work at the end of day
+type BurndownPoint struct {
+	Date      string  `json:"date"`
+	Remaining float64 `json:"remaining"`
+	Ideal     float64 `json:"ideal"`
+}
+
+// Burndown is remaining work of sprint per day
+type Burndown struct {
+	SprintID  int64           `json:"sprint_id"`
+	Metric    Metric          `json:"metric"`
+	StartDate time.Time       `json:"start_date"`
+	EndDate   time.Time       `json:"end_date"`
+	Committed float64         `json:"committed"`
+	Series    []BurndownPoint `json:"series"`
+}
+
+// BurnupPoint is completed work and total scope at the end of day
+type BurnupPoint struct {
+	Date      string  `json:"date"`
+	Completed float64 `json:"completed"`
+	Scope     float64 `json:"scope"`
+}
+
+// Burnup is completed work against sprint scope per day
+type Burnup struct {
+	SprintID  int64         `json:"sprint_id"`
+	Metric    Metric        `json:"metric"`
+	StartDate time.Time     `json:"start_date"`
+	EndDate   time.Time     `json:"end_date"`
+	Series    []BurnupPoint `json:"series"`
+}
+
+// VelocityEntry is committed and completed work of closed sprint
+type VelocityEntry struct {
+	SprintID  int64   `json:"sprint_id"`
+	Name      string  `json:"name"`
+	Committed float64 `json:"committed"`
+	Completed float64 `json:"completed"`
+}
+
+// Velocity of last closed sprints, oldest first
+type Velocity struct {
+	Metric  Metric          `json:"metric"`
+	Sprints []VelocityEntry `json:"sprints"`
+	Average float64         `json:"average"`
+}
+
+// ScopeChangeDay is work added to and removed from sprint during one day.
+// Reestimated is change of estimates of tickets staying in sprint
+type ScopeChangeDay struct {
+	Date          string  `json:"date"`
+	AddedIssues   int     `json:"added_issues"`
+	RemovedIssues int     `json:"removed_issues"`
+	Added         float64 `json:"added"`
+	Removed       float64 `json:"removed"`
+	Reestimated   float64 `json:"reestimated"`
+}
+
+// ScopeChange is daily scope change of sprint after its start
+type ScopeChange struct {
+	SprintID  int64            `json:"sprint_id"`
+	Metric    Metric           `json:"metric"`
+	Committed float64          `json:"committed"`
+	Current   float64          `json:"current"`
+	Days      []ScopeChangeDay `json:"days"`
+}
+
+// round2 rounds value for output
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,124 @@
+package report
+
+import (
+	"context"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/sprint"
+	"github.com/antonovs105/project-management-system-go/internal/ticket"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type Repository interface {
+	GetSprint(ctx context.Context, id int64) (*sprint.Sprint, error)
+	GetActiveSprint(ctx context.Context, projectID int64) (*sprint.Sprint, error)
+	ListClosedSprints(ctx context.Context, projectID int64, limit int) ([]sprint.Sprint, error)
+	ListSprintTickets(ctx context.Context, sprintID int64) ([]ticket.Ticket, error)
+	ListChanges(ctx context.Context, ticketIDs []int64, since time.Time) ([]ticket.Change, error)
+	ListProjectTickets(ctx context.Context, projectID int64) ([]ticket.Ticket, error)
+	ListProjectChanges(ctx context.Context, projectID int64, field string, since time.Time) ([]ticket.Change, error)
+}
+
+type PgRepository struct {
+	db *sqlx.DB
+}
+
+func NewRepository(db *sqlx.DB) Repository {
+	return &PgRepository{db: db}
+}
+
+// GetSprint finds sprint by its id
+func (r *PgRepository) GetSprint(ctx context.Context, id int64) (*sprint.Sprint, error) {
+	var s sprint.Sprint
+	err := r.db.GetContext(ctx, &s, `SELECT * FROM sprints WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// GetActiveSprint returns active sprint of project
+func (r *PgRepository) GetActiveSprint(ctx context.Context, projectID int64) (*sprint.Sprint, error) {
+	var s sprint.Sprint
+	query := `SELECT * FROM sprints WHERE project_id = $1 AND state = 'active'`
+	err := r.db.GetContext(ctx, &s, query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// ListClosedSprints returns last completed sprints of project, latest first
+func (r *PgRepository) ListClosedSprints(ctx context.Context, projectID int64, limit int) ([]sprint.Sprint, error) {
+	var sprints []sprint.Sprint
+	query := `
+		SELECT * FROM sprints
+		WHERE project_id = $1 AND state = 'closed' AND start_date IS NOT NULL
+		ORDER BY completed_at DESC
+		LIMIT $2`
+
+	err := r.db.SelectContext(ctx, &sprints, query, projectID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return sprints, nil
+}
+
+// ListSprintTickets returns tickets which are or ever were in sprint
+func (r *PgRepository) ListSprintTickets(ctx context.Context, sprintID int64) ([]ticket.Ticket, error) {
+	var tickets []ticket.Ticket
+	query := `
+		SELECT * FROM tickets
+		WHERE sprint_id = $1::bigint OR id IN (
+			SELECT ticket_id FROM ticket_changes
+			WHERE field = $2 AND (new_value = $1::bigint::text OR old_value = $1::bigint::text)
+		)`
+
+	err := r.db.SelectContext(ctx, &tickets, query, sprintID, ticket.FieldSprintID)
+	if err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
+// ListChanges returns change log of tickets made after since
+func (r *PgRepository) ListChanges(ctx context.Context, ticketIDs []int64, since time.Time) ([]ticket.Change, error) {
+	var changes []ticket.Change
+	query := `
+		SELECT * FROM ticket_changes
+		WHERE ticket_id = ANY($1) AND changed_at > $2
+		ORDER BY changed_at DESC, id DESC`
+
+	err := r.db.SelectContext(ctx, &changes, query, pq.Array(ticketIDs), since)
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// ListProjectTickets returns all tickets of project
+func (r *PgRepository) ListProjectTickets(ctx context.Context, projectID int64) ([]ticket.Ticket, error) {
+	var tickets []ticket.Ticket
+	err := r.db.SelectContext(ctx, &tickets, `SELECT * FROM tickets WHERE project_id = $1`, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
+// ListProjectChanges returns changes of one field of project tickets made after since
+func (r *PgRepository) ListProjectChanges(ctx context.Context, projectID int64, field string, since time.Time) ([]ticket.Change, error) {
+	var changes []ticket.Change
+	query := `
+		SELECT c.* FROM ticket_changes c
+		JOIN tickets t ON t.id = c.ticket_id
+		WHERE t.project_id = $1 AND c.field = $2 AND c.changed_at > $3
+		ORDER BY c.changed_at DESC, c.id DESC`
+
+	err := r.db.SelectContext(ctx, &changes, query, projectID, field, since)
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
@@ -0,0 +1,85 @@
+package report
+
+import (
+	"context"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/project"
+	"github.com/antonovs105/project-management-system-go/internal/sprint"
+	"github.com/antonovs105/project-management-system-go/internal/ticket"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRepository is a mock implementation of Repository
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) GetSprint(ctx context.Context, id int64) (*sprint.Sprint, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*sprint.Sprint), args.Error(1)
+}
+
+func (m *MockRepository) GetActiveSprint(ctx context.Context, projectID int64) (*sprint.Sprint, error) {
+	args := m.Called(ctx, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*sprint.Sprint), args.Error(1)
+}
+
+func (m *MockRepository) ListClosedSprints(ctx context.Context, projectID int64, limit int) ([]sprint.Sprint, error) {
+	args := m.Called(ctx, projectID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]sprint.Sprint), args.Error(1)
+}
+
+func (m *MockRepository) ListSprintTickets(ctx context.Context, sprintID int64) ([]ticket.Ticket, error) {
+	args := m.Called(ctx, sprintID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]ticket.Ticket), args.Error(1)
+}
+
+func (m *MockRepository) ListChanges(ctx context.Context, ticketIDs []int64, since time.Time) ([]ticket.Change, error) {
+	args := m.Called(ctx, ticketIDs, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]ticket.Change), args.Error(1)
+}
+
+func (m *MockRepository) ListProjectTickets(ctx context.Context, projectID int64) ([]ticket.Ticket, error) {
+	args := m.Called(ctx, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]ticket.Ticket), args.Error(1)
+}
+
+func (m *MockRepository) ListProjectChanges(ctx context.Context, projectID int64, field string, since time.Time) ([]ticket.Change, error) {
+	args := m.Called(ctx, projectID, field, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]ticket.Change), args.Error(1)
+}
+
+// MockProjectChecker
+type MockProjectChecker struct {
+	mock.Mock
+}
+
+func (m *MockProjectChecker) GetProjectByID(ctx context.Context, projectID, userID int64) (*project.Project, error) {
+	args := m.Called(ctx, projectID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*project.Project), args.Error(1)
+}
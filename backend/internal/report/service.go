@@ -0,0 +1,305 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/project"
+	"github.com/antonovs105/project-management-system-go/internal/sprint"
+)
+
+// Velocity sprint count limits
+const (
+	defaultVelocitySprints = 5
+	maxVelocitySprints     = 20
+)
+
+// maxReportDays limits length of daily series
+const maxReportDays = 366
+
+const day = 24 * time.Hour
+
+// ProjectChecker interface
+type ProjectChecker interface {
+	GetProjectByID(ctx context.Context, projectID, userID int64) (*project.Project, error)
+}
+
+type Service struct {
+	repo           Repository
+	projectService ProjectChecker
+	now            func() time.Time
+}
+
+func NewService(repo Repository, projectService ProjectChecker) *Service {
+	return &Service{
+		repo:           repo,
+		projectService: projectService,
+		now:            time.Now,
+	}
+}
+
+// sprintHistory is state of sprint tickets at sprint start and at the end of every day since
+type sprintHistory struct {
+	sprint *sprint.Sprint
+	start  time.Time
+	end    time.Time
+	days   []time.Time
+	// snaps[0] is taken at sprint start, snaps[i+1] at the end of days[i]
+	snaps []snapshot
+}
+
+// resolveSprint checks access and returns given sprint or active sprint of project
+func (s *Service) resolveSprint(ctx context.Context, projectID, userID int64, sprintID *int64) (*sprint.Sprint, error) {
+	// check access
+	_, err := s.projectService.GetProjectByID(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if sprintID == nil {
+		sp, err := s.repo.GetActiveSprint(ctx, projectID)
+		if err != nil {
+			return nil, ErrNoActiveSprint
+		}
+		return sp, nil
+	}
+
+	sp, err := s.repo.GetSprint(ctx, *sprintID)
+	if err != nil || sp.ProjectID != projectID {
+		return nil, ErrSprintNotFound
+	}
+	return sp, nil
+}
+
+// loadHistory replays change log of sprint tickets from now back to sprint start
+func (s *Service) loadHistory(ctx context.Context, sp *sprint.Sprint) (*sprintHistory, error) {
+	if sp.State == sprint.StatePlanned || sp.StartDate == nil {
+		return nil, ErrNotStarted
+	}
+
+	start := *sp.StartDate
+	cutoff := s.now()
+	if sp.CompletedAt != nil {
+		cutoff = *sp.CompletedAt
+	}
+	if cutoff.Before(start) {
+		return nil, ErrNotStarted
+	}
+
+	end := cutoff
+	if sp.EndDate != nil {
+		end = *sp.EndDate
+	}
+
+	var days []time.Time
+	for d := startOfDay(start); !d.After(cutoff); d = d.Add(day) {
+		days = append(days, d)
+	}
+	if len(days) > maxReportDays {
+		return nil, errors.New("sprint is too long for daily report")
+	}
+
+	tickets, err := s.repo.ListSprintTickets(ctx, sp.ID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, 0, len(tickets))
+	for _, t := range tickets {
+		ids = append(ids, t.ID)
+	}
+	changes, err := s.repo.ListChanges(ctx, ids, start)
+	if err != nil {
+		return nil, err
+	}
+
+	// replay goes back in time: last day first, sprint start at the end
+	tl := newTimeline(tickets, changes)
+	tl.at(cutoff)
+	snaps := make([]snapshot, len(days)+1)
+	for i := len(days) - 1; i >= 0; i-- {
+		moment := days[i].Add(day)
+		if moment.After(cutoff) {
+			moment = cutoff
+		}
+		snaps[i+1] = tl.at(moment)
+	}
+	snaps[0] = tl.at(start)
+
+	return &sprintHistory{sprint: sp, start: start, end: end, days: days, snaps: snaps}, nil
+}
+
+// committed is work planned into sprint at its start and not done yet
+func (h *sprintHistory) committed(m Metric) float64 {
+	scope, done := m.sprintTotals(h.snaps[0], h.sprint.ID)
+	return scope - done
+}
+
+// GetBurndown returns remaining work of sprint per day with ideal line.
+// Nil sprintID means active sprint
+func (s *Service) GetBurndown(ctx context.Context, projectID, userID int64, sprintID *int64, m Metric) (*Burndown, error) {
+	sp, err := s.resolveSprint(ctx, projectID, userID, sprintID)
+	if err != nil {
+		return nil, err
+	}
+	h, err := s.loadHistory(ctx, sp)
+	if err != nil {
+		return nil, err
+	}
+
+	committed := h.committed(m)
+	plannedDays := math.Max(1, math.Round(startOfDay(h.end).Sub(startOfDay(h.start)).Hours()/24))
+
+	result := &Burndown{
+		SprintID:  sp.ID,
+		Metric:    m,
+		StartDate: h.start,
+		EndDate:   h.end,
+		Committed: round2(committed),
+		Series:    make([]BurndownPoint, 0, len(h.days)),
+	}
+	for i, d := range h.days {
+		scope, done := m.sprintTotals(h.snaps[i+1], sp.ID)
+		ideal := committed * math.Max(0, 1-float64(i)/plannedDays)
+		result.Series = append(result.Series, BurndownPoint{
+			Date:      d.Format(time.DateOnly),
+			Remaining: round2(scope - done),
+			Ideal:     round2(ideal),
+		})
+	}
+	return result, nil
+}
+
+// GetBurnup returns completed work and scope of sprint per day
+func (s *Service) GetBurnup(ctx context.Context, projectID, userID int64, sprintID *int64, m Metric) (*Burnup, error) {
+	if m == MetricTime {
+		return nil, errors.New("time metric is supported only by burndown")
+	}
+
+	sp, err := s.resolveSprint(ctx, projectID, userID, sprintID)
+	if err != nil {
+		return nil, err
+	}
+	h, err := s.loadHistory(ctx, sp)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Burnup{
+		SprintID:  sp.ID,
+		Metric:    m,
+		StartDate: h.start,
+		EndDate:   h.end,
+		Series:    make([]BurnupPoint, 0, len(h.days)),
+	}
+	for i, d := range h.days {
+		scope, done := m.sprintTotals(h.snaps[i+1], sp.ID)
+		result.Series = append(result.Series, BurnupPoint{
+			Date:      d.Format(time.DateOnly),
+			Completed: round2(done),
+			Scope:     round2(scope),
+		})
+	}
+	return result, nil
+}
+
+// GetScopeChange returns work added to and removed from sprint per day since its start
+func (s *Service) GetScopeChange(ctx context.Context, projectID, userID int64, sprintID *int64, m Metric) (*ScopeChange, error) {
+	sp, err := s.resolveSprint(ctx, projectID, userID, sprintID)
+	if err != nil {
+		return nil, err
+	}
+	h, err := s.loadHistory(ctx, sp)
+	if err != nil {
+		return nil, err
+	}
+
+	startScope, _ := m.sprintTotals(h.snaps[0], sp.ID)
+	currentScope, _ := m.sprintTotals(h.snaps[len(h.snaps)-1], sp.ID)
+
+	result := &ScopeChange{
+		SprintID:  sp.ID,
+		Metric:    m,
+		Committed: round2(startScope),
+		Current:   round2(currentScope),
+		Days:      make([]ScopeChangeDay, 0, len(h.days)),
+	}
+	for i, d := range h.days {
+		change := diffScope(h.snaps[i], h.snaps[i+1], sp.ID, m)
+		change.Date = d.Format(time.DateOnly)
+		result.Days = append(result.Days, change)
+	}
+	return result, nil
+}
+
+// diffScope compares sprint content at the beginning and the end of day
+func diffScope(before, after snapshot, sprintID int64, m Metric) ScopeChangeDay {
+	var change ScopeChangeDay
+	for id, st := range after {
+		old := before[id]
+		switch {
+		case st.inSprint(sprintID) && !old.inSprint(sprintID):
+			change.AddedIssues++
+			change.Added += m.value(st)
+		case !st.inSprint(sprintID) && old.inSprint(sprintID):
+			change.RemovedIssues++
+			change.Removed += m.value(old)
+		case st.inSprint(sprintID):
+			change.Reestimated += m.value(st) - m.value(old)
+		}
+	}
+	change.Added = round2(change.Added)
+	change.Removed = round2(change.Removed)
+	change.Reestimated = round2(change.Reestimated)
+	return change
+}
+
+// GetVelocity returns committed and completed work of last closed sprints, oldest first
+func (s *Service) GetVelocity(ctx context.Context, projectID, userID int64, count int, m Metric) (*Velocity, error) {
+	if m == MetricTime {
+		return nil, errors.New("time metric is supported only by burndown")
+	}
+
+	// check access
+	_, err := s.projectService.GetProjectByID(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if count <= 0 {
+		count = defaultVelocitySprints
+	}
+	if count > maxVelocitySprints {
+		count = maxVelocitySprints
+	}
+
+	sprints, err := s.repo.ListClosedSprints(ctx, projectID, count)
+	if err != nil {
+		return nil, err
+	}
+	slices.Reverse(sprints)
+
+	result := &Velocity{Metric: m, Sprints: make([]VelocityEntry, 0, len(sprints))}
+	var total float64
+	for i := range sprints {
+		h, err := s.loadHistory(ctx, &sprints[i])
+		if err != nil {
+			return nil, err
+		}
+		_, completed := m.sprintTotals(h.snaps[len(h.snaps)-1], sprints[i].ID)
+		total += completed
+
+		result.Sprints = append(result.Sprints, VelocityEntry{
+			SprintID:  sprints[i].ID,
+			Name:      sprints[i].Name,
+			Committed: round2(h.committed(m)),
+			Completed: round2(completed),
+		})
+	}
+	if len(sprints) > 0 {
+		result.Average = round2(total / float64(len(sprints)))
+	}
+	return result, nil
+}
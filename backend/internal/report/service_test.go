@@ -0,0 +1,148 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/project"
+	"github.com/antonovs105/project-management-system-go/internal/sprint"
+	"github.com/antonovs105/project-management-system-go/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func at(day, hour int) time.Time {
+	return time.Date(2024, 3, day, hour, 0, 0, 0, time.UTC)
+}
+
+// sprintFixture is sprint 1 started Mar 1 and planned till Mar 4:
+// A done on Mar 2, D removed on Mar 2, B reestimated 3 -> 5 on Mar 2, C added on Mar 3
+func sprintFixture() (*sprint.Sprint, []ticket.Ticket, []ticket.Change) {
+	sp := &sprint.Sprint{ID: 1, ProjectID: 10, State: sprint.StateActive, StartDate: ptr(at(1, 9)), EndDate: ptr(at(4, 9))}
+	tickets := []ticket.Ticket{
+		{ID: 101, Status: "done", SprintID: ptr(int64(1)), StoryPoints: ptr(3.0)},
+		{ID: 102, Status: "in_progress", SprintID: ptr(int64(1)), StoryPoints: ptr(5.0)},
+		{ID: 103, Status: "new", SprintID: ptr(int64(1)), StoryPoints: ptr(2.0)},
+		{ID: 104, Status: "new", StoryPoints: ptr(8.0)},
+	}
+	changes := []ticket.Change{
+		{ID: 1, TicketID: 101, Field: ticket.FieldStatus, OldValue: ptr("in_progress"), NewValue: ptr("done"), ChangedAt: at(2, 10)},
+		{ID: 2, TicketID: 104, Field: ticket.FieldSprintID, OldValue: ptr("1"), ChangedAt: at(2, 11)},
+		{ID: 3, TicketID: 102, Field: ticket.FieldStoryPoints, OldValue: ptr("3"), NewValue: ptr("5"), ChangedAt: at(2, 15)},
+		{ID: 4, TicketID: 103, Field: ticket.FieldSprintID, NewValue: ptr("1"), ChangedAt: at(3, 10)},
+	}
+	return sp, tickets, changes
+}
+
+func newTestService() (*Service, *MockRepository, *MockProjectChecker) {
+	mockRepo := new(MockRepository)
+	mockProject := new(MockProjectChecker)
+	service := NewService(mockRepo, mockProject)
+	service.now = func() time.Time { return at(3, 12) }
+	return service, mockRepo, mockProject
+}
+
+func expectSprint(mockRepo *MockRepository, mockProject *MockProjectChecker) {
+	sp, tickets, changes := sprintFixture()
+	mockProject.On("GetProjectByID", mock.Anything, int64(10), int64(1)).Return(&project.Project{}, nil).Once()
+	mockRepo.On("GetActiveSprint", mock.Anything, int64(10)).Return(sp, nil).Once()
+	mockRepo.On("ListSprintTickets", mock.Anything, int64(1)).Return(tickets, nil).Once()
+	mockRepo.On("ListChanges", mock.Anything, []int64{101, 102, 103, 104}, at(1, 9)).Return(changes, nil).Once()
+}
+
+func TestService_GetBurndown(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		service, mockRepo, mockProject := newTestService()
+		expectSprint(mockRepo, mockProject)
+
+		report, err := service.GetBurndown(ctx, 10, 1, nil, MetricPoints)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 14.0, report.Committed)
+		assert.Equal(t, []BurndownPoint{
+			{Date: "2024-03-01", Remaining: 14, Ideal: 14},
+			{Date: "2024-03-02", Remaining: 5, Ideal: 9.33},
+			{Date: "2024-03-03", Remaining: 7, Ideal: 4.67},
+		}, report.Series)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("NoActiveSprint", func(t *testing.T) {
+		service, mockRepo, mockProject := newTestService()
+		mockProject.On("GetProjectByID", ctx, int64(10), int64(1)).Return(&project.Project{}, nil).Once()
+		mockRepo.On("GetActiveSprint", ctx, int64(10)).Return(nil, errors.New("no rows")).Once()
+
+		_, err := service.GetBurndown(ctx, 10, 1, nil, MetricPoints)
+
+		assert.ErrorIs(t, err, ErrNoActiveSprint)
+	})
+
+	t.Run("NotStarted", func(t *testing.T) {
+		service, mockRepo, mockProject := newTestService()
+		mockProject.On("GetProjectByID", ctx, int64(10), int64(1)).Return(&project.Project{}, nil).Once()
+		mockRepo.On("GetSprint", ctx, int64(2)).Return(&sprint.Sprint{ID: 2, ProjectID: 10, State: sprint.StatePlanned}, nil).Once()
+
+		_, err := service.GetBurndown(ctx, 10, 1, ptr(int64(2)), MetricPoints)
+
+		assert.ErrorIs(t, err, ErrNotStarted)
+	})
+}
+
+func TestService_GetBurnup(t *testing.T) {
+	service, mockRepo, mockProject := newTestService()
+	expectSprint(mockRepo, mockProject)
+
+	report, err := service.GetBurnup(context.Background(), 10, 1, nil, MetricIssues)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []BurnupPoint{
+		{Date: "2024-03-01", Completed: 0, Scope: 3},
+		{Date: "2024-03-02", Completed: 1, Scope: 2},
+		{Date: "2024-03-03", Completed: 1, Scope: 3},
+	}, report.Series)
+}
+
+func TestService_GetScopeChange(t *testing.T) {
+	service, mockRepo, mockProject := newTestService()
+	expectSprint(mockRepo, mockProject)
+
+	report, err := service.GetScopeChange(context.Background(), 10, 1, nil, MetricPoints)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 14.0, report.Committed)
+	assert.Equal(t, 10.0, report.Current)
+	assert.Equal(t, ScopeChangeDay{Date: "2024-03-01"}, report.Days[0])
+	assert.Equal(t, ScopeChangeDay{Date: "2024-03-02", RemovedIssues: 1, Removed: 8, Reestimated: 2}, report.Days[1])
+	assert.Equal(t, ScopeChangeDay{Date: "2024-03-03", AddedIssues: 1, Added: 2}, report.Days[2])
+}
+
+func TestService_GetVelocity(t *testing.T) {
+	service, mockRepo, mockProject := newTestService()
+	ctx := context.Background()
+
+	sp, tickets, changes := sprintFixture()
+	sp.State = sprint.StateClosed
+	sp.CompletedAt = ptr(at(3, 12))
+
+	mockProject.On("GetProjectByID", ctx, int64(10), int64(1)).Return(&project.Project{}, nil).Once()
+	mockRepo.On("ListClosedSprints", ctx, int64(10), defaultVelocitySprints).Return([]sprint.Sprint{*sp}, nil).Once()
+	mockRepo.On("ListSprintTickets", ctx, int64(1)).Return(tickets, nil).Once()
+	mockRepo.On("ListChanges", ctx, []int64{101, 102, 103, 104}, at(1, 9)).Return(changes, nil).Once()
+
+	report, err := service.GetVelocity(ctx, 10, 1, 0, MetricPoints)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []VelocityEntry{{SprintID: 1, Committed: 14, Completed: 3}}, report.Sprints)
+	assert.Equal(t, 3.0, report.Average)
+
+	_, err = service.GetVelocity(ctx, 10, 1, 0, MetricTime)
+	assert.Error(t, err)
+}
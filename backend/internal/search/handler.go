@@ -0,0 +1,66 @@
+package search
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// Search handler for GET /api/search?q=text&project=1,2&status=open&type=bug&limit=20&offset=0
+func (h *Handler) Search(c echo.Context) error {
+	userID := c.Get("userID").(int64)
+
+	q := Query{
+		Text:     c.QueryParam("q"),
+		Statuses: splitList(c.QueryParam("status")),
+		Types:    splitList(c.QueryParam("type")),
+	}
+	for _, v := range splitList(c.QueryParam("project")) {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid project ID"})
+		}
+		q.ProjectIDs = append(q.ProjectIDs, id)
+	}
+
+	var err error
+	if v := c.QueryParam("limit"); v != "" {
+		q.Limit, err = strconv.Atoi(v)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid limit"})
+		}
+	}
+	if v := c.QueryParam("offset"); v != "" {
+		q.Offset, err = strconv.Atoi(v)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid offset"})
+		}
+	}
+
+	result, err := h.service.Search(c.Request().Context(), userID, q)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// splitList parses comma separated values, nil when empty
+func splitList(v string) []string {
+	var result []string
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			result = append(result, s)
+		}
+	}
+	return result
+}
@@ -0,0 +1,63 @@
+package search
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type Repository interface {
+	Search(ctx context.Context, userID int64, tsQuery, key string, q Query) ([]Hit, error)
+}
+
+type PgRepository struct {
+	db *sqlx.DB
+}
+
+func NewRepository(db *sqlx.DB) Repository {
+	return &PgRepository{db: db}
+}
+
+// Search finds tickets of projects where user is member by text document or exact key.
+// Empty filters match everything
+func (r *PgRepository) Search(ctx context.Context, userID int64, tsQuery, key string, q Query) ([]Hit, error) {
+	var hits []Hit
+	// highlights are HTML, so source text is escaped before <mark> tags are added
+	query := `
+		WITH q AS (SELECT to_tsquery('english', $1) AS query)
+		SELECT t.id AS ticket_id, t.project_id, t.key, t.title, t.status, t.type, t.priority, t.updated_at,
+			ts_headline('english', ` + escapeHTML("t.title") + `, q.query,
+				'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS title_highlight,
+			ts_headline('english',
+				` + escapeHTML(`concat_ws(' ', t.description,
+					(SELECT string_agg(c.body, ' ' ORDER BY c.id) FROM ticket_comments c WHERE c.ticket_id = t.id))`) + `,
+				q.query,
+				'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=20, MinWords=8, FragmentDelimiter=" … "') AS snippet,
+			ts_rank_cd(s.document, q.query)::float8 + CASE WHEN t.key = $2 THEN 10 ELSE 0 END AS rank,
+			COUNT(*) OVER () AS total
+		FROM tickets t
+		JOIN project_members pm ON pm.project_id = t.project_id AND pm.user_id = $3
+		JOIN ticket_search s ON s.ticket_id = t.id
+		CROSS JOIN q
+		WHERE (s.document @@ q.query OR t.key = $2)
+			AND ($4::bigint[] IS NULL OR t.project_id = ANY($4))
+			AND ($5::text[] IS NULL OR t.status = ANY($5))
+			AND ($6::text[] IS NULL OR t.type = ANY($6))
+		ORDER BY rank DESC, t.updated_at DESC, t.id
+		LIMIT $7 OFFSET $8`
+
+	err := r.db.SelectContext(ctx, &hits, query,
+		tsQuery, key, userID,
+		pq.Array(q.ProjectIDs), pq.Array(q.Statuses), pq.Array(q.Types),
+		q.Limit, q.Offset)
+	if err != nil {
+		return nil, err
+	}
+	return hits, nil
+}
+
+// escapeHTML wraps SQL text expression so it is safe to embed in HTML
+func escapeHTML(expr string) string {
+	return `replace(replace(replace(` + expr + `, '&', '&amp;'), '<', '&lt;'), '>', '&gt;')`
+}
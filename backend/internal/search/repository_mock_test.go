@@ -0,0 +1,20 @@
+package search
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRepository is a mock implementation of Repository
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Search(ctx context.Context, userID int64, tsQuery, key string, q Query) ([]Hit, error) {
+	args := m.Called(ctx, userID, tsQuery, key, q)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Hit), args.Error(1)
+}
@@ -0,0 +1,86 @@
+package search
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Limits of search page
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+	// maxTerms keeps generated tsquery small
+	maxTerms = 10
+)
+
+// Query is search request with optional filters
+type Query struct {
+	Text       string
+	ProjectIDs []int64
+	Statuses   []string
+	Types      []string
+	Limit      int
+	Offset     int
+}
+
+// Hit is ticket matching search. Highlights wrap matches in <mark>, ticket text is not escaped
+type Hit struct {
+	TicketID       int64     `db:"ticket_id" json:"ticket_id"`
+	ProjectID      int64     `db:"project_id" json:"project_id"`
+	Key            string    `db:"key" json:"key"`
+	Title          string    `db:"title" json:"title"`
+	Status         string    `db:"status" json:"status"`
+	Type           string    `db:"type" json:"type"`
+	Priority       string    `db:"priority" json:"priority"`
+	TitleHighlight string    `db:"title_highlight" json:"title_highlight"`
+	Snippet        string    `db:"snippet" json:"snippet"`
+	Rank           float64   `db:"rank" json:"rank"`
+	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
+	// Total is number of all hits, same on every row
+	Total int `db:"total" json:"-"`
+}
+
+// Result is one page of hits, best first
+type Result struct {
+	Query  string `json:"query"`
+	Total  int    `json:"total"`
+	Limit  int    `json:"limit"`
+	Offset int    `json:"offset"`
+	Hits   []Hit  `json:"hits"`
+}
+
+// keyPattern matches ticket key like PMS-12
+var keyPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*-[0-9]+$`)
+
+// ticketKey returns upper case key if text is ticket key, otherwise empty string
+func ticketKey(text string) string {
+	text = strings.TrimSpace(text)
+	if !keyPattern.MatchString(text) {
+		return ""
+	}
+	return strings.ToUpper(text)
+}
+
+// toTSQuery turns free text into prefix tsquery where all words must match: "logi fail" -> "logi:* & fail:*".
+// Only letters and digits are kept, so result is safe for to_tsquery
+func toTSQuery(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	seen := make(map[string]bool)
+	terms := make([]string, 0, len(words))
+	for _, w := range words {
+		if seen[w] {
+			continue
+		}
+		seen[w] = true
+		terms = append(terms, w+":*")
+		if len(terms) == maxTerms {
+			break
+		}
+	}
+	return strings.Join(terms, " & ")
+}
@@ -0,0 +1,49 @@
+package search
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrEmptyQuery is returned when query has no words to search
+var ErrEmptyQuery = errors.New("search query is required")
+
+type Service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// Search returns tickets of user projects matching query, best first.
+// Every word matches as prefix, text equal to ticket key puts that ticket on top
+func (s *Service) Search(ctx context.Context, userID int64, q Query) (*Result, error) {
+	tsQuery := toTSQuery(q.Text)
+	key := ticketKey(q.Text)
+	if tsQuery == "" && key == "" {
+		return nil, ErrEmptyQuery
+	}
+
+	if q.Limit <= 0 {
+		q.Limit = defaultLimit
+	}
+	if q.Limit > maxLimit {
+		q.Limit = maxLimit
+	}
+	if q.Offset < 0 {
+		q.Offset = 0
+	}
+
+	hits, err := s.repo.Search(ctx, userID, tsQuery, key, q)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Query: q.Text, Limit: q.Limit, Offset: q.Offset, Hits: []Hit{}}
+	if len(hits) > 0 {
+		result.Total = hits[0].Total
+		result.Hits = hits
+	}
+	return result, nil
+}
@@ -0,0 +1,68 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToTSQuery(t *testing.T) {
+	cases := map[string]string{
+		"login":              "login:*",
+		"Logi  FAIL":         "logi:* & fail:*",
+		"o'reilly & (drop)!": "o:* & reilly:* & drop:*",
+		"PMS-12":             "pms:* & 12:*",
+		"fix fix":            "fix:*",
+		"überprüfung":        "überprüfung:*",
+		"  ":                 "",
+		"!!!":                "",
+	}
+	for in, want := range cases {
+		assert.Equal(t, want, toTSQuery(in), in)
+	}
+}
+
+func TestTicketKey(t *testing.T) {
+	assert.Equal(t, "PMS-12", ticketKey(" pms-12 "))
+	assert.Equal(t, "", ticketKey("pms 12"))
+	assert.Equal(t, "", ticketKey("12-12"))
+}
+
+func TestService_Search(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewService(mockRepo)
+
+	ctx := context.Background()
+	userID := int64(1)
+
+	t.Run("Success", func(t *testing.T) {
+		q := Query{Text: "login", Statuses: []string{"open"}, Limit: defaultLimit}
+		hits := []Hit{{TicketID: 5, Key: "PMS-5", Total: 3}, {TicketID: 6, Key: "PMS-6", Total: 3}}
+		mockRepo.On("Search", ctx, userID, "login:*", "", q).Return(hits, nil).Once()
+
+		result, err := service.Search(ctx, userID, Query{Text: "login", Statuses: []string{"open"}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, result.Total)
+		assert.Len(t, result.Hits, 2)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("LimitCapped", func(t *testing.T) {
+		q := Query{Text: "pms-7", Limit: maxLimit}
+		mockRepo.On("Search", ctx, userID, "pms:* & 7:*", "PMS-7", q).Return([]Hit{}, nil).Once()
+
+		result, err := service.Search(ctx, userID, Query{Text: "pms-7", Limit: 1000, Offset: -5})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, result.Total)
+		assert.NotNil(t, result.Hits)
+	})
+
+	t.Run("EmptyQuery", func(t *testing.T) {
+		_, err := service.Search(ctx, userID, Query{Text: " - "})
+
+		assert.ErrorIs(t, err, ErrEmptyQuery)
+	})
+}
@@ -0,0 +1,92 @@
+package sla
+
+import (
+	"slices"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/ticket"
+)
+
+// Evaluate computes SLA of ticket from its status history (oldest change first).
+// Clock runs from creation to resolution or now, except time spent in pause statuses
+func Evaluate(t *ticket.Ticket, policy *Policy, statusChanges []ticket.Change, now time.Time) TicketSLA {
+	target := time.Duration(policy.ResolveWithinMinutes) * time.Minute
+	paused := func(status string) bool {
+		return slices.Contains(policy.PauseStatuses, status)
+	}
+
+	end := now
+	if t.ResolvedAt != nil {
+		end = *t.ResolvedAt
+	}
+
+	// status at creation is old value of first transition
+	status := t.Status
+	for _, c := range statusChanges {
+		if c.OldValue == nil {
+			if c.NewValue != nil {
+				status = *c.NewValue
+			}
+			continue
+		}
+		status = *c.OldValue
+		break
+	}
+
+	var elapsed time.Duration
+	var breachAt *time.Time
+	cursor := t.CreatedAt
+	advance := func(until time.Time) {
+		if until.After(end) {
+			until = end
+		}
+		if !until.After(cursor) {
+			return
+		}
+		if !paused(status) {
+			elapsed += until.Sub(cursor)
+			if breachAt == nil && elapsed > target {
+				at := until.Add(target - elapsed)
+				breachAt = &at
+			}
+		}
+		cursor = until
+	}
+
+	for _, c := range statusChanges {
+		if c.OldValue == nil {
+			continue
+		}
+		advance(c.ChangedAt)
+		if c.NewValue != nil {
+			status = *c.NewValue
+		}
+	}
+	advance(end)
+
+	result := TicketSLA{
+		TicketID:         t.ID,
+		Key:              t.Key,
+		Title:            t.Title,
+		Priority:         policy.Priority,
+		Status:           t.Status,
+		TargetMinutes:    policy.ResolveWithinMinutes,
+		ElapsedMinutes:   int64(elapsed / time.Minute),
+		RemainingMinutes: int64((target - elapsed) / time.Minute),
+		BreachAt:         breachAt,
+	}
+
+	switch {
+	case breachAt != nil:
+		result.State = StateBreached
+	case t.ResolvedAt != nil:
+		result.State = StateMet
+	case paused(status):
+		result.State = StatePaused
+	default:
+		result.State = StateRunning
+		at := now.Add(target - elapsed)
+		result.BreachAt = &at
+	}
+	return result
+}
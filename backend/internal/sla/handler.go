@@ -0,0 +1,113 @@
+package sla
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/project"
+	"github.com/labstack/echo/v4"
+)
+
+// defaultReportDays is range of SLA report without dates
+const defaultReportDays = 30
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// GetPolicies handler for GET /api/projects/:id/sla-policies
+func (h *Handler) GetPolicies(c echo.Context) error {
+	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid project ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	policies, err := h.service.GetPolicies(c.Request().Context(), projectID, userID)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, policies)
+}
+
+type updatePoliciesRequest struct {
+	Policies []PolicyRequest `json:"policies"`
+}
+
+// UpdatePolicies handler for PUT /api/projects/:id/sla-policies
+func (h *Handler) UpdatePolicies(c echo.Context) error {
+	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid project ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	var req updatePoliciesRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+	}
+
+	policies, err := h.service.UpdatePolicies(c.Request().Context(), projectID, userID, req.Policies)
+	if errors.Is(err, project.ErrForbidden) {
+		return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, policies)
+}
+
+// TicketSLA handler for GET /api/tickets/:id/sla
+func (h *Handler) TicketSLA(c echo.Context) error {
+	ticketID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ticket ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	result, err := h.service.GetTicketSLA(c.Request().Context(), ticketID, userID)
+	if err != nil {
+		if errors.Is(err, ErrNoPolicy) {
+			return c.JSON(http.StatusOK, map[string]any{"ticket_id": ticketID, "state": nil})
+		}
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// Report handler for GET /api/projects/:id/reports/sla?from=YYYY-MM-DD&to=YYYY-MM-DD
+// Defaults to last 30 days
+func (h *Handler) Report(c echo.Context) error {
+	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid project ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	if v := c.QueryParam("to"); v != "" {
+		to, err = time.Parse(time.DateOnly, v)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid to date"})
+		}
+	}
+	from := to.AddDate(0, 0, -(defaultReportDays - 1))
+	if v := c.QueryParam("from"); v != "" {
+		from, err = time.Parse(time.DateOnly, v)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid from date"})
+		}
+	}
+
+	report, err := h.service.GetReport(c.Request().Context(), projectID, userID, from, to)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, report)
+}
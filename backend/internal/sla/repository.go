@@ -0,0 +1,130 @@
+package sla
+
+import (
+	"context"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/ticket"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type Repository interface {
+	ListPolicies(ctx context.Context, projectID int64) ([]Policy, error)
+	ReplacePolicies(ctx context.Context, projectID int64, policies []Policy) error
+	ListTickets(ctx context.Context, projectID int64, resolvedSince time.Time) ([]ticket.Ticket, error)
+	ListTicketsToSweep(ctx context.Context) ([]ticket.Ticket, error)
+	ListStatusChanges(ctx context.Context, ticketIDs []int64) ([]ticket.Change, error)
+	SetBreachedAt(ctx context.Context, ticketID int64, at *time.Time) error
+}
+
+type PgRepository struct {
+	db *sqlx.DB
+}
+
+func NewRepository(db *sqlx.DB) Repository {
+	return &PgRepository{db: db}
+}
+
+// ListPolicies returns SLA policies of project
+func (r *PgRepository) ListPolicies(ctx context.Context, projectID int64) ([]Policy, error) {
+	var policies []Policy
+	query := `SELECT * FROM sla_policies WHERE project_id = $1 ORDER BY resolve_within_minutes`
+
+	err := r.db.SelectContext(ctx, &policies, query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+// ReplacePolicies swaps all policies of project in one transaction
+func (r *PgRepository) ReplacePolicies(ctx context.Context, projectID int64, policies []Policy) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM sla_policies WHERE project_id = $1`, projectID)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO sla_policies (project_id, priority, resolve_within_minutes, pause_statuses)
+		VALUES (:project_id, :priority, :resolve_within_minutes, :pause_statuses)
+		RETURNING *`
+	for i := range policies {
+		policies[i].ProjectID = projectID
+		rows, err := sqlx.NamedQueryContext(ctx, tx, query, &policies[i])
+		if err != nil {
+			return err
+		}
+		if rows.Next() {
+			err = rows.StructScan(&policies[i])
+		}
+		rows.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ListTickets returns open tickets of project and tickets resolved after resolvedSince
+func (r *PgRepository) ListTickets(ctx context.Context, projectID int64, resolvedSince time.Time) ([]ticket.Ticket, error) {
+	var tickets []ticket.Ticket
+	query := `
+		SELECT * FROM tickets
+		WHERE project_id = $1 AND (resolved_at IS NULL OR resolved_at >= $2)`
+
+	err := r.db.SelectContext(ctx, &tickets, query, projectID, resolvedSince)
+	if err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
+// ListTicketsToSweep returns unresolved tickets covered by SLA policy and flagged ones which may
+// have lost their policy. Policies keep priorities lowercased, tickets keep them as entered
+func (r *PgRepository) ListTicketsToSweep(ctx context.Context) ([]ticket.Ticket, error) {
+	var tickets []ticket.Ticket
+	query := `
+		SELECT t.* FROM tickets t
+		WHERE t.resolved_at IS NULL AND (
+			t.sla_breached_at IS NOT NULL
+			OR EXISTS (
+				SELECT 1 FROM sla_policies p
+				WHERE p.project_id = t.project_id AND p.priority = lower(btrim(t.priority))
+			)
+		)`
+
+	err := r.db.SelectContext(ctx, &tickets, query)
+	if err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
+// ListStatusChanges returns status history of tickets, oldest first
+func (r *PgRepository) ListStatusChanges(ctx context.Context, ticketIDs []int64) ([]ticket.Change, error) {
+	var changes []ticket.Change
+	query := `
+		SELECT * FROM ticket_changes
+		WHERE ticket_id = ANY($1) AND field = $2
+		ORDER BY changed_at, id`
+
+	err := r.db.SelectContext(ctx, &changes, query, pq.Array(ticketIDs), ticket.FieldStatus)
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// SetBreachedAt sets or clears SLA breach flag of ticket
+func (r *PgRepository) SetBreachedAt(ctx context.Context, ticketID int64, at *time.Time) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE tickets SET sla_breached_at = $2 WHERE id = $1`, ticketID, at)
+	return err
+}
@@ -0,0 +1,91 @@
+package sla
+
+import (
+	"context"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/project"
+	"github.com/antonovs105/project-management-system-go/internal/ticket"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRepository is a mock implementation of Repository
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) ListPolicies(ctx context.Context, projectID int64) ([]Policy, error) {
+	args := m.Called(ctx, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Policy), args.Error(1)
+}
+
+func (m *MockRepository) ReplacePolicies(ctx context.Context, projectID int64, policies []Policy) error {
+	args := m.Called(ctx, projectID, policies)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ListTickets(ctx context.Context, projectID int64, resolvedSince time.Time) ([]ticket.Ticket, error) {
+	args := m.Called(ctx, projectID, resolvedSince)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]ticket.Ticket), args.Error(1)
+}
+
+func (m *MockRepository) ListTicketsToSweep(ctx context.Context) ([]ticket.Ticket, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]ticket.Ticket), args.Error(1)
+}
+
+func (m *MockRepository) ListStatusChanges(ctx context.Context, ticketIDs []int64) ([]ticket.Change, error) {
+	args := m.Called(ctx, ticketIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]ticket.Change), args.Error(1)
+}
+
+func (m *MockRepository) SetBreachedAt(ctx context.Context, ticketID int64, at *time.Time) error {
+	args := m.Called(ctx, ticketID, at)
+	return args.Error(0)
+}
+
+// MockProjectChecker
+type MockProjectChecker struct {
+	mock.Mock
+}
+
+func (m *MockProjectChecker) GetProjectByID(ctx context.Context, projectID, userID int64) (*project.Project, error) {
+	args := m.Called(ctx, projectID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*project.Project), args.Error(1)
+}
+
+// MockTicketGetter
+type MockTicketGetter struct {
+	mock.Mock
+}
+
+func (m *MockTicketGetter) GetTicketByID(ctx context.Context, ticketID, userID int64) (*ticket.Ticket, error) {
+	args := m.Called(ctx, ticketID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ticket.Ticket), args.Error(1)
+}
+
+func (m *MockProjectChecker) GetManagedProject(ctx context.Context, projectID, userID int64) (*project.Project, error) {
+	args := m.Called(ctx, projectID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*project.Project), args.Error(1)
+}
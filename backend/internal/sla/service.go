@@ -0,0 +1,330 @@
+package sla
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/project"
+	"github.com/antonovs105/project-management-system-go/internal/ticket"
+	"github.com/lib/pq"
+)
+
+// ErrNoPolicy is returned for tickets whose priority has no SLA
+var ErrNoPolicy = errors.New("no SLA policy for ticket priority")
+
+// ProjectChecker interface
+type ProjectChecker interface {
+	GetProjectByID(ctx context.Context, projectID, userID int64) (*project.Project, error)
+	GetManagedProject(ctx context.Context, projectID, userID int64) (*project.Project, error)
+}
+
+// TicketGetter interface
+type TicketGetter interface {
+	GetTicketByID(ctx context.Context, ticketID, userID int64) (*ticket.Ticket, error)
+}
+
+type Service struct {
+	repo           Repository
+	projectService ProjectChecker
+	ticketService  TicketGetter
+	now            func() time.Time
+}
+
+func NewService(repo Repository, projectService ProjectChecker, ticketService TicketGetter) *Service {
+	return &Service{
+		repo:           repo,
+		projectService: projectService,
+		ticketService:  ticketService,
+		now:            time.Now,
+	}
+}
+
+// GetPolicies returns SLA policies of project
+func (s *Service) GetPolicies(ctx context.Context, projectID, userID int64) ([]Policy, error) {
+	// check access
+	_, err := s.projectService.GetProjectByID(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	policies, err := s.repo.ListPolicies(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if policies == nil {
+		policies = []Policy{}
+	}
+	return policies, nil
+}
+
+// PolicyRequest DTO for one policy
+type PolicyRequest struct {
+	Priority             string   `json:"priority"`
+	ResolveWithinMinutes int64    `json:"resolve_within_minutes"`
+	PauseStatuses        []string `json:"pause_statuses"`
+}
+
+// UpdatePolicies replaces SLA policies of project, empty list turns SLA off. Only owners and managers may do it
+func (s *Service) UpdatePolicies(ctx context.Context, projectID, userID int64, req []PolicyRequest) ([]Policy, error) {
+	// check access
+	_, err := s.projectService.GetManagedProject(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	policies := make([]Policy, 0, len(req))
+	seen := make(map[string]bool)
+	for _, r := range req {
+		priority := strings.ToLower(strings.TrimSpace(r.Priority))
+		if priority == "" {
+			return nil, errors.New("policy priority is required")
+		}
+		if seen[priority] {
+			return nil, fmt.Errorf("duplicate policy for priority %q", priority)
+		}
+		seen[priority] = true
+		if r.ResolveWithinMinutes <= 0 {
+			return nil, fmt.Errorf("resolution target of %q must be positive", priority)
+		}
+
+		pause := pq.StringArray{}
+		for _, status := range r.PauseStatuses {
+			if ticket.StatusCategory(status) == ticket.CategoryDone {
+				return nil, fmt.Errorf("done status %q can't pause SLA", status)
+			}
+			pause = append(pause, status)
+		}
+		policies = append(policies, Policy{
+			ProjectID:            projectID,
+			Priority:             priority,
+			ResolveWithinMinutes: r.ResolveWithinMinutes,
+			PauseStatuses:        pause,
+		})
+	}
+
+	if err := s.repo.ReplacePolicies(ctx, projectID, policies); err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+// GetTicketSLA returns SLA status of ticket
+func (s *Service) GetTicketSLA(ctx context.Context, ticketID, userID int64) (*TicketSLA, error) {
+	t, err := s.ticketService.GetTicketByID(ctx, ticketID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	policies, err := s.repo.ListPolicies(ctx, t.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	policy := policyFor(policies, t.Priority)
+	if policy == nil {
+		return nil, ErrNoPolicy
+	}
+
+	changes, err := s.repo.ListStatusChanges(ctx, []int64{t.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	result := Evaluate(t, policy, changes, s.now())
+	return &result, nil
+}
+
+func policyFor(policies []Policy, priority string) *Policy {
+	for i := range policies {
+		if strings.EqualFold(policies[i].Priority, strings.TrimSpace(priority)) {
+			return &policies[i]
+		}
+	}
+	return nil
+}
+
+// evaluateAll computes SLA of tickets covered by policies
+func (s *Service) evaluateAll(ctx context.Context, tickets []ticket.Ticket, policies map[int64][]Policy) ([]TicketSLA, error) {
+	ids := make([]int64, 0, len(tickets))
+	for _, t := range tickets {
+		ids = append(ids, t.ID)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	changes, err := s.repo.ListStatusChanges(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byTicket := make(map[int64][]ticket.Change)
+	for _, c := range changes {
+		byTicket[c.TicketID] = append(byTicket[c.TicketID], c)
+	}
+
+	now := s.now()
+	var result []TicketSLA
+	for i := range tickets {
+		t := &tickets[i]
+		policy := policyFor(policies[t.ProjectID], t.Priority)
+		if policy == nil {
+			continue
+		}
+		result = append(result, Evaluate(t, policy, byTicket[t.ID], now))
+	}
+	return result, nil
+}
+
+// GetReport returns SLA outcome of open tickets and tickets resolved between from and to (inclusive dates)
+func (s *Service) GetReport(ctx context.Context, projectID, userID int64, from, to time.Time) (*Report, error) {
+	if to.Before(from) {
+		return nil, errors.New("'to' date must not be before 'from'")
+	}
+
+	// check access
+	_, err := s.projectService.GetProjectByID(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	policies, err := s.repo.ListPolicies(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	tickets, err := s.repo.ListTickets(ctx, projectID, from)
+	if err != nil {
+		return nil, err
+	}
+	// resolved after range end are out of report
+	until := to.AddDate(0, 0, 1)
+	inRange := tickets[:0]
+	for _, t := range tickets {
+		if t.ResolvedAt == nil || t.ResolvedAt.Before(until) {
+			inRange = append(inRange, t)
+		}
+	}
+
+	evaluated, err := s.evaluateAll(ctx, inRange, map[int64][]Policy{projectID: policies})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		From:       from.Format(time.DateOnly),
+		To:         to.Format(time.DateOnly),
+		Priorities: []PrioritySummary{},
+		Tickets:    []TicketSLA{},
+	}
+	byPriority := make(map[string]*PrioritySummary)
+	for _, p := range policies {
+		report.Priorities = append(report.Priorities, PrioritySummary{Priority: p.Priority})
+	}
+	for i := range report.Priorities {
+		byPriority[report.Priorities[i].Priority] = &report.Priorities[i]
+	}
+
+	for _, e := range evaluated {
+		summary := byPriority[e.Priority]
+		switch e.State {
+		case StateMet:
+			report.Met++
+			summary.Met++
+		case StateBreached:
+			report.Breached++
+			summary.Breached++
+		case StateRunning:
+			report.Running++
+			summary.Running++
+		case StatePaused:
+			report.Paused++
+			summary.Paused++
+		}
+		if e.State != StateMet {
+			report.Tickets = append(report.Tickets, e)
+		}
+	}
+
+	report.Compliance = compliance(report.Met, report.Breached)
+	for i := range report.Priorities {
+		report.Priorities[i].Compliance = compliance(report.Priorities[i].Met, report.Priorities[i].Breached)
+	}
+
+	// breached first, then by time left
+	sort.SliceStable(report.Tickets, func(i, j int) bool {
+		return report.Tickets[i].RemainingMinutes < report.Tickets[j].RemainingMinutes
+	})
+	return report, nil
+}
+
+// compliance is percent of finished tickets resolved in time, 100 when nothing is finished
+func compliance(met, breached int) float64 {
+	if met+breached == 0 {
+		return 100
+	}
+	return math.Round(float64(met)/float64(met+breached)*1000) / 10
+}
+
+// SweepBreaches flags open tickets which exceeded SLA and clears flags which no longer apply
+// (e.g. after priority change or policy removal). Returns number of updated tickets
+func (s *Service) SweepBreaches(ctx context.Context) (int, error) {
+	tickets, err := s.repo.ListTicketsToSweep(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	policies := make(map[int64][]Policy)
+	for _, t := range tickets {
+		if _, ok := policies[t.ProjectID]; ok {
+			continue
+		}
+		list, err := s.repo.ListPolicies(ctx, t.ProjectID)
+		if err != nil {
+			return 0, err
+		}
+		policies[t.ProjectID] = list
+	}
+
+	evaluated, err := s.evaluateAll(ctx, tickets, policies)
+	if err != nil {
+		return 0, err
+	}
+	flagged := make(map[int64]*time.Time, len(tickets))
+	for _, t := range tickets {
+		flagged[t.ID] = t.SLABreachedAt
+	}
+
+	updated := 0
+	for _, e := range evaluated {
+		current := flagged[e.TicketID]
+		delete(flagged, e.TicketID)
+		switch {
+		case e.State == StateBreached && current == nil:
+			err = s.repo.SetBreachedAt(ctx, e.TicketID, e.BreachAt)
+		case e.State != StateBreached && current != nil:
+			err = s.repo.SetBreachedAt(ctx, e.TicketID, nil)
+		default:
+			continue
+		}
+		if err != nil {
+			return updated, err
+		}
+		updated++
+	}
+
+	// tickets left without policy aren't evaluated, their flags are cleared
+	for _, t := range tickets {
+		if current, ok := flagged[t.ID]; !ok || current == nil {
+			continue
+		}
+		if err := s.repo.SetBreachedAt(ctx, t.ID, nil); err != nil {
+			return updated, err
+		}
+		updated++
+	}
+	return updated, nil
+}
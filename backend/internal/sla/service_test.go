@@ -0,0 +1,172 @@
+package sla
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/project"
+	"github.com/antonovs105/project-management-system-go/internal/ticket"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+var base = time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+
+func hours(h int) time.Time {
+	return base.Add(time.Duration(h) * time.Hour)
+}
+
+// criticalPolicy gives 24h, clock stops while waiting for customer
+var criticalPolicy = Policy{Priority: "critical", ResolveWithinMinutes: 24 * 60, PauseStatuses: pq.StringArray{"waiting"}}
+
+func TestEvaluate(t *testing.T) {
+	t.Run("Running", func(t *testing.T) {
+		tk := &ticket.Ticket{ID: 1, Status: "open", CreatedAt: base}
+
+		result := Evaluate(tk, &criticalPolicy, nil, hours(10))
+
+		assert.Equal(t, StateRunning, result.State)
+		assert.Equal(t, int64(10*60), result.ElapsedMinutes)
+		assert.Equal(t, int64(14*60), result.RemainingMinutes)
+		assert.Equal(t, hours(24), *result.BreachAt)
+	})
+
+	t.Run("PausedTimeNotCounted", func(t *testing.T) {
+		tk := &ticket.Ticket{ID: 1, Status: "in_progress", CreatedAt: base}
+		changes := []ticket.Change{
+			{Field: ticket.FieldStatus, NewValue: ptr("new"), ChangedAt: base},
+			{Field: ticket.FieldStatus, OldValue: ptr("new"), NewValue: ptr("waiting"), ChangedAt: hours(2)},
+			{Field: ticket.FieldStatus, OldValue: ptr("waiting"), NewValue: ptr("in_progress"), ChangedAt: hours(30)},
+		}
+
+		result := Evaluate(tk, &criticalPolicy, changes, hours(32))
+
+		assert.Equal(t, StateRunning, result.State)
+		assert.Equal(t, int64(4*60), result.ElapsedMinutes)
+		assert.Equal(t, hours(52), *result.BreachAt)
+	})
+
+	t.Run("CurrentlyPaused", func(t *testing.T) {
+		tk := &ticket.Ticket{ID: 1, Status: "waiting", CreatedAt: base}
+		changes := []ticket.Change{
+			{Field: ticket.FieldStatus, OldValue: ptr("new"), NewValue: ptr("waiting"), ChangedAt: hours(1)},
+		}
+
+		result := Evaluate(tk, &criticalPolicy, changes, hours(100))
+
+		assert.Equal(t, StatePaused, result.State)
+		assert.Equal(t, int64(60), result.ElapsedMinutes)
+		assert.Nil(t, result.BreachAt)
+	})
+
+	t.Run("BreachedThenResolved", func(t *testing.T) {
+		tk := &ticket.Ticket{ID: 1, Status: "done", CreatedAt: base, ResolvedAt: ptr(hours(30))}
+		changes := []ticket.Change{
+			{Field: ticket.FieldStatus, OldValue: ptr("new"), NewValue: ptr("done"), ChangedAt: hours(30)},
+		}
+
+		result := Evaluate(tk, &criticalPolicy, changes, hours(50))
+
+		assert.Equal(t, StateBreached, result.State)
+		assert.Equal(t, int64(30*60), result.ElapsedMinutes)
+		assert.Equal(t, hours(24), *result.BreachAt)
+	})
+
+	t.Run("Met", func(t *testing.T) {
+		tk := &ticket.Ticket{ID: 1, Status: "done", CreatedAt: base, ResolvedAt: ptr(hours(5))}
+
+		result := Evaluate(tk, &criticalPolicy, nil, hours(50))
+
+		assert.Equal(t, StateMet, result.State)
+		assert.Nil(t, result.BreachAt)
+	})
+}
+
+func TestService_UpdatePolicies(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockProject := new(MockProjectChecker)
+	service := NewService(mockRepo, mockProject, new(MockTicketGetter))
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		mockProject.On("GetManagedProject", ctx, int64(10), int64(1)).Return(&project.Project{}, nil).Once()
+		mockRepo.On("ReplacePolicies", ctx, int64(10), mock.MatchedBy(func(p []Policy) bool {
+			return len(p) == 1 && p[0].Priority == "critical"
+		})).Return(nil).Once()
+
+		policies, err := service.UpdatePolicies(ctx, 10, 1, []PolicyRequest{
+			{Priority: " Critical ", ResolveWithinMinutes: 1440, PauseStatuses: []string{"waiting"}},
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, policies, 1)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Duplicate", func(t *testing.T) {
+		mockProject.On("GetManagedProject", ctx, int64(10), int64(1)).Return(&project.Project{}, nil).Once()
+
+		_, err := service.UpdatePolicies(ctx, 10, 1, []PolicyRequest{
+			{Priority: "high", ResolveWithinMinutes: 60},
+			{Priority: "HIGH", ResolveWithinMinutes: 120},
+		})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("DoneStatusCantPause", func(t *testing.T) {
+		mockProject.On("GetManagedProject", ctx, int64(10), int64(1)).Return(&project.Project{}, nil).Once()
+
+		_, err := service.UpdatePolicies(ctx, 10, 1, []PolicyRequest{
+			{Priority: "high", ResolveWithinMinutes: 60, PauseStatuses: []string{"done"}},
+		})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Forbidden", func(t *testing.T) {
+		mockProject.On("GetManagedProject", ctx, int64(10), int64(2)).Return(nil, project.ErrForbidden).Once()
+
+		_, err := service.UpdatePolicies(ctx, 10, 2, []PolicyRequest{{Priority: "high", ResolveWithinMinutes: 60}})
+
+		assert.ErrorIs(t, err, project.ErrForbidden)
+	})
+}
+
+func TestService_SweepBreaches(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewService(mockRepo, new(MockProjectChecker), new(MockTicketGetter))
+	service.now = func() time.Time { return hours(30) }
+	ctx := context.Background()
+
+	tickets := []ticket.Ticket{
+		// breached, not flagged yet
+		{ID: 1, ProjectID: 10, Priority: "critical", Status: "open", CreatedAt: base},
+		// flagged, but priority was lowered since
+		{ID: 2, ProjectID: 10, Priority: "low", Status: "open", CreatedAt: base, SLABreachedAt: ptr(hours(24))},
+		// within target, priority entered in other case
+		{ID: 3, ProjectID: 10, Priority: "Critical", Status: "open", CreatedAt: hours(20)},
+		// flagged, but its priority has no policy anymore
+		{ID: 4, ProjectID: 10, Priority: "medium", Status: "open", CreatedAt: base, SLABreachedAt: ptr(hours(24))},
+	}
+	policies := []Policy{criticalPolicy, {Priority: "low", ResolveWithinMinutes: 7 * 24 * 60}}
+
+	mockRepo.On("ListTicketsToSweep", ctx).Return(tickets, nil).Once()
+	mockRepo.On("ListPolicies", ctx, int64(10)).Return(policies, nil).Once()
+	mockRepo.On("ListStatusChanges", ctx, []int64{1, 2, 3, 4}).Return([]ticket.Change{}, nil).Once()
+	mockRepo.On("SetBreachedAt", ctx, int64(1), ptr(hours(24))).Return(nil).Once()
+	mockRepo.On("SetBreachedAt", ctx, int64(2), (*time.Time)(nil)).Return(nil).Once()
+	mockRepo.On("SetBreachedAt", ctx, int64(4), (*time.Time)(nil)).Return(nil).Once()
+
+	updated, err := service.SweepBreaches(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, updated)
+	mockRepo.AssertExpectations(t)
+}
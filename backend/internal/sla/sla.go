@@ -0,0 +1,65 @@
+package sla
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Policy is resolution target for tickets of one priority
+type Policy struct {
+	ID                   int64  `db:"id" json:"id"`
+	ProjectID            int64  `db:"project_id" json:"project_id"`
+	Priority             string `db:"priority" json:"priority"`
+	ResolveWithinMinutes int64  `db:"resolve_within_minutes" json:"resolve_within_minutes"`
+	// PauseStatuses stop the clock, e.g. waiting for customer
+	PauseStatuses pq.StringArray `db:"pause_statuses" json:"pause_statuses"`
+	CreatedAt     time.Time      `db:"created_at" json:"created_at"`
+}
+
+// SLA states of ticket
+const (
+	StateRunning  = "running"
+	StatePaused   = "paused"
+	StateMet      = "met"
+	StateBreached = "breached"
+)
+
+// TicketSLA is SLA status of one ticket
+type TicketSLA struct {
+	TicketID         int64  `json:"ticket_id"`
+	Key              string `json:"key"`
+	Title            string `json:"title"`
+	Priority         string `json:"priority"`
+	Status           string `json:"status"`
+	State            string `json:"state"`
+	TargetMinutes    int64  `json:"target_minutes"`
+	ElapsedMinutes   int64  `json:"elapsed_minutes"`
+	RemainingMinutes int64  `json:"remaining_minutes"`
+	// BreachAt is when target was or will be exceeded, nil while paused or after it was met
+	BreachAt *time.Time `json:"breach_at"`
+}
+
+// PrioritySummary is SLA outcome of tickets with one priority
+type PrioritySummary struct {
+	Priority   string  `json:"priority"`
+	Met        int     `json:"met"`
+	Breached   int     `json:"breached"`
+	Running    int     `json:"running"`
+	Paused     int     `json:"paused"`
+	Compliance float64 `json:"compliance"`
+}
+
+// Report is SLA summary of project: open tickets and tickets resolved in range
+type Report struct {
+	From       string            `json:"from"`
+	To         string            `json:"to"`
+	Met        int               `json:"met"`
+	Breached   int               `json:"breached"`
+	Running    int               `json:"running"`
+	Paused     int               `json:"paused"`
+	Compliance float64           `json:"compliance"`
+	Priorities []PrioritySummary `json:"priorities"`
+	// Tickets lists breached and still open tickets, closest to breach first
+	Tickets []TicketSLA `json:"tickets"`
+}
@@ -0,0 +1,215 @@
+package sprint
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// Create handler for POST /api/projects/:id/sprints
+func (h *Handler) Create(c echo.Context) error {
+	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid project ID"})
+	}
+
+	var req CreateSprintRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+	}
+
+	userID := c.Get("userID").(int64)
+
+	sp, err := h.service.CreateSprint(c.Request().Context(), req, projectID, userID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusCreated, sp)
+}
+
+// List handler for GET /api/projects/:id/sprints
+func (h *Handler) List(c echo.Context) error {
+	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid project ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	sprints, err := h.service.ListSprints(c.Request().Context(), projectID, userID)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, sprints)
+}
+
+// Get handler for GET /api/sprints/:id
+func (h *Handler) Get(c echo.Context) error {
+	sprintID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid sprint ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	sp, err := h.service.GetSprintByID(c.Request().Context(), sprintID, userID)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, sp)
+}
+
+// Update handler for PATCH /api/sprints/:id
+func (h *Handler) Update(c echo.Context) error {
+	sprintID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid sprint ID"})
+	}
+
+	var req UpdateSprintRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+	}
+
+	userID := c.Get("userID").(int64)
+
+	sp, err := h.service.UpdateSprint(c.Request().Context(), req, sprintID, userID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, sp)
+}
+
+// Delete handler for DELETE /api/sprints/:id
+func (h *Handler) Delete(c echo.Context) error {
+	sprintID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid sprint ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	err = h.service.DeleteSprint(c.Request().Context(), sprintID, userID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+type ticketIDsRequest struct {
+	TicketIDs []int64 `json:"ticket_ids"`
+}
+
+// AddTickets handler for POST /api/sprints/:id/tickets
+func (h *Handler) AddTickets(c echo.Context) error {
+	sprintID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid sprint ID"})
+	}
+
+	var req ticketIDsRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+	}
+
+	userID := c.Get("userID").(int64)
+
+	err = h.service.AssignTickets(c.Request().Context(), sprintID, userID, req.TicketIDs)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Backlog handler for GET /api/projects/:id/backlog
+func (h *Handler) Backlog(c echo.Context) error {
+	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid project ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	tickets, err := h.service.ListBacklog(c.Request().Context(), projectID, userID)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, tickets)
+}
+
+// MoveToBacklog handler for POST /api/projects/:id/backlog
+func (h *Handler) MoveToBacklog(c echo.Context) error {
+	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid project ID"})
+	}
+
+	var req ticketIDsRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+	}
+
+	userID := c.Get("userID").(int64)
+
+	err = h.service.MoveToBacklog(c.Request().Context(), projectID, userID, req.TicketIDs)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Start handler for POST /api/sprints/:id/start
+func (h *Handler) Start(c echo.Context) error {
+	sprintID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid sprint ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	sp, err := h.service.StartSprint(c.Request().Context(), sprintID, userID)
+	if err != nil {
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, sp)
+}
+
+// Complete handler for POST /api/sprints/:id/complete
+func (h *Handler) Complete(c echo.Context) error {
+	sprintID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid sprint ID"})
+	}
+
+	var req CompleteSprintRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+	}
+
+	userID := c.Get("userID").(int64)
+
+	result, err := h.service.CompleteSprint(c.Request().Context(), req, sprintID, userID)
+	if err != nil {
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// Board handler for GET /api/projects/:id/board
+func (h *Handler) Board(c echo.Context) error {
+	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid project ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	board, err := h.service.GetActiveBoard(c.Request().Context(), projectID, userID)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, board)
+}
@@ -0,0 +1,228 @@
+package sprint
+
+import (
+	"context"
+	"errors"
+
+	"github.com/antonovs105/project-management-system-go/internal/ticket"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type Repository interface {
+	Create(ctx context.Context, sprint *Sprint) error
+	GetByID(ctx context.Context, id int64) (*Sprint, error)
+	ListByProjectID(ctx context.Context, projectID int64) ([]Sprint, error)
+	GetActive(ctx context.Context, projectID int64) (*Sprint, error)
+	Update(ctx context.Context, sprint *Sprint) error
+	Delete(ctx context.Context, id int64) error
+	AssignTickets(ctx context.Context, projectID int64, sprintID *int64, ticketIDs []int64, changedBy int64) error
+	ListTickets(ctx context.Context, sprintID int64) ([]ticket.Ticket, error)
+	ListBacklog(ctx context.Context, projectID int64) ([]ticket.Ticket, error)
+	Complete(ctx context.Context, sprint *Sprint, unfinishedIDs []int64, moveTo *int64, changedBy int64) error
+}
+
+type PgRepository struct {
+	db *sqlx.DB
+}
+
+func NewRepository(db *sqlx.DB) Repository {
+	return &PgRepository{db: db}
+}
+
+// Create makes new sprint in DB
+func (r *PgRepository) Create(ctx context.Context, sprint *Sprint) error {
+	query := `
+		INSERT INTO sprints (project_id, name, goal, state, start_date, end_date)
+		VALUES (:project_id, :name, :goal, :state, :start_date, :end_date)
+		RETURNING *`
+
+	rows, err := r.db.NamedQueryContext(ctx, query, sprint)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		return rows.StructScan(sprint)
+	}
+	return errors.New("sprint creation failed: no returning row")
+}
+
+// GetByID finds sprint by its id
+func (r *PgRepository) GetByID(ctx context.Context, id int64) (*Sprint, error) {
+	var s Sprint
+	query := `SELECT * FROM sprints WHERE id = $1`
+	err := r.db.GetContext(ctx, &s, query, id)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// ListByProjectID returns sprints of project, newest first
+func (r *PgRepository) ListByProjectID(ctx context.Context, projectID int64) ([]Sprint, error) {
+	var sprints []Sprint
+	query := `SELECT * FROM sprints WHERE project_id = $1 ORDER BY created_at DESC`
+
+	err := r.db.SelectContext(ctx, &sprints, query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return sprints, nil
+}
+
+// GetActive returns active sprint of project
+func (r *PgRepository) GetActive(ctx context.Context, projectID int64) (*Sprint, error) {
+	var s Sprint
+	query := `SELECT * FROM sprints WHERE project_id = $1 AND state = 'active'`
+	err := r.db.GetContext(ctx, &s, query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Update saves sprint fields
+func (r *PgRepository) Update(ctx context.Context, sprint *Sprint) error {
+	query := `
+		UPDATE sprints
+		SET
+			name = :name,
+			goal = :goal,
+			state = :state,
+			start_date = :start_date,
+			end_date = :end_date,
+			completed_at = :completed_at,
+			updated_at = now()
+		WHERE id = :id`
+
+	result, err := r.db.NamedExecContext(ctx, query, sprint)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("sprint to update not found")
+	}
+	return nil
+}
+
+// Delete removes sprint, its tickets go back to backlog
+func (r *PgRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM sprints WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("sprint to delete not found")
+	}
+	return nil
+}
+
+// AssignTickets moves tickets of project to sprint, nil sprint means backlog
+func (r *PgRepository) AssignTickets(ctx context.Context, projectID int64, sprintID *int64, ticketIDs []int64, changedBy int64) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// change log first, while old sprint is still known
+	if err := logSprintChanges(ctx, tx, sprintID, ticketIDs, changedBy); err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE tickets SET sprint_id = $1, updated_at = now()
+		WHERE project_id = $2 AND id = ANY($3)`
+
+	result, err := tx.ExecContext(ctx, query, sprintID, projectID, pq.Array(ticketIDs))
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected != int64(len(ticketIDs)) {
+		return errors.New("some tickets not found in project")
+	}
+	return tx.Commit()
+}
+
+// logSprintChanges writes sprint_id change log for tickets which really move
+func logSprintChanges(ctx context.Context, tx *sqlx.Tx, sprintID *int64, ticketIDs []int64, changedBy int64) error {
+	query := `
+		INSERT INTO ticket_changes (ticket_id, field, old_value, new_value, changed_by)
+		SELECT id, $1, sprint_id::text, $2::bigint::text, $4
+		FROM tickets
+		WHERE id = ANY($3) AND sprint_id IS DISTINCT FROM $2::bigint`
+	_, err := tx.ExecContext(ctx, query, ticket.FieldSprintID, sprintID, pq.Array(ticketIDs), changedBy)
+	return err
+}
+
+// ListTickets returns tickets of sprint
+func (r *PgRepository) ListTickets(ctx context.Context, sprintID int64) ([]ticket.Ticket, error) {
+	var tickets []ticket.Ticket
+	query := `SELECT * FROM tickets WHERE sprint_id = $1 ORDER BY created_at`
+
+	err := r.db.SelectContext(ctx, &tickets, query, sprintID)
+	if err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
+// ListBacklog returns tickets of project not planned into any sprint
+func (r *PgRepository) ListBacklog(ctx context.Context, projectID int64) ([]ticket.Ticket, error) {
+	var tickets []ticket.Ticket
+	query := `SELECT * FROM tickets WHERE project_id = $1 AND sprint_id IS NULL ORDER BY created_at`
+
+	err := r.db.SelectContext(ctx, &tickets, query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
+// Complete closes sprint and moves unfinished tickets in one transaction
+func (r *PgRepository) Complete(ctx context.Context, sprint *Sprint, unfinishedIDs []int64, moveTo *int64, changedBy int64) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if len(unfinishedIDs) > 0 {
+		if err := logSprintChanges(ctx, tx, moveTo, unfinishedIDs, changedBy); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx,
+			`UPDATE tickets SET sprint_id = $1, updated_at = now() WHERE id = ANY($2)`,
+			moveTo, pq.Array(unfinishedIDs))
+		if err != nil {
+			return err
+		}
+	}
+
+	query := `
+		UPDATE sprints
+		SET state = :state, completed_at = :completed_at, updated_at = now()
+		WHERE id = :id`
+	if _, err := tx.NamedExecContext(ctx, query, sprint); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,92 @@
+package sprint
+
+import (
+	"context"
+
+	"github.com/antonovs105/project-management-system-go/internal/project"
+	"github.com/antonovs105/project-management-system-go/internal/ticket"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRepository is a mock implementation of Repository
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Create(ctx context.Context, sprint *Sprint) error {
+	args := m.Called(ctx, sprint)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetByID(ctx context.Context, id int64) (*Sprint, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Sprint), args.Error(1)
+}
+
+func (m *MockRepository) ListByProjectID(ctx context.Context, projectID int64) ([]Sprint, error) {
+	args := m.Called(ctx, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Sprint), args.Error(1)
+}
+
+func (m *MockRepository) GetActive(ctx context.Context, projectID int64) (*Sprint, error) {
+	args := m.Called(ctx, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Sprint), args.Error(1)
+}
+
+func (m *MockRepository) Update(ctx context.Context, sprint *Sprint) error {
+	args := m.Called(ctx, sprint)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Delete(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRepository) AssignTickets(ctx context.Context, projectID int64, sprintID *int64, ticketIDs []int64, changedBy int64) error {
+	args := m.Called(ctx, projectID, sprintID, ticketIDs, changedBy)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ListTickets(ctx context.Context, sprintID int64) ([]ticket.Ticket, error) {
+	args := m.Called(ctx, sprintID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]ticket.Ticket), args.Error(1)
+}
+
+func (m *MockRepository) ListBacklog(ctx context.Context, projectID int64) ([]ticket.Ticket, error) {
+	args := m.Called(ctx, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]ticket.Ticket), args.Error(1)
+}
+
+func (m *MockRepository) Complete(ctx context.Context, sprint *Sprint, unfinishedIDs []int64, moveTo *int64, changedBy int64) error {
+	args := m.Called(ctx, sprint, unfinishedIDs, moveTo, changedBy)
+	return args.Error(0)
+}
+
+// MockProjectChecker
+type MockProjectChecker struct {
+	mock.Mock
+}
+
+func (m *MockProjectChecker) GetProjectByID(ctx context.Context, projectID, userID int64) (*project.Project, error) {
+	args := m.Called(ctx, projectID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*project.Project), args.Error(1)
+}
@@ -0,0 +1,424 @@
+package sprint
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/project"
+	"github.com/antonovs105/project-management-system-go/internal/ticket"
+)
+
+// defaultSprintLength is used when sprint starts without end date
+const defaultSprintLength = 14 * 24 * time.Hour
+
+// ProjectChecker interface
+type ProjectChecker interface {
+	GetProjectByID(ctx context.Context, projectID, userID int64) (*project.Project, error)
+}
+
+type Service struct {
+	repo           Repository
+	projectService ProjectChecker
+}
+
+func NewService(repo Repository, projectService ProjectChecker) *Service {
+	return &Service{
+		repo:           repo,
+		projectService: projectService,
+	}
+}
+
+// CreateSprintRequest DTO for sprint creation
+type CreateSprintRequest struct {
+	Name      string     `json:"name"`
+	Goal      string     `json:"goal"`
+	StartDate *time.Time `json:"start_date"`
+	EndDate   *time.Time `json:"end_date"`
+}
+
+// CreateSprint logic for planning new sprint
+func (s *Service) CreateSprint(ctx context.Context, req CreateSprintRequest, projectID, userID int64) (*Sprint, error) {
+	// check access
+	_, err := s.projectService.GetProjectByID(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, errors.New("sprint name is required")
+	}
+	if err := validateDates(req.StartDate, req.EndDate); err != nil {
+		return nil, err
+	}
+
+	sp := &Sprint{
+		ProjectID: projectID,
+		Name:      req.Name,
+		Goal:      req.Goal,
+		State:     StatePlanned,
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+	}
+
+	if err := s.repo.Create(ctx, sp); err != nil {
+		return nil, err
+	}
+	return sp, nil
+}
+
+// ListSprints returns all sprints of project
+func (s *Service) ListSprints(ctx context.Context, projectID, userID int64) ([]Sprint, error) {
+	// check access
+	_, err := s.projectService.GetProjectByID(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListByProjectID(ctx, projectID)
+}
+
+// GetSprintByID logic to get single sprint
+func (s *Service) GetSprintByID(ctx context.Context, sprintID, userID int64) (*Sprint, error) {
+	sp, err := s.repo.GetByID(ctx, sprintID)
+	if err != nil {
+		return nil, errors.New("sprint not found")
+	}
+
+	// check access
+	_, err = s.projectService.GetProjectByID(ctx, sp.ProjectID, userID)
+	if err != nil {
+		return nil, errors.New("sprint not found or access denied")
+	}
+
+	return sp, nil
+}
+
+// UpdateSprintRequest DTO for updating sprint
+type UpdateSprintRequest struct {
+	Name      *string    `json:"name"`
+	Goal      *string    `json:"goal"`
+	StartDate *time.Time `json:"start_date"`
+	EndDate   *time.Time `json:"end_date"`
+}
+
+// UpdateSprint logic for update, closed sprints are read only
+func (s *Service) UpdateSprint(ctx context.Context, req UpdateSprintRequest, sprintID, userID int64) (*Sprint, error) {
+	sp, err := s.GetSprintByID(ctx, sprintID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if sp.State == StateClosed {
+		return nil, errors.New("closed sprint can't be changed")
+	}
+
+	if req.Name != nil {
+		if strings.TrimSpace(*req.Name) == "" {
+			return nil, errors.New("sprint name is required")
+		}
+		sp.Name = *req.Name
+	}
+	if req.Goal != nil {
+		sp.Goal = *req.Goal
+	}
+	if req.StartDate != nil {
+		sp.StartDate = req.StartDate
+	}
+	if req.EndDate != nil {
+		sp.EndDate = req.EndDate
+	}
+	if err := validateDates(sp.StartDate, sp.EndDate); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, sp); err != nil {
+		return nil, err
+	}
+	return sp, nil
+}
+
+// DeleteSprint removes planned sprint, its tickets go back to backlog
+func (s *Service) DeleteSprint(ctx context.Context, sprintID, userID int64) error {
+	sp, err := s.GetSprintByID(ctx, sprintID, userID)
+	if err != nil {
+		return err
+	}
+	if sp.State != StatePlanned {
+		return errors.New("only planned sprint can be deleted")
+	}
+
+	return s.repo.Delete(ctx, sprintID)
+}
+
+// AssignTickets plans tickets into sprint
+func (s *Service) AssignTickets(ctx context.Context, sprintID, userID int64, ticketIDs []int64) error {
+	sp, err := s.GetSprintByID(ctx, sprintID, userID)
+	if err != nil {
+		return err
+	}
+	if sp.State == StateClosed {
+		return errors.New("can't add tickets to closed sprint")
+	}
+	if err := validateTicketIDs(ticketIDs); err != nil {
+		return err
+	}
+
+	return s.repo.AssignTickets(ctx, sp.ProjectID, &sp.ID, ticketIDs, userID)
+}
+
+// MoveToBacklog takes tickets out of their sprints
+func (s *Service) MoveToBacklog(ctx context.Context, projectID, userID int64, ticketIDs []int64) error {
+	// check access
+	_, err := s.projectService.GetProjectByID(ctx, projectID, userID)
+	if err != nil {
+		return err
+	}
+	if err := validateTicketIDs(ticketIDs); err != nil {
+		return err
+	}
+
+	return s.repo.AssignTickets(ctx, projectID, nil, ticketIDs, userID)
+}
+
+// validateTicketIDs checks ticket_ids of request is not empty and has no repeats,
+// repository counts updated rows to find tickets from other projects
+func validateTicketIDs(ticketIDs []int64) error {
+	if len(ticketIDs) == 0 {
+		return errors.New("ticket_ids is empty")
+	}
+	seen := make(map[int64]bool, len(ticketIDs))
+	for _, id := range ticketIDs {
+		if seen[id] {
+			return fmt.Errorf("ticket_ids contains ticket %d more than once", id)
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// ListBacklog returns tickets not planned into any sprint
+func (s *Service) ListBacklog(ctx context.Context, projectID, userID int64) ([]ticket.Ticket, error) {
+	// check access
+	_, err := s.projectService.GetProjectByID(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListBacklog(ctx, projectID)
+}
+
+// StartSprint makes planned sprint active, only one sprint per project can be active
+func (s *Service) StartSprint(ctx context.Context, sprintID, userID int64) (*Sprint, error) {
+	sp, err := s.GetSprintByID(ctx, sprintID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if sp.State != StatePlanned {
+		return nil, errors.New("only planned sprint can be started")
+	}
+
+	active, err := s.repo.GetActive(ctx, sp.ProjectID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if active != nil {
+		return nil, errors.New("project already has active sprint")
+	}
+
+	now := time.Now()
+	if sp.StartDate == nil {
+		sp.StartDate = &now
+	}
+	if sp.EndDate == nil {
+		end := sp.StartDate.Add(defaultSprintLength)
+		sp.EndDate = &end
+	}
+	if err := validateDates(sp.StartDate, sp.EndDate); err != nil {
+		return nil, err
+	}
+	sp.State = StateActive
+
+	// unique index guards against concurrent starts
+	if err := s.repo.Update(ctx, sp); err != nil {
+		return nil, err
+	}
+	return sp, nil
+}
+
+// CompleteSprintRequest DTO for sprint completion.
+// Unfinished tickets go to MoveToSprintID, to next planned sprint if MoveToNext, or to backlog
+type CompleteSprintRequest struct {
+	MoveToSprintID *int64 `json:"move_to_sprint_id"`
+	MoveToNext     bool   `json:"move_to_next"`
+}
+
+// CompleteSprintResult tells what happened to sprint tickets
+type CompleteSprintResult struct {
+	Sprint         *Sprint `json:"sprint"`
+	Completed      int     `json:"completed"`
+	CarriedOver    int     `json:"carried_over"`
+	MovedToSprint  *int64  `json:"moved_to_sprint_id"`
+	MovedToBacklog bool    `json:"moved_to_backlog"`
+}
+
+// CompleteSprint closes active sprint and carries over unfinished tickets
+func (s *Service) CompleteSprint(ctx context.Context, req CompleteSprintRequest, sprintID, userID int64) (*CompleteSprintResult, error) {
+	sp, err := s.GetSprintByID(ctx, sprintID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if sp.State != StateActive {
+		return nil, errors.New("only active sprint can be completed")
+	}
+
+	moveTo := req.MoveToSprintID
+	if moveTo == nil && req.MoveToNext {
+		next, err := s.nextPlannedSprint(ctx, sp)
+		if err != nil {
+			return nil, err
+		}
+		if next != nil {
+			moveTo = &next.ID
+		}
+	}
+	if moveTo != nil {
+		target, err := s.repo.GetByID(ctx, *moveTo)
+		if err != nil || target.ProjectID != sp.ProjectID {
+			return nil, errors.New("target sprint not found in project")
+		}
+		if target.State != StatePlanned {
+			return nil, errors.New("unfinished tickets can be moved only to planned sprint")
+		}
+	}
+
+	tickets, err := s.repo.ListTickets(ctx, sp.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CompleteSprintResult{Sprint: sp, MovedToSprint: moveTo, MovedToBacklog: moveTo == nil}
+	var unfinished []int64
+	for _, t := range tickets {
+		if ticket.StatusCategory(t.Status) == ticket.CategoryDone {
+			result.Completed++
+		} else {
+			unfinished = append(unfinished, t.ID)
+		}
+	}
+	result.CarriedOver = len(unfinished)
+
+	now := time.Now()
+	sp.State = StateClosed
+	sp.CompletedAt = &now
+
+	if err := s.repo.Complete(ctx, sp, unfinished, moveTo, userID); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// nextPlannedSprint picks planned sprint starting first, sprints without dates go last by creation
+func (s *Service) nextPlannedSprint(ctx context.Context, current *Sprint) (*Sprint, error) {
+	sprints, err := s.repo.ListByProjectID(ctx, current.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	var planned []Sprint
+	for _, sp := range sprints {
+		if sp.State == StatePlanned && sp.ID != current.ID {
+			planned = append(planned, sp)
+		}
+	}
+	if len(planned) == 0 {
+		return nil, nil
+	}
+
+	sort.SliceStable(planned, func(i, j int) bool {
+		a, b := planned[i], planned[j]
+		switch {
+		case a.StartDate != nil && b.StartDate != nil:
+			return a.StartDate.Before(*b.StartDate)
+		case a.StartDate != nil:
+			return true
+		case b.StartDate != nil:
+			return false
+		default:
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+	})
+	return &planned[0], nil
+}
+
+// BoardColumn is tickets in one status
+type BoardColumn struct {
+	Status   string          `json:"status"`
+	Category string          `json:"category"`
+	Tickets  []ticket.Ticket `json:"tickets"`
+}
+
+// Board is active sprint with tickets grouped by status
+type Board struct {
+	Sprint  *Sprint       `json:"sprint"`
+	Columns []BoardColumn `json:"columns"`
+}
+
+// categoryOrder is left to right order of board columns
+var categoryOrder = map[string]int{
+	ticket.CategoryTodo:       0,
+	ticket.CategoryInProgress: 1,
+	ticket.CategoryDone:       2,
+}
+
+// GetActiveBoard returns board of active sprint
+func (s *Service) GetActiveBoard(ctx context.Context, projectID, userID int64) (*Board, error) {
+	// check access
+	_, err := s.projectService.GetProjectByID(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sp, err := s.repo.GetActive(ctx, projectID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("project has no active sprint")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tickets, err := s.repo.ListTickets(ctx, sp.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	board := &Board{Sprint: sp, Columns: []BoardColumn{}}
+	columns := make(map[string]int)
+	for _, t := range tickets {
+		i, ok := columns[t.Status]
+		if !ok {
+			i = len(board.Columns)
+			columns[t.Status] = i
+			board.Columns = append(board.Columns, BoardColumn{
+				Status:   t.Status,
+				Category: ticket.StatusCategory(t.Status),
+			})
+		}
+		board.Columns[i].Tickets = append(board.Columns[i].Tickets, t)
+	}
+
+	sort.SliceStable(board.Columns, func(i, j int) bool {
+		return categoryOrder[board.Columns[i].Category] < categoryOrder[board.Columns[j].Category]
+	})
+	return board, nil
+}
+
+func validateDates(start, end *time.Time) error {
+	if start != nil && end != nil && !end.After(*start) {
+		return errors.New("sprint end date must be after start date")
+	}
+	return nil
+}
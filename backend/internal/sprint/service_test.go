@@ -0,0 +1,178 @@
+package sprint
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/project"
+	"github.com/antonovs105/project-management-system-go/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestService_StartSprint(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockProject := new(MockProjectChecker)
+	service := NewService(mockRepo, mockProject)
+
+	ctx := context.Background()
+	projectID := int64(10)
+	userID := int64(1)
+
+	t.Run("Success", func(t *testing.T) {
+		sp := &Sprint{ID: 1, ProjectID: projectID, State: StatePlanned}
+		mockRepo.On("GetByID", ctx, int64(1)).Return(sp, nil).Once()
+		mockProject.On("GetProjectByID", ctx, projectID, userID).Return(&project.Project{}, nil).Once()
+		mockRepo.On("GetActive", ctx, projectID).Return(nil, sql.ErrNoRows).Once()
+		mockRepo.On("Update", ctx, sp).Return(nil).Once()
+
+		started, err := service.StartSprint(ctx, 1, userID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, StateActive, started.State)
+		assert.NotNil(t, started.StartDate)
+		assert.Equal(t, defaultSprintLength, started.EndDate.Sub(*started.StartDate))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("AlreadyActive", func(t *testing.T) {
+		sp := &Sprint{ID: 2, ProjectID: projectID, State: StatePlanned}
+		mockRepo.On("GetByID", ctx, int64(2)).Return(sp, nil).Once()
+		mockProject.On("GetProjectByID", ctx, projectID, userID).Return(&project.Project{}, nil).Once()
+		mockRepo.On("GetActive", ctx, projectID).Return(&Sprint{ID: 1, State: StateActive}, nil).Once()
+
+		_, err := service.StartSprint(ctx, 2, userID)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "already has active sprint")
+	})
+
+	t.Run("LookupFails", func(t *testing.T) {
+		sp := &Sprint{ID: 3, ProjectID: projectID, State: StatePlanned}
+		dbErr := errors.New("connection reset")
+		mockRepo.On("GetByID", ctx, int64(3)).Return(sp, nil).Once()
+		mockProject.On("GetProjectByID", ctx, projectID, userID).Return(&project.Project{}, nil).Once()
+		mockRepo.On("GetActive", ctx, projectID).Return(nil, dbErr).Once()
+
+		_, err := service.StartSprint(ctx, 3, userID)
+
+		assert.ErrorIs(t, err, dbErr)
+		mockRepo.AssertNotCalled(t, "Update", ctx, sp)
+	})
+}
+
+func TestService_AssignTickets_Duplicates(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockProject := new(MockProjectChecker)
+	service := NewService(mockRepo, mockProject)
+	ctx := context.Background()
+
+	sp := &Sprint{ID: 1, ProjectID: 10, State: StatePlanned}
+	mockRepo.On("GetByID", ctx, int64(1)).Return(sp, nil).Once()
+	mockProject.On("GetProjectByID", ctx, int64(10), int64(1)).Return(&project.Project{}, nil).Once()
+
+	err := service.AssignTickets(ctx, 1, 1, []int64{5, 6, 5})
+
+	assert.EqualError(t, err, "ticket_ids contains ticket 5 more than once")
+	mockRepo.AssertNotCalled(t, "AssignTickets", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_CompleteSprint(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockProject := new(MockProjectChecker)
+	service := NewService(mockRepo, mockProject)
+
+	ctx := context.Background()
+	projectID := int64(10)
+	userID := int64(1)
+	tickets := []ticket.Ticket{
+		{ID: 100, Status: "done"},
+		{ID: 101, Status: "in_progress"},
+		{ID: 102, Status: "new"},
+	}
+
+	t.Run("ToBacklog", func(t *testing.T) {
+		sp := &Sprint{ID: 1, ProjectID: projectID, State: StateActive}
+		mockRepo.On("GetByID", ctx, int64(1)).Return(sp, nil).Once()
+		mockProject.On("GetProjectByID", ctx, projectID, userID).Return(&project.Project{}, nil).Once()
+		mockRepo.On("ListTickets", ctx, int64(1)).Return(tickets, nil).Once()
+		mockRepo.On("Complete", ctx, sp, []int64{101, 102}, (*int64)(nil), userID).Return(nil).Once()
+
+		result, err := service.CompleteSprint(ctx, CompleteSprintRequest{}, 1, userID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Completed)
+		assert.Equal(t, 2, result.CarriedOver)
+		assert.True(t, result.MovedToBacklog)
+		assert.Equal(t, StateClosed, sp.State)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("ToNextSprint", func(t *testing.T) {
+		sp := &Sprint{ID: 1, ProjectID: projectID, State: StateActive}
+		later := time.Now().Add(48 * time.Hour)
+		sooner := time.Now().Add(24 * time.Hour)
+		sprints := []Sprint{
+			*sp,
+			{ID: 3, ProjectID: projectID, State: StatePlanned, StartDate: &later},
+			{ID: 2, ProjectID: projectID, State: StatePlanned, StartDate: &sooner},
+		}
+		next := &sprints[2]
+
+		mockRepo.On("GetByID", ctx, int64(1)).Return(sp, nil).Once()
+		mockProject.On("GetProjectByID", ctx, projectID, userID).Return(&project.Project{}, nil).Once()
+		mockRepo.On("ListByProjectID", ctx, projectID).Return(sprints, nil).Once()
+		mockRepo.On("GetByID", ctx, int64(2)).Return(next, nil).Once()
+		mockRepo.On("ListTickets", ctx, int64(1)).Return(tickets, nil).Once()
+		mockRepo.On("Complete", ctx, sp, []int64{101, 102}, mock.MatchedBy(func(id *int64) bool {
+			return id != nil && *id == 2
+		}), userID).Return(nil).Once()
+
+		result, err := service.CompleteSprint(ctx, CompleteSprintRequest{MoveToNext: true}, 1, userID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), *result.MovedToSprint)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("NotActive", func(t *testing.T) {
+		sp := &Sprint{ID: 5, ProjectID: projectID, State: StatePlanned}
+		mockRepo.On("GetByID", ctx, int64(5)).Return(sp, nil).Once()
+		mockProject.On("GetProjectByID", ctx, projectID, userID).Return(&project.Project{}, nil).Once()
+
+		_, err := service.CompleteSprint(ctx, CompleteSprintRequest{}, 5, userID)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestService_GetActiveBoard(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockProject := new(MockProjectChecker)
+	service := NewService(mockRepo, mockProject)
+
+	ctx := context.Background()
+	projectID := int64(10)
+	userID := int64(1)
+
+	sp := &Sprint{ID: 1, ProjectID: projectID, State: StateActive}
+	mockProject.On("GetProjectByID", ctx, projectID, userID).Return(&project.Project{}, nil).Once()
+	mockRepo.On("GetActive", ctx, projectID).Return(sp, nil).Once()
+	mockRepo.On("ListTickets", ctx, int64(1)).Return([]ticket.Ticket{
+		{ID: 100, Status: "done"},
+		{ID: 101, Status: "in_progress"},
+		{ID: 102, Status: "new"},
+		{ID: 103, Status: "new"},
+	}, nil).Once()
+
+	board, err := service.GetActiveBoard(ctx, projectID, userID)
+
+	assert.NoError(t, err)
+	assert.Len(t, board.Columns, 3)
+	assert.Equal(t, "new", board.Columns[0].Status)
+	assert.Len(t, board.Columns[0].Tickets, 2)
+	assert.Equal(t, "done", board.Columns[2].Status)
+}
@@ -0,0 +1,23 @@
+package sprint
+
+import "time"
+
+// Sprint states
+const (
+	StatePlanned = "planned"
+	StateActive  = "active"
+	StateClosed  = "closed"
+)
+
+type Sprint struct {
+	ID          int64      `db:"id" json:"id"`
+	ProjectID   int64      `db:"project_id" json:"project_id"`
+	Name        string     `db:"name" json:"name"`
+	Goal        string     `db:"goal" json:"goal"`
+	State       string     `db:"state" json:"state"`
+	StartDate   *time.Time `db:"start_date" json:"start_date"`
+	EndDate     *time.Time `db:"end_date" json:"end_date"`
+	CompletedAt *time.Time `db:"completed_at" json:"completed_at"`
+	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at" json:"updated_at"`
+}
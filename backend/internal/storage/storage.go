@@ -0,0 +1,30 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"time"
+)
+
+var ErrNotFound = errors.New("object not found")
+
+// Storage keeps file contents by key
+type Storage interface {
+	// Put stores size bytes of body under key, replacing old object
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes object, deleting missing object is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// Presigner is storage able to give time-limited download links, so files don't go through backend
+type Presigner interface {
+	PresignGet(key string, expires time.Duration, filename string) (string, error)
+}
+
+// ContentDisposition makes header value that downloads file with filename
+func ContentDisposition(filename string) string {
+	return mime.FormatMediaType("attachment", map[string]string{"filename": filename})
+}
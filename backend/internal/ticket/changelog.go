@@ -0,0 +1,182 @@
+package ticket
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// Fields tracked in ticket change log
+const (
+	FieldTitle             = "title"
+	FieldStatus            = "status"
+	FieldPriority          = "priority"
+	FieldType              = "type"
+	FieldParentID          = "parent_id"
+	FieldAssigneeID        = "assignee_id"
+	FieldSprintID          = "sprint_id"
+	FieldFixVersionID      = "fix_version_id"
+	FieldStoryPoints       = "story_points"
+	FieldOriginalEstimate  = "original_estimate_minutes"
+	FieldRemainingEstimate = "remaining_estimate_minutes"
+	FieldStartDate         = "start_date"
+	FieldDueDate           = "due_date"
+)
+
+// Change is one field change of ticket. Values are stored as text, nil means empty
+type Change struct {
+	ID        int64     `db:"id" json:"id"`
+	TicketID  int64     `db:"ticket_id" json:"ticket_id"`
+	Field     string    `db:"field" json:"field"`
+	OldValue  *string   `db:"old_value" json:"old_value"`
+	NewValue  *string   `db:"new_value" json:"new_value"`
+	ChangedBy *int64    `db:"changed_by" json:"changed_by"`
+	ChangedAt time.Time `db:"changed_at" json:"changed_at"`
+}
+
+// FormatInt encodes nullable id or minutes as change value
+func FormatInt(v *int64) *string {
+	if v == nil {
+		return nil
+	}
+	s := strconv.FormatInt(*v, 10)
+	return &s
+}
+
+// FormatFloat encodes nullable story points as change value
+func FormatFloat(v *float64) *string {
+	if v == nil {
+		return nil
+	}
+	s := strconv.FormatFloat(*v, 'f', -1, 64)
+	return &s
+}
+
+// ParseInt decodes change value, invalid or empty values are nil
+func ParseInt(v *string) *int64 {
+	if v == nil {
+		return nil
+	}
+	n, err := strconv.ParseInt(*v, 10, 64)
+	if err != nil {
+		return nil
+	}
+	return &n
+}
+
+// ParseFloat decodes change value, invalid or empty values are nil
+func ParseFloat(v *string) *float64 {
+	if v == nil {
+		return nil
+	}
+	f, err := strconv.ParseFloat(*v, 64)
+	if err != nil {
+		return nil
+	}
+	return &f
+}
+
+func formatDate(d *Date) *string {
+	if d == nil {
+		return nil
+	}
+	s := d.String()
+	return &s
+}
+
+// ValueOf wraps string field as change value
+func ValueOf(s string) *string {
+	return &s
+}
+
+// trackedValues returns tracked fields of ticket as change values
+func trackedValues(t *Ticket) map[string]*string {
+	return map[string]*string{
+		FieldTitle:             ValueOf(t.Title),
+		FieldStatus:            ValueOf(t.Status),
+		FieldPriority:          ValueOf(t.Priority),
+		FieldType:              ValueOf(t.Type),
+		FieldParentID:          FormatInt(t.ParentID),
+		FieldAssigneeID:        FormatInt(t.AssigneeID),
+		FieldSprintID:          FormatInt(t.SprintID),
+		FieldFixVersionID:      FormatInt(t.FixVersionID),
+		FieldStoryPoints:       FormatFloat(t.StoryPoints),
+		FieldOriginalEstimate:  FormatInt(t.OriginalEstimate),
+		FieldRemainingEstimate: FormatInt(t.RemainingEstimate),
+		FieldStartDate:         formatDate(t.StartDate),
+		FieldDueDate:           formatDate(t.DueDate),
+	}
+}
+
+// trackedFields keeps change order stable
+var trackedFields = []string{
+	FieldTitle, FieldStatus, FieldPriority, FieldType, FieldParentID, FieldAssigneeID,
+	FieldSprintID, FieldFixVersionID, FieldStoryPoints, FieldOriginalEstimate, FieldRemainingEstimate,
+	FieldStartDate, FieldDueDate,
+}
+
+// diffTickets returns changes between old and updated ticket made by userID
+func diffTickets(old, updated *Ticket, userID int64) []Change {
+	before := trackedValues(old)
+	after := trackedValues(updated)
+
+	var changes []Change
+	for _, field := range trackedFields {
+		if equalValues(before[field], after[field]) {
+			continue
+		}
+		changes = append(changes, Change{
+			TicketID:  updated.ID,
+			Field:     field,
+			OldValue:  before[field],
+			NewValue:  after[field],
+			ChangedBy: &userID,
+		})
+	}
+	return changes
+}
+
+// initialChanges records non-empty fields of created ticket, old value is always nil
+func initialChanges(t *Ticket) []Change {
+	values := trackedValues(t)
+
+	var changes []Change
+	for _, field := range trackedFields {
+		if values[field] == nil {
+			continue
+		}
+		reporter := t.ReporterID
+		changes = append(changes, Change{
+			TicketID:  t.ID,
+			Field:     field,
+			NewValue:  values[field],
+			ChangedBy: &reporter,
+		})
+	}
+	return changes
+}
+
+func equalValues(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// GetTicketHistory returns change log of ticket, newest first
+func (s *Service) GetTicketHistory(ctx context.Context, ticketID, userID int64) ([]Change, error) {
+	// check access
+	_, err := s.GetTicketByID(ctx, ticketID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := s.repo.ListChanges(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	if changes == nil {
+		changes = []Change{}
+	}
+	return changes, nil
+}
@@ -0,0 +1,66 @@
+package ticket
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Date is calendar date without time, JSON format is YYYY-MM-DD.
+// Stored as UTC midnight, meaning of the day comes from project timezone
+type Date struct {
+	time.Time
+}
+
+// NewDate truncates moment to its calendar date
+func NewDate(t time.Time) Date {
+	return Date{time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)}
+}
+
+// ParseDate parses YYYY-MM-DD
+func ParseDate(s string) (Date, error) {
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return Date{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", s)
+	}
+	return Date{t}, nil
+}
+
+func (d Date) String() string {
+	return d.Format(time.DateOnly)
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + d.String() + `"`), nil
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	parsed, err := ParseDate(strings.Trim(string(data), `"`))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Value stores date as DATE column
+func (d Date) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// Scan reads DATE column
+func (d *Date) Scan(src any) error {
+	switch v := src.(type) {
+	case time.Time:
+		*d = NewDate(v)
+		return nil
+	case string:
+		parsed, err := ParseDate(v[:min(len(v), 10)])
+		*d = parsed
+		return err
+	case []byte:
+		return d.Scan(string(v))
+	}
+	return fmt.Errorf("can't scan %T into Date", src)
+}
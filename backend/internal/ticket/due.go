@@ -0,0 +1,36 @@
+package ticket
+
+import (
+	"context"
+	"time"
+)
+
+// OverdueTicket is unresolved ticket past its due date
+type OverdueTicket struct {
+	Ticket
+	DaysOverdue int `json:"days_overdue"`
+}
+
+// ListOverdueTickets returns unresolved tickets due before today in project timezone, most overdue first
+func (s *Service) ListOverdueTickets(ctx context.Context, projectID, userID int64) ([]OverdueTicket, error) {
+	// check access
+	p, err := s.projectService.GetProjectByID(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	today := NewDate(p.Today(time.Now()))
+	tickets, err := s.repo.ListDueBefore(ctx, projectID, today)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]OverdueTicket, 0, len(tickets))
+	for _, t := range tickets {
+		result = append(result, OverdueTicket{
+			Ticket:      t,
+			DaysOverdue: int(today.Sub(t.DueDate.Time).Hours() / 24),
+		})
+	}
+	return result, nil
+}
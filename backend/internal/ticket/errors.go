@@ -0,0 +1,10 @@
+package ticket
+
+import "errors"
+
+// Errors handlers map to HTTP statuses
+var (
+	ErrNotFound         = errors.New("ticket not found")
+	ErrInvalidHierarchy = errors.New("invalid hierarchy")
+	ErrHasChildren      = errors.New("ticket has children: delete mode required (cascade, orphan or reparent_to)")
+)
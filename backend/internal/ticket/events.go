@@ -0,0 +1,18 @@
+package ticket
+
+// TicketUpdatedData is payload of ticket.updated event
+type TicketUpdatedData struct {
+	Ticket  *Ticket  `json:"ticket"`
+	Changes []Change `json:"changes"`
+}
+
+// TicketDeletedData is payload of ticket.deleted event
+type TicketDeletedData struct {
+	ID  int64  `json:"id"`
+	Key string `json:"key"`
+	// DeletedIDs are descendants deleted together with ticket in cascade mode
+	DeletedIDs []int64 `json:"deleted_ids,omitempty"`
+	// ReparentedIDs are children moved to NewParentID, nil parent means they became top level
+	ReparentedIDs []int64 `json:"reparented_ids,omitempty"`
+	NewParentID   *int64  `json:"new_parent_id,omitempty"`
+}
@@ -0,0 +1,256 @@
+package ticket
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// Default and max depth for focus mode
+const (
+	defaultGraphDepth = 1
+	maxGraphDepth     = 10
+)
+
+// hierarchyLinkType is the link type used for implicit parent -> child edges
+const hierarchyLinkType = "hierarchy"
+
+// unknownGroup is graph group for tickets with type missing in project scheme
+const unknownGroup = "other"
+
+// GraphNode DTO
+type GraphNode struct {
+	ID       int64  `json:"id"`
+	Label    string `json:"label"`
+	Type     string `json:"type"`
+	Status   string `json:"status"`
+	Priority string `json:"priority"`
+	Group    string `json:"group"`
+	Color    string `json:"color"`
+	Icon     string `json:"icon"`
+	Rank     int    `json:"rank"`
+	// HiddenNeighbors is number of adjacent tickets left out by filters or depth
+	HiddenNeighbors int `json:"hidden_neighbors"`
+}
+
+// GraphLink DTO
+type GraphLink struct {
+	Source int64  `json:"source"`
+	Target int64  `json:"target"`
+	Type   string `json:"type"`
+}
+
+// GraphResponse DTO
+type GraphResponse struct {
+	Nodes []GraphNode `json:"nodes"`
+	Links []GraphLink `json:"links"`
+	// HiddenNodes is number of project tickets not included in Nodes
+	HiddenNodes int `json:"hidden_nodes"`
+}
+
+// GraphFilter narrows down graph. Zero value returns whole project
+type GraphFilter struct {
+	Statuses   []string
+	Types      []string
+	AssigneeID *int64
+	Labels     []string
+	// LinkTypes limits edges, "hierarchy" stands for parent links
+	LinkTypes []string
+	HideDone  bool
+	// RootID enables focus mode: only tickets within Depth hops from root
+	RootID *int64
+	Depth  int
+}
+
+// GetTicketGraph returns nodes and links for react-force-graph
+func (s *Service) GetTicketGraph(ctx context.Context, projectID, userID int64, filter GraphFilter) (*GraphResponse, error) {
+	// check access
+	_, err := s.projectService.GetProjectByID(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tickets, err := s.repo.ListByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	links, err := s.repo.GetLinksByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	scheme, err := s.schemes.SchemeForProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// labels are needed only for label filter
+	ticketLabels := make(map[int64][]string)
+	if len(filter.Labels) > 0 {
+		labels, err := s.repo.GetLabelsByProjectID(ctx, projectID)
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range labels {
+			ticketLabels[l.TicketID] = append(ticketLabels[l.TicketID], l.Name)
+		}
+	}
+
+	// Collect all edges allowed by link type filter
+	edges := make([]GraphLink, 0, len(links)+len(tickets))
+	if len(filter.LinkTypes) == 0 || slices.Contains(filter.LinkTypes, hierarchyLinkType) {
+		for _, t := range tickets {
+			if t.ParentID != nil {
+				edges = append(edges, GraphLink{Source: *t.ParentID, Target: t.ID, Type: hierarchyLinkType})
+			}
+		}
+	}
+	for _, l := range links {
+		if len(filter.LinkTypes) == 0 || slices.Contains(filter.LinkTypes, l.LinkType) {
+			edges = append(edges, GraphLink{Source: l.SourceID, Target: l.TargetID, Type: l.LinkType})
+		}
+	}
+
+	// Attribute filters
+	visible := make(map[int64]bool, len(tickets))
+	for _, t := range tickets {
+		if filter.matches(&t, ticketLabels[t.ID]) {
+			visible[t.ID] = true
+		}
+	}
+
+	// Focus mode
+	if filter.RootID != nil {
+		rootFound := false
+		for _, t := range tickets {
+			if t.ID == *filter.RootID {
+				rootFound = true
+				break
+			}
+		}
+		if !rootFound {
+			return nil, fmt.Errorf("%w: root ticket is not in project", ErrNotFound)
+		}
+
+		depth := filter.Depth
+		if depth <= 0 {
+			depth = defaultGraphDepth
+		}
+		if depth > maxGraphDepth {
+			depth = maxGraphDepth
+		}
+
+		// root is always shown even if it doesn't match filters
+		visible[*filter.RootID] = true
+		visible = neighborhood(edges, visible, *filter.RootID, depth)
+	}
+
+	response := &GraphResponse{
+		Nodes:       make([]GraphNode, 0, len(visible)),
+		Links:       make([]GraphLink, 0, len(edges)),
+		HiddenNodes: len(tickets) - len(visible),
+	}
+
+	// Count hidden neighbors for "+N more"
+	hiddenNeighbors := make(map[int64]map[int64]bool)
+	addHidden := func(node, neighbor int64) {
+		if hiddenNeighbors[node] == nil {
+			hiddenNeighbors[node] = make(map[int64]bool)
+		}
+		hiddenNeighbors[node][neighbor] = true
+	}
+
+	for _, e := range edges {
+		switch {
+		case visible[e.Source] && visible[e.Target]:
+			response.Links = append(response.Links, e)
+		case visible[e.Source]:
+			addHidden(e.Source, e.Target)
+		case visible[e.Target]:
+			addHidden(e.Target, e.Source)
+		}
+	}
+
+	for _, t := range tickets {
+		if !visible[t.ID] {
+			continue
+		}
+		node := GraphNode{
+			ID:              t.ID,
+			Label:           t.Title,
+			Type:            t.Type,
+			Status:          t.Status,
+			Priority:        t.Priority,
+			Group:           unknownGroup,
+			HiddenNeighbors: len(hiddenNeighbors[t.ID]),
+		}
+		// group and look come from project issue type scheme
+		if issueType, ok := scheme.Get(t.Type); ok {
+			node.Group = issueType.Name
+			node.Color = issueType.Color
+			node.Icon = issueType.Icon
+			node.Rank = issueType.Rank
+		}
+		response.Nodes = append(response.Nodes, node)
+	}
+
+	return response, nil
+}
+
+// matches checks ticket against attribute filters
+func (f GraphFilter) matches(t *Ticket, labels []string) bool {
+	if f.HideDone && isDone(t.Status) {
+		return false
+	}
+	if len(f.Statuses) > 0 && !slices.Contains(f.Statuses, t.Status) {
+		return false
+	}
+	if len(f.Types) > 0 && !slices.Contains(f.Types, t.Type) {
+		return false
+	}
+	if f.AssigneeID != nil && (t.AssigneeID == nil || *t.AssigneeID != *f.AssigneeID) {
+		return false
+	}
+	if len(f.Labels) > 0 {
+		found := false
+		for _, l := range labels {
+			if slices.Contains(f.Labels, l) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// neighborhood returns visible tickets reachable from root within depth hops.
+// Edges are treated as undirected, walking only through visible tickets
+func neighborhood(edges []GraphLink, visible map[int64]bool, root int64, depth int) map[int64]bool {
+	adj := make(map[int64][]int64)
+	for _, e := range edges {
+		if visible[e.Source] && visible[e.Target] {
+			adj[e.Source] = append(adj[e.Source], e.Target)
+			adj[e.Target] = append(adj[e.Target], e.Source)
+		}
+	}
+
+	result := map[int64]bool{root: true}
+	frontier := []int64{root}
+	for level := 0; level < depth && len(frontier) > 0; level++ {
+		var next []int64
+		for _, curr := range frontier {
+			for _, neighbor := range adj[curr] {
+				if !result[neighbor] {
+					result[neighbor] = true
+					next = append(next, neighbor)
+				}
+			}
+		}
+		frontier = next
+	}
+	return result
+}
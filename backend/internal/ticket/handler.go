@@ -1,9 +1,14 @@
 package ticket
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/antonovs105/project-management-system-go/internal/pagination"
+	"github.com/antonovs105/project-management-system-go/internal/project"
 	"github.com/labstack/echo/v4"
 )
 
@@ -22,6 +27,12 @@ type createTicketRequest struct {
 	Type        string `json:"type"`
 	ParentID    *int64 `json:"parent_id"`
 	AssigneeID  *int64 `json:"assignee_id"`
+
+	StoryPoints      *float64 `json:"story_points"`
+	OriginalEstimate *int64   `json:"original_estimate_minutes"`
+
+	StartDate *Date `json:"start_date"`
+	DueDate   *Date `json:"due_date"`
 }
 
 // Create handler for POST /api/projects/:projectID/tickets
@@ -45,6 +56,12 @@ func (h *Handler) Create(c echo.Context) error {
 		Type:        req.Type,
 		ParentID:    req.ParentID,
 		AssigneeID:  req.AssigneeID,
+
+		StoryPoints:      req.StoryPoints,
+		OriginalEstimate: req.OriginalEstimate,
+
+		StartDate: req.StartDate,
+		DueDate:   req.DueDate,
 	}
 
 	ticket, err := h.service.CreateTicket(c.Request().Context(), serviceReq, projectID, userID)
@@ -56,6 +73,8 @@ func (h *Handler) Create(c echo.Context) error {
 }
 
 // List handler for GET /api/projects/:projectID/tickets
+// Query: status, priority, type (comma separated), assignee (id, me or none), reporter (id or me),
+// parent (id or none), updated_since, sort (-created by default), limit, cursor
 func (h *Handler) List(c echo.Context) error {
 	projectID, err := strconv.ParseInt(c.Param("projectID"), 10, 64)
 	if err != nil {
@@ -64,12 +83,128 @@ func (h *Handler) List(c echo.Context) error {
 
 	userID := c.Get("userID").(int64)
 
-	tickets, err := h.service.ListTicketsInProject(c.Request().Context(), projectID, userID)
+	filter, err := parseListFilter(c, userID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	page, err := pagination.Parse(c.QueryParams(), DefaultSort, SortFields)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	result, err := h.service.ListTicketsInProject(c.Request().Context(), projectID, userID, filter, page)
 	if err != nil {
 		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
 	}
 
-	return c.JSON(http.StatusOK, tickets)
+	pagination.SetHeaders(c.Response().Header(), c.Request().URL, result.Total, result.Next)
+	return c.JSON(http.StatusOK, result.Items)
+}
+
+// MyTickets handler for GET /api/me/tickets
+func (h *Handler) MyTickets(c echo.Context) error {
+	userID := c.Get("userID").(int64)
+
+	listFilter, err := parseListFilter(c, userID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	filter := MyTicketsFilter{ListFilter: listFilter, Relations: queryList(c, "relation")}
+	for _, v := range queryList(c, "project") {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid project ID"})
+		}
+		filter.ProjectIDs = append(filter.ProjectIDs, id)
+	}
+	page, err := pagination.Parse(c.QueryParams(), DefaultSort, SortFields)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	ctx := c.Request().Context()
+	result, err := h.service.ListMyTickets(ctx, userID, filter, page)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	pagination.SetHeaders(c.Response().Header(), c.Request().URL, result.Total, result.Next)
+
+	group := c.QueryParam("group")
+	if group == "" {
+		return c.JSON(http.StatusOK, result.Items)
+	}
+	groups, err := h.service.GroupMyTickets(ctx, userID, filter, group, result.Items)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, groups)
+}
+
+func parseListFilter(c echo.Context, userID int64) (ListFilter, error) {
+	filter := ListFilter{
+		Statuses:   queryList(c, "status"),
+		Priorities: queryList(c, "priority"),
+		Types:      queryList(c, "type"),
+	}
+
+	switch v := c.QueryParam("assignee"); v {
+	case "":
+	case "none":
+		filter.Unassigned = true
+	case "me":
+		filter.AssigneeID = &userID
+	default:
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return filter, errors.New("Invalid assignee")
+		}
+		filter.AssigneeID = &id
+	}
+
+	switch v := c.QueryParam("reporter"); v {
+	case "":
+	case "me":
+		filter.ReporterID = &userID
+	default:
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return filter, errors.New("Invalid reporter")
+		}
+		filter.ReporterID = &id
+	}
+
+	switch v := c.QueryParam("parent"); v {
+	case "":
+	case "none":
+		filter.TopLevel = true
+	default:
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return filter, errors.New("Invalid parent")
+		}
+		filter.ParentID = &id
+	}
+
+	if v := c.QueryParam("resolved"); v != "" {
+		resolved, err := strconv.ParseBool(v)
+		if err != nil {
+			return filter, errors.New("resolved must be true or false")
+		}
+		filter.Resolved = &resolved
+	}
+
+	if v := c.QueryParam("updated_since"); v != "" {
+		since, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			d, dateErr := ParseDate(v)
+			if dateErr != nil {
+				return filter, errors.New("updated_since must be RFC 3339 time or YYYY-MM-DD")
+			}
+			since = d.Time
+		}
+		filter.UpdatedSince = &since
+	}
+	return filter, nil
 }
 
 type updateTicketRequest struct {
@@ -80,17 +215,41 @@ type updateTicketRequest struct {
 	Type        *string `json:"type"`
 	ParentID    **int64 `json:"parent_id"`
 	AssigneeID  **int64 `json:"assignee_id"`
+
+	StoryPoints       **float64 `json:"story_points"`
+	OriginalEstimate  **int64   `json:"original_estimate_minutes"`
+	RemainingEstimate **int64   `json:"remaining_estimate_minutes"`
+
+	StartDate **Date `json:"start_date"`
+	DueDate   **Date `json:"due_date"`
 }
 
-// Get handler for GET /api/tickets/:id
-func (h *Handler) Get(c echo.Context) error {
-	ticketID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+// Overdue handler for GET /api/projects/:projectID/tickets/overdue
+func (h *Handler) Overdue(c echo.Context) error {
+	projectID, err := strconv.ParseInt(c.Param("projectID"), 10, 64)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ticket ID"})
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid project ID"})
 	}
 	userID := c.Get("userID").(int64)
 
-	ticket, err := h.service.GetTicketByID(c.Request().Context(), ticketID, userID)
+	tickets, err := h.service.ListOverdueTickets(c.Request().Context(), projectID, userID)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, tickets)
+}
+
+// Get handler for GET /api/tickets/:id, id is either numeric ID or key like PMS-123
+func (h *Handler) Get(c echo.Context) error {
+	userID := c.Get("userID").(int64)
+
+	var ticket *Ticket
+	ticketID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		ticket, err = h.service.GetTicketByKey(c.Request().Context(), c.Param("id"), userID)
+	} else {
+		ticket, err = h.service.GetTicketByID(c.Request().Context(), ticketID, userID)
+	}
 	if err != nil {
 		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
 	}
@@ -118,16 +277,24 @@ func (h *Handler) Update(c echo.Context) error {
 		Type:        req.Type,
 		ParentID:    req.ParentID,
 		AssigneeID:  req.AssigneeID,
+
+		StoryPoints:       req.StoryPoints,
+		OriginalEstimate:  req.OriginalEstimate,
+		RemainingEstimate: req.RemainingEstimate,
+
+		StartDate: req.StartDate,
+		DueDate:   req.DueDate,
 	}
 
 	err = h.service.UpdateTicket(c.Request().Context(), serviceReq, ticketID, userID)
 	if err != nil {
-		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		return c.JSON(errorStatus(err, http.StatusNotFound), map[string]string{"error": err.Error()})
 	}
 	return c.NoContent(http.StatusNoContent)
 }
 
 // Delete handler for DELETE /api/tickets/:id
+// Query params: mode (cascade, orphan, reparent_to), reparent_to (new parent ID)
 func (h *Handler) Delete(c echo.Context) error {
 	ticketID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
@@ -135,13 +302,99 @@ func (h *Handler) Delete(c echo.Context) error {
 	}
 	userID := c.Get("userID").(int64)
 
-	err = h.service.DeleteTicket(c.Request().Context(), ticketID, userID)
+	req := DeleteTicketRequest{Mode: DeleteMode(c.QueryParam("mode"))}
+	if v := c.QueryParam("reparent_to"); v != "" {
+		newParentID, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid reparent_to ticket ID"})
+		}
+		req.NewParentID = &newParentID
+		if req.Mode == DeleteModeNone {
+			req.Mode = DeleteModeReparent
+		}
+	}
+
+	err = h.service.DeleteTicket(c.Request().Context(), req, ticketID, userID)
 	if err != nil {
-		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		return c.JSON(errorStatus(err, http.StatusBadRequest), map[string]string{"error": err.Error()})
 	}
 	return c.NoContent(http.StatusNoContent)
 }
 
+// errorStatus maps service errors to HTTP status, fallback for unknown ones
+func errorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, ErrNotFound), errors.Is(err, project.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrHasChildren):
+		return http.StatusConflict
+	case errors.Is(err, ErrInvalidHierarchy):
+		return http.StatusUnprocessableEntity
+	default:
+		return fallback
+	}
+}
+
+// Children handler for GET /api/tickets/:id/children
+func (h *Handler) Children(c echo.Context) error {
+	ticketID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ticket ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	children, err := h.service.GetTicketChildren(c.Request().Context(), ticketID, userID)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, children)
+}
+
+// Ancestors handler for GET /api/tickets/:id/ancestors
+func (h *Handler) Ancestors(c echo.Context) error {
+	ticketID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ticket ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	ancestors, err := h.service.GetTicketAncestors(c.Request().Context(), ticketID, userID)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, ancestors)
+}
+
+// Tree handler for GET /api/tickets/:id/tree
+func (h *Handler) Tree(c echo.Context) error {
+	ticketID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ticket ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	tree, err := h.service.GetTicketTree(c.Request().Context(), ticketID, userID)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, tree)
+}
+
+// History handler for GET /api/tickets/:id/history
+func (h *Handler) History(c echo.Context) error {
+	ticketID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ticket ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	changes, err := h.service.GetTicketHistory(c.Request().Context(), ticketID, userID)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, changes)
+}
+
 type addLinkRequest struct {
 	TargetID int64  `json:"target_id"`
 	LinkType string `json:"link_type"`
@@ -193,6 +446,7 @@ func (h *Handler) RemoveLink(c echo.Context) error {
 }
 
 // GetGraph handler for GET /api/projects/:projectID/graph
+// Query params: status, type, label, link_type (comma separated), assignee, hide_done, root, depth
 func (h *Handler) GetGraph(c echo.Context) error {
 	projectID, err := strconv.ParseInt(c.Param("projectID"), 10, 64)
 	if err != nil {
@@ -200,10 +454,100 @@ func (h *Handler) GetGraph(c echo.Context) error {
 	}
 	userID := c.Get("userID").(int64)
 
-	graph, err := h.service.GetTicketGraph(c.Request().Context(), projectID, userID)
+	filter := GraphFilter{
+		Statuses:  queryList(c, "status"),
+		Types:     queryList(c, "type"),
+		Labels:    queryList(c, "label"),
+		LinkTypes: queryList(c, "link_type"),
+	}
+
+	if v := c.QueryParam("assignee"); v != "" {
+		assigneeID, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid assignee"})
+		}
+		filter.AssigneeID = &assigneeID
+	}
+	if v := c.QueryParam("hide_done"); v != "" {
+		filter.HideDone, err = strconv.ParseBool(v)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid hide_done"})
+		}
+	}
+	if v := c.QueryParam("root"); v != "" {
+		rootID, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid root ticket ID"})
+		}
+		filter.RootID = &rootID
+	}
+	if v := c.QueryParam("depth"); v != "" {
+		filter.Depth, err = strconv.Atoi(v)
+		if err != nil || filter.Depth < 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid depth"})
+		}
+	}
+
+	graph, err := h.service.GetTicketGraph(c.Request().Context(), projectID, userID, filter)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
 	}
 
 	return c.JSON(http.StatusOK, graph)
 }
+
+// queryList reads query param given both as repeated keys and comma separated values
+func queryList(c echo.Context, name string) []string {
+	var result []string
+	for _, raw := range c.QueryParams()[name] {
+		for _, v := range strings.Split(raw, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				result = append(result, v)
+			}
+		}
+	}
+	return result
+}
+
+// Watchers handler for GET /api/tickets/:id/watchers
+func (h *Handler) Watchers(c echo.Context) error {
+	ticketID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ticket ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	watchers, err := h.service.ListWatchers(c.Request().Context(), ticketID, userID)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, watchers)
+}
+
+// Watch handler for POST /api/tickets/:id/watch
+func (h *Handler) Watch(c echo.Context) error {
+	ticketID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ticket ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	if err := h.service.Watch(c.Request().Context(), ticketID, userID); err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Unwatch handler for DELETE /api/tickets/:id/watch
+func (h *Handler) Unwatch(c echo.Context) error {
+	ticketID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ticket ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	if err := h.service.Unwatch(c.Request().Context(), ticketID, userID); err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,182 @@
+package ticket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/antonovs105/project-management-system-go/internal/event"
+	"github.com/antonovs105/project-management-system-go/internal/issuetype"
+)
+
+// DeleteMode tells what to do with children of deleted ticket
+type DeleteMode string
+
+const (
+	// DeleteModeNone deletes only tickets without children
+	DeleteModeNone DeleteMode = ""
+	// DeleteModeCascade deletes ticket with whole subtree
+	DeleteModeCascade DeleteMode = "cascade"
+	// DeleteModeOrphan detaches children from ticket
+	DeleteModeOrphan DeleteMode = "orphan"
+	// DeleteModeReparent moves children to another ticket
+	DeleteModeReparent DeleteMode = "reparent_to"
+)
+
+// DeleteTicketRequest DTO for deleting ticket
+type DeleteTicketRequest struct {
+	Mode DeleteMode
+	// NewParentID is required for DeleteModeReparent
+	NewParentID *int64
+}
+
+// validateHierarchy checks that ticket of given type can be placed under parentID.
+// Walks the whole ancestor chain so ticket can't become its own ancestor
+func (s *Service) validateHierarchy(ctx context.Context, scheme *issuetype.Scheme, t *Ticket, newType string, parentID *int64) error {
+	issueType, ok := scheme.Get(newType)
+	if !ok {
+		return errors.New("invalid ticket type")
+	}
+
+	if parentID == nil {
+		if issueType.RequiresParent {
+			return fmt.Errorf("%w: %s must have a parent", ErrInvalidHierarchy, newType)
+		}
+		return nil
+	}
+
+	if *parentID == t.ID {
+		return fmt.Errorf("%w: cannot be own parent", ErrInvalidHierarchy)
+	}
+
+	parent, err := s.repo.GetByID(ctx, *parentID)
+	if err != nil {
+		return errors.New("parent ticket not found")
+	}
+	if parent.ProjectID != t.ProjectID {
+		return errors.New("parent ticket must be in the same project")
+	}
+
+	// parent type check
+	if !scheme.CanParent(parent.Type, newType) {
+		return fmt.Errorf("%w: %s can't be parent of %s", ErrInvalidHierarchy, parent.Type, newType)
+	}
+
+	// ancestor chain check, new ticket can't be anyone's ancestor
+	if t.ID != 0 {
+		ancestors, err := s.repo.GetAncestors(ctx, parent.ID)
+		if err != nil {
+			return err
+		}
+		for _, a := range ancestors {
+			if a.ID == t.ID {
+				return fmt.Errorf("%w: cycle detected, ticket is an ancestor of the new parent", ErrInvalidHierarchy)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateChildren checks that existing children still fit under ticket of new type
+func (s *Service) validateChildren(ctx context.Context, scheme *issuetype.Scheme, ticketID int64, newType string) error {
+	children, err := s.repo.ListChildren(ctx, ticketID)
+	if err != nil {
+		return err
+	}
+	for _, c := range children {
+		if !scheme.CanParent(newType, c.Type) {
+			return fmt.Errorf("%w: child ticket %d of type %s can't be under %s", ErrInvalidHierarchy, c.ID, c.Type, newType)
+		}
+	}
+	return nil
+}
+
+// DeleteTicket logic for deleting
+func (s *Service) DeleteTicket(ctx context.Context, req DeleteTicketRequest, ticketID, userID int64) error {
+	// check access
+	ticketToDelete, err := s.GetTicketByID(ctx, ticketID, userID)
+	if err != nil {
+		return err
+	}
+
+	children, err := s.repo.ListChildren(ctx, ticketID)
+	if err != nil {
+		return err
+	}
+
+	scheme, err := s.schemes.SchemeForProject(ctx, ticketToDelete.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	data := &TicketDeletedData{ID: ticketToDelete.ID, Key: ticketToDelete.Key}
+	ev := event.Draft{ProjectID: ticketToDelete.ProjectID, ActorID: userID, Type: event.TicketDeleted, Data: data}
+
+	switch req.Mode {
+	case DeleteModeNone:
+		if len(children) > 0 {
+			return ErrHasChildren
+		}
+		err = s.repo.Delete(ctx, ticketID, ev)
+
+	case DeleteModeCascade:
+		var descendants []Ticket
+		if descendants, err = s.repo.GetDescendants(ctx, ticketID); err != nil {
+			return err
+		}
+		for _, d := range descendants {
+			data.DeletedIDs = append(data.DeletedIDs, d.ID)
+		}
+		err = s.repo.DeleteTree(ctx, ticketID, ev)
+
+	case DeleteModeOrphan:
+		for _, c := range children {
+			if issueType, ok := scheme.Get(c.Type); ok && issueType.RequiresParent {
+				return fmt.Errorf("%w: %s %d can't be orphaned", ErrInvalidHierarchy, c.Type, c.ID)
+			}
+		}
+		data.ReparentedIDs = childIDs(children)
+		err = s.repo.DeleteAndReparent(ctx, ticketID, nil, ev)
+
+	case DeleteModeReparent:
+		if req.NewParentID == nil {
+			return errors.New("reparent_to requires new parent ID")
+		}
+		if *req.NewParentID == ticketID {
+			return fmt.Errorf("%w: cannot reparent children to deleted ticket", ErrInvalidHierarchy)
+		}
+		// each child must fit under new parent
+		for _, c := range children {
+			child := c
+			if err := s.validateHierarchy(ctx, scheme, &child, child.Type, req.NewParentID); err != nil {
+				return err
+			}
+		}
+		// new parent must not be inside deleted subtree
+		var ancestors []Ticket
+		if ancestors, err = s.repo.GetAncestors(ctx, *req.NewParentID); err != nil {
+			return err
+		}
+		for _, a := range ancestors {
+			if a.ID == ticketToDelete.ID {
+				return fmt.Errorf("%w: new parent is a descendant of deleted ticket", ErrInvalidHierarchy)
+			}
+		}
+		data.ReparentedIDs = childIDs(children)
+		data.NewParentID = req.NewParentID
+		err = s.repo.DeleteAndReparent(ctx, ticketID, req.NewParentID, ev)
+
+	default:
+		return fmt.Errorf("unknown delete mode %q", req.Mode)
+	}
+	return err
+}
+
+func childIDs(children []Ticket) []int64 {
+	ids := make([]int64, len(children))
+	for i, c := range children {
+		ids[i] = c.ID
+	}
+	return ids
+}
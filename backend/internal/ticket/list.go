@@ -0,0 +1,100 @@
+package ticket
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/pagination"
+)
+
+// DefaultSort is newest tickets first
+const DefaultSort = "-created"
+
+// priorities from lowest, unknown priorities rank 0
+var priorities = []string{"lowest", "low", "medium", "high", "highest", "critical"}
+
+func priorityRank(priority string) int {
+	for i, p := range priorities {
+		if strings.EqualFold(p, priority) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// priorityRankSQL is priorityRank as SQL expression
+var priorityRankSQL = func() string {
+	var b strings.Builder
+	b.WriteString("CASE lower(priority)")
+	for i, p := range priorities {
+		b.WriteString(" WHEN '" + p + "' THEN " + strconv.Itoa(i+1))
+	}
+	b.WriteString(" ELSE 0 END")
+	return b.String()
+}()
+
+// SortFields are fields tickets can be sorted by. All of them are not null, so keyset works
+var SortFields = map[string]pagination.SortField{
+	"created":  {Column: "created_at", Cast: "timestamptz"},
+	"updated":  {Column: "updated_at", Cast: "timestamptz"},
+	"key":      {Column: "number", Cast: "bigint"},
+	"title":    {Column: "title", Cast: "text"},
+	"status":   {Column: "status", Cast: "text"},
+	"type":     {Column: "type", Cast: "text"},
+	"priority": {Column: priorityRankSQL, Cast: "int"},
+}
+
+// sortValue returns value of sort field as cursor value
+func sortValue(t *Ticket, field string) string {
+	switch field {
+	case "updated":
+		return t.UpdatedAt.Format(time.RFC3339Nano)
+	case "key":
+		return strconv.FormatInt(t.Number, 10)
+	case "title":
+		return t.Title
+	case "status":
+		return t.Status
+	case "type":
+		return t.Type
+	case "priority":
+		return strconv.Itoa(priorityRank(t.Priority))
+	default:
+		return t.CreatedAt.Format(time.RFC3339Nano)
+	}
+}
+
+// ListFilter narrows ticket list, zero value matches every ticket
+type ListFilter struct {
+	Statuses   []string
+	Priorities []string
+	Types      []string
+	AssigneeID *int64
+	Unassigned bool
+	ReporterID *int64
+	ParentID   *int64
+	// TopLevel selects tickets without parent
+	TopLevel bool
+	// Resolved selects resolved (true) or unresolved (false) tickets
+	Resolved     *bool
+	UpdatedSince *time.Time
+}
+
+// ListTicketsInProject returns filtered page of project tickets
+func (s *Service) ListTicketsInProject(ctx context.Context, projectID, userID int64, filter ListFilter, page pagination.Params) (*pagination.Page[Ticket], error) {
+	// check access
+	_, err := s.projectService.GetProjectByID(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tickets, total, err := s.repo.ListPage(ctx, projectID, filter, page)
+	if err != nil {
+		return nil, err
+	}
+	return pagination.NewPage(tickets, total, page, func(t *Ticket) pagination.Cursor {
+		return page.Cursor(sortValue(t, page.Sort), t.ID)
+	}), nil
+}
@@ -0,0 +1,151 @@
+package ticket
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/antonovs105/project-management-system-go/internal/pagination"
+)
+
+// Relations of user to ticket
+const (
+	RelationAssigned = "assigned"
+	RelationReported = "reported"
+	RelationWatching = "watching"
+)
+
+// Relations are all relations "my work" can be filtered by
+var Relations = []string{RelationAssigned, RelationReported, RelationWatching}
+
+// groupExpressions are SQL expressions "my work" can be grouped by. Ticket key starts with project key
+var groupExpressions = map[string]string{
+	"project":  "split_part(key, '-', 1)",
+	"status":   "status",
+	"priority": "priority",
+	"type":     "type",
+}
+
+// MyTicketsFilter narrows "my work" list. Empty Relations means any relation
+type MyTicketsFilter struct {
+	ListFilter
+	Relations  []string
+	ProjectIDs []int64
+}
+
+// MyTicket is ticket with relations of current user to it
+type MyTicket struct {
+	Ticket
+	Relations []string `json:"relations"`
+}
+
+// GroupCount is number of tickets in group
+type GroupCount struct {
+	Key   string `db:"key" json:"key"`
+	Count int    `db:"count" json:"count"`
+}
+
+// TicketGroup is group of "my work" page. Total counts tickets of group on all pages
+type TicketGroup struct {
+	Key     string     `json:"key"`
+	Total   int        `json:"total"`
+	Tickets []MyTicket `json:"tickets"`
+}
+
+// ListMyTickets returns page of tickets user is assigned to, reported or watches across user's projects
+func (s *Service) ListMyTickets(ctx context.Context, userID int64, filter MyTicketsFilter, page pagination.Params) (*pagination.Page[MyTicket], error) {
+	for _, rel := range filter.Relations {
+		if !isRelation(rel) {
+			return nil, fmt.Errorf("unknown relation %q, expected one of: %s", rel, strings.Join(Relations, ", "))
+		}
+	}
+
+	tickets, total, err := s.repo.ListForUser(ctx, userID, filter, page)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, len(tickets))
+	for i, t := range tickets {
+		ids[i] = t.ID
+	}
+	watched, err := s.repo.WatchedAmong(ctx, userID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]MyTicket, len(tickets))
+	for i, t := range tickets {
+		items[i] = MyTicket{Ticket: t, Relations: relationsOf(&t, userID, watched[t.ID])}
+	}
+	return pagination.NewPage(items, total, page, func(t *MyTicket) pagination.Cursor {
+		return page.Cursor(sortValue(&t.Ticket, page.Sort), t.ID)
+	}), nil
+}
+
+// GroupMyTickets splits page of ListMyTickets into groups. Every group with matching tickets is
+// returned, including groups without tickets on this page
+func (s *Service) GroupMyTickets(ctx context.Context, userID int64, filter MyTicketsFilter, group string, items []MyTicket) ([]TicketGroup, error) {
+	if _, ok := groupExpressions[group]; !ok {
+		return nil, fmt.Errorf("can't group by %q, expected one of: project, status, priority, type", group)
+	}
+
+	counts, err := s.repo.CountForUser(ctx, userID, filter, group)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]TicketGroup, len(counts))
+	index := make(map[string]int, len(counts))
+	for i, c := range counts {
+		groups[i] = TicketGroup{Key: c.Key, Total: c.Count, Tickets: []MyTicket{}}
+		index[c.Key] = i
+	}
+	for _, item := range items {
+		i, ok := index[groupKey(&item.Ticket, group)]
+		if !ok {
+			// ticket changed between queries, it will be in right group on refresh
+			continue
+		}
+		groups[i].Tickets = append(groups[i].Tickets, item)
+	}
+	return groups, nil
+}
+
+// groupKey is value of group expression for ticket
+func groupKey(t *Ticket, group string) string {
+	switch group {
+	case "project":
+		key, _, _ := strings.Cut(t.Key, "-")
+		return key
+	case "status":
+		return t.Status
+	case "priority":
+		return t.Priority
+	default:
+		return t.Type
+	}
+}
+
+func relationsOf(t *Ticket, userID int64, watching bool) []string {
+	relations := []string{}
+	if t.AssigneeID != nil && *t.AssigneeID == userID {
+		relations = append(relations, RelationAssigned)
+	}
+	if t.ReporterID == userID {
+		relations = append(relations, RelationReported)
+	}
+	if watching {
+		relations = append(relations, RelationWatching)
+	}
+	return relations
+}
+
+func isRelation(rel string) bool {
+	for _, r := range Relations {
+		if r == rel {
+			return true
+		}
+	}
+	return false
+}
@@ -2,21 +2,46 @@ package ticket
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 
+	"github.com/antonovs105/project-management-system-go/internal/event"
+	"github.com/antonovs105/project-management-system-go/internal/pagination"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 // Repository interface
 type Repository interface {
-	Create(ctx context.Context, ticket *Ticket) error
+	Create(ctx context.Context, ticket *Ticket, ev event.Draft) error
 	ListByProjectID(ctx context.Context, projectID int64) ([]Ticket, error)
+	ListPage(ctx context.Context, projectID int64, filter ListFilter, page pagination.Params) ([]Ticket, int, error)
+	ListForUser(ctx context.Context, userID int64, filter MyTicketsFilter, page pagination.Params) ([]Ticket, int, error)
+	CountForUser(ctx context.Context, userID int64, filter MyTicketsFilter, group string) ([]GroupCount, error)
 	GetByID(ctx context.Context, id int64) (*Ticket, error)
-	Update(ctx context.Context, ticket *Ticket) error
-	Delete(ctx context.Context, id int64) error
-	CreateLink(ctx context.Context, link *TicketLink) error
-	DeleteLink(ctx context.Context, linkID int64) error
+	GetByKey(ctx context.Context, key string) (*Ticket, error)
+	Update(ctx context.Context, ticket *Ticket, changes []Change, ev event.Draft) error
+	Delete(ctx context.Context, id int64, ev event.Draft) error
+	DeleteTree(ctx context.Context, id int64, ev event.Draft) error
+	DeleteAndReparent(ctx context.Context, id int64, newParentID *int64, ev event.Draft) error
+	ListChildren(ctx context.Context, parentID int64) ([]Ticket, error)
+	GetAncestors(ctx context.Context, id int64) ([]Ticket, error)
+	GetDescendants(ctx context.Context, id int64) ([]Ticket, error)
+	SumChildSubtrees(ctx context.Context, parentID int64) ([]SubtreeTotals, error)
+	CreateLink(ctx context.Context, link *TicketLink, ev event.Draft) error
+	GetLinkByID(ctx context.Context, linkID int64) (*TicketLink, error)
+	DeleteLink(ctx context.Context, linkID int64, ev event.Draft) error
 	GetLinksByProjectID(ctx context.Context, projectID int64) ([]TicketLink, error)
+	GetLabelsByProjectID(ctx context.Context, projectID int64) ([]TicketLabel, error)
+	ListChanges(ctx context.Context, ticketID int64) ([]Change, error)
+	ListDueBefore(ctx context.Context, projectID int64, date Date) ([]Ticket, error)
+	ListWatchers(ctx context.Context, ticketID int64) ([]Watcher, error)
+	AddWatcher(ctx context.Context, ticketID, userID int64) error
+	RemoveWatcher(ctx context.Context, ticketID, userID int64) error
+	WatchedAmong(ctx context.Context, userID int64, ticketIDs []int64) (map[int64]bool, error)
 }
 
 type PgRepository struct {
@@ -27,23 +52,62 @@ func NewRepository(db *sqlx.DB) Repository {
 	return &PgRepository{db: db}
 }
 
-// Create new ticket in DB
-func (r *PgRepository) Create(ctx context.Context, ticket *Ticket) error {
+// Create new ticket in DB and writes ev to outbox.
+// Number comes from project counter in the same transaction, so numbers are gap-free
+func (r *PgRepository) Create(ctx context.Context, ticket *Ticket, ev event.Draft) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// row lock on project serializes concurrent creates
+	var projectKey string
+	counterQuery := `
+		UPDATE projects SET ticket_counter = ticket_counter + 1
+		WHERE id = $1
+		RETURNING key, ticket_counter`
+	err = tx.QueryRowxContext(ctx, counterQuery, ticket.ProjectID).Scan(&projectKey, &ticket.Number)
+	if err != nil {
+		return err
+	}
+	ticket.Key = fmt.Sprintf("%s-%d", projectKey, ticket.Number)
+	if err := checkSchemeType(ctx, tx, ticket.ProjectID, ticket.Type); err != nil {
+		return err
+	}
+
 	query := `
-		INSERT INTO tickets (title, description, status, priority, type, parent_id, project_id, reporter_id, assignee_id)
-		VALUES (:title, :description, :status, :priority, :type, :parent_id, :project_id, :reporter_id, :assignee_id)
+		INSERT INTO tickets (number, key, title, description, status, priority, type, parent_id, project_id, reporter_id, assignee_id,
+			story_points, original_estimate_minutes, remaining_estimate_minutes, start_date, due_date)
+		VALUES (:number, :key, :title, :description, :status, :priority, :type, :parent_id, :project_id, :reporter_id, :assignee_id,
+			:story_points, :original_estimate_minutes, :remaining_estimate_minutes, :start_date, :due_date)
 		RETURNING *`
 
-	rows, err := r.db.NamedQueryContext(ctx, query, ticket)
+	rows, err := sqlx.NamedQueryContext(ctx, tx, query, ticket)
 	if err != nil {
 		return err
 	}
-	defer rows.Close()
+	if !rows.Next() {
+		rows.Close()
+		return errors.New("ticket creation failed: no returning row")
+	}
+	if err := rows.StructScan(ticket); err != nil {
+		rows.Close()
+		return err
+	}
+	rows.Close()
 
-	if rows.Next() {
-		return rows.StructScan(ticket)
+	if err := insertChanges(ctx, tx, initialChanges(ticket)); err != nil {
+		return err
+	}
+	if err := AddWatchers(ctx, tx, ticket.ID, &ticket.ReporterID, ticket.AssigneeID); err != nil {
+		return err
 	}
-	return errors.New("ticket creation failed: no returning row")
+	if err := event.Write(ctx, tx, ev); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 // ListByProjectID gets all tickets in a project
@@ -58,6 +122,130 @@ func (r *PgRepository) ListByProjectID(ctx context.Context, projectID int64) ([]
 	return tickets, nil
 }
 
+// ListPage returns page of filtered project tickets (limit+1 rows to detect next page) and total count
+func (r *PgRepository) ListPage(ctx context.Context, projectID int64, filter ListFilter, page pagination.Params) ([]Ticket, int, error) {
+	where, args := filterConditions([]string{"project_id = $1"}, []any{projectID}, filter)
+	return r.selectPage(ctx, where, args, page)
+}
+
+// ListForUser returns page of tickets related to user in projects user is member of and total count
+func (r *PgRepository) ListForUser(ctx context.Context, userID int64, filter MyTicketsFilter, page pagination.Params) ([]Ticket, int, error) {
+	where, args := userConditions(userID, filter)
+	return r.selectPage(ctx, where, args, page)
+}
+
+// CountForUser counts tickets of ListForUser grouped by group expression, groups are ordered by key
+func (r *PgRepository) CountForUser(ctx context.Context, userID int64, filter MyTicketsFilter, group string) ([]GroupCount, error) {
+	expr, ok := groupExpressions[group]
+	if !ok {
+		return nil, fmt.Errorf("can't group by %q", group)
+	}
+	where, args := userConditions(userID, filter)
+
+	var counts []GroupCount
+	query := fmt.Sprintf(`
+		SELECT %s AS key, COUNT(*) AS count
+		FROM tickets
+		WHERE %s
+		GROUP BY 1
+		ORDER BY 1`, expr, where)
+	if err := r.db.SelectContext(ctx, &counts, query, args...); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// selectPage counts tickets matching where and selects one page of them
+func (r *PgRepository) selectPage(ctx context.Context, where string, args []any, page pagination.Params) ([]Ticket, int, error) {
+	var total int
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM tickets WHERE `+where, args...); err != nil {
+		return nil, 0, err
+	}
+
+	keyset, orderBy, args := page.Keyset(SortFields, "id", args)
+	args = append(args, page.Limit+1)
+	query := fmt.Sprintf(`SELECT * FROM tickets WHERE %s AND %s ORDER BY %s LIMIT $%d`, where, keyset, orderBy, len(args))
+
+	var tickets []Ticket
+	if err := r.db.SelectContext(ctx, &tickets, query, args...); err != nil {
+		return nil, 0, err
+	}
+	return tickets, total, nil
+}
+
+// userConditions selects tickets of user's projects where user has one of requested relations,
+// any relation when none is requested
+func userConditions(userID int64, filter MyTicketsFilter) (string, []any) {
+	conds := []string{"project_id IN (SELECT project_id FROM project_members WHERE user_id = $1)"}
+	args := []any{userID}
+
+	relations := filter.Relations
+	if len(relations) == 0 {
+		relations = Relations
+	}
+	var related []string
+	for _, rel := range relations {
+		switch rel {
+		case RelationAssigned:
+			related = append(related, "assignee_id = $1")
+		case RelationReported:
+			related = append(related, "reporter_id = $1")
+		case RelationWatching:
+			related = append(related, "EXISTS (SELECT 1 FROM ticket_watchers w WHERE w.ticket_id = tickets.id AND w.user_id = $1)")
+		}
+	}
+	conds = append(conds, "("+strings.Join(related, " OR ")+")")
+	if len(filter.ProjectIDs) > 0 {
+		args = append(args, pq.Array(filter.ProjectIDs))
+		conds = append(conds, "project_id = ANY($2)")
+	}
+	return filterConditions(conds, args, filter.ListFilter)
+}
+
+// filterConditions appends conditions of filter to conds and returns them joined with AND
+func filterConditions(conds []string, args []any, filter ListFilter) (string, []any) {
+	add := func(cond string, v any) {
+		args = append(args, v)
+		conds = append(conds, strings.ReplaceAll(cond, "?", "$"+strconv.Itoa(len(args))))
+	}
+
+	if len(filter.Statuses) > 0 {
+		add("status = ANY(?)", pq.Array(filter.Statuses))
+	}
+	if len(filter.Priorities) > 0 {
+		add("priority = ANY(?)", pq.Array(filter.Priorities))
+	}
+	if len(filter.Types) > 0 {
+		add("type = ANY(?)", pq.Array(filter.Types))
+	}
+	if filter.AssigneeID != nil {
+		add("assignee_id = ?", *filter.AssigneeID)
+	}
+	if filter.Unassigned {
+		conds = append(conds, "assignee_id IS NULL")
+	}
+	if filter.ReporterID != nil {
+		add("reporter_id = ?", *filter.ReporterID)
+	}
+	if filter.ParentID != nil {
+		add("parent_id = ?", *filter.ParentID)
+	}
+	if filter.TopLevel {
+		conds = append(conds, "parent_id IS NULL")
+	}
+	if filter.Resolved != nil {
+		if *filter.Resolved {
+			conds = append(conds, "resolved_at IS NOT NULL")
+		} else {
+			conds = append(conds, "resolved_at IS NULL")
+		}
+	}
+	if filter.UpdatedSince != nil {
+		add("updated_at >= ?", *filter.UpdatedSince)
+	}
+	return strings.Join(conds, " AND "), args
+}
+
 // GetByID finds single ticket by its id
 func (r *PgRepository) GetByID(ctx context.Context, id int64) (*Ticket, error) {
 	var t Ticket
@@ -66,8 +254,62 @@ func (r *PgRepository) GetByID(ctx context.Context, id int64) (*Ticket, error) {
 	return &t, err
 }
 
-// Update renews (new synonym!) ticket data in DB
-func (r *PgRepository) Update(ctx context.Context, ticket *Ticket) error {
+// GetByKey finds ticket by key like PMS-123. Old keys of renamed projects resolve too
+func (r *PgRepository) GetByKey(ctx context.Context, key string) (*Ticket, error) {
+	sep := strings.LastIndex(key, "-")
+	if sep <= 0 {
+		return nil, errors.New("invalid ticket key")
+	}
+	number, err := strconv.ParseInt(key[sep+1:], 10, 64)
+	if err != nil {
+		return nil, errors.New("invalid ticket key")
+	}
+
+	var t Ticket
+	query := `
+		SELECT * FROM tickets
+		WHERE number = $2 AND project_id = (
+			SELECT id FROM projects WHERE key = $1
+			UNION ALL
+			SELECT project_id FROM project_key_aliases WHERE key = $1
+			LIMIT 1
+		)`
+	err = r.db.GetContext(ctx, &t, query, strings.ToUpper(key[:sep]), number)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// Update renews (new synonym!) ticket data in DB and writes change log and ev in the same transaction
+func (r *PgRepository) Update(ctx context.Context, ticket *Ticket, changes []Change, ev event.Draft) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var old struct {
+		AssigneeID *int64 `db:"assignee_id"`
+		Type       string `db:"type"`
+	}
+	err = tx.GetContext(ctx, &old, `SELECT assignee_id, COALESCE(type, '') AS type FROM tickets WHERE id = $1 FOR UPDATE`, ticket.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if ticket.Type != old.Type {
+		// key share lock doesn't block other ticket writes, only scheme replacement
+		if _, err := tx.ExecContext(ctx, `SELECT 1 FROM projects WHERE id = $1 FOR KEY SHARE`, ticket.ProjectID); err != nil {
+			return err
+		}
+		if err := checkSchemeType(ctx, tx, ticket.ProjectID, ticket.Type); err != nil {
+			return err
+		}
+	}
+
 	query := `
 		UPDATE tickets
 		SET
@@ -78,10 +320,17 @@ func (r *PgRepository) Update(ctx context.Context, ticket *Ticket) error {
 			type = :type,
 			parent_id = :parent_id,
 			assignee_id = :assignee_id,
+			story_points = :story_points,
+			original_estimate_minutes = :original_estimate_minutes,
+			remaining_estimate_minutes = :remaining_estimate_minutes,
+			started_at = :started_at,
+			resolved_at = :resolved_at,
+			start_date = :start_date,
+			due_date = :due_date,
 			updated_at = now()
 		WHERE id = :id`
 
-	result, err := r.db.NamedExecContext(ctx, query, ticket)
+	result, err := tx.NamedExecContext(ctx, query, ticket)
 	if err != nil {
 		return err
 	}
@@ -91,16 +340,91 @@ func (r *PgRepository) Update(ctx context.Context, ticket *Ticket) error {
 		return err
 	}
 	if rowsAffected == 0 {
-		return errors.New("ticket to update not found")
+		return ErrNotFound
+	}
+
+	if err := insertChanges(ctx, tx, changes); err != nil {
+		return err
+	}
+	// new assignee starts watching, unchanged one may have stopped on purpose
+	if !equalValues(FormatInt(old.AssigneeID), FormatInt(ticket.AssigneeID)) {
+		if err := AddWatchers(ctx, tx, ticket.ID, ticket.AssigneeID); err != nil {
+			return err
+		}
+	}
+	if err := event.Write(ctx, tx, ev); err != nil {
+		return err
 	}
 
+	return tx.Commit()
+}
+
+// checkSchemeType makes sure type is still in project scheme, projects without own scheme use default one.
+// Caller holds lock on project row, so scheme can't be replaced before tx ends
+func checkSchemeType(ctx context.Context, tx *sqlx.Tx, projectID int64, ticketType string) error {
+	var ok bool
+	query := `
+		SELECT NOT EXISTS (SELECT 1 FROM issue_types WHERE project_id = $1)
+			OR EXISTS (SELECT 1 FROM issue_types WHERE project_id = $1 AND name = $2)`
+	if err := tx.GetContext(ctx, &ok, query, projectID, ticketType); err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: type %s was removed from project scheme", ErrInvalidHierarchy, ticketType)
+	}
 	return nil
 }
 
-// Delete removes ticket from DB
-func (r *PgRepository) Delete(ctx context.Context, id int64) error {
-	query := `DELETE FROM tickets WHERE id = $1`
-	result, err := r.db.ExecContext(ctx, query, id)
+// Delete removes ticket without children from DB and writes ev to outbox. Ticket row is locked first,
+// so child created concurrently either shows up in the check or waits for delete and fails
+func (r *PgRepository) Delete(ctx context.Context, id int64, ev event.Draft) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var locked int64
+	if err := tx.GetContext(ctx, &locked, `SELECT id FROM tickets WHERE id = $1 FOR UPDATE`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		return err
+	}
+	var hasChildren bool
+	if err := tx.GetContext(ctx, &hasChildren, `SELECT EXISTS(SELECT 1 FROM tickets WHERE parent_id = $1)`, id); err != nil {
+		return err
+	}
+	if hasChildren {
+		return ErrHasChildren
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM tickets WHERE id = $1`, id); err != nil {
+		return err
+	}
+	if err := event.Write(ctx, tx, ev); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// DeleteTree removes ticket together with all its descendants and writes ev to outbox
+func (r *PgRepository) DeleteTree(ctx context.Context, id int64, ev event.Draft) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		WITH RECURSIVE subtree AS (
+			SELECT id FROM tickets WHERE id = $1
+			UNION
+			SELECT t.id FROM tickets t JOIN subtree s ON t.parent_id = s.id
+		)
+		DELETE FROM tickets WHERE id IN (SELECT id FROM subtree)`
+	result, err := tx.ExecContext(ctx, query, id)
 	if err != nil {
 		return err
 	}
@@ -110,35 +434,182 @@ func (r *PgRepository) Delete(ctx context.Context, id int64) error {
 		return err
 	}
 	if rowsAffected == 0 {
-		return errors.New("ticket to delete not found")
+		return ErrNotFound
+	}
+	if err := event.Write(ctx, tx, ev); err != nil {
+		return err
 	}
 
-	return nil
+	return tx.Commit()
+}
+
+// DeleteAndReparent moves children to newParentID (nil detaches them), removes ticket and writes ev in one transaction
+func (r *PgRepository) DeleteAndReparent(ctx context.Context, id int64, newParentID *int64, ev event.Draft) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `UPDATE tickets SET parent_id = $2, updated_at = now() WHERE parent_id = $1`, id, newParentID)
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM tickets WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	if err := event.Write(ctx, tx, ev); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-// CreateLink adds a link between tickets
-func (r *PgRepository) CreateLink(ctx context.Context, link *TicketLink) error {
+// ListChildren returns direct children of ticket
+func (r *PgRepository) ListChildren(ctx context.Context, parentID int64) ([]Ticket, error) {
+	var tickets []Ticket
+	query := `SELECT * FROM tickets WHERE parent_id = $1 ORDER BY created_at`
+
+	err := r.db.SelectContext(ctx, &tickets, query, parentID)
+	if err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
+// GetAncestors returns ticket ancestors starting from its parent up to the root
+func (r *PgRepository) GetAncestors(ctx context.Context, id int64) ([]Ticket, error) {
+	var tickets []Ticket
+	// depth guard protects from already broken data with cycles
+	query := `
+		WITH RECURSIVE chain AS (
+			SELECT t.id, t.parent_id, 1 AS depth FROM tickets t
+			WHERE t.id = (SELECT parent_id FROM tickets WHERE id = $1)
+			UNION ALL
+			SELECT t.id, t.parent_id, c.depth + 1 FROM tickets t
+			JOIN chain c ON t.id = c.parent_id
+			WHERE c.depth < 100
+		)
+		SELECT t.* FROM chain c
+		JOIN tickets t ON t.id = c.id
+		ORDER BY c.depth`
+
+	err := r.db.SelectContext(ctx, &tickets, query, id)
+	if err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
+// GetDescendants returns all tickets below given one in hierarchy
+func (r *PgRepository) GetDescendants(ctx context.Context, id int64) ([]Ticket, error) {
+	var tickets []Ticket
+	query := `
+		WITH RECURSIVE subtree AS (
+			SELECT id FROM tickets WHERE parent_id = $1
+			UNION
+			SELECT t.id FROM tickets t JOIN subtree s ON t.parent_id = s.id
+		)
+		SELECT t.* FROM tickets t
+		JOIN subtree s ON s.id = t.id
+		ORDER BY t.created_at`
+
+	err := r.db.SelectContext(ctx, &tickets, query, id)
+	if err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
+// SumChildSubtrees sums descendants of every direct child of ticket, grouped by child and status
+func (r *PgRepository) SumChildSubtrees(ctx context.Context, parentID int64) ([]SubtreeTotals, error) {
+	var totals []SubtreeTotals
+	query := `
+		WITH RECURSIVE subtree AS (
+			SELECT id, id AS child_id FROM tickets WHERE parent_id = $1
+			UNION
+			SELECT t.id, s.child_id FROM tickets t JOIN subtree s ON t.parent_id = s.id
+		)
+		SELECT s.child_id, t.status, COUNT(*) AS count,
+			COALESCE(SUM(t.story_points), 0) AS story_points,
+			COALESCE(SUM(t.original_estimate_minutes), 0) AS original_estimate_minutes,
+			COALESCE(SUM(t.remaining_estimate_minutes), 0) AS remaining_estimate_minutes,
+			COALESCE(SUM(t.time_spent_minutes), 0) AS time_spent_minutes
+		FROM subtree s
+		JOIN tickets t ON t.id = s.id
+		WHERE s.id <> s.child_id
+		GROUP BY s.child_id, t.status`
+
+	err := r.db.SelectContext(ctx, &totals, query, parentID)
+	if err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
+
+// CreateLink adds a link between tickets and writes ev to outbox
+func (r *PgRepository) CreateLink(ctx context.Context, link *TicketLink, ev event.Draft) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	query := `
 		INSERT INTO ticket_links (source_id, target_id, link_type)
 		VALUES (:source_id, :target_id, :link_type)
 		RETURNING *`
 
-	rows, err := r.db.NamedQueryContext(ctx, query, link)
+	rows, err := sqlx.NamedQueryContext(ctx, tx, query, link)
 	if err != nil {
 		return err
 	}
-	defer rows.Close()
+	if !rows.Next() {
+		rows.Close()
+		return errors.New("link creation failed")
+	}
+	if err := rows.StructScan(link); err != nil {
+		rows.Close()
+		return err
+	}
+	rows.Close()
 
-	if rows.Next() {
-		return rows.StructScan(link)
+	if err := event.Write(ctx, tx, ev); err != nil {
+		return err
 	}
-	return errors.New("link creation failed")
+
+	return tx.Commit()
 }
 
-// DeleteLink removes a link
-func (r *PgRepository) DeleteLink(ctx context.Context, linkID int64) error {
+// GetLinkByID finds link by its id
+func (r *PgRepository) GetLinkByID(ctx context.Context, linkID int64) (*TicketLink, error) {
+	var link TicketLink
+	err := r.db.GetContext(ctx, &link, `SELECT * FROM ticket_links WHERE id = $1`, linkID)
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// DeleteLink removes a link and writes ev to outbox
+func (r *PgRepository) DeleteLink(ctx context.Context, linkID int64, ev event.Draft) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	query := `DELETE FROM ticket_links WHERE id = $1`
-	result, err := r.db.ExecContext(ctx, query, linkID)
+	result, err := tx.ExecContext(ctx, query, linkID)
 	if err != nil {
 		return err
 	}
@@ -149,7 +620,11 @@ func (r *PgRepository) DeleteLink(ctx context.Context, linkID int64) error {
 	if rows == 0 {
 		return errors.New("link not found")
 	}
-	return nil
+	if err := event.Write(ctx, tx, ev); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 // GetLinksByProjectID returns all links where the source ticket belongs to the given project
@@ -166,3 +641,123 @@ func (r *PgRepository) GetLinksByProjectID(ctx context.Context, projectID int64)
 	}
 	return links, nil
 }
+
+// GetLabelsByProjectID returns labels of all tickets in project
+func (r *PgRepository) GetLabelsByProjectID(ctx context.Context, projectID int64) ([]TicketLabel, error) {
+	var labels []TicketLabel
+	query := `
+		SELECT tl.ticket_id, l.name FROM ticket_labels tl
+		JOIN labels l ON l.id = tl.label_id
+		JOIN tickets t ON t.id = tl.ticket_id
+		WHERE t.project_id = $1
+	`
+	err := r.db.SelectContext(ctx, &labels, query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+// ListChanges returns change log of ticket, newest first
+func (r *PgRepository) ListChanges(ctx context.Context, ticketID int64) ([]Change, error) {
+	var changes []Change
+	query := `SELECT * FROM ticket_changes WHERE ticket_id = $1 ORDER BY changed_at DESC, id DESC`
+
+	err := r.db.SelectContext(ctx, &changes, query, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// insertChanges writes change log rows inside transaction
+func insertChanges(ctx context.Context, tx *sqlx.Tx, changes []Change) error {
+	query := `
+		INSERT INTO ticket_changes (ticket_id, field, old_value, new_value, changed_by)
+		VALUES (:ticket_id, :field, :old_value, :new_value, :changed_by)`
+	for _, c := range changes {
+		if _, err := tx.NamedExecContext(ctx, query, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListDueBefore returns unresolved tickets of project with due date before date, earliest first
+func (r *PgRepository) ListDueBefore(ctx context.Context, projectID int64, date Date) ([]Ticket, error) {
+	var tickets []Ticket
+	query := `
+		SELECT * FROM tickets
+		WHERE project_id = $1 AND due_date < $2 AND resolved_at IS NULL
+		ORDER BY due_date, id`
+
+	err := r.db.SelectContext(ctx, &tickets, query, projectID, date)
+	if err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
+// AddWatchers makes users watch ticket inside tx, nil ids are skipped
+func AddWatchers(ctx context.Context, tx sqlx.ExecerContext, ticketID int64, userIDs ...*int64) error {
+	var ids []int64
+	for _, id := range userIDs {
+		if id != nil {
+			ids = append(ids, *id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO ticket_watchers (ticket_id, user_id)
+		SELECT $1, unnest($2::bigint[])
+		ON CONFLICT DO NOTHING`
+	_, err := tx.ExecContext(ctx, query, ticketID, pq.Array(ids))
+	return err
+}
+
+// ListWatchers returns watchers of ticket in order they started watching
+func (r *PgRepository) ListWatchers(ctx context.Context, ticketID int64) ([]Watcher, error) {
+	var watchers []Watcher
+	query := `
+		SELECT w.user_id, u.username, w.created_at
+		FROM ticket_watchers w
+		JOIN users u ON u.id = w.user_id
+		WHERE w.ticket_id = $1
+		ORDER BY w.created_at, w.user_id`
+	if err := r.db.SelectContext(ctx, &watchers, query, ticketID); err != nil {
+		return nil, err
+	}
+	return watchers, nil
+}
+
+// AddWatcher makes user watch ticket
+func (r *PgRepository) AddWatcher(ctx context.Context, ticketID, userID int64) error {
+	return AddWatchers(ctx, r.db, ticketID, &userID)
+}
+
+// RemoveWatcher stops user watching ticket
+func (r *PgRepository) RemoveWatcher(ctx context.Context, ticketID, userID int64) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM ticket_watchers WHERE ticket_id = $1 AND user_id = $2`, ticketID, userID)
+	return err
+}
+
+// WatchedAmong tells which of tickets user watches
+func (r *PgRepository) WatchedAmong(ctx context.Context, userID int64, ticketIDs []int64) (map[int64]bool, error) {
+	watched := make(map[int64]bool)
+	if len(ticketIDs) == 0 {
+		return watched, nil
+	}
+
+	var ids []int64
+	query := `SELECT ticket_id FROM ticket_watchers WHERE user_id = $1 AND ticket_id = ANY($2)`
+	if err := r.db.SelectContext(ctx, &ids, query, userID, pq.Array(ticketIDs)); err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		watched[id] = true
+	}
+	return watched, nil
+}
@@ -3,6 +3,9 @@ package ticket
 import (
 	"context"
 
+	"github.com/antonovs105/project-management-system-go/internal/event"
+	"github.com/antonovs105/project-management-system-go/internal/issuetype"
+	"github.com/antonovs105/project-management-system-go/internal/pagination"
 	"github.com/antonovs105/project-management-system-go/internal/project"
 	"github.com/stretchr/testify/mock"
 )
@@ -12,8 +15,8 @@ type MockRepository struct {
 	mock.Mock
 }
 
-func (m *MockRepository) Create(ctx context.Context, ticket *Ticket) error {
-	args := m.Called(ctx, ticket)
+func (m *MockRepository) Create(ctx context.Context, ticket *Ticket, ev event.Draft) error {
+	args := m.Called(ctx, ticket, ev)
 	return args.Error(0)
 }
 
@@ -25,6 +28,30 @@ func (m *MockRepository) ListByProjectID(ctx context.Context, projectID int64) (
 	return args.Get(0).([]Ticket), args.Error(1)
 }
 
+func (m *MockRepository) ListPage(ctx context.Context, projectID int64, filter ListFilter, page pagination.Params) ([]Ticket, int, error) {
+	args := m.Called(ctx, projectID, filter, page)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]Ticket), args.Int(1), args.Error(2)
+}
+
+func (m *MockRepository) ListForUser(ctx context.Context, userID int64, filter MyTicketsFilter, page pagination.Params) ([]Ticket, int, error) {
+	args := m.Called(ctx, userID, filter, page)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]Ticket), args.Int(1), args.Error(2)
+}
+
+func (m *MockRepository) CountForUser(ctx context.Context, userID int64, filter MyTicketsFilter, group string) ([]GroupCount, error) {
+	args := m.Called(ctx, userID, filter, group)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]GroupCount), args.Error(1)
+}
+
 func (m *MockRepository) GetByID(ctx context.Context, id int64) (*Ticket, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -33,23 +60,81 @@ func (m *MockRepository) GetByID(ctx context.Context, id int64) (*Ticket, error)
 	return args.Get(0).(*Ticket), args.Error(1)
 }
 
-func (m *MockRepository) Update(ctx context.Context, ticket *Ticket) error {
-	args := m.Called(ctx, ticket)
+func (m *MockRepository) GetByKey(ctx context.Context, key string) (*Ticket, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Ticket), args.Error(1)
+}
+
+func (m *MockRepository) Update(ctx context.Context, ticket *Ticket, changes []Change, ev event.Draft) error {
+	args := m.Called(ctx, ticket, changes, ev)
 	return args.Error(0)
 }
 
-func (m *MockRepository) Delete(ctx context.Context, id int64) error {
-	args := m.Called(ctx, id)
+func (m *MockRepository) Delete(ctx context.Context, id int64, ev event.Draft) error {
+	args := m.Called(ctx, id, ev)
+	return args.Error(0)
+}
+
+func (m *MockRepository) DeleteTree(ctx context.Context, id int64, ev event.Draft) error {
+	args := m.Called(ctx, id, ev)
 	return args.Error(0)
 }
 
-func (m *MockRepository) CreateLink(ctx context.Context, link *TicketLink) error {
-	args := m.Called(ctx, link)
+func (m *MockRepository) DeleteAndReparent(ctx context.Context, id int64, newParentID *int64, ev event.Draft) error {
+	args := m.Called(ctx, id, newParentID, ev)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ListChildren(ctx context.Context, parentID int64) ([]Ticket, error) {
+	args := m.Called(ctx, parentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Ticket), args.Error(1)
+}
+
+func (m *MockRepository) GetAncestors(ctx context.Context, id int64) ([]Ticket, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Ticket), args.Error(1)
+}
+
+func (m *MockRepository) GetDescendants(ctx context.Context, id int64) ([]Ticket, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Ticket), args.Error(1)
+}
+
+func (m *MockRepository) SumChildSubtrees(ctx context.Context, parentID int64) ([]SubtreeTotals, error) {
+	args := m.Called(ctx, parentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]SubtreeTotals), args.Error(1)
+}
+
+func (m *MockRepository) CreateLink(ctx context.Context, link *TicketLink, ev event.Draft) error {
+	args := m.Called(ctx, link, ev)
 	return args.Error(0)
 }
 
-func (m *MockRepository) DeleteLink(ctx context.Context, linkID int64) error {
+func (m *MockRepository) GetLinkByID(ctx context.Context, linkID int64) (*TicketLink, error) {
 	args := m.Called(ctx, linkID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*TicketLink), args.Error(1)
+}
+
+func (m *MockRepository) DeleteLink(ctx context.Context, linkID int64, ev event.Draft) error {
+	args := m.Called(ctx, linkID, ev)
 	return args.Error(0)
 }
 
@@ -61,6 +146,56 @@ func (m *MockRepository) GetLinksByProjectID(ctx context.Context, projectID int6
 	return args.Get(0).([]TicketLink), args.Error(1)
 }
 
+func (m *MockRepository) GetLabelsByProjectID(ctx context.Context, projectID int64) ([]TicketLabel, error) {
+	args := m.Called(ctx, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]TicketLabel), args.Error(1)
+}
+
+func (m *MockRepository) ListChanges(ctx context.Context, ticketID int64) ([]Change, error) {
+	args := m.Called(ctx, ticketID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Change), args.Error(1)
+}
+
+func (m *MockRepository) ListDueBefore(ctx context.Context, projectID int64, date Date) ([]Ticket, error) {
+	args := m.Called(ctx, projectID, date)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Ticket), args.Error(1)
+}
+
+func (m *MockRepository) ListWatchers(ctx context.Context, ticketID int64) ([]Watcher, error) {
+	args := m.Called(ctx, ticketID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Watcher), args.Error(1)
+}
+
+func (m *MockRepository) AddWatcher(ctx context.Context, ticketID, userID int64) error {
+	args := m.Called(ctx, ticketID, userID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) RemoveWatcher(ctx context.Context, ticketID, userID int64) error {
+	args := m.Called(ctx, ticketID, userID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) WatchedAmong(ctx context.Context, userID int64, ticketIDs []int64) (map[int64]bool, error) {
+	args := m.Called(ctx, userID, ticketIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int64]bool), args.Error(1)
+}
+
 // MockProjectChecker
 type MockProjectChecker struct {
 	mock.Mock
@@ -73,3 +208,10 @@ func (m *MockProjectChecker) GetProjectByID(ctx context.Context, projectID, user
 	}
 	return args.Get(0).(*project.Project), args.Error(1)
 }
+
+// StubSchemeProvider always returns default issue type scheme
+type StubSchemeProvider struct{}
+
+func (StubSchemeProvider) SchemeForProject(ctx context.Context, projectID int64) (*issuetype.Scheme, error) {
+	return issuetype.DefaultScheme(), nil
+}
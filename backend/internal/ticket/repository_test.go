@@ -0,0 +1,33 @@
+package ticket
+
+import (
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserConditions(t *testing.T) {
+	const member = "project_id IN (SELECT project_id FROM project_members WHERE user_id = $1)"
+	const watching = "EXISTS (SELECT 1 FROM ticket_watchers w WHERE w.ticket_id = tickets.id AND w.user_id = $1)"
+
+	t.Run("NoRelationsMeansAny", func(t *testing.T) {
+		where, args := userConditions(7, MyTicketsFilter{})
+
+		assert.Equal(t, member+" AND (assignee_id = $1 OR reporter_id = $1 OR "+watching+")", where)
+		assert.Equal(t, []any{int64(7)}, args)
+	})
+
+	t.Run("RequestedRelations", func(t *testing.T) {
+		where, _ := userConditions(7, MyTicketsFilter{Relations: []string{RelationReported}})
+
+		assert.Equal(t, member+" AND (reporter_id = $1)", where)
+	})
+
+	t.Run("Projects", func(t *testing.T) {
+		where, args := userConditions(7, MyTicketsFilter{Relations: []string{RelationAssigned}, ProjectIDs: []int64{3}})
+
+		assert.Equal(t, member+" AND (assignee_id = $1) AND project_id = ANY($2)", where)
+		assert.Equal(t, []any{int64(7), pq.Array([]int64{3})}, args)
+	})
+}
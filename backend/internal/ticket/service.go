@@ -3,7 +3,11 @@ package ticket
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
+	"github.com/antonovs105/project-management-system-go/internal/event"
+	"github.com/antonovs105/project-management-system-go/internal/issuetype"
 	"github.com/antonovs105/project-management-system-go/internal/project"
 )
 
@@ -12,15 +16,22 @@ type ProjectChecker interface {
 	GetProjectByID(ctx context.Context, projectID, userID int64) (*project.Project, error)
 }
 
+// SchemeProvider interface
+type SchemeProvider interface {
+	SchemeForProject(ctx context.Context, projectID int64) (*issuetype.Scheme, error)
+}
+
 type Service struct {
 	repo           Repository
 	projectService ProjectChecker
+	schemes        SchemeProvider
 }
 
-func NewService(repo Repository, projectService ProjectChecker) *Service {
+func NewService(repo Repository, projectService ProjectChecker, schemes SchemeProvider) *Service {
 	return &Service{
 		repo:           repo,
 		projectService: projectService,
+		schemes:        schemes,
 	}
 }
 
@@ -32,13 +43,11 @@ type CreateTicketRequest struct {
 	Type        string
 	ParentID    *int64
 	AssigneeID  *int64
-}
-
-// Hierarchy ranks
-var ticketRanks = map[string]int{
-	"epic":    3,
-	"task":    2,
-	"subtask": 1,
+	StoryPoints *float64
+	// OriginalEstimate in minutes, remaining estimate starts equal to it
+	OriginalEstimate *int64
+	StartDate        *Date
+	DueDate          *Date
 }
 
 // CreateTicket logic for ticket creation
@@ -49,39 +58,19 @@ func (s *Service) CreateTicket(ctx context.Context, req CreateTicketRequest, pro
 		return nil, err
 	}
 
-	// Validate Ticket Type
-	rank, ok := ticketRanks[req.Type]
-	if !ok {
-		if req.Type == "" {
-			req.Type = "task"
-			rank = 2
-		} else {
-			return nil, errors.New("invalid ticket type")
-		}
+	scheme, err := s.schemes.SchemeForProject(ctx, projectID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Validate Hierarchy
-	if req.ParentID != nil {
-		parent, err := s.repo.GetByID(ctx, *req.ParentID)
-		if err != nil {
-			return nil, errors.New("parent ticket not found")
-		}
-		if parent.ProjectID != projectID {
-			return nil, errors.New("parent ticket must be in the same project")
-		}
-
-		parentRank, ok := ticketRanks[parent.Type]
-		if !ok {
-			parentRank = 2
-		}
+	if req.Type == "" {
+		req.Type = scheme.DefaultType()
+	}
 
-		if parentRank <= rank {
-			return nil, errors.New("invalid hierarchy: parent must be of higher rank (Epic > Task > Subtask)")
-		}
-	} else {
-		if req.Type == "subtask" {
-			return nil, errors.New("subtask must have a parent")
-		}
+	// Validate type and hierarchy
+	err = s.validateHierarchy(ctx, scheme, &Ticket{ProjectID: projectID}, req.Type, req.ParentID)
+	if err != nil {
+		return nil, err
 	}
 
 	// TODO: check is AssigneeID a project member
@@ -96,9 +85,23 @@ func (s *Service) CreateTicket(ctx context.Context, req CreateTicketRequest, pro
 		ProjectID:   projectID,
 		ReporterID:  reporterID,
 		AssigneeID:  req.AssigneeID,
+		StoryPoints: req.StoryPoints,
+
+		OriginalEstimate:  req.OriginalEstimate,
+		RemainingEstimate: req.OriginalEstimate,
+		StartDate:         req.StartDate,
+		DueDate:           req.DueDate,
+	}
+
+	if err := validateEstimates(t); err != nil {
+		return nil, err
+	}
+	if err := validateDates(t); err != nil {
+		return nil, err
 	}
 
-	err = s.repo.Create(ctx, t)
+	ev := event.Draft{ProjectID: projectID, ActorID: reporterID, Type: event.TicketCreated, Data: t}
+	err = s.repo.Create(ctx, t, ev)
 	if err != nil {
 		return nil, err
 	}
@@ -106,28 +109,64 @@ func (s *Service) CreateTicket(ctx context.Context, req CreateTicketRequest, pro
 	return t, nil
 }
 
-// ListTicketsInProject logic for ticket list
-func (s *Service) ListTicketsInProject(ctx context.Context, projectID, userID int64) ([]Ticket, error) {
+// GetTicketByID gogic to get single ticket
+func (s *Service) GetTicketByID(ctx context.Context, ticketID, userID int64) (*Ticket, error) {
+	ticket, err := s.repo.GetByID(ctx, ticketID)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+
 	// check access
-	_, err := s.projectService.GetProjectByID(ctx, projectID, userID)
+	_, err = s.projectService.GetProjectByID(ctx, ticket.ProjectID, userID)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w or access denied", ErrNotFound)
 	}
 
-	return s.repo.ListByProjectID(ctx, projectID)
+	return ticket, nil
 }
 
-// GetTicketByID gogic to get single ticket
-func (s *Service) GetTicketByID(ctx context.Context, ticketID, userID int64) (*Ticket, error) {
-	ticket, err := s.repo.GetByID(ctx, ticketID)
+// MaxStoryPoints is upper bound of ticket story points
+const MaxStoryPoints = 1000
+
+// validateEstimates rejects negative and out of range effort values
+func validateEstimates(t *Ticket) error {
+	if t.StoryPoints != nil {
+		if *t.StoryPoints < 0 {
+			return errors.New("story points can't be negative")
+		}
+		// NaN fails every comparison, so range is checked positively
+		if !(*t.StoryPoints <= MaxStoryPoints) {
+			return fmt.Errorf("story points can't be more than %d", MaxStoryPoints)
+		}
+	}
+	if t.OriginalEstimate != nil && *t.OriginalEstimate < 0 {
+		return errors.New("original estimate can't be negative")
+	}
+	if t.RemainingEstimate != nil && *t.RemainingEstimate < 0 {
+		return errors.New("remaining estimate can't be negative")
+	}
+	return nil
+}
+
+// validateDates checks that ticket is not due before it starts
+func validateDates(t *Ticket) error {
+	if t.StartDate != nil && t.DueDate != nil && t.DueDate.Before(t.StartDate.Time) {
+		return errors.New("due date can't be before start date")
+	}
+	return nil
+}
+
+// GetTicketByKey logic to get single ticket by key like PMS-123
+func (s *Service) GetTicketByKey(ctx context.Context, key string, userID int64) (*Ticket, error) {
+	ticket, err := s.repo.GetByKey(ctx, key)
 	if err != nil {
-		return nil, errors.New("ticket not found")
+		return nil, ErrNotFound
 	}
 
 	// check access
 	_, err = s.projectService.GetProjectByID(ctx, ticket.ProjectID, userID)
 	if err != nil {
-		return nil, errors.New("ticket not found or access denied")
+		return nil, fmt.Errorf("%w or access denied", ErrNotFound)
 	}
 
 	return ticket, nil
@@ -142,6 +181,13 @@ type UpdateTicketRequest struct {
 	Type        *string `json:"type"`
 	ParentID    **int64 `json:"parent_id"`
 	AssigneeID  **int64 `json:"assignee_id"`
+
+	StoryPoints       **float64 `json:"story_points"`
+	OriginalEstimate  **int64   `json:"original_estimate_minutes"`
+	RemainingEstimate **int64   `json:"remaining_estimate_minutes"`
+
+	StartDate **Date `json:"start_date"`
+	DueDate   **Date `json:"due_date"`
 }
 
 // UpdateTicket logic for update
@@ -165,36 +211,25 @@ func (s *Service) UpdateTicket(ctx context.Context, req UpdateTicketRequest, tic
 
 	// Hierarchy Validation if Type or ParentID changes
 	if req.Type != nil || req.ParentID != nil {
-		rank, ok := ticketRanks[newType]
-		if !ok {
-			return errors.New("invalid ticket type")
+		scheme, err := s.schemes.SchemeForProject(ctx, ticketToUpdate.ProjectID)
+		if err != nil {
+			return err
 		}
 
-		if newParentID != nil {
-			parent, err := s.repo.GetByID(ctx, *newParentID)
-			if err != nil {
-				return errors.New("parent ticket not found")
-			}
-			if parent.ProjectID != ticketToUpdate.ProjectID {
-				return errors.New("parent ticket must be in the same project")
-			}
-			if parent.ID == ticketToUpdate.ID {
-				return errors.New("cannot be own parent")
-			}
+		if err := s.validateHierarchy(ctx, scheme, ticketToUpdate, newType, newParentID); err != nil {
+			return err
+		}
 
-			// parent rank check
-			parentRank := ticketRanks[parent.Type]
-			if parentRank <= rank {
-				return errors.New("invalid hierarchy: parent must be of higher rank")
-			}
-		} else {
-			if newType == "subtask" {
-				return errors.New("subtask must have a parent")
+		// Children must still fit if type changes
+		if newType != ticketToUpdate.Type {
+			if err := s.validateChildren(ctx, scheme, ticketToUpdate.ID, newType); err != nil {
+				return err
 			}
 		}
 	}
 
-	// TODO: add more advanced check
+	// keep old values for change log
+	before := *ticketToUpdate
 
 	// update rows
 	if req.Title != nil {
@@ -205,6 +240,7 @@ func (s *Service) UpdateTicket(ctx context.Context, req UpdateTicketRequest, tic
 	}
 	if req.Status != nil {
 		ticketToUpdate.Status = *req.Status
+		applyStatusTransition(ticketToUpdate, before.Status, time.Now())
 	}
 	if req.Priority != nil {
 		ticketToUpdate.Priority = *req.Priority
@@ -219,19 +255,41 @@ func (s *Service) UpdateTicket(ctx context.Context, req UpdateTicketRequest, tic
 	if req.AssigneeID != nil {
 		ticketToUpdate.AssigneeID = *req.AssigneeID
 	}
+	if req.StoryPoints != nil {
+		ticketToUpdate.StoryPoints = *req.StoryPoints
+	}
+	if req.OriginalEstimate != nil {
+		ticketToUpdate.OriginalEstimate = *req.OriginalEstimate
+		// first estimate also sets remaining work
+		if ticketToUpdate.RemainingEstimate == nil && req.RemainingEstimate == nil {
+			ticketToUpdate.RemainingEstimate = *req.OriginalEstimate
+		}
+	}
+	if req.RemainingEstimate != nil {
+		ticketToUpdate.RemainingEstimate = *req.RemainingEstimate
+	}
+	if req.StartDate != nil {
+		ticketToUpdate.StartDate = *req.StartDate
+	}
+	if req.DueDate != nil {
+		ticketToUpdate.DueDate = *req.DueDate
+	}
 
-	return s.repo.Update(ctx, ticketToUpdate)
-}
-
-// DeleteTicket logic for deleting
-func (s *Service) DeleteTicket(ctx context.Context, ticketID, userID int64) error {
-	// check access
-	_, err := s.GetTicketByID(ctx, ticketID, userID)
-	if err != nil {
+	if err := validateEstimates(ticketToUpdate); err != nil {
+		return err
+	}
+	if err := validateDates(ticketToUpdate); err != nil {
 		return err
 	}
 
-	return s.repo.Delete(ctx, ticketID)
+	changes := diffTickets(&before, ticketToUpdate, userID)
+	ev := event.Draft{
+		ProjectID: ticketToUpdate.ProjectID,
+		ActorID:   userID,
+		Type:      event.TicketUpdated,
+		Data:      TicketUpdatedData{Ticket: ticketToUpdate, Changes: changes},
+	}
+	return s.repo.Update(ctx, ticketToUpdate, changes, ev)
 }
 
 // AddTicketLink adds a link and checks for cycles
@@ -279,7 +337,8 @@ func (s *Service) AddTicketLink(ctx context.Context, sourceID, targetID int64, l
 		LinkType: linkType,
 	}
 
-	return s.repo.CreateLink(ctx, link)
+	ev := event.Draft{ProjectID: source.ProjectID, ActorID: userID, Type: event.LinkAdded, Data: link}
+	return s.repo.CreateLink(ctx, link, ev)
 }
 
 // hasPath checks if there is a path from start to end using BFS
@@ -306,86 +365,17 @@ func hasPath(adj map[int64][]int64, start, end int64) bool {
 	return false
 }
 
-// RemoveTicketLink removes a link
+// RemoveTicketLink removes a link, user must have access to its tickets
 func (s *Service) RemoveTicketLink(ctx context.Context, linkID, projectID, userID int64) error {
-
-	err := s.repo.DeleteLink(ctx, linkID)
-	return err
-}
-
-// GraphNode DTO
-type GraphNode struct {
-	ID       int64  `json:"id"`
-	Label    string `json:"label"`
-	Type     string `json:"type"`
-	Status   string `json:"status"`
-	Priority string `json:"priority"`
-	Group    string `json:"group"`
-}
-
-// GraphLink DTO
-type GraphLink struct {
-	Source int64  `json:"source"`
-	Target int64  `json:"target"`
-	Type   string `json:"type"`
-}
-
-// GraphResponse DTO
-type GraphResponse struct {
-	Nodes []GraphNode `json:"nodes"`
-	Links []GraphLink `json:"links"`
-}
-
-// GetTicketGraph returns nodes and links for react-force-graph
-func (s *Service) GetTicketGraph(ctx context.Context, projectID, userID int64) (*GraphResponse, error) {
-	// check access
-	_, err := s.projectService.GetProjectByID(ctx, projectID, userID)
+	link, err := s.repo.GetLinkByID(ctx, linkID)
 	if err != nil {
-		return nil, err
+		return errors.New("link not found")
 	}
-
-	tickets, err := s.repo.ListByProjectID(ctx, projectID)
+	source, err := s.GetTicketByID(ctx, link.SourceID, userID)
 	if err != nil {
-		return nil, err
-	}
-
-	links, err := s.repo.GetLinksByProjectID(ctx, projectID)
-	if err != nil {
-		return nil, err
-	}
-
-	response := &GraphResponse{
-		Nodes: make([]GraphNode, 0, len(tickets)),
-		Links: make([]GraphLink, 0, len(links)+len(tickets)),
-	}
-
-	for _, t := range tickets {
-		response.Nodes = append(response.Nodes, GraphNode{
-			ID:       t.ID,
-			Label:    t.Title,
-			Type:     t.Type,
-			Status:   t.Status,
-			Priority: t.Priority,
-			Group:    t.Type,
-		})
-
-		// Add implicit hierarchy links
-		if t.ParentID != nil {
-			response.Links = append(response.Links, GraphLink{
-				Source: *t.ParentID,
-				Target: t.ID,
-				Type:   "hierarchy",
-			})
-		}
-	}
-
-	for _, l := range links {
-		response.Links = append(response.Links, GraphLink{
-			Source: l.SourceID,
-			Target: l.TargetID,
-			Type:   l.LinkType,
-		})
+		return err
 	}
 
-	return response, nil
+	ev := event.Draft{ProjectID: source.ProjectID, ActorID: userID, Type: event.LinkRemoved, Data: link}
+	return s.repo.DeleteLink(ctx, linkID, ev)
 }
@@ -3,9 +3,12 @@ package ticket
 import (
 	"context"
 	"errors"
+	"math"
 	"testing"
 	"time"
 
+	"github.com/antonovs105/project-management-system-go/internal/event"
+	"github.com/antonovs105/project-management-system-go/internal/pagination"
 	"github.com/antonovs105/project-management-system-go/internal/project"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -14,7 +17,7 @@ import (
 func TestService_CreateTicket(t *testing.T) {
 	mockRepo := new(MockRepository)
 	mockProject := new(MockProjectChecker)
-	service := NewService(mockRepo, mockProject)
+	service := NewService(mockRepo, mockProject, StubSchemeProvider{})
 
 	ctx := context.Background()
 	projectID := int64(10)
@@ -28,7 +31,9 @@ func TestService_CreateTicket(t *testing.T) {
 
 	t.Run("Success", func(t *testing.T) {
 		mockProject.On("GetProjectByID", ctx, projectID, reporterID).Return(&project.Project{ID: projectID}, nil).Once()
-		mockRepo.On("Create", ctx, mock.AnythingOfType("*ticket.Ticket")).Return(nil).Run(func(args mock.Arguments) {
+		mockRepo.On("Create", ctx, mock.AnythingOfType("*ticket.Ticket"), mock.MatchedBy(func(ev event.Draft) bool {
+			return ev.ProjectID == projectID && ev.ActorID == reporterID && ev.Type == event.TicketCreated
+		})).Return(nil).Run(func(args mock.Arguments) {
 			ticket := args.Get(1).(*Ticket)
 			ticket.ID = 100
 		}).Once()
@@ -40,6 +45,9 @@ func TestService_CreateTicket(t *testing.T) {
 		assert.Equal(t, "task", ticket.Type)
 		mockRepo.AssertExpectations(t)
 		mockProject.AssertExpectations(t)
+		// event carries ticket filled by repository
+		ev := mockRepo.Calls[len(mockRepo.Calls)-1].Arguments.Get(2).(event.Draft)
+		assert.Same(t, ticket, ev.Data)
 	})
 
 	t.Run("InvalidType", func(t *testing.T) {
@@ -58,7 +66,7 @@ func TestService_CreateTicket(t *testing.T) {
 func TestService_GetTicketByID(t *testing.T) {
 	mockRepo := new(MockRepository)
 	mockProject := new(MockProjectChecker)
-	service := NewService(mockRepo, mockProject)
+	service := NewService(mockRepo, mockProject, StubSchemeProvider{})
 
 	ctx := context.Background()
 	ticketID := int64(100)
@@ -92,10 +100,144 @@ func TestService_GetTicketByID(t *testing.T) {
 	})
 }
 
+func TestService_GetTicketByKey(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockProject := new(MockProjectChecker)
+	service := NewService(mockRepo, mockProject, StubSchemeProvider{})
+
+	ctx := context.Background()
+	userID := int64(1)
+	expectedTicket := &Ticket{ID: 100, ProjectID: 10, Number: 7, Key: "PMS-7"}
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo.On("GetByKey", ctx, "PMS-7").Return(expectedTicket, nil).Once()
+		mockProject.On("GetProjectByID", ctx, int64(10), userID).Return(&project.Project{ID: 10}, nil).Once()
+
+		ticket, err := service.GetTicketByKey(ctx, "PMS-7", userID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedTicket, ticket)
+	})
+
+	t.Run("AccessDenied", func(t *testing.T) {
+		mockRepo.On("GetByKey", ctx, "PMS-7").Return(expectedTicket, nil).Once()
+		mockProject.On("GetProjectByID", ctx, int64(10), userID).Return(nil, errors.New("denied")).Once()
+
+		ticket, err := service.GetTicketByKey(ctx, "PMS-7", userID)
+
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Nil(t, ticket)
+	})
+}
+
+func TestService_UpdateTicket_Hierarchy(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockProject := new(MockProjectChecker)
+	service := NewService(mockRepo, mockProject, StubSchemeProvider{})
+
+	ctx := context.Background()
+	projectID := int64(10)
+	userID := int64(1)
+
+	t.Run("AncestorCycle", func(t *testing.T) {
+		// epic 1 -> task 2. Make epic 1 a subtask of its own child
+		epic1 := &Ticket{ID: 1, ProjectID: projectID, Type: "epic"}
+		task2 := &Ticket{ID: 2, ProjectID: projectID, Type: "task", ParentID: int64Ptr(1)}
+
+		mockRepo.On("GetByID", ctx, int64(1)).Return(epic1, nil).Once()
+		mockProject.On("GetProjectByID", ctx, projectID, userID).Return(&project.Project{}, nil).Once()
+		mockRepo.On("GetByID", ctx, int64(2)).Return(task2, nil).Once()
+		mockRepo.On("GetAncestors", ctx, int64(2)).Return([]Ticket{*epic1}, nil).Once()
+
+		newType := "subtask"
+		newParent := int64Ptr(2)
+		err := service.UpdateTicket(ctx, UpdateTicketRequest{Type: &newType, ParentID: &newParent}, 1, userID)
+
+		assert.ErrorIs(t, err, ErrInvalidHierarchy)
+		assert.Contains(t, err.Error(), "cycle detected")
+	})
+
+	t.Run("TypeChangeBreaksChildren", func(t *testing.T) {
+		epic1 := &Ticket{ID: 1, ProjectID: projectID, Type: "epic"}
+
+		mockRepo.On("GetByID", ctx, int64(1)).Return(epic1, nil).Once()
+		mockProject.On("GetProjectByID", ctx, projectID, userID).Return(&project.Project{}, nil).Once()
+		mockRepo.On("ListChildren", ctx, int64(1)).Return([]Ticket{{ID: 2, Type: "task"}}, nil).Once()
+
+		newType := "task"
+		err := service.UpdateTicket(ctx, UpdateTicketRequest{Type: &newType}, 1, userID)
+
+		assert.ErrorIs(t, err, ErrInvalidHierarchy)
+	})
+}
+
+func TestService_DeleteTicket(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockProject := new(MockProjectChecker)
+	service := NewService(mockRepo, mockProject, StubSchemeProvider{})
+
+	ctx := context.Background()
+	projectID := int64(10)
+	userID := int64(1)
+	task := &Ticket{ID: 2, ProjectID: projectID, Type: "task"}
+	children := []Ticket{{ID: 3, ProjectID: projectID, Type: "subtask", ParentID: int64Ptr(2)}}
+
+	expectTicket := func() {
+		mockRepo.On("GetByID", ctx, int64(2)).Return(task, nil).Once()
+		mockProject.On("GetProjectByID", ctx, projectID, userID).Return(&project.Project{}, nil).Once()
+		mockRepo.On("ListChildren", ctx, int64(2)).Return(children, nil).Once()
+	}
+
+	t.Run("ChildrenWithoutMode", func(t *testing.T) {
+		expectTicket()
+
+		err := service.DeleteTicket(ctx, DeleteTicketRequest{}, 2, userID)
+
+		assert.ErrorIs(t, err, ErrHasChildren)
+	})
+
+	t.Run("Cascade", func(t *testing.T) {
+		expectTicket()
+		mockRepo.On("GetDescendants", ctx, int64(2)).Return(children, nil).Once()
+		mockRepo.On("DeleteTree", ctx, int64(2), mock.MatchedBy(func(ev event.Draft) bool {
+			return ev.Type == event.TicketDeleted && assert.Equal(t, []int64{3}, ev.Data.(*TicketDeletedData).DeletedIDs)
+		})).Return(nil).Once()
+
+		err := service.DeleteTicket(ctx, DeleteTicketRequest{Mode: DeleteModeCascade}, 2, userID)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("OrphanSubtask", func(t *testing.T) {
+		expectTicket()
+
+		err := service.DeleteTicket(ctx, DeleteTicketRequest{Mode: DeleteModeOrphan}, 2, userID)
+
+		assert.ErrorIs(t, err, ErrInvalidHierarchy)
+	})
+
+	t.Run("Reparent", func(t *testing.T) {
+		expectTicket()
+		otherTask := &Ticket{ID: 4, ProjectID: projectID, Type: "task"}
+		mockRepo.On("GetByID", ctx, int64(4)).Return(otherTask, nil).Once()
+		mockRepo.On("GetAncestors", ctx, int64(4)).Return([]Ticket{}, nil).Twice()
+		mockRepo.On("DeleteAndReparent", ctx, int64(2), int64Ptr(4), mock.MatchedBy(func(ev event.Draft) bool {
+			data := ev.Data.(*TicketDeletedData)
+			return assert.Equal(t, []int64{3}, data.ReparentedIDs) && assert.Equal(t, int64Ptr(4), data.NewParentID)
+		})).Return(nil).Once()
+
+		err := service.DeleteTicket(ctx, DeleteTicketRequest{Mode: DeleteModeReparent, NewParentID: int64Ptr(4)}, 2, userID)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
 func TestService_AddTicketLink(t *testing.T) {
 	mockRepo := new(MockRepository)
 	mockProject := new(MockProjectChecker)
-	service := NewService(mockRepo, mockProject)
+	service := NewService(mockRepo, mockProject, StubSchemeProvider{})
 
 	ctx := context.Background()
 	projectID := int64(10)
@@ -119,7 +261,9 @@ func TestService_AddTicketLink(t *testing.T) {
 		mockRepo.On("GetLinksByProjectID", ctx, projectID).Return([]TicketLink{}, nil).Once()
 
 		// Mock CreateLink
-		mockRepo.On("CreateLink", ctx, mock.AnythingOfType("*ticket.TicketLink")).Return(nil).Once()
+		mockRepo.On("CreateLink", ctx, mock.AnythingOfType("*ticket.TicketLink"), mock.MatchedBy(func(ev event.Draft) bool {
+			return ev.ProjectID == projectID && ev.Type == event.LinkAdded
+		})).Return(nil).Once()
 
 		err := service.AddTicketLink(ctx, sourceID, targetID, "blocks", projectID, userID)
 
@@ -154,7 +298,7 @@ func TestService_AddTicketLink(t *testing.T) {
 func TestService_GetTicketGraph(t *testing.T) {
 	mockRepo := new(MockRepository)
 	mockProject := new(MockProjectChecker)
-	service := NewService(mockRepo, mockProject)
+	service := NewService(mockRepo, mockProject, StubSchemeProvider{})
 
 	ctx := context.Background()
 	projectID := int64(10)
@@ -170,14 +314,405 @@ func TestService_GetTicketGraph(t *testing.T) {
 		mockRepo.On("ListByProjectID", ctx, projectID).Return(tickets, nil).Once()
 		mockRepo.On("GetLinksByProjectID", ctx, projectID).Return([]TicketLink{}, nil).Once()
 
-		graph, err := service.GetTicketGraph(ctx, projectID, userID)
+		graph, err := service.GetTicketGraph(ctx, projectID, userID, GraphFilter{})
 
 		assert.NoError(t, err)
 		assert.NotNil(t, graph)
 		assert.Len(t, graph.Nodes, 2)
 		assert.Len(t, graph.Links, 1) // 1 hierarchy link
 		assert.Equal(t, "hierarchy", graph.Links[0].Type)
+		assert.Equal(t, 0, graph.HiddenNodes)
+	})
+
+	// Epic(1) -> Task(2) -> Subtask(3), Task(2) blocks Task(4), Task(4) done
+	tickets := []Ticket{
+		{ID: 1, Title: "Epic", Type: "epic", Status: "new"},
+		{ID: 2, Title: "Task", Type: "task", Status: "in_progress", ParentID: int64Ptr(1)},
+		{ID: 3, Title: "Subtask", Type: "subtask", Status: "new", ParentID: int64Ptr(2)},
+		{ID: 4, Title: "Other", Type: "task", Status: "done"},
+	}
+	links := []TicketLink{{ID: 1, SourceID: 2, TargetID: 4, LinkType: "blocks"}}
+
+	t.Run("HideDone", func(t *testing.T) {
+		mockProject.On("GetProjectByID", ctx, projectID, userID).Return(&project.Project{}, nil).Once()
+		mockRepo.On("ListByProjectID", ctx, projectID).Return(tickets, nil).Once()
+		mockRepo.On("GetLinksByProjectID", ctx, projectID).Return(links, nil).Once()
+
+		graph, err := service.GetTicketGraph(ctx, projectID, userID, GraphFilter{HideDone: true})
+
+		assert.NoError(t, err)
+		assert.Len(t, graph.Nodes, 3)
+		assert.Len(t, graph.Links, 2)
+		assert.Equal(t, 1, graph.HiddenNodes)
+		assert.Equal(t, 1, graph.Nodes[1].HiddenNeighbors) // task 2 has hidden task 4
+	})
+
+	t.Run("LabelFilter", func(t *testing.T) {
+		mockProject.On("GetProjectByID", ctx, projectID, userID).Return(&project.Project{}, nil).Once()
+		mockRepo.On("ListByProjectID", ctx, projectID).Return(tickets, nil).Once()
+		mockRepo.On("GetLinksByProjectID", ctx, projectID).Return(links, nil).Once()
+		mockRepo.On("GetLabelsByProjectID", ctx, projectID).Return([]TicketLabel{{TicketID: 3, Name: "backend"}}, nil).Once()
+
+		graph, err := service.GetTicketGraph(ctx, projectID, userID, GraphFilter{Labels: []string{"backend"}})
+
+		assert.NoError(t, err)
+		assert.Len(t, graph.Nodes, 1)
+		assert.Equal(t, int64(3), graph.Nodes[0].ID)
+		assert.Equal(t, 3, graph.HiddenNodes)
+	})
+
+	t.Run("Focus", func(t *testing.T) {
+		mockProject.On("GetProjectByID", ctx, projectID, userID).Return(&project.Project{}, nil).Once()
+		mockRepo.On("ListByProjectID", ctx, projectID).Return(tickets, nil).Once()
+		mockRepo.On("GetLinksByProjectID", ctx, projectID).Return(links, nil).Once()
+
+		graph, err := service.GetTicketGraph(ctx, projectID, userID, GraphFilter{RootID: int64Ptr(1), Depth: 1})
+
+		assert.NoError(t, err)
+		assert.Len(t, graph.Nodes, 2) // epic and its task
+		assert.Len(t, graph.Links, 1)
+		assert.Equal(t, 2, graph.HiddenNodes)
+		assert.Equal(t, 2, graph.Nodes[1].HiddenNeighbors) // subtask and blocked task
+	})
+
+	t.Run("FocusRootNotInProject", func(t *testing.T) {
+		mockProject.On("GetProjectByID", ctx, projectID, userID).Return(&project.Project{}, nil).Once()
+		mockRepo.On("ListByProjectID", ctx, projectID).Return(tickets, nil).Once()
+		mockRepo.On("GetLinksByProjectID", ctx, projectID).Return(links, nil).Once()
+
+		graph, err := service.GetTicketGraph(ctx, projectID, userID, GraphFilter{RootID: int64Ptr(999)})
+
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Nil(t, graph)
 	})
 }
 
 func int64Ptr(i int64) *int64 { return &i }
+
+func TestService_GetTicketTree(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockProject := new(MockProjectChecker)
+	service := NewService(mockRepo, mockProject, StubSchemeProvider{})
+
+	ctx := context.Background()
+	projectID := int64(10)
+	userID := int64(1)
+
+	epic := &Ticket{ID: 1, ProjectID: projectID, Type: "epic", Status: "in_progress"}
+	descendants := []Ticket{
+		{ID: 2, ProjectID: projectID, Type: "task", Status: "done", ParentID: int64Ptr(1)},
+		{ID: 3, ProjectID: projectID, Type: "task", Status: "review", ParentID: int64Ptr(1)},
+		{ID: 4, ProjectID: projectID, Type: "subtask", Status: "new", ParentID: int64Ptr(3)},
+		{ID: 5, ProjectID: projectID, Type: "subtask", Status: "done", ParentID: int64Ptr(3)},
+	}
+
+	t.Run("Rollup", func(t *testing.T) {
+		mockRepo.On("GetByID", ctx, int64(1)).Return(epic, nil).Once()
+		mockProject.On("GetProjectByID", ctx, projectID, userID).Return(&project.Project{}, nil).Once()
+		mockRepo.On("GetDescendants", ctx, int64(1)).Return(descendants, nil).Once()
+
+		tree, err := service.GetTicketTree(ctx, 1, userID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, Rollup{Total: 4, Todo: 1, InProgress: 1, Done: 2, PercentComplete: 50}, tree.Rollup)
+		assert.Len(t, tree.Children, 2)
+		assert.Equal(t, 2, tree.Children[1].Rollup.Total)
+		assert.Equal(t, float64(50), tree.Children[1].Rollup.PercentComplete)
+	})
+
+	t.Run("Children", func(t *testing.T) {
+		mockRepo.On("GetByID", ctx, int64(1)).Return(epic, nil).Once()
+		mockProject.On("GetProjectByID", ctx, projectID, userID).Return(&project.Project{}, nil).Once()
+		mockRepo.On("ListChildren", ctx, int64(1)).Return(descendants[:2], nil).Once()
+		mockRepo.On("SumChildSubtrees", ctx, int64(1)).Return([]SubtreeTotals{
+			{ChildID: 3, Status: "new", Count: 1, StoryPoints: 2},
+			{ChildID: 3, Status: "done", Count: 1, StoryPoints: 3},
+		}, nil).Once()
+
+		children, err := service.GetTicketChildren(ctx, 1, userID)
+
+		assert.NoError(t, err)
+		assert.Len(t, children, 2)
+		assert.Equal(t, Rollup{}, children[0].Rollup)
+		assert.Nil(t, children[1].Children)
+		assert.Equal(t, Rollup{Total: 2, Todo: 1, Done: 1, PercentComplete: 50, StoryPoints: 5, DonePoints: 3}, children[1].Rollup)
+	})
+}
+
+func TestService_UpdateTicket_ChangeLog(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockProject := new(MockProjectChecker)
+	service := NewService(mockRepo, mockProject, StubSchemeProvider{})
+
+	ctx := context.Background()
+	userID := int64(1)
+	points := 3.0
+
+	existing := &Ticket{ID: 1, ProjectID: 10, Type: "task", Status: "new", StoryPoints: &points}
+	mockRepo.On("GetByID", ctx, int64(1)).Return(existing, nil).Once()
+	mockProject.On("GetProjectByID", ctx, int64(10), userID).Return(&project.Project{}, nil).Once()
+	mockRepo.On("Update", ctx, existing, mock.MatchedBy(func(changes []Change) bool {
+		return len(changes) == 2 &&
+			changes[0].Field == FieldStatus && *changes[0].OldValue == "new" && *changes[0].NewValue == "done" &&
+			changes[1].Field == FieldStoryPoints && *changes[1].OldValue == "3" && changes[1].NewValue == nil &&
+			*changes[1].ChangedBy == userID
+	}), mock.MatchedBy(func(ev event.Draft) bool {
+		return ev.ProjectID == 10 && ev.ActorID == userID && ev.Type == event.TicketUpdated
+	})).Return(nil).Once()
+
+	status := "done"
+	var noPoints *float64
+	err := service.UpdateTicket(ctx, UpdateTicketRequest{Status: &status, StoryPoints: &noPoints}, 1, userID)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestApplyStatusTransition(t *testing.T) {
+	now := time.Now()
+	tk := &Ticket{Status: "in_progress"}
+
+	applyStatusTransition(tk, "new", now)
+	assert.Equal(t, &now, tk.StartedAt)
+	assert.Nil(t, tk.ResolvedAt)
+
+	later := now.Add(time.Hour)
+	tk.Status = "done"
+	applyStatusTransition(tk, "in_progress", later)
+	assert.Equal(t, &now, tk.StartedAt)
+	assert.Equal(t, &later, tk.ResolvedAt)
+
+	// reopening clears resolution but keeps cycle start
+	tk.Status = "open"
+	applyStatusTransition(tk, "done", later.Add(time.Hour))
+	assert.Equal(t, &now, tk.StartedAt)
+	assert.Nil(t, tk.ResolvedAt)
+}
+
+func TestDate_JSON(t *testing.T) {
+	var d Date
+	assert.NoError(t, d.UnmarshalJSON([]byte(`"2024-03-01"`)))
+	out, err := d.MarshalJSON()
+	assert.NoError(t, err)
+	assert.Equal(t, `"2024-03-01"`, string(out))
+
+	assert.Error(t, d.UnmarshalJSON([]byte(`"01.03.2024"`)))
+}
+
+func TestService_ListOverdueTickets(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockProject := new(MockProjectChecker)
+	service := NewService(mockRepo, mockProject, StubSchemeProvider{})
+
+	ctx := context.Background()
+	p := &project.Project{ID: 10, Timezone: "Pacific/Kiritimati"}
+	today := NewDate(p.Today(time.Now()))
+	due := NewDate(today.AddDate(0, 0, -3))
+
+	mockProject.On("GetProjectByID", ctx, int64(10), int64(1)).Return(p, nil).Once()
+	mockRepo.On("ListDueBefore", ctx, int64(10), today).Return([]Ticket{{ID: 1, DueDate: &due}}, nil).Once()
+
+	tickets, err := service.ListOverdueTickets(ctx, 10, 1)
+
+	assert.NoError(t, err)
+	assert.Len(t, tickets, 1)
+	assert.Equal(t, 3, tickets[0].DaysOverdue)
+}
+
+func TestService_UpdateTicket_Dates(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockProject := new(MockProjectChecker)
+	service := NewService(mockRepo, mockProject, StubSchemeProvider{})
+
+	ctx := context.Background()
+	start, _ := ParseDate("2024-03-10")
+	existing := &Ticket{ID: 1, ProjectID: 10, Type: "task", StartDate: &start}
+	mockRepo.On("GetByID", ctx, int64(1)).Return(existing, nil).Once()
+	mockProject.On("GetProjectByID", ctx, int64(10), int64(1)).Return(&project.Project{}, nil).Once()
+
+	due, _ := ParseDate("2024-03-01")
+	duePtr := &due
+	err := service.UpdateTicket(ctx, UpdateTicketRequest{DueDate: &duePtr}, 1, 1)
+
+	assert.Error(t, err)
+	assert.Equal(t, "due date can't be before start date", err.Error())
+}
+
+func TestService_ListTicketsInProject(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockProject := new(MockProjectChecker)
+	service := NewService(mockRepo, mockProject, StubSchemeProvider{})
+
+	ctx := context.Background()
+	filter := ListFilter{Statuses: []string{"todo"}}
+
+	t.Run("NextCursor", func(t *testing.T) {
+		page := pagination.Params{Limit: 2, Sort: "priority", Desc: true}
+		tickets := []Ticket{
+			{ID: 3, Priority: "high"},
+			{ID: 1, Priority: "medium"},
+			{ID: 2, Priority: "low"},
+		}
+		mockProject.On("GetProjectByID", ctx, int64(10), int64(1)).Return(&project.Project{ID: 10}, nil).Once()
+		mockRepo.On("ListPage", ctx, int64(10), filter, page).Return(tickets, 7, nil).Once()
+
+		result, err := service.ListTicketsInProject(ctx, 10, 1, filter, page)
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Items, 2)
+		assert.Equal(t, 7, result.Total)
+		next, err := pagination.DecodeCursor(result.Next)
+		assert.NoError(t, err)
+		assert.Equal(t, &pagination.Cursor{Sort: "-priority", Value: "3", ID: 1}, next)
+	})
+
+	t.Run("AccessDenied", func(t *testing.T) {
+		mockProject.On("GetProjectByID", ctx, int64(10), int64(2)).Return(nil, errors.New("project not found")).Once()
+
+		_, err := service.ListTicketsInProject(ctx, 10, 2, filter, pagination.Params{Limit: 2, Sort: "created"})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestService_ListMyTickets(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewService(mockRepo, new(MockProjectChecker), StubSchemeProvider{})
+
+	ctx := context.Background()
+	userID := int64(1)
+	other := int64(2)
+	page := pagination.Params{Limit: 10, Sort: "created", Desc: true}
+	tickets := []Ticket{
+		{ID: 1, Key: "PMS-1", Status: "todo", AssigneeID: &userID, ReporterID: userID},
+		{ID: 2, Key: "WEB-4", Status: "todo", AssigneeID: &other, ReporterID: userID},
+		{ID: 3, Key: "PMS-7", Status: "done", AssigneeID: &userID, ReporterID: other},
+	}
+
+	t.Run("Relations", func(t *testing.T) {
+		filter := MyTicketsFilter{}
+		mockRepo.On("ListForUser", ctx, userID, filter, page).Return(tickets, 3, nil).Once()
+		mockRepo.On("WatchedAmong", ctx, userID, []int64{1, 2, 3}).Return(map[int64]bool{2: true}, nil).Once()
+
+		result, err := service.ListMyTickets(ctx, userID, filter, page)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, result.Total)
+		assert.Empty(t, result.Next)
+		assert.Equal(t, []string{RelationAssigned, RelationReported}, result.Items[0].Relations)
+		assert.Equal(t, []string{RelationReported, RelationWatching}, result.Items[1].Relations)
+		assert.Equal(t, []string{RelationAssigned}, result.Items[2].Relations)
+	})
+
+	t.Run("UnknownRelation", func(t *testing.T) {
+		_, err := service.ListMyTickets(ctx, userID, MyTicketsFilter{Relations: []string{"owned"}}, page)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("GroupByProject", func(t *testing.T) {
+		filter := MyTicketsFilter{Relations: []string{RelationAssigned, RelationReported}}
+		mockRepo.On("CountForUser", ctx, userID, filter, "project").
+			Return([]GroupCount{{Key: "OPS", Count: 2}, {Key: "PMS", Count: 5}, {Key: "WEB", Count: 1}}, nil).Once()
+		items := []MyTicket{{Ticket: tickets[0]}, {Ticket: tickets[1]}, {Ticket: tickets[2]}}
+
+		groups, err := service.GroupMyTickets(ctx, userID, filter, "project", items)
+
+		assert.NoError(t, err)
+		assert.Len(t, groups, 3)
+		assert.Empty(t, groups[0].Tickets)
+		assert.Equal(t, 5, groups[1].Total)
+		assert.Len(t, groups[1].Tickets, 2)
+		assert.Equal(t, int64(2), groups[2].Tickets[0].ID)
+	})
+
+	t.Run("UnknownGroup", func(t *testing.T) {
+		_, err := service.GroupMyTickets(ctx, userID, MyTicketsFilter{}, "assignee", nil)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestService_Watchers(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockProject := new(MockProjectChecker)
+	service := NewService(mockRepo, mockProject, StubSchemeProvider{})
+
+	ctx := context.Background()
+
+	t.Run("Watch", func(t *testing.T) {
+		mockRepo.On("GetByID", ctx, int64(5)).Return(&Ticket{ID: 5, ProjectID: 10}, nil).Once()
+		mockProject.On("GetProjectByID", ctx, int64(10), int64(4)).Return(&project.Project{ID: 10}, nil).Once()
+		mockRepo.On("AddWatcher", ctx, int64(5), int64(4)).Return(nil).Once()
+
+		err := service.Watch(ctx, 5, 4)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("ListEmpty", func(t *testing.T) {
+		mockRepo.On("GetByID", ctx, int64(5)).Return(&Ticket{ID: 5, ProjectID: 10}, nil).Once()
+		mockProject.On("GetProjectByID", ctx, int64(10), int64(4)).Return(&project.Project{ID: 10}, nil).Once()
+		mockRepo.On("ListWatchers", ctx, int64(5)).Return(nil, nil).Once()
+
+		watchers, err := service.ListWatchers(ctx, 5, 4)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, watchers)
+		assert.Empty(t, watchers)
+	})
+
+	t.Run("UnwatchAccessDenied", func(t *testing.T) {
+		mockRepo.On("GetByID", ctx, int64(5)).Return(&Ticket{ID: 5, ProjectID: 10}, nil).Once()
+		mockProject.On("GetProjectByID", ctx, int64(10), int64(9)).Return(nil, errors.New("project not found")).Once()
+
+		err := service.Unwatch(ctx, 5, 9)
+
+		assert.ErrorIs(t, err, ErrNotFound)
+		mockRepo.AssertNotCalled(t, "RemoveWatcher", ctx, int64(5), int64(9))
+	})
+}
+
+func TestService_RemoveTicketLink(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockProject := new(MockProjectChecker)
+	service := NewService(mockRepo, mockProject, StubSchemeProvider{})
+
+	ctx := context.Background()
+	link := &TicketLink{ID: 7, SourceID: 1, TargetID: 2, LinkType: "blocks"}
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo.On("GetLinkByID", ctx, int64(7)).Return(link, nil).Once()
+		mockRepo.On("GetByID", ctx, int64(1)).Return(&Ticket{ID: 1, ProjectID: 10}, nil).Once()
+		mockProject.On("GetProjectByID", ctx, int64(10), int64(1)).Return(&project.Project{ID: 10}, nil).Once()
+		ev := event.Draft{ProjectID: 10, ActorID: 1, Type: event.LinkRemoved, Data: link}
+		mockRepo.On("DeleteLink", ctx, int64(7), ev).Return(nil).Once()
+
+		err := service.RemoveTicketLink(ctx, 7, 0, 1)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("AccessDenied", func(t *testing.T) {
+		mockRepo.On("GetLinkByID", ctx, int64(7)).Return(link, nil).Once()
+		mockRepo.On("GetByID", ctx, int64(1)).Return(&Ticket{ID: 1, ProjectID: 10}, nil).Once()
+		mockProject.On("GetProjectByID", ctx, int64(10), int64(2)).Return(nil, errors.New("project not found or access denied")).Once()
+
+		err := service.RemoveTicketLink(ctx, 7, 0, 2)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestValidateEstimates_StoryPoints(t *testing.T) {
+	for _, tt := range []struct {
+		points float64
+		valid  bool
+	}{{0, true}, {13, true}, {MaxStoryPoints, true}, {-1, false}, {MaxStoryPoints + 1, false}, {math.Inf(1), false}, {math.NaN(), false}} {
+		points := tt.points
+		err := validateEstimates(&Ticket{StoryPoints: &points})
+		assert.Equal(t, tt.valid, err == nil, "points %v", tt.points)
+	}
+}
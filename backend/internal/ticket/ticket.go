@@ -3,18 +3,31 @@ package ticket
 import "time"
 
 type Ticket struct {
-	ID          int64     `db:"id" json:"id"`
-	Title       string    `db:"title" json:"title"`
-	Description string    `db:"description" json:"description"`
-	Status      string    `db:"status" json:"status"`
-	Priority    string    `db:"priority" json:"priority"`
-	Type        string    `db:"type" json:"type"`
-	ParentID    *int64    `db:"parent_id" json:"parent_id"`
-	ProjectID   int64     `db:"project_id" json:"project_id"`
-	ReporterID  int64     `db:"reporter_id" json:"reporter_id"`
-	AssigneeID  *int64    `db:"assignee_id" json:"assignee_id"`
-	CreatedAt   time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+	ID                int64      `db:"id" json:"id"`
+	Number            int64      `db:"number" json:"number"`
+	Key               string     `db:"key" json:"key"`
+	Title             string     `db:"title" json:"title"`
+	Description       string     `db:"description" json:"description"`
+	Status            string     `db:"status" json:"status"`
+	Priority          string     `db:"priority" json:"priority"`
+	Type              string     `db:"type" json:"type"`
+	ParentID          *int64     `db:"parent_id" json:"parent_id"`
+	ProjectID         int64      `db:"project_id" json:"project_id"`
+	SprintID          *int64     `db:"sprint_id" json:"sprint_id"`
+	FixVersionID      *int64     `db:"fix_version_id" json:"fix_version_id"`
+	ReporterID        int64      `db:"reporter_id" json:"reporter_id"`
+	AssigneeID        *int64     `db:"assignee_id" json:"assignee_id"`
+	StoryPoints       *float64   `db:"story_points" json:"story_points"`
+	OriginalEstimate  *int64     `db:"original_estimate_minutes" json:"original_estimate_minutes"`
+	RemainingEstimate *int64     `db:"remaining_estimate_minutes" json:"remaining_estimate_minutes"`
+	TimeSpent         int64      `db:"time_spent_minutes" json:"time_spent_minutes"`
+	StartedAt         *time.Time `db:"started_at" json:"started_at"`
+	ResolvedAt        *time.Time `db:"resolved_at" json:"resolved_at"`
+	StartDate         *Date      `db:"start_date" json:"start_date"`
+	DueDate           *Date      `db:"due_date" json:"due_date"`
+	SLABreachedAt     *time.Time `db:"sla_breached_at" json:"sla_breached_at"`
+	CreatedAt         time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time  `db:"updated_at" json:"updated_at"`
 }
 
 type TicketLink struct {
@@ -24,3 +37,9 @@ type TicketLink struct {
 	LinkType  string    `db:"link_type" json:"link_type"`
 	CreatedAt time.Time `db:"created_at" json:"created_at"`
 }
+
+// TicketLabel is label attached to ticket
+type TicketLabel struct {
+	TicketID int64  `db:"ticket_id" json:"ticket_id"`
+	Name     string `db:"name" json:"name"`
+}
@@ -0,0 +1,253 @@
+package ticket
+
+import (
+	"context"
+	"math"
+	"slices"
+	"time"
+)
+
+// Workflow categories statuses are grouped into
+const (
+	CategoryTodo       = "todo"
+	CategoryInProgress = "in_progress"
+	CategoryDone       = "done"
+)
+
+// statusCategories maps known statuses to workflow categories, unknown ones are "todo"
+var statusCategories = map[string]string{
+	"new":         CategoryTodo,
+	"open":        CategoryTodo,
+	"in_progress": CategoryInProgress,
+	"review":      CategoryInProgress,
+	"done":        CategoryDone,
+}
+
+// StatusCategory returns workflow category of status
+func StatusCategory(status string) string {
+	if category, ok := statusCategories[status]; ok {
+		return category
+	}
+	return CategoryTodo
+}
+
+// isDone reports if status means finished work
+func isDone(status string) bool {
+	return StatusCategory(status) == CategoryDone
+}
+
+// applyStatusTransition keeps flow dates in sync with status change.
+// Cycle starts on first move out of todo, resolution is cleared when ticket is reopened
+func applyStatusTransition(t *Ticket, oldStatus string, now time.Time) {
+	oldCategory := StatusCategory(oldStatus)
+	newCategory := StatusCategory(t.Status)
+	if oldCategory == newCategory {
+		return
+	}
+
+	if newCategory != CategoryTodo && t.StartedAt == nil {
+		t.StartedAt = &now
+	}
+	if newCategory == CategoryDone {
+		t.ResolvedAt = &now
+	} else {
+		t.ResolvedAt = nil
+	}
+}
+
+// Rollup is progress and summed effort of all descendants of ticket
+type Rollup struct {
+	Total           int     `json:"total"`
+	Todo            int     `json:"todo"`
+	InProgress      int     `json:"in_progress"`
+	Done            int     `json:"done"`
+	PercentComplete float64 `json:"percent_complete"`
+
+	StoryPoints       float64 `json:"story_points"`
+	DonePoints        float64 `json:"done_points"`
+	OriginalEstimate  int64   `json:"original_estimate_minutes"`
+	RemainingEstimate int64   `json:"remaining_estimate_minutes"`
+	TimeSpent         int64   `json:"time_spent_minutes"`
+}
+
+// add counts one descendant
+func (r *Rollup) add(t *Ticket) {
+	r.Total++
+	if t.StoryPoints != nil {
+		r.StoryPoints += *t.StoryPoints
+	}
+	if t.OriginalEstimate != nil {
+		r.OriginalEstimate += *t.OriginalEstimate
+	}
+	if t.RemainingEstimate != nil {
+		r.RemainingEstimate += *t.RemainingEstimate
+	}
+	r.TimeSpent += t.TimeSpent
+
+	switch StatusCategory(t.Status) {
+	case CategoryDone:
+		r.Done++
+		if t.StoryPoints != nil {
+			r.DonePoints += *t.StoryPoints
+		}
+	case CategoryInProgress:
+		r.InProgress++
+	default:
+		r.Todo++
+	}
+}
+
+// SubtreeTotals is summed effort of descendants of one child with the same status
+type SubtreeTotals struct {
+	ChildID           int64   `db:"child_id"`
+	Status            string  `db:"status"`
+	Count             int     `db:"count"`
+	StoryPoints       float64 `db:"story_points"`
+	OriginalEstimate  int64   `db:"original_estimate_minutes"`
+	RemainingEstimate int64   `db:"remaining_estimate_minutes"`
+	TimeSpent         int64   `db:"time_spent_minutes"`
+}
+
+// addTotals counts group of descendants with the same status
+func (r *Rollup) addTotals(t SubtreeTotals) {
+	r.Total += t.Count
+	r.StoryPoints += t.StoryPoints
+	r.OriginalEstimate += t.OriginalEstimate
+	r.RemainingEstimate += t.RemainingEstimate
+	r.TimeSpent += t.TimeSpent
+
+	switch StatusCategory(t.Status) {
+	case CategoryDone:
+		r.Done += t.Count
+		r.DonePoints += t.StoryPoints
+	case CategoryInProgress:
+		r.InProgress += t.Count
+	default:
+		r.Todo += t.Count
+	}
+}
+
+// merge adds counts of child subtree
+func (r *Rollup) merge(other *Rollup) {
+	r.Total += other.Total
+	r.Todo += other.Todo
+	r.InProgress += other.InProgress
+	r.Done += other.Done
+	r.StoryPoints += other.StoryPoints
+	r.DonePoints += other.DonePoints
+	r.OriginalEstimate += other.OriginalEstimate
+	r.RemainingEstimate += other.RemainingEstimate
+	r.TimeSpent += other.TimeSpent
+}
+
+func (r *Rollup) finish() {
+	if r.Total > 0 {
+		r.PercentComplete = math.Round(float64(r.Done)/float64(r.Total)*1000) / 10
+	}
+}
+
+// TreeNode is ticket with rollup and children
+type TreeNode struct {
+	Ticket
+	Rollup   Rollup     `json:"rollup"`
+	Children []TreeNode `json:"children,omitempty"`
+}
+
+// GetTicketTree returns ticket with all descendants and rollups on every level
+func (s *Service) GetTicketTree(ctx context.Context, ticketID, userID int64) (*TreeNode, error) {
+	root, err := s.GetTicketByID(ctx, ticketID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	descendants, err := s.repo.GetDescendants(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	node := buildTree(root, descendants)
+	return &node, nil
+}
+
+// GetTicketChildren returns direct children of ticket with their rollups.
+// Rollups are summed in database, so deeper levels are never loaded
+func (s *Service) GetTicketChildren(ctx context.Context, ticketID, userID int64) ([]TreeNode, error) {
+	_, err := s.GetTicketByID(ctx, ticketID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	children, err := s.repo.ListChildren(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := s.repo.SumChildSubtrees(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	rollups := make(map[int64]*Rollup)
+	for _, t := range totals {
+		r, ok := rollups[t.ChildID]
+		if !ok {
+			r = &Rollup{}
+			rollups[t.ChildID] = r
+		}
+		r.addTotals(t)
+	}
+
+	nodes := make([]TreeNode, 0, len(children))
+	for _, c := range children {
+		node := TreeNode{Ticket: c}
+		if r, ok := rollups[c.ID]; ok {
+			node.Rollup = *r
+		}
+		node.Rollup.finish()
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// GetTicketAncestors returns ancestors of ticket from the root down to direct parent
+func (s *Service) GetTicketAncestors(ctx context.Context, ticketID, userID int64) ([]Ticket, error) {
+	_, err := s.GetTicketByID(ctx, ticketID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ancestors, err := s.repo.GetAncestors(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Reverse(ancestors)
+	if ancestors == nil {
+		ancestors = []Ticket{}
+	}
+	return ancestors, nil
+}
+
+// buildTree assembles nested tree from flat list of descendants and computes rollups bottom-up
+func buildTree(root *Ticket, descendants []Ticket) TreeNode {
+	byParent := make(map[int64][]Ticket)
+	for _, t := range descendants {
+		if t.ParentID != nil {
+			byParent[*t.ParentID] = append(byParent[*t.ParentID], t)
+		}
+	}
+
+	var build func(t Ticket) TreeNode
+	build = func(t Ticket) TreeNode {
+		node := TreeNode{Ticket: t}
+		for _, child := range byParent[t.ID] {
+			childNode := build(child)
+			node.Rollup.add(&child)
+			node.Rollup.merge(&childNode.Rollup)
+			node.Children = append(node.Children, childNode)
+		}
+		node.Rollup.finish()
+		return node
+	}
+
+	return build(*root)
+}
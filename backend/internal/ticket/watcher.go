@@ -0,0 +1,46 @@
+package ticket
+
+import (
+	"context"
+	"time"
+)
+
+// Watcher is user following changes of ticket
+type Watcher struct {
+	UserID    int64     `db:"user_id" json:"user_id"`
+	Username  string    `db:"username" json:"username"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// ListWatchers returns users watching ticket
+func (s *Service) ListWatchers(ctx context.Context, ticketID, userID int64) ([]Watcher, error) {
+	// check access
+	if _, err := s.GetTicketByID(ctx, ticketID, userID); err != nil {
+		return nil, err
+	}
+
+	watchers, err := s.repo.ListWatchers(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	if watchers == nil {
+		watchers = []Watcher{}
+	}
+	return watchers, nil
+}
+
+// Watch makes user follow ticket, watching twice is no-op
+func (s *Service) Watch(ctx context.Context, ticketID, userID int64) error {
+	if _, err := s.GetTicketByID(ctx, ticketID, userID); err != nil {
+		return err
+	}
+	return s.repo.AddWatcher(ctx, ticketID, userID)
+}
+
+// Unwatch stops user following ticket
+func (s *Service) Unwatch(ctx context.Context, ticketID, userID int64) error {
+	if _, err := s.GetTicketByID(ctx, ticketID, userID); err != nil {
+		return err
+	}
+	return s.repo.RemoveWatcher(ctx, ticketID, userID)
+}
@@ -0,0 +1,539 @@
+package tql
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Env is context of query execution used by functions like currentUser() and now()
+type Env struct {
+	UserID int64
+	Now    time.Time
+}
+
+// SQL is compiled query. Fragments use aliases t for tickets and p for projects,
+// placeholders start at $1 and go in order of Args
+type SQL struct {
+	Where   string
+	OrderBy string
+	Args    []any
+}
+
+type fieldKind int
+
+const (
+	kindProject fieldKind = iota
+	kindKey
+	kindText
+	kindFreeText
+	kindUser
+	kindSprint
+	kindVersion
+	kindParent
+	kindNumber
+	kindTime
+	kindDate
+	kindLabel
+)
+
+type field struct {
+	kind     fieldKind
+	column   string
+	nullable bool
+	// order is ORDER BY expression, empty if field can't be sorted
+	order string
+}
+
+// priorityOrder sorts known priorities from lowest, unknown ones go first
+const priorityOrder = `CASE lower(t.priority) WHEN 'lowest' THEN 1 WHEN 'low' THEN 2 WHEN 'medium' THEN 3 ` +
+	`WHEN 'high' THEN 4 WHEN 'highest' THEN 5 WHEN 'critical' THEN 5 ELSE 0 END`
+
+// fields by lower case name, aliases share definition
+var fields = map[string]field{
+	"project":     {kind: kindProject, column: "t.project_id", order: "p.key"},
+	"key":         {kind: kindKey, column: "t.key", order: "p.key, t.number"},
+	"issue":       {kind: kindKey, column: "t.key", order: "p.key, t.number"},
+	"status":      {kind: kindText, column: "t.status", order: "t.status"},
+	"priority":    {kind: kindText, column: "t.priority", order: priorityOrder},
+	"type":        {kind: kindText, column: "t.type", order: "t.type"},
+	"issuetype":   {kind: kindText, column: "t.type", order: "t.type"},
+	"title":       {kind: kindFreeText, column: "t.title", order: "t.title"},
+	"summary":     {kind: kindFreeText, column: "t.title", order: "t.title"},
+	"description": {kind: kindFreeText, column: "t.description", nullable: true},
+	"text":        {kind: kindFreeText},
+	"assignee":    {kind: kindUser, column: "t.assignee_id", nullable: true, order: "(SELECT u.username FROM users u WHERE u.id = t.assignee_id)"},
+	"reporter":    {kind: kindUser, column: "t.reporter_id", order: "(SELECT u.username FROM users u WHERE u.id = t.reporter_id)"},
+	"sprint":      {kind: kindSprint, column: "t.sprint_id", nullable: true, order: "t.sprint_id"},
+	"fixversion":  {kind: kindVersion, column: "t.fix_version_id", nullable: true, order: "t.fix_version_id"},
+	"version":     {kind: kindVersion, column: "t.fix_version_id", nullable: true, order: "t.fix_version_id"},
+	"parent":      {kind: kindParent, column: "t.parent_id", nullable: true, order: "t.parent_id"},
+	"storypoints": {kind: kindNumber, column: "t.story_points", nullable: true, order: "t.story_points"},
+	"points":      {kind: kindNumber, column: "t.story_points", nullable: true, order: "t.story_points"},
+	"created":     {kind: kindTime, column: "t.created_at", order: "t.created_at"},
+	"updated":     {kind: kindTime, column: "t.updated_at", order: "t.updated_at"},
+	"started":     {kind: kindTime, column: "t.started_at", nullable: true, order: "t.started_at"},
+	"resolved":    {kind: kindTime, column: "t.resolved_at", nullable: true, order: "t.resolved_at"},
+	"startdate":   {kind: kindDate, column: "t.start_date", nullable: true, order: "t.start_date"},
+	"duedate":     {kind: kindDate, column: "t.due_date", nullable: true, order: "t.due_date"},
+	"due":         {kind: kindDate, column: "t.due_date", nullable: true, order: "t.due_date"},
+	"label":       {kind: kindLabel},
+	"labels":      {kind: kindLabel},
+}
+
+// operators allowed per field kind, IS [NOT] EMPTY is checked by nullable flag
+var kindOps = map[fieldKind]string{
+	kindProject:  "= != in not in",
+	kindKey:      "= != in not in",
+	kindText:     "= != in not in",
+	kindFreeText: "~ !~",
+	kindUser:     "= != in not in",
+	kindSprint:   "= != in not in",
+	kindVersion:  "= != in not in",
+	kindParent:   "= != in not in",
+	kindNumber:   "= != < <= > >= in not in",
+	kindTime:     "= != < <= > >=",
+	kindDate:     "= != < <= > >= in not in",
+	kindLabel:    "= != in not in",
+}
+
+type compiler struct {
+	env  Env
+	args []any
+}
+
+// Compile turns parsed query into SQL condition and ordering with values passed as arguments
+func Compile(q *Query, env Env) (*SQL, error) {
+	c := &compiler{env: env}
+	result := &SQL{Where: "TRUE"}
+
+	if q.Where != nil {
+		where, err := c.node(q.Where)
+		if err != nil {
+			return nil, err
+		}
+		result.Where = where
+	}
+
+	orders := make([]string, 0, len(q.OrderBy)+1)
+	for _, o := range q.OrderBy {
+		f, ok := fields[strings.ToLower(o.Field)]
+		if !ok {
+			return nil, errorAt(o.Pos, "unknown field %q", o.Field)
+		}
+		if f.order == "" {
+			return nil, errorAt(o.Pos, "can't order by %q", o.Field)
+		}
+		dir := " ASC NULLS LAST"
+		if o.Desc {
+			dir = " DESC NULLS LAST"
+		}
+		for _, expr := range strings.Split(f.order, ", ") {
+			orders = append(orders, expr+dir)
+		}
+	}
+	if len(orders) == 0 {
+		orders = append(orders, "t.created_at DESC")
+	}
+	orders = append(orders, "t.id DESC")
+	result.OrderBy = strings.Join(orders, ", ")
+
+	result.Args = c.args
+	return result, nil
+}
+
+// arg adds argument and returns its placeholder
+func (c *compiler) arg(v any) string {
+	c.args = append(c.args, v)
+	return "$" + strconv.Itoa(len(c.args))
+}
+
+func (c *compiler) node(n Node) (string, error) {
+	switch n := n.(type) {
+	case *And:
+		return c.binary(n.Left, n.Right, "AND")
+	case *Or:
+		return c.binary(n.Left, n.Right, "OR")
+	case *Not:
+		x, err := c.node(n.X)
+		if err != nil {
+			return "", err
+		}
+		// NULL comparison is treated as false, so NOT of it is true
+		return "NOT COALESCE(" + x + ", FALSE)", nil
+	case *Clause:
+		return c.clause(n)
+	}
+	return "", fmt.Errorf("unknown node %T", n)
+}
+
+func (c *compiler) binary(left, right Node, op string) (string, error) {
+	l, err := c.node(left)
+	if err != nil {
+		return "", err
+	}
+	r, err := c.node(right)
+	if err != nil {
+		return "", err
+	}
+	return "(" + l + " " + op + " " + r + ")", nil
+}
+
+func (c *compiler) clause(cl *Clause) (string, error) {
+	f, ok := fields[strings.ToLower(cl.Field)]
+	if !ok {
+		return "", errorAt(cl.Pos, "unknown field %q", cl.Field)
+	}
+
+	if cl.Op == OpIsEmpty || cl.Op == OpIsNotEmpty {
+		return c.emptiness(cl, f)
+	}
+	if !allowed(f.kind, cl.Op) {
+		return "", errorAt(cl.Pos, "operator %q is not supported by field %q", cl.Op, cl.Field)
+	}
+
+	switch f.kind {
+	case kindFreeText:
+		return c.freeText(cl, f)
+	case kindNumber:
+		return c.number(cl, f)
+	case kindTime:
+		return c.moment(cl, f)
+	case kindDate:
+		return c.date(cl, f)
+	case kindLabel:
+		return c.label(cl)
+	}
+
+	// set membership of ids or names
+	var scalars, sets []string
+	for _, v := range cl.Values {
+		expr, isSet, err := c.member(f, v)
+		if err != nil {
+			return "", err
+		}
+		if isSet {
+			sets = append(sets, expr)
+		} else {
+			scalars = append(scalars, expr)
+		}
+	}
+	var parts []string
+	if len(scalars) > 0 {
+		parts = append(parts, f.column+" IN ("+strings.Join(scalars, ", ")+")")
+	}
+	for _, s := range sets {
+		parts = append(parts, f.column+" IN ("+s+")")
+	}
+	positive := "(" + strings.Join(parts, " OR ") + ")"
+
+	if cl.Op == OpNotEq || cl.Op == OpNotIn {
+		// like JQL, empty fields don't match negative conditions
+		return "(" + f.column + " IS NOT NULL AND NOT " + positive + ")", nil
+	}
+	return positive, nil
+}
+
+func allowed(kind fieldKind, op string) bool {
+	ops := kindOps[kind]
+	if op == OpNotIn {
+		return strings.Contains(ops, "not in")
+	}
+	for _, o := range strings.Split(strings.ReplaceAll(ops, "not in", ""), " ") {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *compiler) emptiness(cl *Clause, f field) (string, error) {
+	not := cl.Op == OpIsNotEmpty
+	switch {
+	case f.kind == kindLabel:
+		expr := "EXISTS (SELECT 1 FROM ticket_labels tl WHERE tl.ticket_id = t.id)"
+		if not {
+			return expr, nil
+		}
+		return "NOT " + expr, nil
+	case f.kind == kindFreeText && f.column != "":
+		if not {
+			return "COALESCE(" + f.column + ", '') <> ''", nil
+		}
+		return "COALESCE(" + f.column + ", '') = ''", nil
+	case f.nullable:
+		if not {
+			return f.column + " IS NOT NULL", nil
+		}
+		return f.column + " IS NULL", nil
+	}
+	return "", errorAt(cl.Pos, "field %q is never empty", cl.Field)
+}
+
+// member converts value to id placeholder or subquery returning ids
+func (c *compiler) member(f field, v Value) (expr string, isSet bool, err error) {
+	if v.Func != "" {
+		return c.setFunction(f, v)
+	}
+
+	switch f.kind {
+	case kindProject:
+		if id, err := strconv.ParseInt(v.Text, 10, 64); err == nil {
+			return c.arg(id), false, nil
+		}
+		return "SELECT id FROM projects WHERE key = " + c.arg(strings.ToUpper(v.Text)), true, nil
+	case kindKey:
+		return c.arg(strings.ToUpper(v.Text)), false, nil
+	case kindText:
+		return c.arg(strings.ToLower(v.Text)), false, nil
+	case kindUser:
+		if id, err := strconv.ParseInt(v.Text, 10, 64); err == nil {
+			return c.arg(id), false, nil
+		}
+		p := c.arg(v.Text)
+		return "SELECT id FROM users WHERE username = " + p + " OR email = " + p, true, nil
+	case kindSprint:
+		if id, err := strconv.ParseInt(v.Text, 10, 64); err == nil {
+			return c.arg(id), false, nil
+		}
+		return "SELECT id FROM sprints WHERE project_id = t.project_id AND name = " + c.arg(v.Text), true, nil
+	case kindVersion:
+		if id, err := strconv.ParseInt(v.Text, 10, 64); err == nil {
+			return c.arg(id), false, nil
+		}
+		return "SELECT id FROM versions WHERE project_id = t.project_id AND name = " + c.arg(v.Text), true, nil
+	case kindParent:
+		if id, err := strconv.ParseInt(v.Text, 10, 64); err == nil {
+			return c.arg(id), false, nil
+		}
+		return "SELECT id FROM tickets WHERE key = " + c.arg(strings.ToUpper(v.Text)), true, nil
+	}
+	return "", false, errorAt(v.Pos, "unsupported value %q", v.Text)
+}
+
+// setFunction compiles functions used as values of id fields
+func (c *compiler) setFunction(f field, v Value) (string, bool, error) {
+	name := strings.ToLower(v.Func)
+	if len(v.Args) > 0 {
+		return "", false, errorAt(v.Pos, "%s() takes no arguments", v.Func)
+	}
+
+	switch {
+	case name == "currentuser" && f.kind == kindUser:
+		return c.arg(c.env.UserID), false, nil
+	case name == "opensprints" && f.kind == kindSprint:
+		return "SELECT id FROM sprints WHERE state IN ('planned', 'active')", true, nil
+	case name == "closedsprints" && f.kind == kindSprint:
+		return "SELECT id FROM sprints WHERE state = 'closed'", true, nil
+	case name == "releasedversions" && f.kind == kindVersion:
+		return "SELECT id FROM versions WHERE released", true, nil
+	case name == "unreleasedversions" && f.kind == kindVersion:
+		return "SELECT id FROM versions WHERE NOT released AND NOT archived", true, nil
+	}
+	return "", false, errorAt(v.Pos, "function %s() can't be used here", v.Func)
+}
+
+// likePattern escapes LIKE wildcards and wraps text for substring match
+func likePattern(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + r.Replace(s) + "%"
+}
+
+func (c *compiler) freeText(cl *Clause, f field) (string, error) {
+	v := cl.Values[0]
+	if v.Func != "" {
+		return "", errorAt(v.Pos, "function %s() can't be used here", v.Func)
+	}
+	p := c.arg(likePattern(v.Text))
+
+	var expr string
+	if f.column == "" {
+		expr = "(t.title ILIKE " + p + " OR COALESCE(t.description, '') ILIKE " + p +
+			" OR EXISTS (SELECT 1 FROM ticket_comments tc WHERE tc.ticket_id = t.id AND tc.body ILIKE " + p + "))"
+	} else {
+		expr = "COALESCE(" + f.column + ", '') ILIKE " + p
+	}
+	if cl.Op == OpNotContain {
+		return "NOT " + expr, nil
+	}
+	return expr, nil
+}
+
+func (c *compiler) number(cl *Clause, f field) (string, error) {
+	var placeholders []string
+	for _, v := range cl.Values {
+		n, err := strconv.ParseFloat(v.Text, 64)
+		if err != nil || v.Func != "" {
+			return "", errorAt(v.Pos, "%q is not a number", v.Text)
+		}
+		placeholders = append(placeholders, c.arg(n))
+	}
+
+	switch cl.Op {
+	case OpIn:
+		return f.column + " IN (" + strings.Join(placeholders, ", ") + ")", nil
+	case OpNotIn:
+		return f.column + " NOT IN (" + strings.Join(placeholders, ", ") + ")", nil
+	case OpNotEq:
+		return f.column + " <> " + placeholders[0], nil
+	}
+	return f.column + " " + cl.Op + " " + placeholders[0], nil
+}
+
+func (c *compiler) label(cl *Clause) (string, error) {
+	var placeholders []string
+	for _, v := range cl.Values {
+		if v.Func != "" {
+			return "", errorAt(v.Pos, "function %s() can't be used here", v.Func)
+		}
+		placeholders = append(placeholders, c.arg(v.Text))
+	}
+	expr := "EXISTS (SELECT 1 FROM ticket_labels tl JOIN labels l ON l.id = tl.label_id " +
+		"WHERE tl.ticket_id = t.id AND l.name IN (" + strings.Join(placeholders, ", ") + "))"
+	if cl.Op == OpNotEq || cl.Op == OpNotIn {
+		return "NOT " + expr, nil
+	}
+	return expr, nil
+}
+
+// moment compares timestamp column. Plain dates cover whole day, so created = 2024-01-31 matches the day
+func (c *compiler) moment(cl *Clause, f field) (string, error) {
+	t, wholeDay, err := c.timeValue(cl.Values[0])
+	if err != nil {
+		return "", err
+	}
+	if !wholeDay {
+		op := cl.Op
+		if op == OpNotEq {
+			op = "<>"
+		}
+		return f.column + " " + op + " " + c.arg(t), nil
+	}
+
+	next := t.AddDate(0, 0, 1)
+	switch cl.Op {
+	case OpEq:
+		return "(" + f.column + " >= " + c.arg(t) + " AND " + f.column + " < " + c.arg(next) + ")", nil
+	case OpNotEq:
+		return "(" + f.column + " < " + c.arg(t) + " OR " + f.column + " >= " + c.arg(next) + ")", nil
+	case OpLess:
+		return f.column + " < " + c.arg(t), nil
+	case OpLessEq:
+		return f.column + " < " + c.arg(next), nil
+	case OpGreater:
+		return f.column + " >= " + c.arg(next), nil
+	default:
+		return f.column + " >= " + c.arg(t), nil
+	}
+}
+
+// date compares DATE column with calendar day of value
+func (c *compiler) date(cl *Clause, f field) (string, error) {
+	var placeholders []string
+	for _, v := range cl.Values {
+		t, _, err := c.timeValue(v)
+		if err != nil {
+			return "", err
+		}
+		placeholders = append(placeholders, c.arg(t.Format(time.DateOnly))+"::date")
+	}
+
+	switch cl.Op {
+	case OpIn:
+		return f.column + " IN (" + strings.Join(placeholders, ", ") + ")", nil
+	case OpNotIn:
+		return f.column + " NOT IN (" + strings.Join(placeholders, ", ") + ")", nil
+	case OpNotEq:
+		return f.column + " <> " + placeholders[0], nil
+	}
+	return f.column + " " + cl.Op + " " + placeholders[0], nil
+}
+
+// relativePattern matches offsets like -7d, 2w, -4h, 30m
+var relativePattern = regexp.MustCompile(`^([+-]?)(\d+)([wdhm])$`)
+
+// timeValue resolves date, date time, relative offset or time function. wholeDay is true for plain dates
+func (c *compiler) timeValue(v Value) (t time.Time, wholeDay bool, err error) {
+	now := c.env.Now.UTC()
+	if v.Func != "" {
+		t, err := c.timeFunction(v, now)
+		return t, false, err
+	}
+
+	if d, err := time.Parse(time.DateOnly, v.Text); err == nil {
+		return d, true, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04", time.RFC3339} {
+		if d, err := time.Parse(layout, v.Text); err == nil {
+			return d.UTC(), false, nil
+		}
+	}
+	if offset, ok := parseOffset(v.Text); ok {
+		return now.Add(offset), false, nil
+	}
+	return time.Time{}, false, errorAt(v.Pos, "%q is not a date, use YYYY-MM-DD, offset like -7d or function like startOfDay()", v.Text)
+}
+
+func parseOffset(s string) (time.Duration, bool) {
+	m := relativePattern.FindStringSubmatch(s)
+	if m == nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(m[2])
+	if err != nil {
+		return 0, false
+	}
+	unit := map[string]time.Duration{
+		"w": 7 * 24 * time.Hour,
+		"d": 24 * time.Hour,
+		"h": time.Hour,
+		"m": time.Minute,
+	}[m[3]]
+	d := time.Duration(n) * unit
+	if m[1] == "-" {
+		d = -d
+	}
+	return d, true
+}
+
+// timeFunction resolves now() and startOf/endOf functions, optional argument shifts result like startOfDay(-1d)
+func (c *compiler) timeFunction(v Value, now time.Time) (time.Time, error) {
+	if len(v.Args) > 1 {
+		return time.Time{}, errorAt(v.Pos, "%s() takes at most one argument", v.Func)
+	}
+	var offset time.Duration
+	if len(v.Args) == 1 {
+		var ok bool
+		offset, ok = parseOffset(v.Args[0].Text)
+		if !ok || v.Args[0].Func != "" {
+			return time.Time{}, errorAt(v.Args[0].Pos, "%q is not offset like -1d", v.Args[0].Text)
+		}
+	}
+
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	week := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	var t time.Time
+	switch strings.ToLower(v.Func) {
+	case "now":
+		t = now
+	case "startofday":
+		t = day
+	case "endofday":
+		t = day.AddDate(0, 0, 1).Add(-time.Microsecond)
+	case "startofweek":
+		t = week
+	case "endofweek":
+		t = week.AddDate(0, 0, 7).Add(-time.Microsecond)
+	case "startofmonth":
+		t = month
+	case "endofmonth":
+		t = month.AddDate(0, 1, 0).Add(-time.Microsecond)
+	default:
+		return time.Time{}, errorAt(v.Pos, "function %s() can't be used here", v.Func)
+	}
+	return t.Add(offset), nil
+}
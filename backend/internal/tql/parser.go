@@ -0,0 +1,330 @@
+package tql
+
+import "strings"
+
+// Operators of clause
+const (
+	OpEq         = "="
+	OpNotEq      = "!="
+	OpLess       = "<"
+	OpLessEq     = "<="
+	OpGreater    = ">"
+	OpGreaterEq  = ">="
+	OpContains   = "~"
+	OpNotContain = "!~"
+	OpIn         = "in"
+	OpNotIn      = "not in"
+	OpIsEmpty    = "is empty"
+	OpIsNotEmpty = "is not empty"
+)
+
+// Node is part of condition tree: *And, *Or, *Not or *Clause
+type Node interface {
+	node()
+}
+
+type And struct {
+	Left, Right Node
+}
+
+type Or struct {
+	Left, Right Node
+}
+
+type Not struct {
+	X Node
+}
+
+// Clause compares field with values, e.g. status in (open, review)
+type Clause struct {
+	Field  string
+	Op     string
+	Values []Value
+	Pos    int
+}
+
+// Value is literal or function call like currentUser()
+type Value struct {
+	Text string
+	// Func is function name, empty for literal
+	Func string
+	Args []Value
+	Pos  int
+}
+
+func (*And) node()    {}
+func (*Or) node()     {}
+func (*Not) node()    {}
+func (*Clause) node() {}
+
+// Order is one ORDER BY item
+type Order struct {
+	Field string
+	Desc  bool
+	Pos   int
+}
+
+// Query is parsed query, Where is nil when query has only ordering or is empty
+type Query struct {
+	Where   Node
+	OrderBy []Order
+}
+
+// keywords can't be used as unquoted values
+var keywords = map[string]bool{
+	"and": true, "or": true, "not": true, "in": true, "is": true,
+	"empty": true, "null": true, "order": true, "by": true,
+}
+
+type parser struct {
+	tokens []token
+	next   int
+}
+
+// Parse parses query like `project = PMS AND status != done ORDER BY priority DESC`
+func Parse(input string) (*Query, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+
+	q := &Query{}
+	if !p.peek().is("order") && p.peek().kind != tokEOF {
+		q.Where, err = p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if p.peek().is("order") {
+		q.OrderBy, err = p.parseOrderBy()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, errorAt(t.pos, "unexpected %q", t.text)
+	}
+	return q, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) take() token {
+	t := p.tokens[p.next]
+	if t.kind != tokEOF {
+		p.next++
+	}
+	return t
+}
+
+func (p *parser) parseOr() (Node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().is("or") {
+		p.take()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Or{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().is("and") {
+		p.take()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &And{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (Node, error) {
+	if p.peek().is("not") {
+		p.take()
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{X: x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	t := p.peek()
+	if t.kind == tokLParen {
+		p.take()
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.take(); closing.kind != tokRParen {
+			return nil, errorAt(closing.pos, "expected ')'")
+		}
+		return x, nil
+	}
+	return p.parseClause()
+}
+
+func (p *parser) parseClause() (Node, error) {
+	field := p.take()
+	if field.kind != tokWord || keywords[strings.ToLower(field.text)] {
+		return nil, errorAt(field.pos, "expected field name")
+	}
+	clause := &Clause{Field: field.text, Pos: field.pos}
+
+	t := p.take()
+	switch {
+	case t.kind == tokOp:
+		clause.Op = t.text
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		clause.Values = []Value{v}
+	case t.is("in"):
+		clause.Op = OpIn
+	case t.is("not"):
+		if in := p.take(); !in.is("in") {
+			return nil, errorAt(in.pos, "expected IN after NOT")
+		}
+		clause.Op = OpNotIn
+	case t.is("is"):
+		clause.Op = OpIsEmpty
+		if p.peek().is("not") {
+			p.take()
+			clause.Op = OpIsNotEmpty
+		}
+		if e := p.take(); !e.is("empty") && !e.is("null") {
+			return nil, errorAt(e.pos, "expected EMPTY")
+		}
+		return clause, nil
+	default:
+		return nil, errorAt(t.pos, "expected operator after %q", field.text)
+	}
+
+	if clause.Op == OpIn || clause.Op == OpNotIn {
+		values, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		clause.Values = values
+	}
+	return clause, nil
+}
+
+// parseList reads (a, b, c) or single function call like openSprints()
+func (p *parser) parseList() ([]Value, error) {
+	if p.peek().kind != tokLParen {
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		if v.Func == "" {
+			return nil, errorAt(v.Pos, "expected list in parentheses")
+		}
+		return []Value{v}, nil
+	}
+	p.take()
+
+	var values []Value
+	for {
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+
+		t := p.take()
+		if t.kind == tokRParen {
+			return values, nil
+		}
+		if t.kind != tokComma {
+			return nil, errorAt(t.pos, "expected ',' or ')'")
+		}
+	}
+}
+
+func (p *parser) parseValue() (Value, error) {
+	t := p.take()
+	switch t.kind {
+	case tokString:
+		return Value{Text: t.text, Pos: t.pos}, nil
+	case tokWord:
+		if keywords[strings.ToLower(t.text)] {
+			return Value{}, errorAt(t.pos, "expected value, got keyword %q (quote it to use as value)", t.text)
+		}
+		if p.peek().kind != tokLParen {
+			return Value{Text: t.text, Pos: t.pos}, nil
+		}
+		return p.parseCall(t)
+	default:
+		return Value{}, errorAt(t.pos, "expected value")
+	}
+}
+
+func (p *parser) parseCall(name token) (Value, error) {
+	p.take()
+	v := Value{Func: name.text, Pos: name.pos}
+	if p.peek().kind == tokRParen {
+		p.take()
+		return v, nil
+	}
+	for {
+		arg, err := p.parseValue()
+		if err != nil {
+			return Value{}, err
+		}
+		v.Args = append(v.Args, arg)
+
+		t := p.take()
+		if t.kind == tokRParen {
+			return v, nil
+		}
+		if t.kind != tokComma {
+			return Value{}, errorAt(t.pos, "expected ',' or ')'")
+		}
+	}
+}
+
+func (p *parser) parseOrderBy() ([]Order, error) {
+	p.take()
+	if by := p.take(); !by.is("by") {
+		return nil, errorAt(by.pos, "expected BY after ORDER")
+	}
+
+	var orders []Order
+	for {
+		field := p.take()
+		if field.kind != tokWord || keywords[strings.ToLower(field.text)] {
+			return nil, errorAt(field.pos, "expected field name")
+		}
+		o := Order{Field: field.text, Pos: field.pos}
+		if p.peek().is("desc") {
+			p.take()
+			o.Desc = true
+		} else if p.peek().is("asc") {
+			p.take()
+		}
+		orders = append(orders, o)
+
+		if p.peek().kind != tokComma {
+			return orders, nil
+		}
+		p.take()
+	}
+}
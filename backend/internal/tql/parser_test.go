@@ -0,0 +1,152 @@
+package tql
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var env = Env{UserID: 7, Now: time.Date(2024, 5, 15, 10, 30, 0, 0, time.UTC)}
+
+func compile(t *testing.T, query string) *SQL {
+	t.Helper()
+	q, err := Parse(query)
+	require.NoError(t, err)
+	sql, err := Compile(q, env)
+	require.NoError(t, err)
+	return sql
+}
+
+func TestParse(t *testing.T) {
+	q, err := Parse(`project = PMS AND (status != done OR NOT assignee is empty) order by priority desc, created`)
+	require.NoError(t, err)
+
+	and, ok := q.Where.(*And)
+	require.True(t, ok)
+	assert.Equal(t, &Clause{Field: "project", Op: OpEq, Values: []Value{{Text: "PMS", Pos: 10}}, Pos: 0}, and.Left)
+
+	or, ok := and.Right.(*Or)
+	require.True(t, ok)
+	assert.Equal(t, OpNotEq, or.Left.(*Clause).Op)
+	assert.Equal(t, OpIsEmpty, or.Right.(*Not).X.(*Clause).Op)
+
+	assert.Equal(t, []Order{{Field: "priority", Desc: true, Pos: 69}, {Field: "created", Pos: 84}}, q.OrderBy)
+}
+
+func TestParse_Values(t *testing.T) {
+	q, err := Parse(`status not in ("in review", 'it\'s', open) and sprint in openSprints() and created > startOfDay(-1d)`)
+	require.NoError(t, err)
+
+	left := q.Where.(*And).Left.(*And)
+	in := left.Left.(*Clause)
+	assert.Equal(t, OpNotIn, in.Op)
+	assert.Equal(t, []string{"in review", "it's", "open"}, []string{in.Values[0].Text, in.Values[1].Text, in.Values[2].Text})
+	assert.Equal(t, "openSprints", left.Right.(*Clause).Values[0].Func)
+
+	created := q.Where.(*And).Right.(*Clause)
+	assert.Equal(t, "startOfDay", created.Values[0].Func)
+	assert.Equal(t, "-1d", created.Values[0].Args[0].Text)
+}
+
+func TestParse_Errors(t *testing.T) {
+	cases := map[string]int{
+		`status =`:                     9,
+		`status = done and`:            18,
+		`(status = done`:               15,
+		`status in open`:               11,
+		`status = "open`:               10,
+		`status ! done`:                8,
+		`status = done order priority`: 21,
+		`= done`:                       1,
+		`status = and`:                 10,
+		`status = done)`:               14,
+	}
+	for query, pos := range cases {
+		_, err := Parse(query)
+		var qe *Error
+		if assert.True(t, errors.As(err, &qe), query) {
+			assert.Equal(t, pos, qe.Pos, query)
+		}
+	}
+}
+
+func TestParse_Empty(t *testing.T) {
+	q, err := Parse("  ")
+	require.NoError(t, err)
+	assert.Nil(t, q.Where)
+
+	sql, err := Compile(q, env)
+	require.NoError(t, err)
+	assert.Equal(t, "TRUE", sql.Where)
+	assert.Equal(t, "t.created_at DESC, t.id DESC", sql.OrderBy)
+}
+
+func TestCompile(t *testing.T) {
+	sql := compile(t, `project = PMS AND status != Done AND assignee = currentUser() ORDER BY priority DESC`)
+
+	assert.Equal(t, "(("+
+		"(t.project_id IN (SELECT id FROM projects WHERE key = $1)) AND "+
+		"(t.status IS NOT NULL AND NOT (t.status IN ($2)))) AND "+
+		"(t.assignee_id IN ($3)))", sql.Where)
+	assert.Equal(t, []any{"PMS", "done", int64(7)}, sql.Args)
+	assert.Equal(t, priorityOrder+" DESC NULLS LAST, t.id DESC", sql.OrderBy)
+}
+
+func TestCompile_Sets(t *testing.T) {
+	sql := compile(t, `sprint in (12, openSprints()) and fixVersion is empty and not labels = backend`)
+
+	assert.Equal(t, "(("+
+		"(t.sprint_id IN ($1) OR t.sprint_id IN (SELECT id FROM sprints WHERE state IN ('planned', 'active'))) AND "+
+		"t.fix_version_id IS NULL) AND "+
+		"NOT COALESCE(EXISTS (SELECT 1 FROM ticket_labels tl JOIN labels l ON l.id = tl.label_id WHERE tl.ticket_id = t.id AND l.name IN ($2)), FALSE))",
+		sql.Where)
+	assert.Equal(t, []any{int64(12), "backend"}, sql.Args)
+}
+
+func TestCompile_Dates(t *testing.T) {
+	sql := compile(t, `created = 2024-05-01 and updated >= -7d and resolved < startOfWeek() and due <= 2d`)
+
+	assert.Equal(t, "((("+
+		"(t.created_at >= $1 AND t.created_at < $2) AND "+
+		"t.updated_at >= $3) AND "+
+		"t.resolved_at < $4) AND "+
+		"t.due_date <= $5::date)", sql.Where)
+	assert.Equal(t, []any{
+		time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC),
+		env.Now.Add(-7 * 24 * time.Hour),
+		time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC),
+		"2024-05-17",
+	}, sql.Args)
+}
+
+func TestCompile_Text(t *testing.T) {
+	sql := compile(t, `summary ~ "50%_off" and points > 3`)
+
+	assert.Equal(t, "(COALESCE(t.title, '') ILIKE $1 AND t.story_points > $2)", sql.Where)
+	assert.Equal(t, []any{`%50\%\_off%`, 3.0}, sql.Args)
+}
+
+func TestCompile_Errors(t *testing.T) {
+	cases := []string{
+		`color = red`,
+		`status > done`,
+		`summary = x`,
+		`reporter is empty`,
+		`created = yesterday`,
+		`points = many`,
+		`sprint = currentUser()`,
+		`assignee = openSprints()`,
+		`status = done order by description`,
+	}
+	for _, query := range cases {
+		q, err := Parse(query)
+		require.NoError(t, err, query)
+		_, err = Compile(q, env)
+		var qe *Error
+		assert.True(t, errors.As(err, &qe), query)
+	}
+}
@@ -0,0 +1,224 @@
+package version
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// Create handler for POST /api/projects/:id/versions
+func (h *Handler) Create(c echo.Context) error {
+	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid project ID"})
+	}
+
+	var req CreateVersionRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+	}
+
+	userID := c.Get("userID").(int64)
+
+	v, err := h.service.CreateVersion(c.Request().Context(), req, projectID, userID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusCreated, v)
+}
+
+// List handler for GET /api/projects/:id/versions?archived=true
+func (h *Handler) List(c echo.Context) error {
+	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid project ID"})
+	}
+	userID := c.Get("userID").(int64)
+	includeArchived := c.QueryParam("archived") == "true"
+
+	versions, err := h.service.ListVersions(c.Request().Context(), projectID, userID, includeArchived)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, versions)
+}
+
+// Get handler for GET /api/versions/:id
+func (h *Handler) Get(c echo.Context) error {
+	versionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid version ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	v, err := h.service.GetVersionByID(c.Request().Context(), versionID, userID)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, v)
+}
+
+// Update handler for PATCH /api/versions/:id
+func (h *Handler) Update(c echo.Context) error {
+	versionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid version ID"})
+	}
+
+	var req UpdateVersionRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+	}
+
+	userID := c.Get("userID").(int64)
+
+	v, err := h.service.UpdateVersion(c.Request().Context(), req, versionID, userID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, v)
+}
+
+// Delete handler for DELETE /api/versions/:id
+func (h *Handler) Delete(c echo.Context) error {
+	versionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid version ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	err = h.service.DeleteVersion(c.Request().Context(), versionID, userID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+type ticketIDsRequest struct {
+	TicketIDs []int64 `json:"ticket_ids"`
+}
+
+// Tickets handler for GET /api/versions/:id/tickets
+func (h *Handler) Tickets(c echo.Context) error {
+	versionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid version ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	tickets, err := h.service.ListTickets(c.Request().Context(), versionID, userID)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, tickets)
+}
+
+// AddTickets handler for POST /api/versions/:id/tickets
+func (h *Handler) AddTickets(c echo.Context) error {
+	versionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid version ID"})
+	}
+
+	var req ticketIDsRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+	}
+
+	userID := c.Get("userID").(int64)
+
+	err = h.service.AssignTickets(c.Request().Context(), versionID, userID, req.TicketIDs)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// RemoveTickets handler for DELETE /api/versions/:id/tickets
+func (h *Handler) RemoveTickets(c echo.Context) error {
+	versionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid version ID"})
+	}
+
+	var req ticketIDsRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+	}
+
+	userID := c.Get("userID").(int64)
+
+	err = h.service.RemoveTickets(c.Request().Context(), versionID, userID, req.TicketIDs)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Progress handler for GET /api/versions/:id/progress
+func (h *Handler) Progress(c echo.Context) error {
+	versionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid version ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	progress, err := h.service.GetProgress(c.Request().Context(), versionID, userID)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, progress)
+}
+
+// Release handler for POST /api/versions/:id/release
+func (h *Handler) Release(c echo.Context) error {
+	versionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid version ID"})
+	}
+
+	var req ReleaseVersionRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+	}
+
+	userID := c.Get("userID").(int64)
+
+	result, err := h.service.ReleaseVersion(c.Request().Context(), req, versionID, userID)
+	if err != nil {
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// ReleaseNotes handler for GET /api/versions/:id/release-notes?format=markdown|json
+func (h *Handler) ReleaseNotes(c echo.Context) error {
+	versionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid version ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	format := c.QueryParam("format")
+	if format != "" && format != "json" && format != "markdown" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "format must be json or markdown"})
+	}
+
+	notes, err := h.service.GetReleaseNotes(c.Request().Context(), versionID, userID)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+	if format == "markdown" {
+		return c.Blob(http.StatusOK, "text/markdown; charset=utf-8", []byte(notes.Markdown()))
+	}
+	return c.JSON(http.StatusOK, notes)
+}
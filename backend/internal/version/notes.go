@@ -0,0 +1,158 @@
+package version
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/antonovs105/project-management-system-go/internal/ticket"
+)
+
+// NoteItem is one ticket in release notes
+type NoteItem struct {
+	TicketID int64  `json:"ticket_id"`
+	Key      string `json:"key"`
+	Title    string `json:"title"`
+}
+
+// NoteGroup is tickets of one type
+type NoteGroup struct {
+	Type    string     `json:"type"`
+	Tickets []NoteItem `json:"tickets"`
+}
+
+// ReleaseNotes lists done tickets of version grouped by type, higher level types first
+type ReleaseNotes struct {
+	VersionID   int64        `json:"version_id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	ReleaseDate *ticket.Date `json:"release_date"`
+	Released    bool         `json:"released"`
+	Groups      []NoteGroup  `json:"groups"`
+}
+
+// GetReleaseNotes returns notes of done tickets, for unreleased version it is a preview
+func (s *Service) GetReleaseNotes(ctx context.Context, versionID, userID int64) (*ReleaseNotes, error) {
+	v, err := s.GetVersionByID(ctx, versionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tickets, err := s.repo.ListTickets(ctx, v.ID)
+	if err != nil {
+		return nil, err
+	}
+	var done []ticket.Ticket
+	for _, t := range tickets {
+		if ticket.StatusCategory(t.Status) == ticket.CategoryDone {
+			done = append(done, t)
+		}
+	}
+
+	return s.buildNotes(ctx, v, done)
+}
+
+// buildNotes groups tickets by type in order of issue type rank, unknown types go last by name
+func (s *Service) buildNotes(ctx context.Context, v *Version, tickets []ticket.Ticket) (*ReleaseNotes, error) {
+	scheme, err := s.schemes.SchemeForProject(ctx, v.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	notes := &ReleaseNotes{
+		VersionID:   v.ID,
+		Name:        v.Name,
+		Description: v.Description,
+		ReleaseDate: v.ReleaseDate,
+		Released:    v.Released,
+		Groups:      []NoteGroup{},
+	}
+	groups := make(map[string]int)
+	for _, t := range tickets {
+		i, ok := groups[t.Type]
+		if !ok {
+			i = len(notes.Groups)
+			groups[t.Type] = i
+			notes.Groups = append(notes.Groups, NoteGroup{Type: t.Type})
+		}
+		notes.Groups[i].Tickets = append(notes.Groups[i].Tickets, NoteItem{
+			TicketID: t.ID,
+			Key:      t.Key,
+			Title:    t.Title,
+		})
+	}
+
+	rank := func(name string) int {
+		if it, ok := scheme.Get(name); ok {
+			return it.Rank
+		}
+		return -1
+	}
+	sort.SliceStable(notes.Groups, func(i, j int) bool {
+		ri, rj := rank(notes.Groups[i].Type), rank(notes.Groups[j].Type)
+		if ri != rj {
+			return ri > rj
+		}
+		return notes.Groups[i].Type < notes.Groups[j].Type
+	})
+	return notes, nil
+}
+
+// Markdown renders release notes as Markdown document
+func (n *ReleaseNotes) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", escapeMarkdown(n.Name))
+
+	switch {
+	case n.Released && n.ReleaseDate != nil:
+		fmt.Fprintf(&b, "Released on %s\n\n", n.ReleaseDate)
+	case n.ReleaseDate != nil:
+		fmt.Fprintf(&b, "Planned for %s\n\n", n.ReleaseDate)
+	default:
+		b.WriteString("Unreleased\n\n")
+	}
+
+	if desc := strings.TrimSpace(n.Description); desc != "" {
+		b.WriteString(desc)
+		b.WriteString("\n\n")
+	}
+
+	if len(n.Groups) == 0 {
+		b.WriteString("_No completed tickets._\n")
+		return b.String()
+	}
+	for i, g := range n.Groups {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "## %s\n\n", escapeMarkdown(typeTitle(g.Type)))
+		for _, item := range g.Tickets {
+			fmt.Fprintf(&b, "- %s %s\n", item.Key, escapeMarkdown(item.Title))
+		}
+	}
+	return b.String()
+}
+
+// typeTitle capitalizes type name for heading
+func typeTitle(name string) string {
+	if name == "" {
+		return "Other"
+	}
+	r, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToUpper(r)) + name[size:]
+}
+
+// markdownEscaper backslash-escapes characters with meaning in Markdown, line breaks become spaces
+var markdownEscaper = strings.NewReplacer(
+	"\\", "\\\\", "`", "\\`", "*", "\\*", "_", "\\_", "{", "\\{", "}", "\\}", "[", "\\[", "]", "\\]",
+	"<", "\\<", ">", "\\>", "(", "\\(", ")", "\\)", "#", "\\#", "+", "\\+", "-", "\\-", "!", "\\!",
+	"|", "\\|", "~", "\\~", "&", "\\&", "\r\n", " ", "\n", " ", "\r", " ",
+)
+
+// escapeMarkdown makes user text render literally inside one Markdown line
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
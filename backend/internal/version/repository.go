@@ -0,0 +1,200 @@
+package version
+
+import (
+	"context"
+	"errors"
+
+	"github.com/antonovs105/project-management-system-go/internal/ticket"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type Repository interface {
+	Create(ctx context.Context, version *Version) error
+	GetByID(ctx context.Context, id int64) (*Version, error)
+	ListByProjectID(ctx context.Context, projectID int64, includeArchived bool) ([]Version, error)
+	Update(ctx context.Context, version *Version) error
+	Delete(ctx context.Context, id int64) error
+	AssignTickets(ctx context.Context, projectID int64, versionID *int64, ticketIDs []int64, changedBy int64) error
+	ListTickets(ctx context.Context, versionID int64) ([]ticket.Ticket, error)
+	Release(ctx context.Context, version *Version, unfinishedIDs []int64, moveTo *int64, changedBy int64) error
+}
+
+type PgRepository struct {
+	db *sqlx.DB
+}
+
+func NewRepository(db *sqlx.DB) Repository {
+	return &PgRepository{db: db}
+}
+
+// Create makes new version in DB
+func (r *PgRepository) Create(ctx context.Context, version *Version) error {
+	query := `
+		INSERT INTO versions (project_id, name, description, release_date)
+		VALUES (:project_id, :name, :description, :release_date)
+		RETURNING *`
+
+	rows, err := r.db.NamedQueryContext(ctx, query, version)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		return rows.StructScan(version)
+	}
+	return errors.New("version creation failed: no returning row")
+}
+
+// GetByID finds version by its id
+func (r *PgRepository) GetByID(ctx context.Context, id int64) (*Version, error) {
+	var v Version
+	query := `SELECT * FROM versions WHERE id = $1`
+	err := r.db.GetContext(ctx, &v, query, id)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// ListByProjectID returns versions of project, unreleased first by release date
+func (r *PgRepository) ListByProjectID(ctx context.Context, projectID int64, includeArchived bool) ([]Version, error) {
+	var versions []Version
+	query := `
+		SELECT * FROM versions
+		WHERE project_id = $1 AND ($2 OR NOT archived)
+		ORDER BY released, release_date NULLS LAST, created_at`
+
+	err := r.db.SelectContext(ctx, &versions, query, projectID, includeArchived)
+	if err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// Update saves version fields
+func (r *PgRepository) Update(ctx context.Context, version *Version) error {
+	query := `
+		UPDATE versions
+		SET name = :name, description = :description, release_date = :release_date,
+			released = :released, released_at = :released_at, archived = :archived, updated_at = now()
+		WHERE id = :id`
+
+	result, err := r.db.NamedExecContext(ctx, query, version)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("version not found")
+	}
+	return nil
+}
+
+// Delete removes version, its tickets lose fix version
+func (r *PgRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM versions WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("version not found")
+	}
+	return nil
+}
+
+// AssignTickets sets fix version of project tickets, nil version clears it
+func (r *PgRepository) AssignTickets(ctx context.Context, projectID int64, versionID *int64, ticketIDs []int64, changedBy int64) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// change log first, while old version is still known
+	if err := logVersionChanges(ctx, tx, versionID, ticketIDs, changedBy); err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE tickets SET fix_version_id = $1, updated_at = now()
+		WHERE project_id = $2 AND id = ANY($3)`
+
+	result, err := tx.ExecContext(ctx, query, versionID, projectID, pq.Array(ticketIDs))
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected != int64(len(ticketIDs)) {
+		return errors.New("some tickets not found in project")
+	}
+	return tx.Commit()
+}
+
+// logVersionChanges writes fix_version_id change log for tickets which really move
+func logVersionChanges(ctx context.Context, tx *sqlx.Tx, versionID *int64, ticketIDs []int64, changedBy int64) error {
+	query := `
+		INSERT INTO ticket_changes (ticket_id, field, old_value, new_value, changed_by)
+		SELECT id, $1, fix_version_id::text, $2::bigint::text, $4
+		FROM tickets
+		WHERE id = ANY($3) AND fix_version_id IS DISTINCT FROM $2::bigint`
+	_, err := tx.ExecContext(ctx, query, ticket.FieldFixVersionID, versionID, pq.Array(ticketIDs), changedBy)
+	return err
+}
+
+// ListTickets returns tickets targeting version
+func (r *PgRepository) ListTickets(ctx context.Context, versionID int64) ([]ticket.Ticket, error) {
+	var tickets []ticket.Ticket
+	query := `SELECT * FROM tickets WHERE fix_version_id = $1 ORDER BY number`
+
+	err := r.db.SelectContext(ctx, &tickets, query, versionID)
+	if err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
+// Release marks version released and moves unfinished tickets in one transaction
+func (r *PgRepository) Release(ctx context.Context, version *Version, unfinishedIDs []int64, moveTo *int64, changedBy int64) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if len(unfinishedIDs) > 0 {
+		if err := logVersionChanges(ctx, tx, moveTo, unfinishedIDs, changedBy); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx,
+			`UPDATE tickets SET fix_version_id = $1, updated_at = now() WHERE id = ANY($2)`,
+			moveTo, pq.Array(unfinishedIDs))
+		if err != nil {
+			return err
+		}
+	}
+
+	query := `
+		UPDATE versions
+		SET released = :released, released_at = :released_at, release_date = :release_date, updated_at = now()
+		WHERE id = :id`
+	if _, err := tx.NamedExecContext(ctx, query, version); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,84 @@
+package version
+
+import (
+	"context"
+
+	"github.com/antonovs105/project-management-system-go/internal/issuetype"
+	"github.com/antonovs105/project-management-system-go/internal/project"
+	"github.com/antonovs105/project-management-system-go/internal/ticket"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRepository is a mock implementation of Repository
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Create(ctx context.Context, version *Version) error {
+	args := m.Called(ctx, version)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetByID(ctx context.Context, id int64) (*Version, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Version), args.Error(1)
+}
+
+func (m *MockRepository) ListByProjectID(ctx context.Context, projectID int64, includeArchived bool) ([]Version, error) {
+	args := m.Called(ctx, projectID, includeArchived)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Version), args.Error(1)
+}
+
+func (m *MockRepository) Update(ctx context.Context, version *Version) error {
+	args := m.Called(ctx, version)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Delete(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRepository) AssignTickets(ctx context.Context, projectID int64, versionID *int64, ticketIDs []int64, changedBy int64) error {
+	args := m.Called(ctx, projectID, versionID, ticketIDs, changedBy)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ListTickets(ctx context.Context, versionID int64) ([]ticket.Ticket, error) {
+	args := m.Called(ctx, versionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]ticket.Ticket), args.Error(1)
+}
+
+func (m *MockRepository) Release(ctx context.Context, version *Version, unfinishedIDs []int64, moveTo *int64, changedBy int64) error {
+	args := m.Called(ctx, version, unfinishedIDs, moveTo, changedBy)
+	return args.Error(0)
+}
+
+// MockProjectChecker
+type MockProjectChecker struct {
+	mock.Mock
+}
+
+func (m *MockProjectChecker) GetProjectByID(ctx context.Context, projectID, userID int64) (*project.Project, error) {
+	args := m.Called(ctx, projectID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*project.Project), args.Error(1)
+}
+
+// StubSchemeProvider always returns default issue type scheme
+type StubSchemeProvider struct{}
+
+func (StubSchemeProvider) SchemeForProject(ctx context.Context, projectID int64) (*issuetype.Scheme, error) {
+	return issuetype.DefaultScheme(), nil
+}
@@ -0,0 +1,396 @@
+package version
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/issuetype"
+	"github.com/antonovs105/project-management-system-go/internal/project"
+	"github.com/antonovs105/project-management-system-go/internal/ticket"
+)
+
+// ProjectChecker interface
+type ProjectChecker interface {
+	GetProjectByID(ctx context.Context, projectID, userID int64) (*project.Project, error)
+}
+
+// SchemeProvider interface
+type SchemeProvider interface {
+	SchemeForProject(ctx context.Context, projectID int64) (*issuetype.Scheme, error)
+}
+
+type Service struct {
+	repo           Repository
+	projectService ProjectChecker
+	schemes        SchemeProvider
+	now            func() time.Time
+}
+
+func NewService(repo Repository, projectService ProjectChecker, schemes SchemeProvider) *Service {
+	return &Service{
+		repo:           repo,
+		projectService: projectService,
+		schemes:        schemes,
+		now:            time.Now,
+	}
+}
+
+// CreateVersionRequest DTO for version creation
+type CreateVersionRequest struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	ReleaseDate *ticket.Date `json:"release_date"`
+}
+
+// CreateVersion logic for adding new version to project
+func (s *Service) CreateVersion(ctx context.Context, req CreateVersionRequest, projectID, userID int64) (*Version, error) {
+	// check access
+	_, err := s.projectService.GetProjectByID(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("version name is required")
+	}
+
+	v := &Version{
+		ProjectID:   projectID,
+		Name:        name,
+		Description: req.Description,
+		ReleaseDate: req.ReleaseDate,
+	}
+
+	// unique constraint guards against duplicate names
+	if err := s.repo.Create(ctx, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// ListVersions returns versions of project, archived only on request
+func (s *Service) ListVersions(ctx context.Context, projectID, userID int64, includeArchived bool) ([]Version, error) {
+	// check access
+	_, err := s.projectService.GetProjectByID(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := s.repo.ListByProjectID(ctx, projectID, includeArchived)
+	if err != nil {
+		return nil, err
+	}
+	if versions == nil {
+		versions = []Version{}
+	}
+	return versions, nil
+}
+
+// GetVersionByID logic to get single version
+func (s *Service) GetVersionByID(ctx context.Context, versionID, userID int64) (*Version, error) {
+	v, _, err := s.getVersion(ctx, versionID, userID)
+	return v, err
+}
+
+// getVersion returns version with its project after access check
+func (s *Service) getVersion(ctx context.Context, versionID, userID int64) (*Version, *project.Project, error) {
+	v, err := s.repo.GetByID(ctx, versionID)
+	if err != nil {
+		return nil, nil, errors.New("version not found")
+	}
+
+	// check access
+	p, err := s.projectService.GetProjectByID(ctx, v.ProjectID, userID)
+	if err != nil {
+		return nil, nil, errors.New("version not found or access denied")
+	}
+
+	return v, p, nil
+}
+
+// UpdateVersionRequest DTO for updating version
+type UpdateVersionRequest struct {
+	Name        *string       `json:"name"`
+	Description *string       `json:"description"`
+	ReleaseDate **ticket.Date `json:"release_date"`
+	Archived    *bool         `json:"archived"`
+}
+
+// UpdateVersion logic for update, released version can only be renamed or archived
+func (s *Service) UpdateVersion(ctx context.Context, req UpdateVersionRequest, versionID, userID int64) (*Version, error) {
+	v, err := s.GetVersionByID(ctx, versionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, errors.New("version name is required")
+		}
+		v.Name = name
+	}
+	if req.Description != nil {
+		v.Description = *req.Description
+	}
+	if req.ReleaseDate != nil {
+		if v.Released {
+			return nil, errors.New("release date of released version can't be changed")
+		}
+		v.ReleaseDate = *req.ReleaseDate
+	}
+	if req.Archived != nil {
+		v.Archived = *req.Archived
+	}
+
+	if err := s.repo.Update(ctx, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// DeleteVersion removes unreleased version, its tickets lose fix version
+func (s *Service) DeleteVersion(ctx context.Context, versionID, userID int64) error {
+	v, err := s.GetVersionByID(ctx, versionID, userID)
+	if err != nil {
+		return err
+	}
+	if v.Released {
+		return errors.New("released version can't be deleted, archive it instead")
+	}
+
+	return s.repo.Delete(ctx, versionID)
+}
+
+// AssignTickets makes tickets target version
+func (s *Service) AssignTickets(ctx context.Context, versionID, userID int64, ticketIDs []int64) error {
+	v, err := s.GetVersionByID(ctx, versionID, userID)
+	if err != nil {
+		return err
+	}
+	if v.Released || v.Archived {
+		return errors.New("tickets can target only unreleased version")
+	}
+	if len(ticketIDs) == 0 {
+		return errors.New("no tickets given")
+	}
+
+	return s.repo.AssignTickets(ctx, v.ProjectID, &v.ID, ticketIDs, userID)
+}
+
+// RemoveTickets clears fix version of tickets targeting version
+func (s *Service) RemoveTickets(ctx context.Context, versionID, userID int64, ticketIDs []int64) error {
+	v, err := s.GetVersionByID(ctx, versionID, userID)
+	if err != nil {
+		return err
+	}
+	if len(ticketIDs) == 0 {
+		return errors.New("no tickets given")
+	}
+
+	tickets, err := s.repo.ListTickets(ctx, v.ID)
+	if err != nil {
+		return err
+	}
+	inVersion := make(map[int64]bool, len(tickets))
+	for _, t := range tickets {
+		inVersion[t.ID] = true
+	}
+	for _, id := range ticketIDs {
+		if !inVersion[id] {
+			return errors.New("some tickets don't target this version")
+		}
+	}
+
+	return s.repo.AssignTickets(ctx, v.ProjectID, nil, ticketIDs, userID)
+}
+
+// ListTickets returns tickets targeting version
+func (s *Service) ListTickets(ctx context.Context, versionID, userID int64) ([]ticket.Ticket, error) {
+	v, err := s.GetVersionByID(ctx, versionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tickets, err := s.repo.ListTickets(ctx, v.ID)
+	if err != nil {
+		return nil, err
+	}
+	if tickets == nil {
+		tickets = []ticket.Ticket{}
+	}
+	return tickets, nil
+}
+
+// GetProgress returns counts and points of version tickets by status category
+func (s *Service) GetProgress(ctx context.Context, versionID, userID int64) (*Progress, error) {
+	v, p, err := s.getVersion(ctx, versionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tickets, err := s.repo.ListTickets(ctx, v.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := &Progress{Version: v, Total: len(tickets)}
+	for _, t := range tickets {
+		done := false
+		switch ticket.StatusCategory(t.Status) {
+		case ticket.CategoryDone:
+			progress.Done++
+			done = true
+		case ticket.CategoryInProgress:
+			progress.InProgress++
+		default:
+			progress.Todo++
+		}
+
+		if t.StoryPoints == nil {
+			progress.Unestimated++
+			continue
+		}
+		progress.Points += *t.StoryPoints
+		if done {
+			progress.DonePoints += *t.StoryPoints
+		}
+	}
+	if progress.Total > 0 {
+		progress.Percent = math.Round(float64(progress.Done)/float64(progress.Total)*1000) / 10
+	}
+
+	today := p.Today(s.now())
+	progress.Overdue = !v.Released && v.ReleaseDate != nil && v.ReleaseDate.Before(today)
+	return progress, nil
+}
+
+// ReleaseVersionRequest DTO for releasing version. Release date defaults to today.
+// Unfinished tickets go to MoveToVersionID, to next unreleased version if MoveToNext, or lose fix version
+type ReleaseVersionRequest struct {
+	ReleaseDate     *ticket.Date `json:"release_date"`
+	MoveToVersionID *int64       `json:"move_to_version_id"`
+	MoveToNext      bool         `json:"move_to_next"`
+}
+
+// ReleaseVersionResult tells what happened to version tickets
+type ReleaseVersionResult struct {
+	Version        *Version      `json:"version"`
+	Completed      int           `json:"completed"`
+	CarriedOver    int           `json:"carried_over"`
+	MovedToVersion *int64        `json:"moved_to_version_id"`
+	Notes          *ReleaseNotes `json:"release_notes"`
+}
+
+// ReleaseVersion marks version released, carries over unfinished tickets and returns release notes
+func (s *Service) ReleaseVersion(ctx context.Context, req ReleaseVersionRequest, versionID, userID int64) (*ReleaseVersionResult, error) {
+	v, p, err := s.getVersion(ctx, versionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if v.Released {
+		return nil, errors.New("version is already released")
+	}
+	if v.Archived {
+		return nil, errors.New("archived version can't be released")
+	}
+
+	moveTo := req.MoveToVersionID
+	if moveTo == nil && req.MoveToNext {
+		next, err := s.nextUnreleasedVersion(ctx, v)
+		if err != nil {
+			return nil, err
+		}
+		if next != nil {
+			moveTo = &next.ID
+		}
+	}
+	if moveTo != nil {
+		target, err := s.repo.GetByID(ctx, *moveTo)
+		if err != nil || target.ProjectID != v.ProjectID || target.ID == v.ID {
+			return nil, errors.New("target version not found in project")
+		}
+		if target.Released || target.Archived {
+			return nil, errors.New("unfinished tickets can be moved only to unreleased version")
+		}
+	}
+
+	tickets, err := s.repo.ListTickets(ctx, v.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []ticket.Ticket
+	var unfinished []int64
+	for _, t := range tickets {
+		if ticket.StatusCategory(t.Status) == ticket.CategoryDone {
+			done = append(done, t)
+		} else {
+			unfinished = append(unfinished, t.ID)
+		}
+	}
+
+	now := s.now()
+	v.Released = true
+	v.ReleasedAt = &now
+	if req.ReleaseDate != nil {
+		v.ReleaseDate = req.ReleaseDate
+	} else {
+		today := ticket.NewDate(p.Today(now))
+		v.ReleaseDate = &today
+	}
+
+	if err := s.repo.Release(ctx, v, unfinished, moveTo, userID); err != nil {
+		return nil, err
+	}
+
+	notes, err := s.buildNotes(ctx, v, done)
+	if err != nil {
+		return nil, err
+	}
+	return &ReleaseVersionResult{
+		Version:        v,
+		Completed:      len(done),
+		CarriedOver:    len(unfinished),
+		MovedToVersion: moveTo,
+		Notes:          notes,
+	}, nil
+}
+
+// nextUnreleasedVersion picks active version released first, versions without date go last by creation
+func (s *Service) nextUnreleasedVersion(ctx context.Context, current *Version) (*Version, error) {
+	versions, err := s.repo.ListByProjectID(ctx, current.ProjectID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []Version
+	for _, v := range versions {
+		if !v.Released && !v.Archived && v.ID != current.ID {
+			candidates = append(candidates, v)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		switch {
+		case a.ReleaseDate != nil && b.ReleaseDate != nil:
+			return a.ReleaseDate.Before(b.ReleaseDate.Time)
+		case a.ReleaseDate != nil:
+			return true
+		case b.ReleaseDate != nil:
+			return false
+		default:
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+	})
+	return &candidates[0], nil
+}
@@ -0,0 +1,183 @@
+package version
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/project"
+	"github.com/antonovs105/project-management-system-go/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func date(s string) *ticket.Date {
+	d, err := ticket.ParseDate(s)
+	if err != nil {
+		panic(err)
+	}
+	return &d
+}
+
+func TestService_GetProgress(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockProject := new(MockProjectChecker)
+	service := NewService(mockRepo, mockProject, StubSchemeProvider{})
+	service.now = func() time.Time { return time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC) }
+
+	ctx := context.Background()
+	projectID := int64(10)
+	userID := int64(1)
+	points := func(v float64) *float64 { return &v }
+
+	t.Run("Success", func(t *testing.T) {
+		v := &Version{ID: 1, ProjectID: projectID, ReleaseDate: date("2024-05-01")}
+		mockRepo.On("GetByID", ctx, int64(1)).Return(v, nil).Once()
+		mockProject.On("GetProjectByID", ctx, projectID, userID).Return(&project.Project{Timezone: "UTC"}, nil).Once()
+		mockRepo.On("ListTickets", ctx, int64(1)).Return([]ticket.Ticket{
+			{ID: 100, Status: "done", StoryPoints: points(5)},
+			{ID: 101, Status: "in_progress", StoryPoints: points(3)},
+			{ID: 102, Status: "new"},
+			{ID: 103, Status: "done"},
+		}, nil).Once()
+
+		progress, err := service.GetProgress(ctx, 1, userID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, progress.Total)
+		assert.Equal(t, 2, progress.Done)
+		assert.Equal(t, 1, progress.InProgress)
+		assert.Equal(t, 1, progress.Todo)
+		assert.Equal(t, 8.0, progress.Points)
+		assert.Equal(t, 5.0, progress.DonePoints)
+		assert.Equal(t, 2, progress.Unestimated)
+		assert.Equal(t, 50.0, progress.Percent)
+		assert.True(t, progress.Overdue)
+	})
+}
+
+func TestService_ReleaseVersion(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockProject := new(MockProjectChecker)
+	service := NewService(mockRepo, mockProject, StubSchemeProvider{})
+	now := time.Date(2024, 5, 10, 23, 30, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	projectID := int64(10)
+	userID := int64(1)
+	tickets := []ticket.Ticket{
+		{ID: 100, Key: "PMS-1", Title: "Login", Type: "task", Status: "done"},
+		{ID: 101, Key: "PMS-2", Title: "Auth", Type: "epic", Status: "done"},
+		{ID: 102, Key: "PMS-3", Title: "Logout", Type: "task", Status: "in_progress"},
+	}
+
+	t.Run("MoveToNext", func(t *testing.T) {
+		v := &Version{ID: 1, ProjectID: projectID, Name: "1.0"}
+		mockRepo.On("GetByID", ctx, int64(1)).Return(v, nil).Once()
+		mockProject.On("GetProjectByID", ctx, projectID, userID).Return(&project.Project{Timezone: "Europe/Berlin"}, nil).Once()
+		mockRepo.On("ListByProjectID", ctx, projectID, false).Return([]Version{
+			{ID: 1, ProjectID: projectID},
+			{ID: 3, ProjectID: projectID},
+			{ID: 2, ProjectID: projectID, ReleaseDate: date("2024-06-01")},
+		}, nil).Once()
+		mockRepo.On("GetByID", ctx, int64(2)).Return(&Version{ID: 2, ProjectID: projectID}, nil).Once()
+		mockRepo.On("ListTickets", ctx, int64(1)).Return(tickets, nil).Once()
+		next := int64(2)
+		mockRepo.On("Release", ctx, v, []int64{102}, &next, userID).Return(nil).Once()
+
+		result, err := service.ReleaseVersion(ctx, ReleaseVersionRequest{MoveToNext: true}, 1, userID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Completed)
+		assert.Equal(t, 1, result.CarriedOver)
+		assert.Equal(t, &next, result.MovedToVersion)
+		assert.True(t, v.Released)
+		// already next day in project timezone
+		assert.Equal(t, "2024-05-11", v.ReleaseDate.String())
+
+		assert.Len(t, result.Notes.Groups, 2)
+		assert.Equal(t, "epic", result.Notes.Groups[0].Type)
+		assert.Equal(t, "task", result.Notes.Groups[1].Type)
+		assert.Equal(t, "PMS-1", result.Notes.Groups[1].Tickets[0].Key)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("AlreadyReleased", func(t *testing.T) {
+		v := &Version{ID: 4, ProjectID: projectID, Released: true}
+		mockRepo.On("GetByID", ctx, int64(4)).Return(v, nil).Once()
+		mockProject.On("GetProjectByID", ctx, projectID, userID).Return(&project.Project{}, nil).Once()
+
+		_, err := service.ReleaseVersion(ctx, ReleaseVersionRequest{}, 4, userID)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "already released")
+	})
+
+	t.Run("TargetReleased", func(t *testing.T) {
+		v := &Version{ID: 5, ProjectID: projectID}
+		target := int64(6)
+		mockRepo.On("GetByID", ctx, int64(5)).Return(v, nil).Once()
+		mockProject.On("GetProjectByID", ctx, projectID, userID).Return(&project.Project{}, nil).Once()
+		mockRepo.On("GetByID", ctx, int64(6)).Return(&Version{ID: 6, ProjectID: projectID, Released: true}, nil).Once()
+
+		_, err := service.ReleaseVersion(ctx, ReleaseVersionRequest{MoveToVersionID: &target}, 5, userID)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "only to unreleased version")
+	})
+}
+
+func TestService_RemoveTickets(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockProject := new(MockProjectChecker)
+	service := NewService(mockRepo, mockProject, StubSchemeProvider{})
+
+	ctx := context.Background()
+	projectID := int64(10)
+	userID := int64(1)
+
+	t.Run("NotInVersion", func(t *testing.T) {
+		mockRepo.On("GetByID", ctx, int64(1)).Return(&Version{ID: 1, ProjectID: projectID}, nil).Once()
+		mockProject.On("GetProjectByID", ctx, projectID, userID).Return(&project.Project{}, nil).Once()
+		mockRepo.On("ListTickets", ctx, int64(1)).Return([]ticket.Ticket{{ID: 100}}, nil).Once()
+
+		err := service.RemoveTickets(ctx, 1, userID, []int64{100, 200})
+
+		assert.Error(t, err)
+		mockRepo.AssertNotCalled(t, "AssignTickets", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestReleaseNotes_Markdown(t *testing.T) {
+	notes := &ReleaseNotes{
+		Name:        "1.0",
+		Description: "First public release",
+		ReleaseDate: date("2024-05-11"),
+		Released:    true,
+		Groups: []NoteGroup{
+			{Type: "epic", Tickets: []NoteItem{{Key: "PMS-2", Title: "Auth"}}},
+			{Type: "task", Tickets: []NoteItem{{Key: "PMS-1", Title: "Login"}}},
+		},
+	}
+
+	expected := "# 1.0\n\nReleased on 2024-05-11\n\nFirst public release\n\n" +
+		"## Epic\n\n- PMS-2 Auth\n\n## Task\n\n- PMS-1 Login\n"
+	assert.Equal(t, expected, notes.Markdown())
+
+	empty := &ReleaseNotes{Name: "2.0"}
+	assert.Equal(t, "# 2.0\n\nUnreleased\n\n_No completed tickets._\n", empty.Markdown())
+}
+
+func TestReleaseNotes_MarkdownEscaping(t *testing.T) {
+	notes := &ReleaseNotes{
+		Name: "v1 [beta]",
+		Groups: []NoteGroup{
+			{Type: "éclair", Tickets: []NoteItem{{Key: "PMS-1", Title: "Fix *bold* <script>\n# heading [x](http://evil)"}}},
+		},
+	}
+
+	expected := "# v1 \\[beta\\]\n\nUnreleased\n\n" +
+		"## Éclair\n\n- PMS-1 Fix \\*bold\\* \\<script\\> \\# heading \\[x\\]\\(http://evil\\)\n"
+	assert.Equal(t, expected, notes.Markdown())
+}
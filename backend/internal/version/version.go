@@ -0,0 +1,37 @@
+package version
+
+import (
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/ticket"
+)
+
+// Version is project release which tickets can target
+type Version struct {
+	ID          int64        `db:"id" json:"id"`
+	ProjectID   int64        `db:"project_id" json:"project_id"`
+	Name        string       `db:"name" json:"name"`
+	Description string       `db:"description" json:"description"`
+	ReleaseDate *ticket.Date `db:"release_date" json:"release_date"`
+	Released    bool         `db:"released" json:"released"`
+	ReleasedAt  *time.Time   `db:"released_at" json:"released_at"`
+	Archived    bool         `db:"archived" json:"archived"`
+	CreatedAt   time.Time    `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time    `db:"updated_at" json:"updated_at"`
+}
+
+// Progress is state of tickets targeting version
+type Progress struct {
+	Version     *Version `json:"version"`
+	Total       int      `json:"total"`
+	Todo        int      `json:"todo"`
+	InProgress  int      `json:"in_progress"`
+	Done        int      `json:"done"`
+	Points      float64  `json:"points"`
+	DonePoints  float64  `json:"done_points"`
+	Unestimated int      `json:"unestimated"`
+	// Percent is share of done tickets
+	Percent float64 `json:"percent"`
+	// Overdue means release date has passed and version is not released
+	Overdue bool `json:"overdue"`
+}
@@ -0,0 +1,185 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// Delivery settings
+const (
+	requestTimeout = 10 * time.Second
+	// lease is how long claimed delivery is hidden from other dispatchers. Deliveries are claimed
+	// one by one, so lease only has to cover single request
+	lease = time.Minute
+	// MaxAttempts before delivery is failed
+	MaxAttempts = 8
+	// MaxFailures in a row before webhook is disabled
+	MaxFailures = 20
+	// maxResponseBody stored in attempt log
+	maxResponseBody = 4096
+	// batchSize is max number of deliveries sent per dispatch
+	batchSize       = 20
+	firstRetryDelay = 30 * time.Second
+	maxRetryDelay   = 6 * time.Hour
+)
+
+// RetryDelay is exponential backoff after failed attempt: 30s, 1m, 2m ... capped at 6h
+func RetryDelay(attempt int) time.Duration {
+	delay := firstRetryDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= maxRetryDelay {
+			return maxRetryDelay
+		}
+	}
+	return delay
+}
+
+// ErrForbiddenAddress is returned when webhook host resolves to internal address
+var ErrForbiddenAddress = errors.New("webhook address is not allowed")
+
+// cgnat is shared address space (RFC 6598), not covered by netip.Addr.IsPrivate
+var cgnat = netip.MustParsePrefix("100.64.0.0/10")
+
+// publicAddr reports if addr is reachable public unicast address
+func publicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() && !cgnat.Contains(addr)
+}
+
+// dialControl checks address right before connecting, after DNS resolution, so host which
+// resolves to internal address (including DNS rebinding) is refused
+func dialControl(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !publicAddr(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, addrPort.Addr())
+	}
+	return nil
+}
+
+// newClient makes HTTP client for deliveries. It connects only to public addresses, ignores
+// proxy settings and does not follow redirects
+func newClient() *http.Client {
+	dialer := &net.Dialer{Timeout: requestTimeout, Control: dialControl}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   requestTimeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// RunDispatcher sends due deliveries every interval until ctx is done
+func (s *Service) RunDispatcher(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.DispatchDue(ctx); err != nil {
+				log.Printf("Webhook dispatch failed: %v", err)
+			}
+		}
+	}
+}
+
+// DispatchDue sends up to batchSize due deliveries, returns number of sent requests.
+// Each delivery is claimed right before sending, so its lease never waits behind slow ones
+func (s *Service) DispatchDue(ctx context.Context) (int, error) {
+	sent := 0
+	for sent < batchSize && ctx.Err() == nil {
+		deliveries, err := s.repo.ClaimDue(ctx, 1, lease)
+		if err != nil {
+			return sent, err
+		}
+		if len(deliveries) == 0 {
+			break
+		}
+		if err := s.deliver(ctx, &deliveries[0]); err != nil {
+			log.Printf("Can't record webhook delivery %d: %v", deliveries[0].ID, err)
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// deliver sends delivery once and records result
+func (s *Service) deliver(ctx context.Context, d *Delivery) error {
+	w, err := s.repo.GetByID(ctx, d.WebhookID)
+	if err != nil {
+		return err
+	}
+
+	body := []byte(d.Payload)
+	timestamp := s.now().Unix()
+	attempt := &Attempt{DeliveryID: d.ID, RequestBody: d.Payload}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	var resp *http.Response
+	start := time.Now()
+	if err == nil {
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", "pms-webhooks/1.0")
+		req.Header.Set("X-Webhook-Event", d.EventType)
+		req.Header.Set("X-Webhook-Delivery", strconv.FormatInt(d.ID, 10))
+		req.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+		req.Header.Set("X-Webhook-Signature", Sign(w.Secret, timestamp, body))
+		headers, _ := json.Marshal(req.Header)
+		attempt.RequestHeaders = string(headers)
+
+		resp, err = s.client.Do(req)
+	}
+	attempt.DurationMs = int(time.Since(start).Milliseconds())
+
+	d.Attempts++
+	d.Error = nil
+	d.ResponseStatus = nil
+	if err != nil {
+		msg := err.Error()
+		attempt.Error = &msg
+		d.Error = &msg
+	} else {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+		resp.Body.Close()
+		status := resp.StatusCode
+		text := string(respBody)
+		attempt.ResponseStatus = &status
+		attempt.ResponseBody = &text
+		d.ResponseStatus = &status
+	}
+
+	now := s.now()
+	switch {
+	case err == nil && d.ResponseStatus != nil && *d.ResponseStatus < 300 && *d.ResponseStatus >= 200:
+		d.Status = StatusSucceeded
+		d.DeliveredAt = &now
+	case d.Attempts >= MaxAttempts:
+		d.Status = StatusFailed
+	default:
+		d.Status = StatusPending
+		d.NextAttemptAt = now.Add(RetryDelay(d.Attempts))
+	}
+
+	return s.repo.RecordAttempt(context.WithoutCancel(ctx), d, attempt, MaxFailures)
+}
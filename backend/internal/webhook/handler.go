@@ -0,0 +1,159 @@
+package webhook
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// Create handler for POST /api/projects/:projectID/webhooks
+func (h *Handler) Create(c echo.Context) error {
+	projectID, err := strconv.ParseInt(c.Param("projectID"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid project ID"})
+	}
+
+	var req CreateWebhookRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+	}
+
+	userID := c.Get("userID").(int64)
+
+	w, err := h.service.CreateWebhook(c.Request().Context(), req, projectID, userID)
+	if err != nil {
+		return errorJSON(c, err)
+	}
+	return c.JSON(http.StatusCreated, w)
+}
+
+// List handler for GET /api/projects/:projectID/webhooks
+func (h *Handler) List(c echo.Context) error {
+	projectID, err := strconv.ParseInt(c.Param("projectID"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid project ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	webhooks, err := h.service.ListWebhooks(c.Request().Context(), projectID, userID)
+	if err != nil {
+		return errorJSON(c, err)
+	}
+	return c.JSON(http.StatusOK, webhooks)
+}
+
+// Get handler for GET /api/webhooks/:id
+func (h *Handler) Get(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid webhook ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	w, err := h.service.GetWebhook(c.Request().Context(), id, userID)
+	if err != nil {
+		return errorJSON(c, err)
+	}
+	return c.JSON(http.StatusOK, w)
+}
+
+// Update handler for PATCH /api/webhooks/:id
+func (h *Handler) Update(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid webhook ID"})
+	}
+
+	var req UpdateWebhookRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+	}
+
+	userID := c.Get("userID").(int64)
+
+	w, err := h.service.UpdateWebhook(c.Request().Context(), req, id, userID)
+	if err != nil {
+		return errorJSON(c, err)
+	}
+	return c.JSON(http.StatusOK, w)
+}
+
+// Delete handler for DELETE /api/webhooks/:id
+func (h *Handler) Delete(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid webhook ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	if err := h.service.DeleteWebhook(c.Request().Context(), id, userID); err != nil {
+		return errorJSON(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Deliveries handler for GET /api/webhooks/:id/deliveries
+func (h *Handler) Deliveries(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid webhook ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	deliveries, err := h.service.ListDeliveries(c.Request().Context(), id, userID)
+	if err != nil {
+		return errorJSON(c, err)
+	}
+	return c.JSON(http.StatusOK, deliveries)
+}
+
+// Delivery handler for GET /api/webhook-deliveries/:id
+func (h *Handler) Delivery(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid delivery ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	delivery, err := h.service.GetDelivery(c.Request().Context(), id, userID)
+	if err != nil {
+		return errorJSON(c, err)
+	}
+	return c.JSON(http.StatusOK, delivery)
+}
+
+// Redeliver handler for POST /api/webhook-deliveries/:id/redeliver
+func (h *Handler) Redeliver(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid delivery ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	delivery, err := h.service.Redeliver(c.Request().Context(), id, userID)
+	if err != nil {
+		return errorJSON(c, err)
+	}
+	return c.JSON(http.StatusAccepted, delivery)
+}
+
+func errorJSON(c echo.Context, err error) error {
+	status := http.StatusBadRequest
+	switch {
+	case errors.Is(err, ErrForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, ErrNotFound):
+		status = http.StatusNotFound
+	}
+	return c.JSON(status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,252 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/event"
+	"github.com/jmoiron/sqlx"
+)
+
+type Repository interface {
+	Create(ctx context.Context, w *Webhook) error
+	GetByID(ctx context.Context, id int64) (*Webhook, error)
+	ListByProjectID(ctx context.Context, projectID int64) ([]Webhook, error)
+	Update(ctx context.Context, w *Webhook) error
+	Delete(ctx context.Context, id int64) error
+	Enqueue(ctx context.Context, tx sqlx.ExtContext, e event.Event, payload string) error
+	CreateDelivery(ctx context.Context, d *Delivery) error
+	GetDelivery(ctx context.Context, id int64) (*Delivery, error)
+	ListDeliveries(ctx context.Context, webhookID int64, limit int) ([]Delivery, error)
+	ListAttempts(ctx context.Context, deliveryID int64) ([]Attempt, error)
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]Delivery, error)
+	RecordAttempt(ctx context.Context, d *Delivery, a *Attempt, maxFailures int) error
+}
+
+type PgRepository struct {
+	db *sqlx.DB
+}
+
+func NewRepository(db *sqlx.DB) Repository {
+	return &PgRepository{db: db}
+}
+
+// Create makes new webhook in DB
+func (r *PgRepository) Create(ctx context.Context, w *Webhook) error {
+	query := `
+		INSERT INTO webhooks (project_id, url, secret, event_types, created_by)
+		VALUES (:project_id, :url, :secret, :event_types, :created_by)
+		RETURNING *`
+
+	rows, err := r.db.NamedQueryContext(ctx, query, w)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		return rows.StructScan(w)
+	}
+	return errors.New("webhook creation failed: no returning row")
+}
+
+// GetByID finds webhook by its id
+func (r *PgRepository) GetByID(ctx context.Context, id int64) (*Webhook, error) {
+	var w Webhook
+	err := r.db.GetContext(ctx, &w, `SELECT * FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// ListByProjectID returns webhooks of project, oldest first
+func (r *PgRepository) ListByProjectID(ctx context.Context, projectID int64) ([]Webhook, error) {
+	var webhooks []Webhook
+	err := r.db.SelectContext(ctx, &webhooks, `SELECT * FROM webhooks WHERE project_id = $1 ORDER BY id`, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// Update saves url, event types and state of webhook
+func (r *PgRepository) Update(ctx context.Context, w *Webhook) error {
+	query := `
+		UPDATE webhooks
+		SET url = :url, event_types = :event_types, active = :active,
+			failure_count = :failure_count, disabled_at = :disabled_at, updated_at = now()
+		WHERE id = :id`
+
+	result, err := r.db.NamedExecContext(ctx, query, w)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("webhook not found")
+	}
+	return nil
+}
+
+// Delete removes webhook with its deliveries
+func (r *PgRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("webhook not found")
+	}
+	return nil
+}
+
+// Enqueue queues event for every active webhook of its project subscribed to event type.
+// Event already queued for webhook is skipped
+func (r *PgRepository) Enqueue(ctx context.Context, tx sqlx.ExtContext, e event.Event, payload string) error {
+	query := `
+		INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload)
+		SELECT id, $2, $3, $4
+		FROM webhooks
+		WHERE project_id = $1 AND active
+			AND (cardinality(event_types) = 0 OR $3 = ANY(event_types))
+		ON CONFLICT (webhook_id, event_id) DO NOTHING`
+
+	_, err := tx.ExecContext(ctx, query, e.ProjectID, e.ID, e.Type, payload)
+	return err
+}
+
+// CreateDelivery queues single delivery
+func (r *PgRepository) CreateDelivery(ctx context.Context, d *Delivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload, redelivery_of)
+		VALUES (:webhook_id, :event_id, :event_type, :payload, :redelivery_of)
+		RETURNING *`
+
+	rows, err := r.db.NamedQueryContext(ctx, query, d)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		return rows.StructScan(d)
+	}
+	return errors.New("delivery creation failed: no returning row")
+}
+
+// GetDelivery finds delivery by its id
+func (r *PgRepository) GetDelivery(ctx context.Context, id int64) (*Delivery, error) {
+	var d Delivery
+	err := r.db.GetContext(ctx, &d, `SELECT * FROM webhook_deliveries WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// ListDeliveries returns latest deliveries of webhook, newest first
+func (r *PgRepository) ListDeliveries(ctx context.Context, webhookID int64, limit int) ([]Delivery, error) {
+	var deliveries []Delivery
+	query := `SELECT * FROM webhook_deliveries WHERE webhook_id = $1 ORDER BY id DESC LIMIT $2`
+
+	err := r.db.SelectContext(ctx, &deliveries, query, webhookID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// ListAttempts returns attempts of delivery, oldest first
+func (r *PgRepository) ListAttempts(ctx context.Context, deliveryID int64) ([]Attempt, error) {
+	var attempts []Attempt
+	query := `SELECT * FROM webhook_attempts WHERE delivery_id = $1 ORDER BY id`
+
+	err := r.db.SelectContext(ctx, &attempts, query, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	return attempts, nil
+}
+
+// ClaimDue takes due pending deliveries of active webhooks. Claimed rows are leased by moving
+// next attempt into future, so other dispatchers skip them and crashed dispatcher's rows come back
+func (r *PgRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]Delivery, error) {
+	var deliveries []Delivery
+	query := `
+		UPDATE webhook_deliveries
+		SET next_attempt_at = now() + $2 * interval '1 millisecond', updated_at = now()
+		WHERE id IN (
+			SELECT d.id
+			FROM webhook_deliveries d
+			JOIN webhooks w ON w.id = d.webhook_id AND w.active
+			WHERE d.status = 'pending' AND d.next_attempt_at <= now()
+			ORDER BY d.next_attempt_at
+			LIMIT $1
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		RETURNING *`
+
+	err := r.db.SelectContext(ctx, &deliveries, query, limit, lease.Milliseconds())
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// RecordAttempt saves attempt result in delivery, logs attempt and updates failure counter of webhook.
+// Webhook with maxFailures consecutive failures is disabled
+func (r *PgRepository) RecordAttempt(ctx context.Context, d *Delivery, a *Attempt, maxFailures int) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, next_attempt_at = $4, response_status = $5,
+			error = $6, delivered_at = $7, updated_at = now()
+		WHERE id = $1`
+	_, err = tx.ExecContext(ctx, query, d.ID, d.Status, d.Attempts, d.NextAttemptAt,
+		d.ResponseStatus, d.Error, d.DeliveredAt)
+	if err != nil {
+		return err
+	}
+
+	query = `
+		INSERT INTO webhook_attempts (delivery_id, request_headers, request_body, response_status, response_body, error, duration_ms)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err = tx.ExecContext(ctx, query, d.ID, a.RequestHeaders, a.RequestBody,
+		a.ResponseStatus, a.ResponseBody, a.Error, a.DurationMs)
+	if err != nil {
+		return err
+	}
+
+	if d.Status == StatusSucceeded {
+		query = `UPDATE webhooks SET failure_count = 0 WHERE id = $1`
+		_, err = tx.ExecContext(ctx, query, d.WebhookID)
+	} else {
+		query = `
+			UPDATE webhooks
+			SET failure_count = failure_count + 1,
+				active = failure_count + 1 < $2,
+				disabled_at = CASE WHEN failure_count + 1 >= $2 THEN now() END,
+				updated_at = now()
+			WHERE id = $1 AND active`
+		_, err = tx.ExecContext(ctx, query, d.WebhookID, maxFailures)
+	}
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
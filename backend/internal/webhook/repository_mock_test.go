@@ -0,0 +1,107 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/event"
+	"github.com/antonovs105/project-management-system-go/internal/project"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRepository is a mock implementation of Repository
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Create(ctx context.Context, w *Webhook) error {
+	args := m.Called(ctx, w)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetByID(ctx context.Context, id int64) (*Webhook, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Webhook), args.Error(1)
+}
+
+func (m *MockRepository) ListByProjectID(ctx context.Context, projectID int64) ([]Webhook, error) {
+	args := m.Called(ctx, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Webhook), args.Error(1)
+}
+
+func (m *MockRepository) Update(ctx context.Context, w *Webhook) error {
+	args := m.Called(ctx, w)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Delete(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Enqueue(ctx context.Context, tx sqlx.ExtContext, e event.Event, payload string) error {
+	args := m.Called(ctx, tx, e, payload)
+	return args.Error(0)
+}
+
+func (m *MockRepository) CreateDelivery(ctx context.Context, d *Delivery) error {
+	args := m.Called(ctx, d)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetDelivery(ctx context.Context, id int64) (*Delivery, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Delivery), args.Error(1)
+}
+
+func (m *MockRepository) ListDeliveries(ctx context.Context, webhookID int64, limit int) ([]Delivery, error) {
+	args := m.Called(ctx, webhookID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Delivery), args.Error(1)
+}
+
+func (m *MockRepository) ListAttempts(ctx context.Context, deliveryID int64) ([]Attempt, error) {
+	args := m.Called(ctx, deliveryID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Attempt), args.Error(1)
+}
+
+func (m *MockRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]Delivery, error) {
+	args := m.Called(ctx, limit, lease)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Delivery), args.Error(1)
+}
+
+func (m *MockRepository) RecordAttempt(ctx context.Context, d *Delivery, a *Attempt, maxFailures int) error {
+	args := m.Called(ctx, d, a, maxFailures)
+	return args.Error(0)
+}
+
+// MockProjectChecker
+type MockProjectChecker struct {
+	mock.Mock
+}
+
+func (m *MockProjectChecker) GetProjectByID(ctx context.Context, projectID, userID int64) (*project.Project, error) {
+	args := m.Called(ctx, projectID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*project.Project), args.Error(1)
+}
@@ -0,0 +1,282 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/event"
+	"github.com/antonovs105/project-management-system-go/internal/project"
+	"github.com/jmoiron/sqlx"
+)
+
+// deliveriesLimit is number of latest deliveries in log
+const deliveriesLimit = 100
+
+var (
+	ErrNotFound  = errors.New("webhook not found")
+	ErrForbidden = errors.New("only project owner can manage webhooks")
+)
+
+// ProjectChecker interface
+type ProjectChecker interface {
+	GetProjectByID(ctx context.Context, projectID, userID int64) (*project.Project, error)
+}
+
+type Service struct {
+	repo           Repository
+	projectService ProjectChecker
+	client         *http.Client
+	now            func() time.Time
+}
+
+func NewService(repo Repository, projectService ProjectChecker) *Service {
+	return &Service{
+		repo:           repo,
+		projectService: projectService,
+		client:         newClient(),
+		now:            time.Now,
+	}
+}
+
+// CreateWebhookRequest DTO for webhook creation. Empty secret is generated
+type CreateWebhookRequest struct {
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret"`
+	EventTypes []string `json:"event_types"`
+}
+
+// UpdateWebhookRequest DTO for update. Activating webhook resets its failures
+type UpdateWebhookRequest struct {
+	URL        *string   `json:"url"`
+	EventTypes *[]string `json:"event_types"`
+	Active     *bool     `json:"active"`
+}
+
+// CreateWebhook subscribes URL to project events
+func (s *Service) CreateWebhook(ctx context.Context, req CreateWebhookRequest, projectID, userID int64) (*Webhook, error) {
+	if err := s.checkOwner(ctx, projectID, userID); err != nil {
+		return nil, err
+	}
+	if err := validateURL(req.URL); err != nil {
+		return nil, err
+	}
+	if err := validateEventTypes(req.EventTypes); err != nil {
+		return nil, err
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		var err error
+		if secret, err = generateSecret(); err != nil {
+			return nil, err
+		}
+	} else if len(secret) < 16 || len(secret) > 64 {
+		return nil, errors.New("secret must be 16 to 64 characters long")
+	}
+
+	w := &Webhook{
+		ProjectID:  projectID,
+		URL:        req.URL,
+		Secret:     secret,
+		EventTypes: append([]string{}, req.EventTypes...),
+		CreatedBy:  &userID,
+	}
+	if err := s.repo.Create(ctx, w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// ListWebhooks returns webhooks of project without secrets
+func (s *Service) ListWebhooks(ctx context.Context, projectID, userID int64) ([]Webhook, error) {
+	if err := s.checkOwner(ctx, projectID, userID); err != nil {
+		return nil, err
+	}
+	webhooks, err := s.repo.ListByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+	return webhooks, nil
+}
+
+// GetWebhook returns webhook without secret
+func (s *Service) GetWebhook(ctx context.Context, id, userID int64) (*Webhook, error) {
+	w, err := s.ownedWebhook(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	w.Secret = ""
+	return w, nil
+}
+
+// UpdateWebhook changes url, event types or state of webhook
+func (s *Service) UpdateWebhook(ctx context.Context, req UpdateWebhookRequest, id, userID int64) (*Webhook, error) {
+	w, err := s.ownedWebhook(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL != nil {
+		if err := validateURL(*req.URL); err != nil {
+			return nil, err
+		}
+		w.URL = *req.URL
+	}
+	if req.EventTypes != nil {
+		if err := validateEventTypes(*req.EventTypes); err != nil {
+			return nil, err
+		}
+		w.EventTypes = append([]string{}, *req.EventTypes...)
+	}
+	if req.Active != nil {
+		if *req.Active && !w.Active {
+			w.FailureCount = 0
+			w.DisabledAt = nil
+		}
+		w.Active = *req.Active
+	}
+
+	if err := s.repo.Update(ctx, w); err != nil {
+		return nil, err
+	}
+	w.Secret = ""
+	return w, nil
+}
+
+// DeleteWebhook removes webhook with its delivery log
+func (s *Service) DeleteWebhook(ctx context.Context, id, userID int64) error {
+	if _, err := s.ownedWebhook(ctx, id, userID); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// ListDeliveries returns latest deliveries of webhook
+func (s *Service) ListDeliveries(ctx context.Context, webhookID, userID int64) ([]Delivery, error) {
+	if _, err := s.ownedWebhook(ctx, webhookID, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListDeliveries(ctx, webhookID, deliveriesLimit)
+}
+
+// GetDelivery returns delivery with log of its attempts
+func (s *Service) GetDelivery(ctx context.Context, deliveryID, userID int64) (*DeliveryDetails, error) {
+	d, err := s.ownedDelivery(ctx, deliveryID, userID)
+	if err != nil {
+		return nil, err
+	}
+	attempts, err := s.repo.ListAttempts(ctx, d.ID)
+	if err != nil {
+		return nil, err
+	}
+	if attempts == nil {
+		attempts = []Attempt{}
+	}
+	return &DeliveryDetails{Delivery: *d, AttemptLog: attempts}, nil
+}
+
+// Redeliver queues copy of delivery with the same payload
+func (s *Service) Redeliver(ctx context.Context, deliveryID, userID int64) (*Delivery, error) {
+	d, err := s.ownedDelivery(ctx, deliveryID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	redelivery := &Delivery{
+		WebhookID:    d.WebhookID,
+		EventType:    d.EventType,
+		Payload:      d.Payload,
+		RedeliveryOf: &d.ID,
+	}
+	if err := s.repo.CreateDelivery(ctx, redelivery); err != nil {
+		return nil, err
+	}
+	return redelivery, nil
+}
+
+// Consume queues event for subscribed webhooks of its project in relay transaction
+func (s *Service) Consume(ctx context.Context, tx *sqlx.Tx, e event.Event) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return s.repo.Enqueue(ctx, tx, e, string(payload))
+}
+
+func (s *Service) checkOwner(ctx context.Context, projectID, userID int64) error {
+	p, err := s.projectService.GetProjectByID(ctx, projectID, userID)
+	if err != nil {
+		return err
+	}
+	if p.OwnerID != userID {
+		return ErrForbidden
+	}
+	return nil
+}
+
+func (s *Service) ownedWebhook(ctx context.Context, id, userID int64) (*Webhook, error) {
+	w, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	if err := s.checkOwner(ctx, w.ProjectID, userID); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (s *Service) ownedDelivery(ctx context.Context, id, userID int64) (*Delivery, error) {
+	d, err := s.repo.GetDelivery(ctx, id)
+	if err != nil {
+		return nil, errors.New("delivery not found")
+	}
+	if _, err := s.ownedWebhook(ctx, d.WebhookID, userID); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func validateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("url must be absolute http or https URL")
+	}
+	// names are checked again on every connect, this only rejects obvious internal hosts early
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrForbiddenAddress
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !publicAddr(addr) {
+		return ErrForbiddenAddress
+	}
+	return nil
+}
+
+func validateEventTypes(types []string) error {
+	for _, t := range types {
+		if !event.IsType(t) {
+			return fmt.Errorf("unknown event type %q", t)
+		}
+	}
+	return nil
+}
+
+func generateSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,243 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/event"
+	"github.com/antonovs105/project-management-system-go/internal/project"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSign(t *testing.T) {
+	sig := Sign("topsecret", 1700000000, []byte(`{"id":1}`))
+
+	assert.Equal(t, "sha256=", sig[:7])
+	assert.Len(t, sig, 7+64)
+	assert.Equal(t, sig, Sign("topsecret", 1700000000, []byte(`{"id":1}`)))
+	assert.NotEqual(t, sig, Sign("topsecret", 1700000001, []byte(`{"id":1}`)))
+	assert.NotEqual(t, sig, Sign("other", 1700000000, []byte(`{"id":1}`)))
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, 30*time.Second, RetryDelay(1))
+	assert.Equal(t, time.Minute, RetryDelay(2))
+	assert.Equal(t, 4*time.Minute, RetryDelay(4))
+	assert.Equal(t, 6*time.Hour, RetryDelay(20))
+}
+
+func TestService_CreateWebhook(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockProject := new(MockProjectChecker)
+	service := NewService(mockRepo, mockProject)
+
+	ctx := context.Background()
+	owned := &project.Project{ID: 10, OwnerID: 1}
+
+	t.Run("GeneratedSecret", func(t *testing.T) {
+		mockProject.On("GetProjectByID", ctx, int64(10), int64(1)).Return(owned, nil).Once()
+		mockRepo.On("Create", ctx, mock.MatchedBy(func(w *Webhook) bool {
+			return len(w.Secret) == 48 && w.URL == "https://example.com/hook"
+		})).Return(nil).Once()
+
+		req := CreateWebhookRequest{URL: "https://example.com/hook", EventTypes: []string{event.TicketCreated}}
+		w, err := service.CreateWebhook(ctx, req, 10, 1)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, w.Secret)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("NotOwner", func(t *testing.T) {
+		mockProject.On("GetProjectByID", ctx, int64(10), int64(2)).Return(owned, nil).Once()
+
+		_, err := service.CreateWebhook(ctx, CreateWebhookRequest{URL: "https://example.com"}, 10, 2)
+
+		assert.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("InvalidInput", func(t *testing.T) {
+		for _, req := range []CreateWebhookRequest{
+			{URL: "ftp://example.com"},
+			{URL: "/relative"},
+			{URL: "http://localhost:8080/hook"},
+			{URL: "http://127.0.0.1/hook"},
+			{URL: "http://169.254.169.254/latest/meta-data"},
+			{URL: "http://10.0.0.5/hook"},
+			{URL: "http://[::1]/hook"},
+			{URL: "https://example.com", EventTypes: []string{"ticket.exploded"}},
+			{URL: "https://example.com", Secret: "short"},
+		} {
+			mockProject.On("GetProjectByID", ctx, int64(10), int64(1)).Return(owned, nil).Once()
+
+			_, err := service.CreateWebhook(ctx, req, 10, 1)
+
+			assert.Error(t, err, req.URL)
+		}
+	})
+}
+
+func TestService_UpdateWebhook_Reactivate(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockProject := new(MockProjectChecker)
+	service := NewService(mockRepo, mockProject)
+
+	ctx := context.Background()
+	disabledAt := time.Now()
+	w := &Webhook{ID: 3, ProjectID: 10, Secret: "s", FailureCount: 20, DisabledAt: &disabledAt}
+	active := true
+
+	mockRepo.On("GetByID", ctx, int64(3)).Return(w, nil).Once()
+	mockProject.On("GetProjectByID", ctx, int64(10), int64(1)).Return(&project.Project{ID: 10, OwnerID: 1}, nil).Once()
+	mockRepo.On("Update", ctx, mock.MatchedBy(func(w *Webhook) bool {
+		return w.Active && w.FailureCount == 0 && w.DisabledAt == nil
+	})).Return(nil).Once()
+
+	updated, err := service.UpdateWebhook(ctx, UpdateWebhookRequest{Active: &active}, 3, 1)
+
+	assert.NoError(t, err)
+	assert.Empty(t, updated.Secret)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_Redeliver(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockProject := new(MockProjectChecker)
+	service := NewService(mockRepo, mockProject)
+
+	ctx := context.Background()
+	eventID := int64(99)
+	original := &Delivery{ID: 5, WebhookID: 3, EventID: &eventID, EventType: event.TicketUpdated, Payload: `{"id":99}`, Status: StatusFailed}
+
+	mockRepo.On("GetDelivery", ctx, int64(5)).Return(original, nil).Once()
+	mockRepo.On("GetByID", ctx, int64(3)).Return(&Webhook{ID: 3, ProjectID: 10}, nil).Once()
+	mockProject.On("GetProjectByID", ctx, int64(10), int64(1)).Return(&project.Project{ID: 10, OwnerID: 1}, nil).Once()
+	mockRepo.On("CreateDelivery", ctx, mock.MatchedBy(func(d *Delivery) bool {
+		return d.EventID == nil && *d.RedeliveryOf == 5 && d.Payload == original.Payload
+	})).Return(nil).Once()
+
+	_, err := service.Redeliver(ctx, 5, 1)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_DispatchDue(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewService(mockRepo, new(MockProjectChecker))
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	status := http.StatusOK
+	var received *http.Request
+	var receivedBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received, receivedBody = r, string(body)
+		w.WriteHeader(status)
+		w.Write([]byte("thanks"))
+	}))
+	defer server.Close()
+
+	// test server listens on loopback, which production client refuses
+	service.client = server.Client()
+	hook := &Webhook{ID: 3, URL: server.URL, Secret: "topsecret", Active: true}
+
+	t.Run("Succeeded", func(t *testing.T) {
+		mockRepo.On("ClaimDue", ctx, 1, lease).Return([]Delivery{{ID: 7, WebhookID: 3, EventType: event.TicketCreated, Payload: `{"id":1}`}}, nil).Once()
+		mockRepo.On("ClaimDue", ctx, 1, lease).Return([]Delivery{}, nil).Once()
+		mockRepo.On("GetByID", ctx, int64(3)).Return(hook, nil).Once()
+		mockRepo.On("RecordAttempt", mock.Anything, mock.MatchedBy(func(d *Delivery) bool {
+			return d.Status == StatusSucceeded && d.Attempts == 1 && *d.ResponseStatus == 200
+		}), mock.MatchedBy(func(a *Attempt) bool {
+			return *a.ResponseBody == "thanks" && a.RequestBody == `{"id":1}`
+		}), MaxFailures).Return(nil).Once()
+
+		n, err := service.DispatchDue(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, n)
+		assert.Equal(t, `{"id":1}`, receivedBody)
+		assert.Equal(t, "7", received.Header.Get("X-Webhook-Delivery"))
+		assert.Equal(t, strconv.FormatInt(now.Unix(), 10), received.Header.Get("X-Webhook-Timestamp"))
+		assert.Equal(t, Sign("topsecret", now.Unix(), []byte(receivedBody)), received.Header.Get("X-Webhook-Signature"))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("RetriedWithBackoff", func(t *testing.T) {
+		status = http.StatusInternalServerError
+		mockRepo.On("ClaimDue", ctx, 1, lease).Return([]Delivery{{ID: 8, WebhookID: 3, Attempts: 2, Payload: `{}`}}, nil).Once()
+		mockRepo.On("ClaimDue", ctx, 1, lease).Return([]Delivery{}, nil).Once()
+		mockRepo.On("GetByID", ctx, int64(3)).Return(hook, nil).Once()
+		mockRepo.On("RecordAttempt", mock.Anything, mock.MatchedBy(func(d *Delivery) bool {
+			return d.Status == StatusPending && d.Attempts == 3 && d.NextAttemptAt.Equal(now.Add(2*time.Minute))
+		}), mock.AnythingOfType("*webhook.Attempt"), MaxFailures).Return(nil).Once()
+
+		_, err := service.DispatchDue(ctx)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("FailedAfterLastAttempt", func(t *testing.T) {
+		mockRepo.On("ClaimDue", ctx, 1, lease).Return([]Delivery{{ID: 9, WebhookID: 3, Attempts: MaxAttempts - 1, Payload: `{}`}}, nil).Once()
+		mockRepo.On("ClaimDue", ctx, 1, lease).Return([]Delivery{}, nil).Once()
+		mockRepo.On("GetByID", ctx, int64(3)).Return(hook, nil).Once()
+		mockRepo.On("RecordAttempt", mock.Anything, mock.MatchedBy(func(d *Delivery) bool {
+			return d.Status == StatusFailed && d.Attempts == MaxAttempts
+		}), mock.AnythingOfType("*webhook.Attempt"), MaxFailures).Return(nil).Once()
+
+		_, err := service.DispatchDue(ctx)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestClient_RejectsInternalAddresses(t *testing.T) {
+	redirected := false
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirected = true
+	}))
+	defer target.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusFound)
+	}))
+	defer server.Close()
+
+	_, err := newClient().Post(server.URL, "application/json", nil)
+	assert.ErrorIs(t, err, ErrForbiddenAddress)
+
+	// redirects are returned as is, not followed
+	client := server.Client()
+	client.CheckRedirect = newClient().CheckRedirect
+	resp, err := client.Post(server.URL, "application/json", nil)
+	if assert.NoError(t, err) {
+		resp.Body.Close()
+		assert.Equal(t, http.StatusFound, resp.StatusCode)
+		assert.False(t, redirected)
+	}
+}
+
+func TestService_Consume(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewService(mockRepo, new(MockProjectChecker))
+
+	ctx := context.Background()
+	e := event.Event{ID: 1, ProjectID: 10, Type: event.TicketCreated, Data: []byte(`{"id":5}`)}
+	tx := &sqlx.Tx{}
+	mockRepo.On("Enqueue", ctx, tx, e, mock.MatchedBy(func(payload string) bool {
+		return assert.Contains(t, payload, `"data":{"id":5}`)
+	})).Return(nil).Once()
+
+	assert.NoError(t, service.Consume(ctx, tx, e))
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,82 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Delivery statuses
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// Webhook is project subscription for events sent to URL
+type Webhook struct {
+	ID        int64  `db:"id" json:"id"`
+	ProjectID int64  `db:"project_id" json:"project_id"`
+	URL       string `db:"url" json:"url"`
+	// Secret is returned only when webhook is created
+	Secret string `db:"secret" json:"secret,omitempty"`
+	// EventTypes to send, empty means all
+	EventTypes   pq.StringArray `db:"event_types" json:"event_types"`
+	Active       bool           `db:"active" json:"active"`
+	FailureCount int            `db:"failure_count" json:"failure_count"`
+	DisabledAt   *time.Time     `db:"disabled_at" json:"disabled_at"`
+	CreatedBy    *int64         `db:"created_by" json:"created_by"`
+	CreatedAt    time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt    time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+// Delivery is one event queued for webhook
+type Delivery struct {
+	ID             int64      `db:"id" json:"id"`
+	WebhookID      int64      `db:"webhook_id" json:"webhook_id"`
+	EventID        *int64     `db:"event_id" json:"event_id"`
+	EventType      string     `db:"event_type" json:"event_type"`
+	Payload        string     `db:"payload" json:"payload"`
+	Status         string     `db:"status" json:"status"`
+	Attempts       int        `db:"attempts" json:"attempts"`
+	NextAttemptAt  time.Time  `db:"next_attempt_at" json:"next_attempt_at"`
+	ResponseStatus *int       `db:"response_status" json:"response_status"`
+	Error          *string    `db:"error" json:"error"`
+	RedeliveryOf   *int64     `db:"redelivery_of" json:"redelivery_of"`
+	DeliveredAt    *time.Time `db:"delivered_at" json:"delivered_at"`
+	CreatedAt      time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+// Attempt is logged HTTP request of delivery
+type Attempt struct {
+	ID             int64     `db:"id" json:"id"`
+	DeliveryID     int64     `db:"delivery_id" json:"delivery_id"`
+	RequestHeaders string    `db:"request_headers" json:"request_headers"`
+	RequestBody    string    `db:"request_body" json:"request_body"`
+	ResponseStatus *int      `db:"response_status" json:"response_status"`
+	ResponseBody   *string   `db:"response_body" json:"response_body"`
+	Error          *string   `db:"error" json:"error"`
+	DurationMs     int       `db:"duration_ms" json:"duration_ms"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+}
+
+// DeliveryDetails is delivery with its attempts log
+type DeliveryDetails struct {
+	Delivery
+	AttemptLog []Attempt `json:"attempt_log"`
+}
+
+// Sign returns signature of body sent at timestamp: hex HMAC-SHA256 of "<timestamp>.<body>".
+// Receivers recompute it with webhook secret and compare to X-Webhook-Signature header
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,53 @@
+package worklog
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Work calendar used for "d" and "w" units
+const (
+	minutesPerHour = 60
+	hoursPerDay    = 8
+	daysPerWeek    = 5
+)
+
+var unitMinutes = map[byte]int64{
+	'm': 1,
+	'h': minutesPerHour,
+	'd': hoursPerDay * minutesPerHour,
+	'w': daysPerWeek * hoursPerDay * minutesPerHour,
+}
+
+// ParseDuration parses durations like "1w 2d 3h 30m" or "1h30m" into minutes.
+// Days and weeks are working ones: 1d = 8h, 1w = 5d
+func ParseDuration(s string) (int64, error) {
+	s = strings.ToLower(strings.ReplaceAll(s, " ", ""))
+	if s == "" {
+		return 0, errors.New("empty duration")
+	}
+
+	var total int64
+	start := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= '0' && c <= '9' {
+			continue
+		}
+		mult, ok := unitMinutes[c]
+		if !ok || i == start {
+			return 0, errors.New("invalid duration, use format like 1d 2h 30m")
+		}
+		n, err := strconv.ParseInt(s[start:i], 10, 64)
+		if err != nil {
+			return 0, errors.New("invalid duration number")
+		}
+		total += n * mult
+		start = i + 1
+	}
+	if start != len(s) {
+		return 0, errors.New("duration number without unit")
+	}
+	return total, nil
+}
@@ -0,0 +1,96 @@
+package worklog
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// Create handler for POST /api/tickets/:id/worklogs
+func (h *Handler) Create(c echo.Context) error {
+	ticketID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ticket ID"})
+	}
+
+	var req CreateWorklogRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+	}
+
+	userID := c.Get("userID").(int64)
+
+	w, err := h.service.LogWork(c.Request().Context(), req, ticketID, userID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusCreated, w)
+}
+
+// List handler for GET /api/tickets/:id/worklogs
+func (h *Handler) List(c echo.Context) error {
+	ticketID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ticket ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	worklogs, err := h.service.ListTicketWorklogs(c.Request().Context(), ticketID, userID)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, worklogs)
+}
+
+// Delete handler for DELETE /api/worklogs/:id
+func (h *Handler) Delete(c echo.Context) error {
+	worklogID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid worklog ID"})
+	}
+	userID := c.Get("userID").(int64)
+
+	err = h.service.DeleteWorklog(c.Request().Context(), worklogID, userID)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Timesheet handler for GET /api/me/worklogs?from=YYYY-MM-DD&to=YYYY-MM-DD
+// Defaults to last 7 days
+func (h *Handler) Timesheet(c echo.Context) error {
+	userID := c.Get("userID").(int64)
+
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	from := to.AddDate(0, 0, -6)
+	var err error
+	if v := c.QueryParam("from"); v != "" {
+		from, err = time.Parse(time.DateOnly, v)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid from date"})
+		}
+	}
+	if v := c.QueryParam("to"); v != "" {
+		to, err = time.Parse(time.DateOnly, v)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid to date"})
+		}
+	}
+
+	sheet, err := h.service.GetTimesheet(c.Request().Context(), userID, from, to)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, sheet)
+}
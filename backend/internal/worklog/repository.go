@@ -0,0 +1,164 @@
+package worklog
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/ticket"
+	"github.com/jmoiron/sqlx"
+)
+
+type Repository interface {
+	Create(ctx context.Context, w *Worklog, adjustRemaining bool) error
+	GetByID(ctx context.Context, id int64) (*Worklog, error)
+	ListByTicketID(ctx context.Context, ticketID int64) ([]Worklog, error)
+	Delete(ctx context.Context, w *Worklog, adjustRemaining bool) error
+	TotalsByTicketID(ctx context.Context, ticketID int64) ([]UserTotal, error)
+	Timesheet(ctx context.Context, userID int64, from, to time.Time) ([]TimesheetEntry, error)
+}
+
+type PgRepository struct {
+	db *sqlx.DB
+}
+
+func NewRepository(db *sqlx.DB) Repository {
+	return &PgRepository{db: db}
+}
+
+// Create saves worklog, adds it to ticket time spent and optionally decrements remaining estimate
+func (r *PgRepository) Create(ctx context.Context, w *Worklog, adjustRemaining bool) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO worklogs (ticket_id, user_id, duration_minutes, work_date, note)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING *`
+	err = tx.GetContext(ctx, w, query, w.TicketID, w.UserID, w.DurationMinutes, w.WorkDate, w.Note)
+	if err != nil {
+		return err
+	}
+
+	if err := updateTicketTime(ctx, tx, w.TicketID, w.DurationMinutes, adjustRemaining, w.UserID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetByID finds worklog by its id
+func (r *PgRepository) GetByID(ctx context.Context, id int64) (*Worklog, error) {
+	var w Worklog
+	err := r.db.GetContext(ctx, &w, `SELECT * FROM worklogs WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// ListByTicketID returns worklogs of ticket, latest work first
+func (r *PgRepository) ListByTicketID(ctx context.Context, ticketID int64) ([]Worklog, error) {
+	var worklogs []Worklog
+	query := `SELECT * FROM worklogs WHERE ticket_id = $1 ORDER BY work_date DESC, created_at DESC`
+
+	err := r.db.SelectContext(ctx, &worklogs, query, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	return worklogs, nil
+}
+
+// Delete removes worklog, subtracts it from time spent and optionally gives time back to remaining estimate
+func (r *PgRepository) Delete(ctx context.Context, w *Worklog, adjustRemaining bool) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM worklogs WHERE id = $1`, w.ID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("worklog to delete not found")
+	}
+
+	if err := updateTicketTime(ctx, tx, w.TicketID, -w.DurationMinutes, adjustRemaining, w.UserID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// updateTicketTime adds delta to ticket time spent and optionally takes it from remaining estimate.
+// Neither goes below zero, time given back by negative delta raises remaining estimate at most
+// to original estimate. Remaining estimate change is written to ticket change log
+func updateTicketTime(ctx context.Context, tx *sqlx.Tx, ticketID, delta int64, adjustRemaining bool, userID int64) error {
+	query := `
+		WITH old AS (
+			SELECT id, remaining_estimate_minutes FROM tickets WHERE id = $1 FOR UPDATE
+		), updated AS (
+			UPDATE tickets t
+			SET
+				time_spent_minutes = GREATEST(t.time_spent_minutes + $2, 0),
+				remaining_estimate_minutes = CASE
+					WHEN NOT $3 OR t.remaining_estimate_minutes IS NULL THEN t.remaining_estimate_minutes
+					WHEN $2 >= 0 THEN GREATEST(t.remaining_estimate_minutes - $2, 0)
+					-- time given back doesn't raise remaining above original estimate
+					ELSE LEAST(t.remaining_estimate_minutes - $2,
+						GREATEST(COALESCE(t.original_estimate_minutes, t.remaining_estimate_minutes - $2), t.remaining_estimate_minutes))
+				END,
+				updated_at = now()
+			FROM old
+			WHERE t.id = old.id
+			RETURNING t.id, old.remaining_estimate_minutes AS old_value, t.remaining_estimate_minutes AS new_value
+		)
+		INSERT INTO ticket_changes (ticket_id, field, old_value, new_value, changed_by)
+		SELECT id, $4, old_value::text, new_value::text, $5
+		FROM updated
+		WHERE old_value IS DISTINCT FROM new_value`
+	_, err := tx.ExecContext(ctx, query, ticketID, delta, adjustRemaining, ticket.FieldRemainingEstimate, userID)
+	return err
+}
+
+// TotalsByTicketID returns time logged on ticket per user
+func (r *PgRepository) TotalsByTicketID(ctx context.Context, ticketID int64) ([]UserTotal, error) {
+	var totals []UserTotal
+	query := `
+		SELECT user_id, SUM(duration_minutes) AS total_minutes
+		FROM worklogs WHERE ticket_id = $1
+		GROUP BY user_id ORDER BY total_minutes DESC`
+
+	err := r.db.SelectContext(ctx, &totals, query, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
+
+// Timesheet returns time logged by user per day and ticket, dates inclusive
+func (r *PgRepository) Timesheet(ctx context.Context, userID int64, from, to time.Time) ([]TimesheetEntry, error) {
+	var entries []TimesheetEntry
+	query := `
+		SELECT w.work_date, w.ticket_id, t.key AS ticket_key, t.project_id, SUM(w.duration_minutes) AS total_minutes
+		FROM worklogs w
+		JOIN tickets t ON t.id = w.ticket_id
+		WHERE w.user_id = $1 AND w.work_date BETWEEN $2 AND $3
+		GROUP BY w.work_date, w.ticket_id, t.key, t.project_id
+		ORDER BY w.work_date, t.key`
+
+	err := r.db.SelectContext(ctx, &entries, query, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
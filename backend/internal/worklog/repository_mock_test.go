@@ -0,0 +1,69 @@
+package worklog
+
+import (
+	"context"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/ticket"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRepository is a mock implementation of Repository
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Create(ctx context.Context, w *Worklog, adjustRemaining bool) error {
+	args := m.Called(ctx, w, adjustRemaining)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetByID(ctx context.Context, id int64) (*Worklog, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Worklog), args.Error(1)
+}
+
+func (m *MockRepository) ListByTicketID(ctx context.Context, ticketID int64) ([]Worklog, error) {
+	args := m.Called(ctx, ticketID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Worklog), args.Error(1)
+}
+
+func (m *MockRepository) Delete(ctx context.Context, w *Worklog, adjustRemaining bool) error {
+	args := m.Called(ctx, w, adjustRemaining)
+	return args.Error(0)
+}
+
+func (m *MockRepository) TotalsByTicketID(ctx context.Context, ticketID int64) ([]UserTotal, error) {
+	args := m.Called(ctx, ticketID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]UserTotal), args.Error(1)
+}
+
+func (m *MockRepository) Timesheet(ctx context.Context, userID int64, from, to time.Time) ([]TimesheetEntry, error) {
+	args := m.Called(ctx, userID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]TimesheetEntry), args.Error(1)
+}
+
+// MockTicketGetter
+type MockTicketGetter struct {
+	mock.Mock
+}
+
+func (m *MockTicketGetter) GetTicketByID(ctx context.Context, ticketID, userID int64) (*ticket.Ticket, error) {
+	args := m.Called(ctx, ticketID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ticket.Ticket), args.Error(1)
+}
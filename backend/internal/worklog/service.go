@@ -0,0 +1,178 @@
+package worklog
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/ticket"
+)
+
+// maxTimesheetRange limits timesheet queries
+const maxTimesheetRange = 366 * 24 * time.Hour
+
+// TicketGetter interface
+type TicketGetter interface {
+	GetTicketByID(ctx context.Context, ticketID, userID int64) (*ticket.Ticket, error)
+}
+
+type Service struct {
+	repo          Repository
+	ticketService TicketGetter
+}
+
+func NewService(repo Repository, ticketService TicketGetter) *Service {
+	return &Service{
+		repo:          repo,
+		ticketService: ticketService,
+	}
+}
+
+// CreateWorklogRequest DTO for logging work.
+// Either DurationMinutes or Duration ("1h 30m") is needed, empty WorkDate means today
+type CreateWorklogRequest struct {
+	DurationMinutes int64  `json:"duration_minutes"`
+	Duration        string `json:"duration"`
+	WorkDate        string `json:"work_date"`
+	Note            string `json:"note"`
+	// AdjustRemaining decrements remaining estimate, true if not set
+	AdjustRemaining *bool `json:"adjust_remaining"`
+}
+
+// LogWork adds worklog to ticket
+func (s *Service) LogWork(ctx context.Context, req CreateWorklogRequest, ticketID, userID int64) (*Worklog, error) {
+	// check access
+	_, err := s.ticketService.GetTicketByID(ctx, ticketID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	minutes := req.DurationMinutes
+	if req.Duration != "" {
+		minutes, err = ParseDuration(req.Duration)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if minutes <= 0 {
+		return nil, errors.New("duration must be positive")
+	}
+
+	workDate := time.Now().UTC().Truncate(24 * time.Hour)
+	if req.WorkDate != "" {
+		workDate, err = time.Parse(time.DateOnly, req.WorkDate)
+		if err != nil {
+			return nil, errors.New("invalid work date, expected YYYY-MM-DD")
+		}
+	}
+
+	adjust := req.AdjustRemaining == nil || *req.AdjustRemaining
+
+	w := &Worklog{
+		TicketID:        ticketID,
+		UserID:          userID,
+		DurationMinutes: minutes,
+		WorkDate:        workDate,
+		Note:            req.Note,
+	}
+
+	if err := s.repo.Create(ctx, w, adjust); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// TicketWorklogs is worklogs of ticket with totals
+type TicketWorklogs struct {
+	Worklogs     []Worklog   `json:"worklogs"`
+	TotalMinutes int64       `json:"total_minutes"`
+	PerUser      []UserTotal `json:"per_user"`
+}
+
+// ListTicketWorklogs returns worklogs of ticket with per-user totals
+func (s *Service) ListTicketWorklogs(ctx context.Context, ticketID, userID int64) (*TicketWorklogs, error) {
+	// check access
+	_, err := s.ticketService.GetTicketByID(ctx, ticketID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	worklogs, err := s.repo.ListByTicketID(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	totals, err := s.repo.TotalsByTicketID(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &TicketWorklogs{Worklogs: worklogs, PerUser: totals}
+	if result.Worklogs == nil {
+		result.Worklogs = []Worklog{}
+	}
+	if result.PerUser == nil {
+		result.PerUser = []UserTotal{}
+	}
+	for _, t := range totals {
+		result.TotalMinutes += t.TotalMinutes
+	}
+	return result, nil
+}
+
+// DeleteWorklog removes own worklog, time goes back to remaining estimate
+func (s *Service) DeleteWorklog(ctx context.Context, worklogID, userID int64) error {
+	w, err := s.repo.GetByID(ctx, worklogID)
+	if err != nil {
+		return errors.New("worklog not found")
+	}
+	if w.UserID != userID {
+		return errors.New("only author can delete worklog")
+	}
+
+	// check access
+	_, err = s.ticketService.GetTicketByID(ctx, w.TicketID, userID)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Delete(ctx, w, true)
+}
+
+// Timesheet is time logged by user in date range
+type Timesheet struct {
+	From         string           `json:"from"`
+	To           string           `json:"to"`
+	TotalMinutes int64            `json:"total_minutes"`
+	PerDay       map[string]int64 `json:"per_day"`
+	Entries      []TimesheetEntry `json:"entries"`
+}
+
+// GetTimesheet returns worklogs of user grouped by day and ticket, dates inclusive
+func (s *Service) GetTimesheet(ctx context.Context, userID int64, from, to time.Time) (*Timesheet, error) {
+	if to.Before(from) {
+		return nil, errors.New("'to' date must not be before 'from'")
+	}
+	if to.Sub(from) > maxTimesheetRange {
+		return nil, errors.New("date range is too long, max is one year")
+	}
+
+	entries, err := s.repo.Timesheet(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	sheet := &Timesheet{
+		From:    from.Format(time.DateOnly),
+		To:      to.Format(time.DateOnly),
+		PerDay:  make(map[string]int64),
+		Entries: entries,
+	}
+	if sheet.Entries == nil {
+		sheet.Entries = []TimesheetEntry{}
+	}
+	for _, e := range entries {
+		sheet.TotalMinutes += e.TotalMinutes
+		sheet.PerDay[e.WorkDate.Format(time.DateOnly)] += e.TotalMinutes
+	}
+	return sheet, nil
+}
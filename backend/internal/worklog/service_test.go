@@ -0,0 +1,154 @@
+package worklog
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/antonovs105/project-management-system-go/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestParseDuration(t *testing.T) {
+	cases := map[string]int64{
+		"30m":       30,
+		"1h30m":     90,
+		"1d 2h 30m": 8*60 + 2*60 + 30,
+		"1w":        5 * 8 * 60,
+		"2H":        120,
+	}
+	for in, want := range cases {
+		got, err := ParseDuration(in)
+		assert.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+
+	for _, in := range []string{"", "h", "10", "5x", "1h 2"} {
+		_, err := ParseDuration(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestService_LogWork(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockTicket := new(MockTicketGetter)
+	service := NewService(mockRepo, mockTicket)
+
+	ctx := context.Background()
+	ticketID := int64(5)
+	userID := int64(1)
+
+	t.Run("Success", func(t *testing.T) {
+		mockTicket.On("GetTicketByID", ctx, ticketID, userID).Return(&ticket.Ticket{ID: ticketID}, nil).Once()
+		mockRepo.On("Create", ctx, mock.MatchedBy(func(w *Worklog) bool {
+			return w.DurationMinutes == 150 && w.WorkDate.Format(time.DateOnly) == "2024-03-01"
+		}), true).Return(nil).Once()
+
+		req := CreateWorklogRequest{Duration: "2h 30m", WorkDate: "2024-03-01"}
+		w, err := service.LogWork(ctx, req, ticketID, userID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, userID, w.UserID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("KeepRemaining", func(t *testing.T) {
+		keep := false
+		mockTicket.On("GetTicketByID", ctx, ticketID, userID).Return(&ticket.Ticket{ID: ticketID}, nil).Once()
+		mockRepo.On("Create", ctx, mock.AnythingOfType("*worklog.Worklog"), false).Return(nil).Once()
+
+		req := CreateWorklogRequest{DurationMinutes: 45, AdjustRemaining: &keep}
+		_, err := service.LogWork(ctx, req, ticketID, userID)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("ZeroDuration", func(t *testing.T) {
+		mockTicket.On("GetTicketByID", ctx, ticketID, userID).Return(&ticket.Ticket{ID: ticketID}, nil).Once()
+
+		_, err := service.LogWork(ctx, CreateWorklogRequest{}, ticketID, userID)
+
+		assert.Error(t, err)
+		assert.Equal(t, "duration must be positive", err.Error())
+	})
+
+	t.Run("AccessDenied", func(t *testing.T) {
+		mockTicket.On("GetTicketByID", ctx, ticketID, int64(2)).Return(nil, ticket.ErrNotFound).Once()
+
+		_, err := service.LogWork(ctx, CreateWorklogRequest{DurationMinutes: 10}, ticketID, 2)
+
+		assert.ErrorIs(t, err, ticket.ErrNotFound)
+	})
+}
+
+func TestService_DeleteWorklog(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockTicket := new(MockTicketGetter)
+	service := NewService(mockRepo, mockTicket)
+
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		w := &Worklog{ID: 1, TicketID: 5, UserID: 1, DurationMinutes: 60}
+		mockRepo.On("GetByID", ctx, int64(1)).Return(w, nil).Once()
+		mockTicket.On("GetTicketByID", ctx, int64(5), int64(1)).Return(&ticket.Ticket{ID: 5}, nil).Once()
+		mockRepo.On("Delete", ctx, w, true).Return(nil).Once()
+
+		err := service.DeleteWorklog(ctx, 1, 1)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("NotAuthor", func(t *testing.T) {
+		w := &Worklog{ID: 2, TicketID: 5, UserID: 1}
+		mockRepo.On("GetByID", ctx, int64(2)).Return(w, nil).Once()
+
+		err := service.DeleteWorklog(ctx, 2, 3)
+
+		assert.Error(t, err)
+		assert.Equal(t, "only author can delete worklog", err.Error())
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mockRepo.On("GetByID", ctx, int64(3)).Return(nil, errors.New("no rows")).Once()
+
+		err := service.DeleteWorklog(ctx, 3, 1)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestService_GetTimesheet(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewService(mockRepo, new(MockTicketGetter))
+
+	ctx := context.Background()
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 7, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Success", func(t *testing.T) {
+		entries := []TimesheetEntry{
+			{WorkDate: from, TicketID: 1, TotalMinutes: 60},
+			{WorkDate: from, TicketID: 2, TotalMinutes: 30},
+			{WorkDate: to, TicketID: 1, TotalMinutes: 120},
+		}
+		mockRepo.On("Timesheet", ctx, int64(1), from, to).Return(entries, nil).Once()
+
+		sheet, err := service.GetTimesheet(ctx, 1, from, to)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(210), sheet.TotalMinutes)
+		assert.Equal(t, int64(90), sheet.PerDay["2024-03-01"])
+		assert.Equal(t, int64(120), sheet.PerDay["2024-03-07"])
+	})
+
+	t.Run("InvalidRange", func(t *testing.T) {
+		_, err := service.GetTimesheet(ctx, 1, to, from)
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,28 @@
+package worklog
+
+import "time"
+
+type Worklog struct {
+	ID              int64     `db:"id" json:"id"`
+	TicketID        int64     `db:"ticket_id" json:"ticket_id"`
+	UserID          int64     `db:"user_id" json:"user_id"`
+	DurationMinutes int64     `db:"duration_minutes" json:"duration_minutes"`
+	WorkDate        time.Time `db:"work_date" json:"work_date"`
+	Note            string    `db:"note" json:"note"`
+	CreatedAt       time.Time `db:"created_at" json:"created_at"`
+}
+
+// UserTotal is time logged by one user
+type UserTotal struct {
+	UserID       int64 `db:"user_id" json:"user_id"`
+	TotalMinutes int64 `db:"total_minutes" json:"total_minutes"`
+}
+
+// TimesheetEntry is time logged by user on ticket during one day
+type TimesheetEntry struct {
+	WorkDate     time.Time `db:"work_date" json:"work_date"`
+	TicketID     int64     `db:"ticket_id" json:"ticket_id"`
+	TicketKey    string    `db:"ticket_key" json:"ticket_key"`
+	ProjectID    int64     `db:"project_id" json:"project_id"`
+	TotalMinutes int64     `db:"total_minutes" json:"total_minutes"`
+}
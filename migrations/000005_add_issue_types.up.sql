@@ -0,0 +1,18 @@
+CREATE TABLE issue_types (
+    id BIGSERIAL PRIMARY KEY,
+    project_id BIGINT NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    rank INT NOT NULL,
+    icon VARCHAR(50) NOT NULL DEFAULT '',
+    color VARCHAR(20) NOT NULL DEFAULT '',
+    requires_parent BOOLEAN NOT NULL DEFAULT false,
+    allowed_parents TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    UNIQUE (project_id, name),
+
+    CONSTRAINT fk_project FOREIGN KEY(project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE issue_types IS 'Per-project issue type scheme, projects without rows use the default epic > task > subtask scheme';
+COMMENT ON COLUMN issue_types.allowed_parents IS 'Types that may parent this one, empty means any type of higher rank';
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS project_key_aliases;
+ALTER TABLE tickets DROP COLUMN IF EXISTS key;
+ALTER TABLE tickets DROP COLUMN IF EXISTS number;
+ALTER TABLE projects DROP COLUMN IF EXISTS ticket_counter;
+ALTER TABLE projects DROP COLUMN IF EXISTS key;
@@ -0,0 +1,35 @@
+ALTER TABLE projects ADD COLUMN key VARCHAR(10);
+ALTER TABLE projects ADD COLUMN ticket_counter BIGINT NOT NULL DEFAULT 0;
+
+-- existing projects get generated keys, owners can rename them later
+UPDATE projects SET key = 'P' || id;
+ALTER TABLE projects ALTER COLUMN key SET NOT NULL;
+ALTER TABLE projects ADD CONSTRAINT projects_key_key UNIQUE (key);
+
+ALTER TABLE tickets ADD COLUMN number BIGINT;
+ALTER TABLE tickets ADD COLUMN key VARCHAR(32);
+
+WITH numbered AS (
+    SELECT id, row_number() OVER (PARTITION BY project_id ORDER BY id) AS n FROM tickets
+)
+UPDATE tickets t SET number = numbered.n FROM numbered WHERE numbered.id = t.id;
+
+UPDATE tickets t SET key = p.key || '-' || t.number FROM projects p WHERE p.id = t.project_id;
+
+UPDATE projects p SET ticket_counter = COALESCE((SELECT MAX(number) FROM tickets WHERE project_id = p.id), 0);
+
+ALTER TABLE tickets ALTER COLUMN number SET NOT NULL;
+ALTER TABLE tickets ALTER COLUMN key SET NOT NULL;
+ALTER TABLE tickets ADD CONSTRAINT tickets_project_id_number_key UNIQUE (project_id, number);
+ALTER TABLE tickets ADD CONSTRAINT tickets_key_key UNIQUE (key);
+
+CREATE TABLE project_key_aliases (
+    key VARCHAR(10) PRIMARY KEY,
+    project_id BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT fk_project FOREIGN KEY(project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE project_key_aliases IS 'Previous keys of renamed projects, keep old ticket keys resolvable';
+COMMENT ON COLUMN tickets.key IS 'Denormalized project key and number, e.g. PMS-123. Rewritten on project key rename';
@@ -0,0 +1,2 @@
+ALTER TABLE tickets DROP COLUMN IF EXISTS sprint_id;
+DROP TABLE IF EXISTS sprints;
@@ -0,0 +1,24 @@
+CREATE TABLE sprints (
+    id BIGSERIAL PRIMARY KEY,
+    project_id BIGINT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    goal TEXT NOT NULL DEFAULT '',
+    state VARCHAR(20) NOT NULL DEFAULT 'planned',
+    start_date TIMESTAMPTZ,
+    end_date TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT fk_project FOREIGN KEY(project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    CONSTRAINT chk_sprint_state CHECK (state IN ('planned', 'active', 'closed'))
+);
+
+-- only one active sprint per project
+CREATE UNIQUE INDEX idx_sprints_one_active ON sprints(project_id) WHERE state = 'active';
+CREATE INDEX idx_sprints_project_id ON sprints(project_id);
+
+ALTER TABLE tickets ADD COLUMN sprint_id BIGINT REFERENCES sprints(id) ON DELETE SET NULL;
+CREATE INDEX idx_tickets_sprint_id ON tickets(sprint_id);
+
+COMMENT ON COLUMN tickets.sprint_id IS 'NULL means ticket is in project backlog';
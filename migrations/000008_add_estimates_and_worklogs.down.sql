@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS worklogs;
+ALTER TABLE tickets DROP COLUMN IF EXISTS time_spent_minutes;
+ALTER TABLE tickets DROP COLUMN IF EXISTS remaining_estimate_minutes;
+ALTER TABLE tickets DROP COLUMN IF EXISTS original_estimate_minutes;
+ALTER TABLE tickets DROP COLUMN IF EXISTS story_points;
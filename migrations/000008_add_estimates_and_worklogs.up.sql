@@ -0,0 +1,23 @@
+ALTER TABLE tickets ADD COLUMN story_points NUMERIC(6, 2);
+ALTER TABLE tickets ADD COLUMN original_estimate_minutes BIGINT;
+ALTER TABLE tickets ADD COLUMN remaining_estimate_minutes BIGINT;
+ALTER TABLE tickets ADD COLUMN time_spent_minutes BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE worklogs (
+    id BIGSERIAL PRIMARY KEY,
+    ticket_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    duration_minutes BIGINT NOT NULL,
+    work_date DATE NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT fk_ticket FOREIGN KEY(ticket_id) REFERENCES tickets(id) ON DELETE CASCADE,
+    CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_worklog_duration CHECK (duration_minutes > 0)
+);
+
+CREATE INDEX idx_worklogs_ticket_id ON worklogs(ticket_id);
+CREATE INDEX idx_worklogs_user_id_work_date ON worklogs(user_id, work_date);
+
+COMMENT ON COLUMN tickets.time_spent_minutes IS 'Sum of worklogs, maintained together with worklogs rows';
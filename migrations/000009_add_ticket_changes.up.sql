@@ -0,0 +1,17 @@
+CREATE TABLE ticket_changes (
+    id BIGSERIAL PRIMARY KEY,
+    ticket_id BIGINT NOT NULL,
+    field VARCHAR(50) NOT NULL,
+    old_value TEXT,
+    new_value TEXT,
+    changed_by BIGINT,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT fk_ticket FOREIGN KEY(ticket_id) REFERENCES tickets(id) ON DELETE CASCADE,
+    CONSTRAINT fk_changed_by FOREIGN KEY(changed_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_ticket_changes_ticket_id ON ticket_changes(ticket_id, changed_at);
+CREATE INDEX idx_ticket_changes_sprint ON ticket_changes(field, new_value, old_value) WHERE field = 'sprint_id';
+
+COMMENT ON TABLE ticket_changes IS 'Field level history of tickets, NULL old_value on creation';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_tickets_project_resolved_at;
+ALTER TABLE tickets DROP COLUMN IF EXISTS resolved_at;
+ALTER TABLE tickets DROP COLUMN IF EXISTS started_at;
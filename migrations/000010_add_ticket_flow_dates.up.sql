@@ -0,0 +1,20 @@
+ALTER TABLE tickets ADD COLUMN started_at TIMESTAMPTZ;
+ALTER TABLE tickets ADD COLUMN resolved_at TIMESTAMPTZ;
+
+-- best effort backfill from change log, falls back to last update for old tickets
+UPDATE tickets t SET started_at = COALESCE(
+    (SELECT MIN(c.changed_at) FROM ticket_changes c
+     WHERE c.ticket_id = t.id AND c.field = 'status' AND c.new_value IN ('in_progress', 'review', 'done')),
+    t.updated_at)
+WHERE t.status IN ('in_progress', 'review', 'done');
+
+UPDATE tickets t SET resolved_at = COALESCE(
+    (SELECT MAX(c.changed_at) FROM ticket_changes c
+     WHERE c.ticket_id = t.id AND c.field = 'status' AND c.new_value = 'done'),
+    t.updated_at)
+WHERE t.status = 'done';
+
+CREATE INDEX idx_tickets_project_resolved_at ON tickets(project_id, resolved_at);
+
+COMMENT ON COLUMN tickets.started_at IS 'First move into in-progress category, start of cycle time';
+COMMENT ON COLUMN tickets.resolved_at IS 'Last move into done category, NULL while ticket is not done';
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS sla_policies;
+DROP INDEX IF EXISTS idx_tickets_project_due_date;
+ALTER TABLE tickets DROP CONSTRAINT IF EXISTS chk_ticket_dates;
+ALTER TABLE tickets DROP COLUMN IF EXISTS sla_breached_at;
+ALTER TABLE tickets DROP COLUMN IF EXISTS due_date;
+ALTER TABLE tickets DROP COLUMN IF EXISTS start_date;
+ALTER TABLE projects DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1,25 @@
+ALTER TABLE projects ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+
+ALTER TABLE tickets ADD COLUMN start_date DATE;
+ALTER TABLE tickets ADD COLUMN due_date DATE;
+ALTER TABLE tickets ADD COLUMN sla_breached_at TIMESTAMPTZ;
+ALTER TABLE tickets ADD CONSTRAINT chk_ticket_dates CHECK (due_date IS NULL OR start_date IS NULL OR due_date >= start_date);
+
+CREATE INDEX idx_tickets_project_due_date ON tickets(project_id, due_date) WHERE resolved_at IS NULL;
+
+CREATE TABLE sla_policies (
+    id BIGSERIAL PRIMARY KEY,
+    project_id BIGINT NOT NULL,
+    priority VARCHAR(50) NOT NULL,
+    resolve_within_minutes BIGINT NOT NULL,
+    pause_statuses TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT fk_project FOREIGN KEY(project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    CONSTRAINT uq_sla_policy_priority UNIQUE (project_id, priority),
+    CONSTRAINT chk_sla_target CHECK (resolve_within_minutes > 0)
+);
+
+COMMENT ON COLUMN tickets.start_date IS 'Calendar date in project timezone';
+COMMENT ON COLUMN tickets.due_date IS 'Calendar date in project timezone, ticket is overdue after its end';
+COMMENT ON COLUMN tickets.sla_breached_at IS 'Moment SLA target was exceeded, set by breach sweeper';
@@ -0,0 +1,2 @@
+ALTER TABLE tickets DROP COLUMN IF EXISTS fix_version_id;
+DROP TABLE IF EXISTS versions;
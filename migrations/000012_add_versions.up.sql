@@ -0,0 +1,20 @@
+CREATE TABLE versions (
+    id BIGSERIAL PRIMARY KEY,
+    project_id BIGINT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    release_date DATE,
+    released BOOLEAN NOT NULL DEFAULT false,
+    released_at TIMESTAMPTZ,
+    archived BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT fk_project FOREIGN KEY(project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    CONSTRAINT uq_version_name UNIQUE (project_id, name)
+);
+
+ALTER TABLE tickets ADD COLUMN fix_version_id BIGINT REFERENCES versions(id) ON DELETE SET NULL;
+CREATE INDEX idx_tickets_fix_version_id ON tickets(fix_version_id);
+
+COMMENT ON COLUMN tickets.fix_version_id IS 'Version in which ticket is planned to be shipped';
@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS trg_ticket_comments_search ON ticket_comments;
+DROP TRIGGER IF EXISTS trg_tickets_search ON tickets;
+DROP FUNCTION IF EXISTS ticket_comments_search_trigger();
+DROP FUNCTION IF EXISTS tickets_search_trigger();
+DROP FUNCTION IF EXISTS refresh_ticket_search(BIGINT);
+DROP TABLE IF EXISTS ticket_search;
+DROP TABLE IF EXISTS ticket_comments;
@@ -0,0 +1,63 @@
+CREATE TABLE ticket_comments (
+    id BIGSERIAL PRIMARY KEY,
+    ticket_id BIGINT NOT NULL,
+    author_id BIGINT NOT NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT fk_ticket FOREIGN KEY(ticket_id) REFERENCES tickets(id) ON DELETE CASCADE,
+    CONSTRAINT fk_author FOREIGN KEY(author_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_ticket_comments_ticket_id ON ticket_comments(ticket_id, created_at);
+
+-- search document lives outside tickets so SELECT * of tickets stays unchanged
+CREATE TABLE ticket_search (
+    ticket_id BIGINT PRIMARY KEY REFERENCES tickets(id) ON DELETE CASCADE,
+    document TSVECTOR NOT NULL
+);
+
+CREATE INDEX idx_ticket_search_document ON ticket_search USING GIN (document);
+
+COMMENT ON TABLE ticket_search IS 'Full-text document of ticket: key and title (A), description (B), comments (C). Maintained by triggers';
+
+CREATE FUNCTION refresh_ticket_search(p_ticket_id BIGINT) RETURNS void AS $$
+    INSERT INTO ticket_search (ticket_id, document)
+    SELECT t.id,
+        setweight(to_tsvector('english', t.key || ' ' || t.title), 'A') ||
+        setweight(to_tsvector('english', coalesce(t.description, '')), 'B') ||
+        setweight(to_tsvector('english', coalesce(
+            (SELECT string_agg(c.body, ' ' ORDER BY c.id) FROM ticket_comments c WHERE c.ticket_id = t.id), '')), 'C')
+    FROM tickets t
+    WHERE t.id = p_ticket_id
+    ON CONFLICT (ticket_id) DO UPDATE SET document = EXCLUDED.document;
+$$ LANGUAGE sql;
+
+CREATE FUNCTION tickets_search_trigger() RETURNS trigger AS $$
+BEGIN
+    PERFORM refresh_ticket_search(NEW.id);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_tickets_search
+    AFTER INSERT OR UPDATE OF key, title, description ON tickets
+    FOR EACH ROW EXECUTE FUNCTION tickets_search_trigger();
+
+CREATE FUNCTION ticket_comments_search_trigger() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        PERFORM refresh_ticket_search(OLD.ticket_id);
+    ELSE
+        PERFORM refresh_ticket_search(NEW.ticket_id);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_ticket_comments_search
+    AFTER INSERT OR UPDATE OF body OR DELETE ON ticket_comments
+    FOR EACH ROW EXECUTE FUNCTION ticket_comments_search_trigger();
+
+SELECT refresh_ticket_search(id) FROM tickets;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS filter_favourites;
+DROP TABLE IF EXISTS filters;
@@ -0,0 +1,32 @@
+CREATE TABLE filters (
+    id BIGSERIAL PRIMARY KEY,
+    owner_id BIGINT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    query TEXT NOT NULL,
+    visibility VARCHAR(20) NOT NULL DEFAULT 'private',
+    project_id BIGINT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT fk_owner FOREIGN KEY(owner_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_project FOREIGN KEY(project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    CONSTRAINT uq_filter_name UNIQUE (owner_id, name),
+    CONSTRAINT chk_filter_visibility CHECK (visibility IN ('private', 'project', 'public')),
+    CONSTRAINT chk_filter_project CHECK ((visibility = 'project') = (project_id IS NOT NULL))
+);
+
+CREATE INDEX idx_filters_project_id ON filters(project_id) WHERE visibility = 'project';
+
+CREATE TABLE filter_favourites (
+    user_id BIGINT NOT NULL,
+    filter_id BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (user_id, filter_id),
+    CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_filter FOREIGN KEY(filter_id) REFERENCES filters(id) ON DELETE CASCADE
+);
+
+COMMENT ON COLUMN filters.query IS 'Ticket query language text, e.g. project = PMS AND status != done';
+COMMENT ON COLUMN filters.visibility IS 'private: owner only, project: members of project_id, public: every user';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_projects_owner_created;
+DROP INDEX IF EXISTS idx_tickets_project_updated;
+DROP INDEX IF EXISTS idx_tickets_project_created;
@@ -0,0 +1,3 @@
+CREATE INDEX idx_tickets_project_created ON tickets(project_id, created_at, id);
+CREATE INDEX idx_tickets_project_updated ON tickets(project_id, updated_at, id);
+CREATE INDEX idx_projects_owner_created ON projects(owner_id, created_at, id);
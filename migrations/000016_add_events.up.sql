@@ -0,0 +1,15 @@
+-- events are kept for a while so reconnecting clients can replay what they missed
+CREATE TABLE events (
+    id BIGSERIAL PRIMARY KEY,
+    project_id BIGINT NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    actor_id BIGINT,
+    data JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT fk_project FOREIGN KEY(project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    CONSTRAINT fk_actor FOREIGN KEY(actor_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_events_project_id ON events(project_id, id);
+CREATE INDEX idx_events_created_at ON events(created_at);
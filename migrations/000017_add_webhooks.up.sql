@@ -0,0 +1,64 @@
+CREATE TABLE webhooks (
+    id BIGSERIAL PRIMARY KEY,
+    project_id BIGINT NOT NULL,
+    url TEXT NOT NULL,
+    secret VARCHAR(64) NOT NULL,
+    -- empty list means every event type
+    event_types TEXT[] NOT NULL DEFAULT '{}',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    -- consecutive failed attempts, webhook is disabled when it gets too big
+    failure_count INT NOT NULL DEFAULT 0,
+    disabled_at TIMESTAMPTZ,
+    created_by BIGINT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT fk_project FOREIGN KEY(project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    CONSTRAINT fk_created_by FOREIGN KEY(created_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_webhooks_project_id ON webhooks(project_id);
+
+-- deliveries are the durable queue: pending rows are claimed by dispatchers with SKIP LOCKED
+CREATE TABLE webhook_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    webhook_id BIGINT NOT NULL,
+    event_id BIGINT,
+    event_type VARCHAR(50) NOT NULL,
+    payload TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    response_status INT,
+    error TEXT,
+    redelivery_of BIGINT,
+    delivered_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT fk_webhook FOREIGN KEY(webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE,
+    CONSTRAINT fk_redelivery_of FOREIGN KEY(redelivery_of) REFERENCES webhook_deliveries(id) ON DELETE SET NULL,
+    CONSTRAINT chk_status CHECK (status IN ('pending', 'succeeded', 'failed'))
+);
+
+-- one delivery per event, redeliveries have no event_id
+CREATE UNIQUE INDEX idx_webhook_deliveries_event ON webhook_deliveries(webhook_id, event_id);
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_webhook_deliveries_webhook_id ON webhook_deliveries(webhook_id, created_at);
+
+-- log of every attempt with request and response
+CREATE TABLE webhook_attempts (
+    id BIGSERIAL PRIMARY KEY,
+    delivery_id BIGINT NOT NULL,
+    request_headers TEXT NOT NULL,
+    request_body TEXT NOT NULL,
+    response_status INT,
+    response_body TEXT,
+    error TEXT,
+    duration_ms INT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT fk_delivery FOREIGN KEY(delivery_id) REFERENCES webhook_deliveries(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_webhook_attempts_delivery_id ON webhook_attempts(delivery_id, id);
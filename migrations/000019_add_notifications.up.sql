@@ -0,0 +1,31 @@
+CREATE TABLE notifications (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    type VARCHAR(32) NOT NULL,
+    event_id BIGINT NOT NULL,
+    project_id BIGINT NOT NULL,
+    ticket_id BIGINT,
+    actor_id BIGINT,
+    data JSONB NOT NULL DEFAULT '{}',
+    read_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_project FOREIGN KEY(project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    CONSTRAINT fk_ticket FOREIGN KEY(ticket_id) REFERENCES tickets(id) ON DELETE CASCADE,
+    CONSTRAINT fk_actor FOREIGN KEY(actor_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- event gives each user at most one notification of a type
+CREATE UNIQUE INDEX idx_notifications_event ON notifications(user_id, event_id, type);
+CREATE INDEX idx_notifications_user_created ON notifications(user_id, created_at, id);
+CREATE INDEX idx_notifications_unread ON notifications(user_id) WHERE read_at IS NULL;
+
+CREATE TABLE notification_preferences (
+    user_id BIGINT NOT NULL,
+    type VARCHAR(32) NOT NULL,
+    enabled BOOLEAN NOT NULL,
+
+    PRIMARY KEY (user_id, type),
+    CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS notification_email_settings;
+DROP INDEX IF EXISTS idx_notifications_unemailed;
+ALTER TABLE notifications DROP COLUMN IF EXISTS emailed_at;
@@ -0,0 +1,17 @@
+ALTER TABLE notifications ADD COLUMN emailed_at TIMESTAMPTZ;
+
+-- notifications made before emails existed are not sent
+UPDATE notifications SET emailed_at = created_at;
+
+CREATE INDEX idx_notifications_unemailed ON notifications(user_id, created_at)
+    WHERE emailed_at IS NULL AND read_at IS NULL;
+
+CREATE TABLE notification_email_settings (
+    user_id BIGINT PRIMARY KEY,
+    -- off, immediate or daily
+    mode VARCHAR(16) NOT NULL DEFAULT 'immediate',
+    last_digest_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
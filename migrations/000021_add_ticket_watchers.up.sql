@@ -0,0 +1,20 @@
+CREATE TABLE ticket_watchers (
+    ticket_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (ticket_id, user_id),
+    CONSTRAINT fk_ticket FOREIGN KEY(ticket_id) REFERENCES tickets(id) ON DELETE CASCADE,
+    CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_ticket_watchers_user ON ticket_watchers(user_id);
+
+-- existing reporters, assignees and commenters watch their tickets
+INSERT INTO ticket_watchers (ticket_id, user_id)
+SELECT id, reporter_id FROM tickets
+UNION
+SELECT id, assignee_id FROM tickets WHERE assignee_id IS NOT NULL
+UNION
+SELECT ticket_id, author_id FROM ticket_comments
+ON CONFLICT DO NOTHING;
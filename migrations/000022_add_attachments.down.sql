@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS trg_attachments_delete ON attachments;
+DROP FUNCTION IF EXISTS attachments_delete_trigger();
+DROP TABLE IF EXISTS attachment_deletions;
+DROP TABLE IF EXISTS attachments;
@@ -0,0 +1,34 @@
+CREATE TABLE attachments (
+    id BIGSERIAL PRIMARY KEY,
+    ticket_id BIGINT NOT NULL,
+    uploader_id BIGINT,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(127) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    storage_key VARCHAR(512) NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT fk_ticket FOREIGN KEY(ticket_id) REFERENCES tickets(id) ON DELETE CASCADE,
+    CONSTRAINT fk_uploader FOREIGN KEY(uploader_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_attachments_ticket ON attachments(ticket_id, id);
+
+-- stored files of deleted attachments, cleanup worker removes them from storage
+CREATE TABLE attachment_deletions (
+    storage_key VARCHAR(512) PRIMARY KEY,
+    deleted_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- fires for cascades from tickets and projects too, so no file is left behind
+CREATE FUNCTION attachments_delete_trigger() RETURNS trigger AS $$
+BEGIN
+    INSERT INTO attachment_deletions (storage_key) VALUES (OLD.storage_key)
+    ON CONFLICT DO NOTHING;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_attachments_delete
+    AFTER DELETE ON attachments
+    FOR EACH ROW EXECUTE FUNCTION attachments_delete_trigger();
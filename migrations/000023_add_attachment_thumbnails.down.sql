@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS trg_attachment_thumbnails_delete ON attachment_thumbnails;
+DROP TABLE IF EXISTS attachment_thumbnails;
+ALTER TABLE attachments DROP COLUMN IF EXISTS thumbnail_next_attempt_at;
+ALTER TABLE attachments DROP COLUMN IF EXISTS thumbnail_attempts;
+ALTER TABLE attachments DROP COLUMN IF EXISTS thumbnail_status;
@@ -0,0 +1,28 @@
+-- thumbnail_status is NULL for files without thumbnails, otherwise pending, ready or failed
+ALTER TABLE attachments ADD COLUMN thumbnail_status VARCHAR(16);
+ALTER TABLE attachments ADD COLUMN thumbnail_attempts INT NOT NULL DEFAULT 0;
+ALTER TABLE attachments ADD COLUMN thumbnail_next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+-- images uploaded before get thumbnails too
+UPDATE attachments SET thumbnail_status = 'pending'
+WHERE content_type IN ('image/png', 'image/jpeg', 'image/gif');
+
+CREATE INDEX idx_attachments_thumbnails_pending ON attachments(thumbnail_next_attempt_at)
+    WHERE thumbnail_status = 'pending';
+
+CREATE TABLE attachment_thumbnails (
+    attachment_id BIGINT NOT NULL,
+    size VARCHAR(16) NOT NULL,
+    storage_key VARCHAR(512) NOT NULL UNIQUE,
+    content_type VARCHAR(127) NOT NULL,
+    width INT NOT NULL,
+    height INT NOT NULL,
+
+    PRIMARY KEY (attachment_id, size),
+    CONSTRAINT fk_attachment FOREIGN KEY(attachment_id) REFERENCES attachments(id) ON DELETE CASCADE
+);
+
+-- thumbnail files are queued for cleanup like originals
+CREATE TRIGGER trg_attachment_thumbnails_delete
+    AFTER DELETE ON attachment_thumbnails
+    FOR EACH ROW EXECUTE FUNCTION attachments_delete_trigger();
@@ -0,0 +1,3 @@
+ALTER TABLE tickets DROP CONSTRAINT tickets_parent_id_fkey;
+ALTER TABLE tickets ADD CONSTRAINT tickets_parent_id_fkey
+    FOREIGN KEY (parent_id) REFERENCES tickets(id);
@@ -0,0 +1,4 @@
+-- children of deleted ticket are detached instead of failing delete, app picks cascade/orphan/reparent explicitly
+ALTER TABLE tickets DROP CONSTRAINT tickets_parent_id_fkey;
+ALTER TABLE tickets ADD CONSTRAINT tickets_parent_id_fkey
+    FOREIGN KEY (parent_id) REFERENCES tickets(id) ON DELETE SET NULL;
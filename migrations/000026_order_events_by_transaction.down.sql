@@ -0,0 +1,3 @@
+ALTER TABLE event_checkpoints DROP COLUMN IF EXISTS last_tx_id;
+DROP INDEX IF EXISTS idx_events_tx_id;
+ALTER TABLE events DROP COLUMN IF EXISTS tx_id;
@@ -0,0 +1,8 @@
+-- events are ordered by writing transaction, so no global lock is needed to keep relay from skipping
+-- ids that commit late. Existing events and checkpoints share migration's transaction id
+ALTER TABLE events ADD COLUMN tx_id xid8 NOT NULL DEFAULT pg_current_xact_id();
+CREATE INDEX idx_events_tx_id ON events(tx_id, id);
+
+ALTER TABLE event_checkpoints ADD COLUMN last_tx_id xid8;
+UPDATE event_checkpoints SET last_tx_id = pg_current_xact_id();
+ALTER TABLE event_checkpoints ALTER COLUMN last_tx_id SET NOT NULL;
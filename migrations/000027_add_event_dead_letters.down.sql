@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS event_dead_letters;
+ALTER TABLE event_checkpoints
+    DROP COLUMN IF EXISTS last_error,
+    DROP COLUMN IF EXISTS attempts,
+    DROP COLUMN IF EXISTS failed_event_id;
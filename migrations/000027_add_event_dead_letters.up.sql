@@ -0,0 +1,16 @@
+-- consumer remembers event it fails on, after too many attempts event is put aside so others can go on
+ALTER TABLE event_checkpoints
+    ADD COLUMN failed_event_id BIGINT,
+    ADD COLUMN attempts INT NOT NULL DEFAULT 0,
+    ADD COLUMN last_error TEXT;
+
+CREATE TABLE event_dead_letters (
+    id BIGSERIAL PRIMARY KEY,
+    consumer VARCHAR(64) NOT NULL,
+    event_id BIGINT NOT NULL,
+    attempts INT NOT NULL,
+    error TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_event_dead_letters_consumer ON event_dead_letters(consumer, created_at);
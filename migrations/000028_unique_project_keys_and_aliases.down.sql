@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS trg_project_key_aliases_unique ON project_key_aliases;
+DROP TRIGGER IF EXISTS trg_projects_key_unique ON projects;
+DROP FUNCTION IF EXISTS project_keys_unique_trigger();
@@ -0,0 +1,29 @@
+-- current keys and aliases of old keys share one namespace, so ticket key resolves to one project.
+-- Advisory lock on key makes concurrent writers of the same key check one after another
+CREATE FUNCTION project_keys_unique_trigger() RETURNS trigger AS $$
+DECLARE
+    owner_id BIGINT;
+BEGIN
+    PERFORM pg_advisory_xact_lock(hashtext('project_key:' || NEW.key));
+
+    IF TG_TABLE_NAME = 'projects' THEN
+        SELECT project_id INTO owner_id FROM project_key_aliases WHERE key = NEW.key AND project_id <> NEW.id;
+    ELSE
+        SELECT id INTO owner_id FROM projects WHERE key = NEW.key AND id <> NEW.project_id;
+    END IF;
+
+    IF owner_id IS NOT NULL THEN
+        RAISE EXCEPTION 'project key % is already used by project %', NEW.key, owner_id
+            USING ERRCODE = 'unique_violation', CONSTRAINT = 'project_keys_unique';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_projects_key_unique
+    BEFORE INSERT OR UPDATE OF key ON projects
+    FOR EACH ROW EXECUTE FUNCTION project_keys_unique_trigger();
+
+CREATE TRIGGER trg_project_key_aliases_unique
+    BEFORE INSERT OR UPDATE OF key ON project_key_aliases
+    FOR EACH ROW EXECUTE FUNCTION project_keys_unique_trigger();